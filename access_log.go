@@ -0,0 +1,119 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNilLogger when a nil logger is provided.
+	ErrNilLogger = errors.New("logger cannot be nil")
+)
+
+// AccessLogger is middleware that logs a line for every request with the names of the Faults that
+// injected into it, so that injected requests stand out in standard access logs.
+type AccessLogger struct {
+	logger PrintfLogger
+	nowF   func() time.Time
+}
+
+// AccessLoggerOption configures an AccessLogger.
+type AccessLoggerOption interface {
+	applyAccessLogger(a *AccessLogger) error
+}
+
+func (o nowFuncOption) applyAccessLogger(a *AccessLogger) error {
+	a.nowF = o
+	return nil
+}
+
+// NewAccessLogger returns an AccessLogger that logs to l.
+func NewAccessLogger(l PrintfLogger, opts ...AccessLoggerOption) (*AccessLogger, error) {
+	if l == nil {
+		return nil, &OptionError{Option: "NewAccessLogger", Value: nil, Err: ErrNilLogger}
+	}
+
+	// set defaults
+	a := &AccessLogger{
+		logger: l,
+		nowF:   time.Now,
+	}
+
+	// apply options
+	err := applyOptions(opts, AccessLoggerOption.applyAccessLogger, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Handler logs each request after next handles it, such as
+// "fault: GET /search?q=test 503 22 1.2ms injected=ErrorInjector". The status is "aborted" if next
+// panics, such as with the RejectInjector, and injected is "-" if no Fault injected. Wrap the
+// AccessLogger around the Faults it should report on.
+func (a *AccessLogger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, injected := TrackInjections(r)
+		rw := &ResponseRecorderWriter{w: w}
+		start := a.nowF()
+
+		status := "aborted"
+		defer func() {
+			names := "-"
+			if n := injected(); len(n) > 0 {
+				names = strings.Join(n, ",")
+			}
+
+			a.logger.Printf("fault: %s %s %s %d %s injected=%s",
+				r.Method, r.URL.RequestURI(), status, rw.BytesWritten(), a.nowF().Sub(start), names)
+		}()
+
+		next.ServeHTTP(rw, r)
+		status = strconv.Itoa(rw.StatusCode())
+	})
+}
+
+// injectionTrackerKey is the request context key for an injectionTracker.
+type injectionTrackerKey struct{}
+
+// injectionTracker records the names of the Faults that inject into a request.
+type injectionTracker struct {
+	names []string
+	mtx   sync.Mutex
+}
+
+// TrackInjections returns a shallow copy of r that records the names of the Faults that inject into
+// it, and a function that returns those names in the order they injected. Middleware that wraps
+// Faults cannot see the context values that the Faults add for the handlers they wrap, so use
+// TrackInjections to add fault information to other access log middleware. Call the function after
+// the request is handled.
+func TrackInjections(r *http.Request) (*http.Request, func() []string) {
+	t := &injectionTracker{}
+	r = r.WithContext(context.WithValue(r.Context(), injectionTrackerKey{}, t))
+
+	return r, func() []string {
+		t.mtx.Lock()
+		defer t.mtx.Unlock()
+
+		return append([]string(nil), t.names...)
+	}
+}
+
+// trackInjection records that the Fault named name injected into r if r is tracked by
+// TrackInjections.
+func trackInjection(r *http.Request, name string) {
+	t, ok := r.Context().Value(injectionTrackerKey{}).(*injectionTracker)
+	if !ok {
+		return
+	}
+
+	t.mtx.Lock()
+	t.names = append(t.names, name)
+	t.mtx.Unlock()
+}
@@ -0,0 +1,153 @@
+package fault
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewAccessLogger tests NewAccessLogger.
+func TestNewAccessLogger(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(&bytes.Buffer{}, "", 0)
+
+	tests := []struct {
+		name        string
+		giveLogger  PrintfLogger
+		giveOptions []AccessLoggerOption
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveLogger:  l,
+			giveOptions: nil,
+			wantErr:     nil,
+		},
+		{
+			name:        "all options",
+			giveLogger:  l,
+			giveOptions: []AccessLoggerOption{WithNowFunc(time.Now)},
+			wantErr:     nil,
+		},
+		{
+			name:        "nil logger",
+			giveLogger:  nil,
+			giveOptions: nil,
+			wantErr:     &OptionError{Option: "NewAccessLogger", Value: nil, Err: ErrNilLogger},
+		},
+		{
+			name:        "option error",
+			giveLogger:  l,
+			giveOptions: []AccessLoggerOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, err := NewAccessLogger(tt.giveLogger, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil, a != nil)
+		})
+	}
+}
+
+// TestAccessLoggerHandler tests AccessLogger.Handler.
+func TestAccessLoggerHandler(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRejectInjector()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		giveFaults func(t *testing.T) []*Fault
+		wantLog    string
+		wantPanic  bool
+	}{
+		{
+			name:       "no faults",
+			giveFaults: func(t *testing.T) []*Fault { return nil },
+			wantLog:    "fault: GET /a?b=c 200 2 1s injected=-\n",
+		},
+		{
+			name: "injected",
+			giveFaults: func(t *testing.T) []*Fault {
+				one, err := NewFault(newTestInjectorNoop(), WithEnabled(true), WithParticipation(1.0), WithName("one"))
+				assert.NoError(t, err)
+				two, err := NewFault(newTestInjectorNoop(), WithEnabled(true), WithParticipation(0.0), WithName("two"))
+				assert.NoError(t, err)
+				three, err := NewFault(newTestInjector500s(), WithEnabled(true), WithParticipation(1.0),
+					WithName("three"), WithContextAnnotation(false))
+				assert.NoError(t, err)
+				return []*Fault{one, two, three}
+			},
+			wantLog: "fault: GET /a?b=c 500 22 1s injected=one,three\n",
+		},
+		{
+			name: "aborted",
+			giveFaults: func(t *testing.T) []*Fault {
+				f, err := NewFault(ri, WithEnabled(true), WithParticipation(1.0))
+				assert.NoError(t, err)
+				return []*Fault{f}
+			},
+			wantLog:   "fault: GET /a?b=c aborted 0 1s injected=RejectInjector\n",
+			wantPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			now := time.Unix(0, 0)
+			a, err := NewAccessLogger(log.New(&buf, "", 0), WithNowFunc(func() time.Time {
+				now = now.Add(time.Second)
+				return now
+			}))
+			assert.NoError(t, err)
+
+			h := a.Handler(Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "ok")
+			}), tt.giveFaults(t)...))
+
+			serve := func() {
+				h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a?b=c", nil))
+			}
+			if tt.wantPanic {
+				assert.PanicsWithValue(t, http.ErrAbortHandler, serve)
+			} else {
+				serve()
+			}
+
+			assert.Equal(t, tt.wantLog, buf.String())
+		})
+	}
+}
+
+// TestTrackInjections tests TrackInjections.
+func TestTrackInjections(t *testing.T) {
+	t.Parallel()
+
+	r, injected := TrackInjections(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, injected())
+
+	trackInjection(r, "one")
+	trackInjection(r, "two")
+	assert.Equal(t, []string{"one", "two"}, injected())
+
+	// requests that are not tracked are ignored
+	trackInjection(httptest.NewRequest(http.MethodGet, "/", nil), "three")
+	assert.Equal(t, []string{"one", "two"}, injected())
+}
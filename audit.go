@@ -0,0 +1,127 @@
+package fault
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNilWriter when a nil io.Writer is provided.
+	ErrNilWriter = errors.New("writer cannot be nil")
+)
+
+// defaultRequestIDHeader is the header an AuditReporter reads request IDs from by default.
+const defaultRequestIDHeader = "X-Request-Id"
+
+// AuditReporter is an EventReporter that writes one JSON object per line for every injection,
+// producing an audit trail of injected faults.
+type AuditReporter struct {
+	w      io.Writer
+	header string
+	errorF func(err error)
+
+	// writeMtx serializes writes to AuditReporter.w.
+	writeMtx sync.Mutex
+}
+
+// auditRecord is a single line written by an AuditReporter.
+type auditRecord struct {
+	Time        time.Time `json:"time"`
+	Fault       string    `json:"fault"`
+	Injector    string    `json:"injector"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	RequestID   string    `json:"requestId,omitempty"`
+	InjectionID string    `json:"injectionId,omitempty"`
+}
+
+// AuditReporterOption configures an AuditReporter.
+type AuditReporterOption interface {
+	applyAuditReporter(r *AuditReporter) error
+}
+
+type requestIDHeaderOption string
+
+func (o requestIDHeaderOption) applyAuditReporter(r *AuditReporter) error {
+	r.header = string(o)
+	return nil
+}
+
+// WithRequestIDHeader sets the request header that contains the request ID. Default
+// "X-Request-Id".
+func WithRequestIDHeader(h string) AuditReporterOption {
+	return requestIDHeaderOption(h)
+}
+
+type auditErrorFuncOption func(err error)
+
+func (o auditErrorFuncOption) applyAuditReporter(r *AuditReporter) error {
+	if o == nil {
+		return &OptionError{Option: "WithAuditErrorFunc", Value: nil, Err: ErrNilFunc}
+	}
+	r.errorF = o
+	return nil
+}
+
+// WithAuditErrorFunc sets a function that receives any error writing a record, for example to
+// alert when the audit trail is incomplete. By default errors are ignored.
+func WithAuditErrorFunc(f func(err error)) AuditReporterOption {
+	return auditErrorFuncOption(f)
+}
+
+// NewAuditReporter returns an AuditReporter that writes to w, such as an *os.File or a
+// RotatingFile. Each line is a JSON object with the keys time, fault, injector, method, path,
+// requestId, and, for Faults with WithInjectionID, injectionId.
+func NewAuditReporter(w io.Writer, opts ...AuditReporterOption) (*AuditReporter, error) {
+	if w == nil {
+		return nil, &OptionError{Option: "NewAuditReporter", Value: nil, Err: ErrNilWriter}
+	}
+
+	// set defaults
+	ar := &AuditReporter{
+		w:      w,
+		header: defaultRequestIDHeader,
+		errorF: func(err error) {},
+	}
+
+	// apply options
+	err := applyOptions(opts, AuditReporterOption.applyAuditReporter, ar)
+	if err != nil {
+		return nil, err
+	}
+
+	return ar, nil
+}
+
+// ReportEvent writes e as a single line of JSON. Events for requests that were not injected, which
+// are only sent with WithDebugTrace, are ignored.
+func (r *AuditReporter) ReportEvent(e Event) {
+	if e.Trace != nil && !e.Trace.Injected() {
+		return
+	}
+
+	line, err := json.Marshal(auditRecord{
+		Time:        e.Time,
+		Fault:       e.Fault,
+		Injector:    e.Injector,
+		Method:      e.Request.Method,
+		Path:        e.Request.URL.Path,
+		RequestID:   e.Request.Header.Get(r.header),
+		InjectionID: e.InjectionID,
+	})
+	if err != nil {
+		r.errorF(err)
+		return
+	}
+
+	r.writeMtx.Lock()
+	defer r.writeMtx.Unlock()
+
+	_, err = r.w.Write(append(line, '\n'))
+	if err != nil {
+		r.errorF(err)
+	}
+}
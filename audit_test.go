@@ -0,0 +1,145 @@
+package fault
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestWrite = errors.New("error from test writer")
+)
+
+// testErrWriter returns errTestWrite from every write.
+type testErrWriter struct{}
+
+// Write returns errTestWrite.
+func (w testErrWriter) Write(p []byte) (int, error) {
+	return 0, errTestWrite
+}
+
+// TestNewAuditReporter tests NewAuditReporter.
+func TestNewAuditReporter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveWriter  io.Writer
+		giveOptions []AuditReporterOption
+		wantHeader  string
+		wantErr     error
+	}{
+		{
+			name:        "defaults",
+			giveWriter:  &bytes.Buffer{},
+			giveOptions: nil,
+			wantHeader:  "X-Request-Id",
+			wantErr:     nil,
+		},
+		{
+			name:       "options",
+			giveWriter: &bytes.Buffer{},
+			giveOptions: []AuditReporterOption{
+				WithRequestIDHeader("X-Trace-Id"),
+				WithAuditErrorFunc(func(err error) {}),
+			},
+			wantHeader: "X-Trace-Id",
+			wantErr:    nil,
+		},
+		{
+			name:        "nil writer",
+			giveWriter:  nil,
+			giveOptions: nil,
+			wantErr:     &OptionError{Option: "NewAuditReporter", Value: nil, Err: ErrNilWriter},
+		},
+		{
+			name:        "nil error function",
+			giveWriter:  &bytes.Buffer{},
+			giveOptions: []AuditReporterOption{WithAuditErrorFunc(nil)},
+			wantErr:     &OptionError{Option: "WithAuditErrorFunc", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:        "option error",
+			giveWriter:  &bytes.Buffer{},
+			giveOptions: []AuditReporterOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ar, err := NewAuditReporter(tt.giveWriter, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantHeader, ar.header)
+			} else {
+				assert.Nil(t, ar)
+			}
+		})
+	}
+}
+
+// TestAuditReporter tests AuditReporter.ReportEvent with a Fault.
+func TestAuditReporter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ar, err := NewAuditReporter(&buf)
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjector500s(),
+		WithEnabled(true),
+		WithParticipation(1.0),
+		WithEventReporter(ar),
+		WithNowFunc(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }),
+	)
+	assert.NoError(t, err)
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodPost, "/one", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/two", nil))
+
+	assert.Equal(t, `{"time":"2024-01-02T03:04:05Z","fault":"testInjector500s","injector":"testInjector500s",`+
+		`"method":"POST","path":"/one","requestId":"abc"}`+"\n"+
+		`{"time":"2024-01-02T03:04:05Z","fault":"testInjector500s","injector":"testInjector500s",`+
+		`"method":"GET","path":"/two"}`+"\n", buf.String())
+}
+
+// TestAuditReporterError tests that AuditReporter.ReportEvent passes write errors to its error
+// function.
+func TestAuditReporterError(t *testing.T) {
+	t.Parallel()
+
+	var got error
+	ar, err := NewAuditReporter(testErrWriter{}, WithAuditErrorFunc(func(err error) { got = err }))
+	assert.NoError(t, err)
+
+	ar.ReportEvent(Event{Request: httptest.NewRequest(http.MethodGet, "/", nil)})
+
+	assert.Equal(t, errTestWrite, got)
+
+	// records that cannot be encoded are passed to the error function
+	got = nil
+	ar.ReportEvent(Event{
+		Time:    time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Request: httptest.NewRequest(http.MethodGet, "/", nil),
+	})
+	assert.Error(t, got)
+
+	// the default error function ignores errors
+	ar, err = NewAuditReporter(testErrWriter{})
+	assert.NoError(t, err)
+	ar.ReportEvent(Event{Request: httptest.NewRequest(http.MethodGet, "/", nil)})
+}
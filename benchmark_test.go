@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/lingrino/go-fault"
@@ -43,6 +44,60 @@ func runBenchmark(b *testing.B, f *fault.Fault) {
 	_ = rr
 }
 
+// composedHandler returns the handler that composed benchmarks send requests to, wrapped by f if f
+// is not nil.
+func composedHandler(f *fault.Fault) http.Handler {
+	var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "OK", http.StatusOK)
+	})
+
+	if f != nil {
+		h = f.Handler(h)
+	}
+
+	return h
+}
+
+// composedRequest simulates a request to h.
+func composedRequest(b *testing.B, h http.Handler) *httptest.ResponseRecorder {
+	b.Helper()
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/", nil)
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+
+	return rr
+}
+
+// runComposedBenchmark benchmarks the provided Fault with the handler composed once, as it is in a
+// server, so that only the per-request cost of the Fault is measured.
+func runComposedBenchmark(b *testing.B, f *fault.Fault) {
+	h := composedHandler(f)
+
+	var rr *httptest.ResponseRecorder
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		rr = composedRequest(b, h)
+	}
+
+	_ = rr
+}
+
+// runParallelBenchmark benchmarks the provided Fault with requests from many goroutines at once, as
+// they are in a busy server.
+func runParallelBenchmark(b *testing.B, f *fault.Fault) {
+	h := composedHandler(f)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			composedRequest(b, h)
+		}
+	})
+}
+
 // BenchmarkNoFault is our control using no Fault.
 func BenchmarkNoFault(b *testing.B) {
 	runBenchmark(b, nil)
@@ -79,3 +134,121 @@ func BenchmarkFaultError100Percent(b *testing.B) {
 
 	runBenchmark(b, f)
 }
+
+// BenchmarkNoFaultComposed is our control using no Fault for the composed benchmarks.
+func BenchmarkNoFaultComposed(b *testing.B) {
+	runComposedBenchmark(b, nil)
+}
+
+// BenchmarkFaultDisabledComposed benchmarks a disabled Fault with the handler composed once.
+func BenchmarkFaultDisabledComposed(b *testing.B) {
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(false),
+	)
+
+	runComposedBenchmark(b, f)
+}
+
+// BenchmarkFaultErrorZeroPercentComposed benchmarks an enabled Fault with 0% participation with the
+// handler composed once.
+func BenchmarkFaultErrorZeroPercentComposed(b *testing.B) {
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.0),
+	)
+
+	runComposedBenchmark(b, f)
+}
+
+// BenchmarkFaultErrorZeroPercentNoAnnotation benchmarks an enabled Fault with 0% participation and
+// no context annotation with the handler composed once.
+func BenchmarkFaultErrorZeroPercentNoAnnotation(b *testing.B) {
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.0),
+		fault.WithContextAnnotation(false),
+	)
+
+	runComposedBenchmark(b, f)
+}
+
+// BenchmarkFaultErrorHalfParallel benchmarks an enabled Fault with 50% participation under
+// concurrent requests.
+func BenchmarkFaultErrorHalfParallel(b *testing.B) {
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.5),
+	)
+
+	runParallelBenchmark(b, f)
+}
+
+// BenchmarkFaultErrorHalfParallelSplitRand benchmarks an enabled Fault with 50% participation and
+// WithSplitRand under concurrent requests.
+func BenchmarkFaultErrorHalfParallelSplitRand(b *testing.B) {
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.5),
+		fault.WithSplitRand(true),
+	)
+
+	runParallelBenchmark(b, f)
+}
+
+// benchmarkLists returns n paths and n header keys to values that do not match benchmark requests.
+func benchmarkLists(n int) ([]string, map[string]string) {
+	paths := make([]string, 0, n)
+	headers := make(map[string]string, n)
+	for i := range n {
+		paths = append(paths, "/path/"+strconv.Itoa(i))
+		headers["x-header-"+strconv.Itoa(i)] = "value"
+	}
+	return paths, headers
+}
+
+// BenchmarkFaultLargePathBlocklist benchmarks an enabled Fault with a blocklist of 100000 paths.
+func BenchmarkFaultLargePathBlocklist(b *testing.B) {
+	paths, _ := benchmarkLists(100000)
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.0),
+		fault.WithPathBlocklist(paths),
+	)
+
+	runComposedBenchmark(b, f)
+}
+
+// BenchmarkFaultManyHeaderRules benchmarks an enabled Fault with a blocklist of 100 headers, every
+// one of which is checked because none match.
+func BenchmarkFaultManyHeaderRules(b *testing.B) {
+	_, headers := benchmarkLists(100)
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.0),
+		fault.WithHeaderBlocklist(headers),
+	)
+
+	runComposedBenchmark(b, f)
+}
+
+// BenchmarkFaultLargePathBlocklistBloom benchmarks an enabled Fault with a blocklist of 100000 paths
+// checked with a bloom filter.
+func BenchmarkFaultLargePathBlocklistBloom(b *testing.B) {
+	paths, _ := benchmarkLists(100000)
+	i, _ := fault.NewErrorInjector(http.StatusInternalServerError)
+	f, _ := fault.NewFault(i,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.0),
+		fault.WithPathBlocklist(paths),
+		fault.WithBlocklistBloomFilter(true),
+	)
+
+	runComposedBenchmark(b, f)
+}
@@ -0,0 +1,91 @@
+package fault
+
+// The constants of bloomFilter.
+const (
+	// bloomBitsPerKey is the number of bits of a bloomFilter for each key, which gives a false
+	// positive rate of about 0.5% with bloomHashes bits set per key.
+	bloomBitsPerKey = 16
+	// bloomHashes is the number of bits set in a word of a bloomFilter for each key.
+	bloomHashes = 4
+	// bloomWordBits is the number of bits in a word of a bloomFilter.
+	bloomWordBits = 64
+	// bloomBitIndexBits is the number of bits of the hash of a key used to choose each bit in a word.
+	bloomBitIndexBits = 6
+	// bloomWordIndexBits is the number of low bits of the hash of a key used to choose its word.
+	bloomWordIndexBits = 32
+
+	// fnvOffset64 and fnvPrime64 are the parameters of the 64-bit FNV-1a hash.
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+type blocklistBloomFilterOption bool
+
+func (o blocklistBloomFilterOption) applyFault(f *Fault) error {
+	f.blocklistBloom = bool(o)
+	return nil
+}
+
+// WithBlocklistBloomFilter sets if the path, pattern, and URI blocklists are checked with a bloom
+// filter before their exact lookup. Default false. A bloom filter is a compact bit set that rules
+// out most values that are not in a list by reading a single word, so that the cost of checking a
+// request against a blocklist of 100,000 or more entries stays small and predictable. Values that
+// pass the bloom filter are still looked up exactly, so the result of the check does not change.
+func WithBlocklistBloomFilter(b bool) Option {
+	return blocklistBloomFilterOption(b)
+}
+
+// bloomFilter is a blocked bloom filter, which sets all the bits for a key in a single word so that
+// each lookup reads one word of memory.
+type bloomFilter struct {
+	words []uint64
+}
+
+// newBloomFilter returns a bloomFilter of the keys of m, or nil if m is empty.
+func newBloomFilter(m map[string]bool) *bloomFilter {
+	if len(m) == 0 {
+		return nil
+	}
+
+	b := &bloomFilter{words: make([]uint64, max(1, len(m)*bloomBitsPerKey/bloomWordBits))}
+	for key := range m {
+		word, mask := b.locate(key)
+		b.words[word] |= mask
+	}
+	return b
+}
+
+// mayContain returns false if s is not a key of the bloomFilter, and true if it may be. A nil
+// bloomFilter may contain every key.
+func (b *bloomFilter) mayContain(s string) bool {
+	if b == nil {
+		return true
+	}
+
+	word, mask := b.locate(s)
+	return b.words[word]&mask == mask
+}
+
+// locate returns the index of the word of the bloomFilter for s and the bits of s in that word.
+func (b *bloomFilter) locate(s string) (int, uint64) {
+	h := hashString(s)
+	word := int((h & (1<<bloomWordIndexBits - 1)) % uint64(len(b.words)))
+
+	var mask uint64
+	h >>= bloomWordIndexBits
+	for range bloomHashes {
+		mask |= 1 << (h % bloomWordBits)
+		h >>= bloomBitIndexBits
+	}
+	return word, mask
+}
+
+// fnv64a returns the 64-bit FNV-1a hash of s without allocating.
+func fnv64a(s string) uint64 {
+	h := uint64(fnvOffset64)
+	for i := range len(s) {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
+}
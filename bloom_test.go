@@ -0,0 +1,105 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBloomFilter tests that a bloomFilter contains all of its keys and rules out most other keys.
+func TestBloomFilter(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, newBloomFilter(nil))
+	assert.True(t, (*bloomFilter)(nil).mayContain("/anything"))
+
+	keys := make(map[string]bool, 10000)
+	for i := range 10000 {
+		keys["/blocked/"+strconv.Itoa(i)] = true
+	}
+	b := newBloomFilter(keys)
+
+	for key := range keys {
+		assert.True(t, b.mayContain(key), key)
+	}
+
+	var falsePositives int
+	for i := range 10000 {
+		if b.mayContain("/allowed/" + strconv.Itoa(i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 200)
+
+	// a single key still fills a whole word
+	b = newBloomFilter(map[string]bool{"/": true})
+	assert.Len(t, b.words, 1)
+	assert.True(t, b.mayContain("/"))
+}
+
+// TestWithBlocklistBloomFilter tests that WithBlocklistBloomFilter does not change which requests
+// the blocklists block.
+func TestWithBlocklistBloomFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveOptions  []Option
+		giveURI      string
+		wantInjected bool
+	}{
+		{
+			name:         "path blocked",
+			giveOptions:  []Option{WithPathBlocklist([]string{"/a", "/b"})},
+			giveURI:      "/b",
+			wantInjected: false,
+		},
+		{
+			name:         "path not blocked",
+			giveOptions:  []Option{WithPathBlocklist([]string{"/a", "/b"})},
+			giveURI:      "/c",
+			wantInjected: true,
+		},
+		{
+			name: "pattern blocked",
+			giveOptions: []Option{
+				WithPatternBlocklist([]string{"/a"}),
+				WithPatternFunc(func(r *http.Request) string { return r.URL.Path }),
+			},
+			giveURI:      "/a",
+			wantInjected: false,
+		},
+		{
+			name:         "uri blocked",
+			giveOptions:  []Option{WithURIBlocklist([]string{"/a?q=1"})},
+			giveURI:      "/a?q=1",
+			wantInjected: false,
+		},
+		{
+			name:         "uri not blocked",
+			giveOptions:  []Option{WithURIBlocklist([]string{"/a?q=1"})},
+			giveURI:      "/a?q=2",
+			wantInjected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithParticipation(1.0), WithBlocklistBloomFilter(true)},
+				tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr,
+				httptest.NewRequest(http.MethodGet, tt.giveURI, nil))
+
+			assert.Equal(t, tt.wantInjected, rr.Code == http.StatusInternalServerError)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package fault
+
+type brownoutOption struct {
+	low  int
+	high int
+}
+
+func (o brownoutOption) applyFault(f *Fault) error {
+	if o.low < 0 || o.high <= o.low {
+		return &OptionError{Option: "WithBrownout", Value: []int{o.low, o.high}, Err: ErrInvalidCount}
+	}
+	f.brownoutLow = int64(o.low)
+	f.brownoutHigh = int64(o.high)
+	return nil
+}
+
+// WithBrownout scales participation with the number of requests in flight through the Fault. With
+// low or fewer requests in flight the Fault does not inject, and the participation rises linearly
+// to the full participation from WithParticipation or WithParticipationFunc at high requests in
+// flight. More traffic means more injection, which rehearses brownout and load shedding strategies.
+// low must not be negative and high must be greater than low. Deterministic modes such as
+// WithEveryNth and FaultGroups take priority over WithBrownout.
+func WithBrownout(low, high int) Option {
+	return brownoutOption{low: low, high: high}
+}
+
+// brownoutFactor returns how much of the participation applies for the current number of requests
+// in flight, between 0.0 and 1.0. It returns 1.0 if WithBrownout is not set.
+func (f *Fault) brownoutFactor() float32 {
+	if f.brownoutHigh == 0 {
+		return 1.0
+	}
+
+	n := f.inFlight.Load()
+	switch {
+	case n <= f.brownoutLow:
+		return 0.0
+	case n >= f.brownoutHigh:
+		return 1.0
+	}
+
+	return float32(n-f.brownoutLow) / float32(f.brownoutHigh-f.brownoutLow)
+}
+
+// InFlight returns the number of requests in flight through the Fault. Requests are only counted
+// while WithBrownout is set.
+func (f *Fault) InFlight() int {
+	return int(f.inFlight.Load())
+}
@@ -0,0 +1,196 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNilBucketing when a nil Bucketing is provided.
+	ErrNilBucketing = errors.New("bucketing cannot be nil")
+	// ErrInvalidArms when arms are empty, have duplicate or empty names, or have a weight less than 1.
+	ErrInvalidArms = errors.New("arms must have unique names and weights greater than 0")
+	// ErrUnknownArm when an arm is not one of the arms of a Bucketing.
+	ErrUnknownArm = errors.New("arm not found")
+)
+
+// The constants of the MurmurHash3 finalizer, see mix64.
+const (
+	mixShift       = 33
+	mixMultiplier1 = 0xff51afd7ed558ccd
+	mixMultiplier2 = 0xc4ceb9fe1a85ec53
+)
+
+// Arm is one arm of an experiment, such as "control" or "treatment".
+type Arm struct {
+	// Name identifies the arm.
+	Name string
+	// Weight is the share of identifiers assigned to the arm, relative to the weights of the other
+	// arms.
+	Weight int
+}
+
+// Bucketing consistently assigns requests to the arms of an experiment by hashing an identifier of
+// each request, such as a user ID, so that the same identifier is always in the same arm. Add
+// Faults to one arm with WithArm to inject only into the requests in that arm and compare it with
+// the others.
+type Bucketing struct {
+	name     string
+	keyF     func(r *http.Request) string
+	arms     []Arm
+	total    uint64
+	reporter Reporter
+}
+
+// BucketingOption configures a Bucketing.
+type BucketingOption interface {
+	applyBucketing(b *Bucketing) error
+}
+
+func (o nameOption) applyBucketing(b *Bucketing) error {
+	b.name = string(o)
+	return nil
+}
+
+func (o reporterOption) applyBucketing(b *Bucketing) error {
+	b.reporter = o.reporter
+	return nil
+}
+
+// NewBucketing returns a Bucketing that assigns requests to arms by the identifier that keyF
+// returns, in proportion to the weights of the arms. Requests for which keyF returns "" are not
+// assigned to any arm. The name of the Bucketing, set with WithName, is hashed with the identifier
+// so that Bucketings with different names assign identifiers independently.
+func NewBucketing(keyF func(r *http.Request) string, arms []Arm, opts ...BucketingOption) (*Bucketing, error) {
+	if keyF == nil {
+		return nil, &OptionError{Option: "NewBucketing", Value: nil, Err: ErrNilFunc}
+	}
+
+	if len(arms) == 0 {
+		return nil, &OptionError{Option: "NewBucketing", Value: arms, Err: ErrInvalidArms}
+	}
+
+	names := make(map[string]bool, len(arms))
+	var total uint64
+	for _, arm := range arms {
+		if arm.Name == "" || arm.Weight < 1 || names[arm.Name] {
+			return nil, &OptionError{Option: "NewBucketing", Value: arms, Err: ErrInvalidArms}
+		}
+		names[arm.Name] = true
+		total += uint64(arm.Weight)
+	}
+
+	// set defaults
+	b := &Bucketing{
+		name:     "Bucketing",
+		keyF:     keyF,
+		arms:     append([]Arm(nil), arms...),
+		total:    total,
+		reporter: NewNoopReporter(),
+	}
+
+	// apply options
+	err := applyOptions(opts, BucketingOption.applyBucketing, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// bucketingContextKey is the request context key for the arm a Bucketing assigned to a request.
+type bucketingContextKey struct {
+	bucketing *Bucketing
+}
+
+// Arm returns the name of the arm that r is assigned to, or "" if keyF returns "" for r.
+func (b *Bucketing) Arm(r *http.Request) string {
+	if arm, ok := r.Context().Value(bucketingContextKey{b}).(string); ok {
+		return arm
+	}
+
+	key := b.keyF(r)
+	if key == "" {
+		return ""
+	}
+
+	n := hashString(b.name+"\x00"+key) % b.total
+
+	// n is less than the sum of the weights, so it is in the last arm if it is in no other
+	last := len(b.arms) - 1
+	for _, arm := range b.arms[:last] {
+		if n < uint64(arm.Weight) {
+			return arm.Name
+		}
+		n -= uint64(arm.Weight)
+	}
+	return b.arms[last].Name
+}
+
+// mix64 is the finalizer of MurmurHash3, which spreads every bit of h over the result so that
+// similar identifiers, such as sequential IDs, are assigned to arms independently.
+func mix64(h uint64) uint64 {
+	h ^= h >> mixShift
+	h *= mixMultiplier1
+	h ^= h >> mixShift
+	h *= mixMultiplier2
+	h ^= h >> mixShift
+	return h
+}
+
+// Handler tags every request with its arm, so that Arm does not hash the identifier again for the
+// Faults in the arm, and reports each request to the Reporter of the Bucketing as the name of the
+// Bucketing and the arm, such as "checkout-latency/control", so that every arm shows up in metrics
+// and logs. Requests without an arm are not reported. Place it before the Faults in the arms.
+func (b *Bucketing) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arm := b.Arm(r)
+		if arm == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		name := b.name + "/" + arm
+		go report(b.reporter, name, StateStarted)
+		defer func() { go report(b.reporter, name, StateFinished) }()
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bucketingContextKey{b}, arm)))
+	})
+}
+
+// String describes the Bucketing, such as "Bucketing(control=50, treatment=50)".
+func (b *Bucketing) String() string {
+	arms := make([]string, 0, len(b.arms))
+	for _, arm := range b.arms {
+		arms = append(arms, arm.Name+"="+strconv.Itoa(arm.Weight))
+	}
+	return b.name + "(" + strings.Join(arms, ", ") + ")"
+}
+
+type armOption struct {
+	bucketing *Bucketing
+	arm       string
+}
+
+func (o armOption) applyFault(f *Fault) error {
+	if o.bucketing == nil {
+		return &OptionError{Option: "WithArm", Value: nil, Err: ErrNilBucketing}
+	}
+	for _, arm := range o.bucketing.arms {
+		if arm.Name == o.arm {
+			f.bucketing, f.arm = o.bucketing, o.arm
+			return nil
+		}
+	}
+	return &OptionError{Option: "WithArm", Value: o.arm, Err: ErrUnknownArm}
+}
+
+// WithArm adds the Fault to an arm of a Bucketing, such as "treatment". The Fault participates in
+// every request assigned to the arm and in no others, instead of using its own participation or
+// deterministic mode. Every other check, such as allowlists and Guards, still applies.
+func WithArm(b *Bucketing, arm string) Option {
+	return armOption{bucketing: b, arm: arm}
+}
@@ -0,0 +1,195 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testBucketingKey returns the X-User-Id header of r.
+func testBucketingKey(r *http.Request) string {
+	return r.Header.Get("X-User-Id")
+}
+
+// testBucketingRequest returns a request from user.
+func testBucketingRequest(user string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User-Id", user)
+	return r
+}
+
+// TestNewBucketing tests NewBucketing.
+func TestNewBucketing(t *testing.T) {
+	t.Parallel()
+
+	arms := []Arm{{Name: "control", Weight: 1}, {Name: "treatment", Weight: 1}}
+
+	tests := []struct {
+		name        string
+		giveKeyF    func(r *http.Request) string
+		giveArms    []Arm
+		giveOptions []BucketingOption
+		wantErr     error
+	}{
+		{
+			name:     "valid",
+			giveKeyF: testBucketingKey,
+			giveArms: arms,
+			wantErr:  nil,
+		},
+		{
+			name:        "all options",
+			giveKeyF:    testBucketingKey,
+			giveArms:    arms,
+			giveOptions: []BucketingOption{WithName("checkout"), WithReporter(newTestReporter())},
+			wantErr:     nil,
+		},
+		{
+			name:     "nil key function",
+			giveKeyF: nil,
+			giveArms: arms,
+			wantErr:  &OptionError{Option: "NewBucketing", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:     "no arms",
+			giveKeyF: testBucketingKey,
+			giveArms: nil,
+			wantErr:  &OptionError{Option: "NewBucketing", Value: []Arm(nil), Err: ErrInvalidArms},
+		},
+		{
+			name:     "empty name",
+			giveKeyF: testBucketingKey,
+			giveArms: []Arm{{Name: "", Weight: 1}},
+			wantErr:  &OptionError{Option: "NewBucketing", Value: []Arm{{Name: "", Weight: 1}}, Err: ErrInvalidArms},
+		},
+		{
+			name:     "zero weight",
+			giveKeyF: testBucketingKey,
+			giveArms: []Arm{{Name: "a", Weight: 0}},
+			wantErr:  &OptionError{Option: "NewBucketing", Value: []Arm{{Name: "a", Weight: 0}}, Err: ErrInvalidArms},
+		},
+		{
+			name:     "duplicate name",
+			giveKeyF: testBucketingKey,
+			giveArms: []Arm{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}},
+			wantErr: &OptionError{
+				Option: "NewBucketing", Value: []Arm{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}, Err: ErrInvalidArms,
+			},
+		},
+		{
+			name:        "option error",
+			giveKeyF:    testBucketingKey,
+			giveArms:    arms,
+			giveOptions: []BucketingOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := NewBucketing(tt.giveKeyF, tt.giveArms, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil, b != nil)
+		})
+	}
+}
+
+// TestBucketingArm tests that Bucketing.Arm assigns identifiers consistently and in proportion to
+// the weights of the arms.
+func TestBucketingArm(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBucketing(testBucketingKey, []Arm{{Name: "control", Weight: 3}, {Name: "treatment", Weight: 1}})
+	assert.NoError(t, err)
+	other, err := NewBucketing(testBucketingKey, []Arm{{Name: "control", Weight: 3}, {Name: "treatment", Weight: 1}},
+		WithName("other"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", b.Arm(httptest.NewRequest(http.MethodGet, "/", nil)))
+
+	counts := map[string]int{}
+	var differ bool
+	for i := range 4000 {
+		user := "user-" + strconv.Itoa(i)
+		arm := b.Arm(testBucketingRequest(user))
+		assert.Equal(t, arm, b.Arm(testBucketingRequest(user)))
+		counts[arm]++
+		differ = differ || arm != other.Arm(testBucketingRequest(user))
+	}
+
+	assert.InDelta(t, 3000, counts["control"], 200)
+	assert.InDelta(t, 1000, counts["treatment"], 200)
+	assert.True(t, differ, "differently named Bucketings assign independently")
+}
+
+// TestBucketingHandler tests that Bucketing.Handler tags requests with their arm and reports them.
+func TestBucketingHandler(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 2)}
+	var hashed int
+	b, err := NewBucketing(func(r *http.Request) string {
+		hashed++
+		return testBucketingKey(r)
+	}, []Arm{{Name: "treatment", Weight: 1}}, WithName("checkout"), WithReporter(rep))
+	assert.NoError(t, err)
+
+	var got []string
+	h := b.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, b.Arm(r))
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), testBucketingRequest("user-1"))
+	assert.Equal(t, []string{"treatment"}, got)
+	assert.Equal(t, 1, hashed)
+	assert.ElementsMatch(t, []string{"checkout/treatment started", "checkout/treatment finished"},
+		[]string{<-rep.states, <-rep.states})
+
+	// requests without an identifier have no arm and are not reported
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"treatment", ""}, got)
+	assert.Empty(t, rep.states)
+}
+
+// TestBucketingString tests Bucketing.String.
+func TestBucketingString(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBucketing(testBucketingKey, []Arm{{Name: "control", Weight: 50}, {Name: "treatment", Weight: 50}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bucketing(control=50, treatment=50)", b.String())
+}
+
+// TestWithArm tests that a Fault with WithArm injects into every request in its arm and no others.
+func TestWithArm(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBucketing(testBucketingKey, []Arm{{Name: "control", Weight: 1}, {Name: "treatment", Weight: 1}},
+		WithName("checkout"))
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjector500s(), WithEnabled(true), WithArm(b, "treatment"))
+	assert.NoError(t, err)
+	assert.Contains(t, f.String(), " @ treatment arm of checkout")
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for i := range 100 {
+		r := testBucketingRequest("user-" + strconv.Itoa(i))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+
+		assert.Equal(t, b.Arm(r) == "treatment", rr.Code == http.StatusInternalServerError)
+	}
+
+	_, err = NewFault(newTestInjectorNoop(), WithArm(nil, "treatment"))
+	assert.Equal(t, &OptionError{Option: "WithArm", Value: nil, Err: ErrNilBucketing}, err)
+
+	_, err = NewFault(newTestInjectorNoop(), WithArm(b, "missing"))
+	assert.Equal(t, &OptionError{Option: "WithArm", Value: "missing", Err: ErrUnknownArm}, err)
+}
@@ -0,0 +1,218 @@
+package fault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnsupported when an imported specification uses a feature that has no equivalent in the
+	// package.
+	ErrUnsupported = errors.New("not supported")
+)
+
+// httpChaos is the part of a Chaos Mesh HTTPChaos object that describes the injected faults.
+type httpChaos struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec httpChaosSpec `json:"spec"`
+}
+
+// httpChaosSpec is the spec of a Chaos Mesh HTTPChaos object. Fields that select pods or schedule
+// the experiment, such as selector, mode, port, and duration, are not included because the Faults
+// only run in the application that loads them.
+type httpChaosSpec struct {
+	Target          string            `json:"target"`
+	Path            string            `json:"path"`
+	Method          string            `json:"method"`
+	Code            *int              `json:"code"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+
+	Abort   bool              `json:"abort"`
+	Delay   string            `json:"delay"`
+	Replace *httpChaosReplace `json:"replace"`
+	Patch   *httpChaosPatch   `json:"patch"`
+}
+
+// httpChaosReplace is the replace action of an HTTPChaos spec.
+type httpChaosReplace struct {
+	Headers map[string]string `json:"headers"`
+	Body    []byte            `json:"body"`
+	Path    *string           `json:"path"`
+	Method  *string           `json:"method"`
+	Queries map[string]string `json:"queries"`
+	Code    *int              `json:"code"`
+}
+
+// httpChaosPatch is the patch action of an HTTPChaos spec.
+type httpChaosPatch struct {
+	Headers [][]string      `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+	Queries [][]string      `json:"queries"`
+}
+
+const (
+	httpChaosKind           = "HTTPChaos"
+	httpChaosTargetRequest  = "Request"
+	httpChaosTargetResponse = "Response"
+	// httpChaosHeaderLen is the length of a patched header, which is a key and a value.
+	httpChaosHeaderLen = 2
+)
+
+// ParseHTTPChaos returns a Config for each action in a Chaos Mesh HTTPChaos object encoded as JSON,
+// such as the output of kubectl get httpchaos NAME -o json. The Configs are returned in the order
+// that the actions should run, so create a Fault from each and combine them with Chain, or Load
+// them into a Registry. Each Config is enabled with a participation of 1.0 and is named after the
+// HTTPChaos object and the action, such as "api-chaos-delay".
+//
+// The delay action becomes a SlowInjector, the abort action becomes a RejectInjector, replacing
+// the response code becomes an ErrorInjector with the replaced body as its status text, and
+// replacing or patching request headers becomes a RequestHeaderInjector. Patched headers are set,
+// replacing any existing values. An exact path becomes a path allowlist and request_headers become
+// a header allowlist. Any other action or filter returns an error that wraps ErrUnsupported instead
+// of silently injecting into more requests than the HTTPChaos would.
+func ParseHTTPChaos(data []byte) ([]Config, error) {
+	var hc httpChaos
+	err := json.Unmarshal(data, &hc)
+	if err != nil {
+		return nil, err
+	}
+	if hc.Kind != "" && hc.Kind != httpChaosKind {
+		return nil, fmt.Errorf("kind %s: %w", hc.Kind, ErrUnsupported)
+	}
+
+	base, err := hc.Spec.config()
+	if err != nil {
+		return nil, err
+	}
+
+	actions, injectors, err := hc.Spec.injectors()
+	if err != nil {
+		return nil, err
+	}
+
+	cfgs := make([]Config, 0, len(injectors))
+	for idx := range injectors {
+		cfg := base
+		cfg.Injector = &injectors[idx]
+		if hc.Metadata.Name != "" {
+			cfg.Name = hc.Metadata.Name + "-" + actions[idx]
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	return cfgs, nil
+}
+
+// config returns a Config with the request filters of the spec and no Injector.
+func (s httpChaosSpec) config() (Config, error) {
+	cfg := Config{
+		Enabled:         true,
+		Participation:   1.0,
+		HeaderAllowlist: s.RequestHeaders,
+	}
+
+	switch {
+	case s.Target != httpChaosTargetRequest && s.Target != httpChaosTargetResponse:
+		return Config{}, fmt.Errorf("target %q: %w", s.Target, ErrUnsupported)
+	case s.Method != "":
+		return Config{}, fmt.Errorf("method: %w", ErrUnsupported)
+	case s.Code != nil:
+		return Config{}, fmt.Errorf("code: %w", ErrUnsupported)
+	case len(s.ResponseHeaders) > 0:
+		return Config{}, fmt.Errorf("response_headers: %w", ErrUnsupported)
+	case s.Path != "*" && strings.Contains(s.Path, "*"):
+		return Config{}, fmt.Errorf("path %s: %w", s.Path, ErrUnsupported)
+	}
+
+	if s.Path != "" && s.Path != "*" {
+		cfg.PathAllowlist = []string{s.Path}
+	}
+
+	return cfg, nil
+}
+
+// injectors returns an InjectorConfig and the name of the action for each action in the spec.
+func (s httpChaosSpec) injectors() ([]string, []InjectorConfig, error) {
+	var actions []string
+	var injectors []InjectorConfig
+
+	if s.Delay != "" {
+		d, err := time.ParseDuration(s.Delay)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("delay %s: %w", s.Delay, ErrInvalidDuration)
+		}
+		actions = append(actions, "delay")
+		injectors = append(injectors, InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(d)})
+	}
+
+	if s.Replace != nil || s.Patch != nil {
+		i, err := s.modifyInjector()
+		if err != nil {
+			return nil, nil, err
+		}
+		actions = append(actions, "replace")
+		injectors = append(injectors, i)
+	}
+
+	// abort ends the request, so it runs after every other action
+	if s.Abort {
+		actions = append(actions, "abort")
+		injectors = append(injectors, InjectorConfig{Type: InjectorTypeReject})
+	}
+
+	if len(injectors) == 0 {
+		return nil, nil, fmt.Errorf("action: %w", ErrNilInjector)
+	}
+
+	return actions, injectors, nil
+}
+
+// modifyInjector returns the InjectorConfig for the replace and patch actions of the spec.
+func (s httpChaosSpec) modifyInjector() (InjectorConfig, error) {
+	r := s.Replace
+	if r == nil {
+		r = &httpChaosReplace{}
+	}
+	p := s.Patch
+	if p == nil {
+		p = &httpChaosPatch{}
+	}
+
+	switch {
+	case r.Path != nil, r.Method != nil, len(r.Queries) > 0, len(p.Queries) > 0:
+		return InjectorConfig{}, fmt.Errorf("replace or patch of the path, method, or queries: %w", ErrUnsupported)
+	case len(p.Body) > 0:
+		return InjectorConfig{}, fmt.Errorf("patch.body: %w", ErrUnsupported)
+	}
+
+	if s.Target == httpChaosTargetResponse {
+		if r.Code == nil || len(r.Headers) > 0 || len(p.Headers) > 0 {
+			return InjectorConfig{}, fmt.Errorf("response changes other than replace.code: %w", ErrUnsupported)
+		}
+		return InjectorConfig{Type: InjectorTypeError, StatusCode: *r.Code, StatusText: string(r.Body)}, nil
+	}
+
+	if r.Code != nil || len(r.Body) > 0 {
+		return InjectorConfig{}, fmt.Errorf("replace of the request code or body: %w", ErrUnsupported)
+	}
+
+	set := make(map[string]string, len(r.Headers)+len(p.Headers))
+	for key, val := range r.Headers {
+		set[key] = val
+	}
+	for _, h := range p.Headers {
+		if len(h) != httpChaosHeaderLen {
+			return InjectorConfig{}, fmt.Errorf("patch.headers %v: %w", h, ErrUnsupported)
+		}
+		set[h[0]] = h[1]
+	}
+
+	return InjectorConfig{Type: InjectorTypeRequestHeader, SetHeaders: set}, nil
+}
@@ -0,0 +1,173 @@
+package fault
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseHTTPChaos tests ParseHTTPChaos.
+func TestParseHTTPChaos(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		give     string
+		wantCfgs []Config
+		wantErr  error
+	}{
+		{
+			name: "delay and abort",
+			give: `{"apiVersion":"chaos-mesh.org/v1alpha1","kind":"HTTPChaos","metadata":{"name":"api-chaos"},
+				"spec":{"mode":"all","selector":{},"target":"Request","port":80,"path":"/api",
+				"request_headers":{"X-Chaos":"on"},"delay":"10s","abort":true}}`,
+			wantCfgs: []Config{
+				{
+					Name:            "api-chaos-delay",
+					Enabled:         true,
+					Participation:   1.0,
+					PathAllowlist:   []string{"/api"},
+					HeaderAllowlist: map[string]string{"X-Chaos": "on"},
+					Injector:        &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(10 * time.Second)},
+				},
+				{
+					Name:            "api-chaos-abort",
+					Enabled:         true,
+					Participation:   1.0,
+					PathAllowlist:   []string{"/api"},
+					HeaderAllowlist: map[string]string{"X-Chaos": "on"},
+					Injector:        &InjectorConfig{Type: InjectorTypeReject},
+				},
+			},
+		},
+		{
+			name: "replace response code",
+			give: `{"spec":{"target":"Response","path":"*","replace":{"code":503,"body":"b3ZlcmxvYWRlZA=="}}}`,
+			wantCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 1.0,
+					Injector: &InjectorConfig{
+						Type:       InjectorTypeError,
+						StatusCode: http.StatusServiceUnavailable,
+						StatusText: "overloaded",
+					},
+				},
+			},
+		},
+		{
+			name: "replace and patch request headers",
+			give: `{"metadata":{"name":"h"},"spec":{"target":"Request",
+				"replace":{"headers":{"Authorization":"invalid"}},"patch":{"headers":[["X-Test","1"]]}}}`,
+			wantCfgs: []Config{
+				{
+					Name:          "h-replace",
+					Enabled:       true,
+					Participation: 1.0,
+					Injector: &InjectorConfig{
+						Type:       InjectorTypeRequestHeader,
+						SetHeaders: map[string]string{"Authorization": "invalid", "X-Test": "1"},
+					},
+				},
+			},
+		},
+		{
+			name:    "other kind",
+			give:    `{"kind":"NetworkChaos","spec":{"target":"Request","abort":true}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "missing target",
+			give:    `{"spec":{"abort":true}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "method",
+			give:    `{"spec":{"target":"Request","method":"GET","abort":true}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "code",
+			give:    `{"spec":{"target":"Response","code":200,"abort":true}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "response headers",
+			give:    `{"spec":{"target":"Response","response_headers":{"A":"b"},"abort":true}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "wildcard path",
+			give:    `{"spec":{"target":"Request","path":"/api/*","abort":true}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "invalid delay",
+			give:    `{"spec":{"target":"Request","delay":"soon"}}`,
+			wantErr: ErrInvalidDuration,
+		},
+		{
+			name:    "no action",
+			give:    `{"spec":{"target":"Request"}}`,
+			wantErr: ErrNilInjector,
+		},
+		{
+			name:    "replace path",
+			give:    `{"spec":{"target":"Request","replace":{"path":"/other"}}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "patch body",
+			give:    `{"spec":{"target":"Request","patch":{"body":{"type":"JSON","value":"{}"}}}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "response headers action",
+			give:    `{"spec":{"target":"Response","replace":{"headers":{"A":"b"}}}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "request body",
+			give:    `{"spec":{"target":"Request","replace":{"body":"eA=="}}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "invalid patch header",
+			give:    `{"spec":{"target":"Request","patch":{"headers":[["X-Test"]]}}}`,
+			wantErr: ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfgs, err := ParseHTTPChaos([]byte(tt.give))
+			if tt.wantErr != nil {
+				assert.Nil(t, cfgs)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCfgs, cfgs)
+			for _, cfg := range cfgs {
+				assert.Empty(t, Validate(cfg))
+			}
+		})
+	}
+}
+
+// TestParseHTTPChaosInvalidJSON tests that ParseHTTPChaos returns JSON errors.
+func TestParseHTTPChaosInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	cfgs, err := ParseHTTPChaos([]byte(`{`))
+
+	var syntaxErr *json.SyntaxError
+	assert.Nil(t, cfgs)
+	assert.ErrorAs(t, err, &syntaxErr)
+}
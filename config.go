@@ -0,0 +1,236 @@
+package fault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidInjectorType when an InjectorConfig has an unknown type.
+	ErrInvalidInjectorType = errors.New("not a valid injector type")
+)
+
+// InjectorType is the type of Injector described by an InjectorConfig.
+type InjectorType string
+
+const (
+	// InjectorTypeError describes an ErrorInjector.
+	InjectorTypeError InjectorType = "error"
+	// InjectorTypeReject describes a RejectInjector.
+	InjectorTypeReject InjectorType = "reject"
+	// InjectorTypeSlow describes a SlowInjector.
+	InjectorTypeSlow InjectorType = "slow"
+	// InjectorTypeIdle describes an IdleInjector.
+	InjectorTypeIdle InjectorType = "idle"
+	// InjectorTypeRequestHeader describes a RequestHeaderInjector.
+	InjectorTypeRequestHeader InjectorType = "requestHeader"
+	// InjectorTypeBandwidth describes a BandwidthInjector.
+	InjectorTypeBandwidth InjectorType = "bandwidth"
+	// InjectorTypeSlicer describes a SlicerInjector.
+	InjectorTypeSlicer InjectorType = "slicer"
+)
+
+// Config is a declarative configuration for a Fault, such as one loaded from a file. Use Validate
+// to check a Config and NewFaultFromConfig to create a Fault from one.
+type Config struct {
+	// Name is passed to WithName if not empty.
+	Name string `json:"name,omitempty"`
+	// Enabled is passed to WithEnabled.
+	Enabled bool `json:"enabled"`
+	// Participation is passed to WithParticipation.
+	Participation float32 `json:"participation"`
+
+	// PathBlocklist is passed to WithPathBlocklist.
+	PathBlocklist []string `json:"pathBlocklist,omitempty"`
+	// PathAllowlist is passed to WithPathAllowlist.
+	PathAllowlist []string `json:"pathAllowlist,omitempty"`
+	// PatternBlocklist is passed to WithPatternBlocklist.
+	PatternBlocklist []string `json:"patternBlocklist,omitempty"`
+	// PatternAllowlist is passed to WithPatternAllowlist.
+	PatternAllowlist []string `json:"patternAllowlist,omitempty"`
+	// URIBlocklist is passed to WithURIBlocklist.
+	URIBlocklist []string `json:"uriBlocklist,omitempty"`
+	// URIAllowlist is passed to WithURIAllowlist.
+	URIAllowlist []string `json:"uriAllowlist,omitempty"`
+	// HeaderBlocklist is passed to WithHeaderBlocklist.
+	HeaderBlocklist map[string]string `json:"headerBlocklist,omitempty"`
+	// HeaderAllowlist is passed to WithHeaderAllowlist.
+	HeaderAllowlist map[string]string `json:"headerAllowlist,omitempty"`
+
+	// Injector describes the Injector that the Fault runs. It is required.
+	Injector *InjectorConfig `json:"injector"`
+}
+
+// InjectorConfig is a declarative configuration for one of the package Injectors.
+type InjectorConfig struct {
+	// Type is the type of Injector.
+	Type InjectorType `json:"type"`
+	// StatusCode is the status code returned by an ErrorInjector.
+	StatusCode int `json:"statusCode,omitempty"`
+	// StatusText is passed to WithStatusText for an ErrorInjector if not empty.
+	StatusText string `json:"statusText,omitempty"`
+	// Duration is how long a SlowInjector waits, the timeout of an IdleInjector, the delay before a
+	// RejectInjector rejects, or the delay after each slice of a SlicerInjector.
+	Duration Duration `json:"duration,omitempty"`
+	// Rate is the bytes per second written by a BandwidthInjector.
+	Rate int64 `json:"rate,omitempty"`
+	// SliceSize is the average size in bytes of the slices written by a SlicerInjector.
+	SliceSize int `json:"sliceSize,omitempty"`
+	// SliceVariation is passed to WithSliceVariation for a SlicerInjector if not 0.
+	SliceVariation int `json:"sliceVariation,omitempty"`
+	// SetHeaders is passed to WithSetHeaders for a RequestHeaderInjector.
+	SetHeaders map[string]string `json:"setHeaders,omitempty"`
+	// RemoveHeaders is passed to WithRemoveHeaders for a RequestHeaderInjector.
+	RemoveHeaders []string `json:"removeHeaders,omitempty"`
+}
+
+// Duration is a time.Duration that is encoded in JSON as a string such as "1.5s", and decoded from
+// either a string parsed by time.ParseDuration or an integer number of nanoseconds.
+type Duration time.Duration
+
+// MarshalJSON encodes d as a string such as "1.5s".
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes d from a string such as "1.5s" or an integer number of nanoseconds.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("duration %s: %w", s, ErrInvalidDuration)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var ns int64
+	err := json.Unmarshal(b, &ns)
+	if err != nil {
+		return fmt.Errorf("duration %s: %w", b, ErrInvalidDuration)
+	}
+	*d = Duration(ns)
+	return nil
+}
+
+// Validate checks cfg and returns every problem found, or nil if cfg is valid. Each error wraps
+// the same error that NewFault or NewInjector would return, such as ErrInvalidPercent, or, for a
+// value that Config rejects but the constructor accepts, such as a SlowInjector without a
+// Duration, an *OptionError naming the InjectorConfig field. Use Validate to check configuration
+// changes, for example in CI, before they are deployed.
+func Validate(cfg Config) []error {
+	_, errs := validate(cfg)
+	return errs
+}
+
+// NewFaultFromConfig validates cfg and returns a Fault built from it. If cfg is not valid the
+// returned error joins every error from Validate.
+func NewFaultFromConfig(cfg Config) (*Fault, error) {
+	f, errs := validate(cfg)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return f, nil
+}
+
+// validate builds the Fault described by cfg and returns it along with every problem found. The
+// Fault options are checked by NewFault itself, so Validate and NewFaultFromConfig always agree.
+func validate(cfg Config) (*Fault, []error) {
+	var injectorErr error
+	var i Injector
+	if cfg.Injector == nil {
+		injectorErr = fmt.Errorf("injector: %w", ErrNilInjector)
+	} else {
+		var err error
+		i, err = newInjectorFromConfig(*cfg.Injector)
+		if err != nil {
+			injectorErr = fmt.Errorf("injector %s: %w", cfg.Injector.Type, err)
+		}
+	}
+
+	// check the Fault options even if the Injector is not valid, with a SlowInjector that does not
+	// wait standing in for it
+	fi := i
+	if injectorErr != nil {
+		fi, _ = NewSlowInjector(0)
+	}
+
+	f, err := NewFault(fi, cfg.options()...)
+	errs := splitErrors(err)
+	if injectorErr != nil {
+		errs = append(errs, injectorErr)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return f, nil
+}
+
+// options returns the Fault options described by cfg.
+func (cfg Config) options() []Option {
+	opts := []Option{
+		WithEnabled(cfg.Enabled),
+		WithParticipation(cfg.Participation),
+		WithPathBlocklist(cfg.PathBlocklist),
+		WithPathAllowlist(cfg.PathAllowlist),
+		WithPatternBlocklist(cfg.PatternBlocklist),
+		WithPatternAllowlist(cfg.PatternAllowlist),
+		WithURIBlocklist(cfg.URIBlocklist),
+		WithURIAllowlist(cfg.URIAllowlist),
+		WithHeaderBlocklist(cfg.HeaderBlocklist),
+		WithHeaderAllowlist(cfg.HeaderAllowlist),
+	}
+	if cfg.Name != "" {
+		opts = append(opts, WithName(cfg.Name))
+	}
+	return opts
+}
+
+// newInjectorFromConfig returns the Injector described by cfg.
+func newInjectorFromConfig(cfg InjectorConfig) (Injector, error) {
+	switch cfg.Type {
+	case InjectorTypeError:
+		var opts []ErrorInjectorOption
+		if cfg.StatusText != "" {
+			opts = append(opts, WithStatusText(cfg.StatusText))
+		}
+		return NewErrorInjector(cfg.StatusCode, opts...)
+	case InjectorTypeReject:
+		if cfg.Duration > 0 {
+			return NewRejectInjector(WithRejectDelay(time.Duration(cfg.Duration)))
+		}
+		return NewRejectInjector()
+	case InjectorTypeSlow:
+		// NewSlowInjector accepts a SlowInjector that does not wait, but a Config must set one
+		if cfg.Duration <= 0 {
+			return nil, &OptionError{
+				Option: "InjectorConfig.Duration",
+				Value:  time.Duration(cfg.Duration),
+				Err:    ErrInvalidDuration,
+			}
+		}
+		return NewSlowInjector(time.Duration(cfg.Duration))
+	case InjectorTypeIdle:
+		return NewIdleInjector(time.Duration(cfg.Duration))
+	case InjectorTypeRequestHeader:
+		return NewRequestHeaderInjector(WithSetHeaders(cfg.SetHeaders), WithRemoveHeaders(cfg.RemoveHeaders))
+	case InjectorTypeBandwidth:
+		return NewBandwidthInjector(cfg.Rate)
+	case InjectorTypeSlicer:
+		var opts []SlicerInjectorOption
+		if cfg.SliceVariation != 0 {
+			opts = append(opts, WithSliceVariation(cfg.SliceVariation))
+		}
+		if cfg.Duration != 0 {
+			opts = append(opts, WithSliceDelay(time.Duration(cfg.Duration)))
+		}
+		return NewSlicerInjector(cfg.SliceSize, opts...)
+	default:
+		return nil, ErrInvalidInjectorType
+	}
+}
@@ -0,0 +1,397 @@
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidate tests Validate.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveCfg  Config
+		wantErrs []error
+	}{
+		{
+			name: "valid error",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: http.StatusBadGateway},
+			},
+			wantErrs: nil,
+		},
+		{
+			name: "valid reject",
+			giveCfg: Config{
+				Participation: 1.0,
+				Injector:      &InjectorConfig{Type: InjectorTypeReject},
+			},
+			wantErrs: nil,
+		},
+		{
+			name: "valid slow",
+			giveCfg: Config{
+				Participation: 0.0,
+				Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(time.Second)},
+			},
+			wantErrs: nil,
+		},
+		{
+			name: "nil injector",
+			giveCfg: Config{
+				Participation: 0.5,
+			},
+			wantErrs: []error{ErrNilInjector},
+		},
+		{
+			name: "invalid percent and nil injector",
+			giveCfg: Config{
+				Participation: 100.0,
+			},
+			wantErrs: []error{ErrInvalidPercent, ErrNilInjector},
+		},
+		{
+			name: "negative percent and invalid code",
+			giveCfg: Config{
+				Participation: -0.1,
+				Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: 0},
+			},
+			wantErrs: []error{ErrInvalidPercent, ErrInvalidHTTPCode},
+		},
+		{
+			name: "invalid duration",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(-time.Second)},
+			},
+			wantErrs: []error{ErrInvalidDuration},
+		},
+		{
+			name: "valid idle",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeIdle, Duration: Duration(time.Second)},
+			},
+		},
+		{
+			name: "invalid idle",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeIdle},
+			},
+			wantErrs: []error{ErrInvalidDuration},
+		},
+		{
+			name: "valid request header",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector: &InjectorConfig{
+					Type:          InjectorTypeRequestHeader,
+					SetHeaders:    map[string]string{"X-Test": "injected"},
+					RemoveHeaders: []string{"Authorization"},
+				},
+			},
+		},
+		{
+			name: "valid bandwidth",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeBandwidth, Rate: 1024},
+			},
+		},
+		{
+			name: "invalid bandwidth",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeBandwidth},
+			},
+			wantErrs: []error{ErrInvalidRate},
+		},
+		{
+			name: "valid slicer",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector: &InjectorConfig{
+					Type:           InjectorTypeSlicer,
+					SliceSize:      64,
+					SliceVariation: 32,
+					Duration:       Duration(time.Millisecond),
+				},
+			},
+		},
+		{
+			name: "invalid slicer",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: InjectorTypeSlicer, SliceSize: 8, SliceVariation: 8},
+			},
+			wantErrs: []error{ErrInvalidCount},
+		},
+		{
+			name: "invalid path blocklist",
+			giveCfg: Config{
+				Participation: 0.5,
+				PathBlocklist: []string{"/a%zz"},
+				Injector:      &InjectorConfig{Type: InjectorTypeReject},
+			},
+			wantErrs: []error{ErrInvalidListEntry},
+		},
+		{
+			name: "invalid percent, uri allowlist, and type",
+			giveCfg: Config{
+				Participation: 2.0,
+				URIAllowlist:  []string{"/b%zz"},
+				Injector:      &InjectorConfig{Type: "explode"},
+			},
+			wantErrs: []error{ErrInvalidPercent, ErrInvalidListEntry, ErrInvalidInjectorType},
+		},
+		{
+			name: "invalid type",
+			giveCfg: Config{
+				Participation: 0.5,
+				Injector:      &InjectorConfig{Type: "explode"},
+			},
+			wantErrs: []error{ErrInvalidInjectorType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			errs := Validate(tt.giveCfg)
+
+			assert.Len(t, errs, len(tt.wantErrs))
+			for idx, wantErr := range tt.wantErrs {
+				assert.ErrorIs(t, errs[idx], wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateSlowDuration tests that Validate names the InjectorConfig field of a SlowInjector
+// without a Duration, which NewSlowInjector accepts.
+func TestValidateSlowDuration(t *testing.T) {
+	t.Parallel()
+
+	errs := Validate(Config{Injector: &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(-time.Second)}})
+
+	assert.Equal(t, []error{fmt.Errorf("injector slow: %w", &OptionError{
+		Option: "InjectorConfig.Duration",
+		Value:  -time.Second,
+		Err:    ErrInvalidDuration,
+	})}, errs)
+	assert.EqualError(t, errs[0], "injector slow: InjectorConfig.Duration(-1s): duration must be greater than 0")
+}
+
+// TestNewFaultFromConfig tests NewFaultFromConfig.
+func TestNewFaultFromConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveCfg  Config
+		wantCode int
+		wantBody string
+		wantErrs []error
+	}{
+		{
+			name: "error",
+			giveCfg: Config{
+				Name:          "custom",
+				Enabled:       true,
+				Participation: 1.0,
+				PathBlocklist: []string{"/skip"},
+				Injector: &InjectorConfig{
+					Type:       InjectorTypeError,
+					StatusCode: http.StatusBadGateway,
+					StatusText: "injected",
+				},
+			},
+			wantCode: http.StatusBadGateway,
+			wantBody: "injected",
+		},
+		{
+			name: "blocklisted",
+			giveCfg: Config{
+				Enabled:       true,
+				Participation: 1.0,
+				PathBlocklist: []string{"/"},
+				Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: http.StatusBadGateway},
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name: "slow",
+			giveCfg: Config{
+				Enabled:       true,
+				Participation: 1.0,
+				Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(time.Millisecond)},
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name: "request header",
+			giveCfg: Config{
+				Enabled:       true,
+				Participation: 1.0,
+				Injector: &InjectorConfig{
+					Type:       InjectorTypeRequestHeader,
+					SetHeaders: map[string]string{"X-Test": "injected"},
+				},
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name: "reject",
+			giveCfg: Config{
+				Enabled:       true,
+				Participation: 1.0,
+				Injector:      &InjectorConfig{Type: InjectorTypeReject},
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name: "reject with delay",
+			giveCfg: Config{
+				Enabled:       true,
+				Participation: 1.0,
+				Injector:      &InjectorConfig{Type: InjectorTypeReject, Duration: Duration(time.Millisecond)},
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name: "invalid",
+			giveCfg: Config{
+				Participation: 2.0,
+				Injector:      &InjectorConfig{Type: InjectorTypeSlow},
+			},
+			wantErrs: []error{ErrInvalidPercent, ErrInvalidDuration},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFaultFromConfig(tt.giveCfg)
+			if tt.wantErrs != nil {
+				assert.Nil(t, f)
+				for _, wantErr := range tt.wantErrs {
+					assert.ErrorIs(t, err, wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.giveCfg.Enabled, f.enabled.Load())
+
+			if tt.giveCfg.Injector.Type == InjectorTypeReject {
+				assert.Panics(t, func() { testRequest(t, f) })
+				return
+			}
+
+			rr := testRequest(t, f)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+// TestDurationJSON tests Duration.UnmarshalJSON and Duration.MarshalJSON.
+func TestDurationJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveJSON string
+		want     Duration
+		wantJSON string
+		wantErr  error
+	}{
+		{
+			name:     "string",
+			giveJSON: `{"type":"slow","duration":"1.5s"}`,
+			want:     Duration(1500 * time.Millisecond),
+			wantJSON: `{"type":"slow","duration":"1.5s"}`,
+		},
+		{
+			name:     "nanoseconds",
+			giveJSON: `{"type":"slow","duration":1000000000}`,
+			want:     Duration(time.Second),
+			wantJSON: `{"type":"slow","duration":"1s"}`,
+		},
+		{
+			name:     "missing",
+			giveJSON: `{"type":"reject"}`,
+			want:     0,
+			wantJSON: `{"type":"reject"}`,
+		},
+		{
+			name:     "invalid string",
+			giveJSON: `{"type":"slow","duration":"1 second"}`,
+			wantErr:  ErrInvalidDuration,
+		},
+		{
+			name:     "invalid type",
+			giveJSON: `{"type":"slow","duration":true}`,
+			wantErr:  ErrInvalidDuration,
+		},
+		{
+			name:     "fractional nanoseconds",
+			giveJSON: `{"type":"slow","duration":1.5}`,
+			wantErr:  ErrInvalidDuration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg InjectorConfig
+			err := json.Unmarshal([]byte(tt.giveJSON), &cfg)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, tt.want, cfg.Duration)
+
+			b, err := json.Marshal(cfg)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.wantJSON, string(b))
+		})
+	}
+}
+
+// TestNewFaultFromConfigJSON tests NewFaultFromConfig with a Config decoded from JSON.
+func TestNewFaultFromConfigJSON(t *testing.T) {
+	t.Parallel()
+
+	var cfg Config
+	err := json.Unmarshal([]byte(`{
+		"enabled": true,
+		"participation": 1.0,
+		"injector": {"type": "slow", "duration": "1ms"}
+	}`), &cfg)
+	assert.NoError(t, err)
+
+	f, err := NewFaultFromConfig(cfg)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+	assert.Equal(t, testHandlerCode, rr.Code)
+	assert.Equal(t, "SlowInjector(1ms)", injectorString(f.injector))
+}
@@ -0,0 +1,98 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ContextKey is the type of the keys the fault package uses for request context values.
+type ContextKey int
+
+const (
+	// ContextKeyInjected is the request context key for a []string of the names of the Faults that
+	// injected into the request, in the order they injected.
+	ContextKeyInjected ContextKey = iota + 1
+	// ContextKeySkipped is the request context key for a []string of the names of the enabled Faults
+	// that evaluated the request without injecting, in the order they evaluated.
+	ContextKeySkipped
+	// ContextKeyTrace is the request context key for a []Trace of the decisions of the Faults with
+	// WithDebugTrace that handled the request, in the order they handled it.
+	ContextKeyTrace
+	// ContextKeyDelay is the request context key for the time.Duration that Injectors such as the
+	// SlowInjector and the LoadLatencyInjector waited before the request reached the handler, summed
+	// over every Injector that waited.
+	ContextKeyDelay
+)
+
+type contextAnnotationOption bool
+
+func (o contextAnnotationOption) applyFault(f *Fault) error {
+	f.annotate = bool(o)
+	return nil
+}
+
+// WithContextAnnotation sets if the Fault records whether it injected in the request context under
+// ContextKeyInjected and ContextKeySkipped. Default true. Disable annotation on very hot paths to
+// avoid the allocations of context.WithValue. Injection is not affected.
+func WithContextAnnotation(a bool) Option {
+	return contextAnnotationOption(a)
+}
+
+// InjectedNames returns the names of the Faults that injected into r, in the order they injected, or
+// nil if none did. Downstream handlers can use it to change their behavior for injected requests,
+// such as to skip billing for synthetic failures. It is empty for Faults with
+// WithContextAnnotation(false).
+func InjectedNames(r *http.Request) []string {
+	names, _ := r.Context().Value(ContextKeyInjected).([]string)
+	return names
+}
+
+// InjectedDelay returns the total time that Injectors waited before r reached the handler, or 0 if
+// none did. Delays added after the handler, such as with WithSlowAfterHandler, are not included.
+func InjectedDelay(r *http.Request) time.Duration {
+	d, _ := r.Context().Value(ContextKeyDelay).(time.Duration)
+	return d
+}
+
+// withInjectedDelay returns a shallow copy of r with d added to its InjectedDelay.
+func withInjectedDelay(r *http.Request, d time.Duration) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), ContextKeyDelay, InjectedDelay(r)+d))
+}
+
+// annotateRequest returns r with the name of the Fault appended to the context value for key, or r
+// unchanged if annotation is disabled.
+func (f *Fault) annotateRequest(r *http.Request, key ContextKey) *http.Request {
+	if !f.annotate {
+		return r
+	}
+	return withContextName(r, key, f.name)
+}
+
+// withContextName returns a shallow copy of r with name appended to the []string in the context
+// value for key.
+func withContextName(r *http.Request, key ContextKey, name string) *http.Request {
+	return r.WithContext(&nameContext{Context: r.Context(), key: key, name: name})
+}
+
+// nameContext is a context whose value for key is the []string value for key of its parent with
+// name appended. The slice is only built when the value is read, so that annotating a request
+// allocates as little as possible.
+type nameContext struct {
+	context.Context
+	key  ContextKey
+	name string
+}
+
+// Value returns the names for key, or the value for key of the parent context for any other key.
+func (c *nameContext) Value(key any) any {
+	if k, ok := key.(ContextKey); !ok || k != c.key {
+		return c.Context.Value(key)
+	}
+
+	existing, _ := c.Context.Value(key).([]string)
+
+	names := make([]string, 0, len(existing)+1)
+	names = append(names, existing...)
+	return append(names, c.name)
+}
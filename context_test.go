@@ -0,0 +1,170 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithContextName tests withContextName.
+func TestWithContextName(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, r.Context().Value(ContextKeyInjected))
+
+	one := withContextName(r, ContextKeyInjected, "one")
+	two := withContextName(one, ContextKeyInjected, "two")
+	three := withContextName(one, ContextKeyInjected, "three")
+	skipped := withContextName(two, ContextKeySkipped, "four")
+
+	assert.Nil(t, r.Context().Value(ContextKeyInjected))
+	assert.Equal(t, []string{"one"}, one.Context().Value(ContextKeyInjected))
+	assert.Equal(t, []string{"one", "two"}, two.Context().Value(ContextKeyInjected))
+	assert.Equal(t, []string{"one", "three"}, three.Context().Value(ContextKeyInjected))
+	assert.Equal(t, []string{"one", "two"}, skipped.Context().Value(ContextKeyInjected))
+	assert.Equal(t, []string{"four"}, skipped.Context().Value(ContextKeySkipped))
+
+	// other values pass through
+	type otherKey struct{}
+	other := r.WithContext(context.WithValue(r.Context(), otherKey{}, "other"))
+	five := withContextName(other, ContextKeyInjected, "five")
+	assert.Equal(t, "other", five.Context().Value(otherKey{}))
+	assert.Equal(t, []string{"five"}, five.Context().Value(ContextKeyInjected))
+}
+
+// TestInjectedNames tests InjectedNames.
+func TestInjectedNames(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, InjectedNames(r))
+
+	r = withContextName(r, ContextKeyInjected, "one")
+	r = withContextName(r, ContextKeySkipped, "two")
+	r = withContextName(r, ContextKeyInjected, "three")
+	assert.Equal(t, []string{"one", "three"}, InjectedNames(r))
+}
+
+// TestInjectedDelay tests InjectedDelay.
+func TestInjectedDelay(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Equal(t, time.Duration(0), InjectedDelay(r))
+
+	r = withInjectedDelay(r, time.Second)
+	r = withInjectedDelay(r, 2*time.Second)
+	assert.Equal(t, 3*time.Second, InjectedDelay(r))
+}
+
+// TestFaultHandlerAllocs tests that a Fault only allocates for requests it annotates.
+func TestFaultHandlerAllocs(t *testing.T) {
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		wantAllocs  float64
+	}{
+		{
+			name:        "disabled",
+			giveOptions: []Option{WithEnabled(false)},
+			wantAllocs:  0,
+		},
+		{
+			name:        "skipped without annotation",
+			giveOptions: []Option{WithEnabled(true), WithContextAnnotation(false)},
+			wantAllocs:  0,
+		},
+		{
+			name:        "skipped",
+			giveOptions: []Option{WithEnabled(true)},
+			wantAllocs:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFault(newTestInjectorNoop(), tt.giveOptions...)
+			assert.NoError(t, err)
+
+			h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+
+			allocs := testing.AllocsPerRun(100, func() {
+				h.ServeHTTP(rr, r)
+			})
+
+			assert.Equal(t, tt.wantAllocs, allocs)
+		})
+	}
+}
+
+// TestWithContextAnnotation tests that WithContextAnnotation disables request context annotation
+// without changing injection.
+func TestWithContextAnnotation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveAnnotate bool
+		giveOptions  []Option
+		wantInjected any
+		wantSkipped  any
+	}{
+		{
+			name:         "annotate injected",
+			giveAnnotate: true,
+			giveOptions:  []Option{WithParticipation(1.0)},
+			wantInjected: []string{"testInjectorOneOK"},
+			wantSkipped:  nil,
+		},
+		{
+			name:         "annotate skipped",
+			giveAnnotate: true,
+			giveOptions:  []Option{WithParticipation(0.0)},
+			wantInjected: nil,
+			wantSkipped:  []string{"testInjectorOneOK"},
+		},
+		{
+			name:         "no annotation injected",
+			giveAnnotate: false,
+			giveOptions:  []Option{WithParticipation(1.0)},
+			wantInjected: nil,
+			wantSkipped:  nil,
+		},
+		{
+			name:         "no annotation skipped",
+			giveAnnotate: false,
+			giveOptions:  []Option{WithParticipation(0.0)},
+			wantInjected: nil,
+			wantSkipped:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{
+				WithEnabled(true),
+				WithContextAnnotation(tt.giveAnnotate),
+			}, tt.giveOptions...)
+			f, err := NewFault(newTestInjectorOneOK(), opts...)
+			assert.NoError(t, err)
+
+			var got *http.Request
+			rr := httptest.NewRecorder()
+			f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantInjected, got.Context().Value(ContextKeyInjected))
+			assert.Equal(t, tt.wantSkipped, got.Context().Value(ContextKeySkipped))
+		})
+	}
+}
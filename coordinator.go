@@ -0,0 +1,258 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNilCoordinator when a nil Coordinator is provided.
+	ErrNilCoordinator = errors.New("coordinator cannot be nil")
+	// ErrNilCounter when a nil Counter is provided.
+	ErrNilCounter = errors.New("counter cannot be nil")
+)
+
+// defaultCounterTimeout bounds each call to Counter.Incr by a CounterCoordinator by default.
+const defaultCounterTimeout = time.Second
+
+// Coordinator decides whether a Fault may inject into a request that it selected, for example to
+// enforce an injection rate or budget across a fleet of instances instead of each instance
+// independently injecting into a percent of its own traffic.
+type Coordinator interface {
+	// Allow returns true if the Fault named name may inject. Allow is called for every request the
+	// Fault selects so it should be fast and respect ctx.
+	Allow(ctx context.Context, name string) bool
+}
+
+type coordinatorOption struct {
+	coordinator Coordinator
+}
+
+func (o coordinatorOption) applyFault(f *Fault) error {
+	if o.coordinator == nil {
+		return &OptionError{Option: "WithCoordinator", Value: nil, Err: ErrNilCoordinator}
+	}
+	f.coordinator = o.coordinator
+	return nil
+}
+
+func (o coordinatorOption) applyRegistry(r *Registry) error {
+	if o.coordinator == nil {
+		return &OptionError{Option: "WithCoordinator", Value: nil, Err: ErrNilCoordinator}
+	}
+	r.coordinator = o.coordinator
+	return nil
+}
+
+// CoordinatorOption configures things that accept a Coordinator.
+type CoordinatorOption interface {
+	Option
+	RegistryOption
+}
+
+// WithCoordinator sets a Coordinator that must allow every injection after the Fault selects a
+// request. Requests that the Coordinator does not allow are skipped. Passed to NewRegistry or
+// Registry.Scope, the Coordinator must also allow every injection of the Faults in the Registry and
+// its scopes, such as a CounterCoordinator with WithBudgetKey to share one budget between them.
+func WithCoordinator(c Coordinator) CoordinatorOption {
+	return coordinatorOption{coordinator: c}
+}
+
+// coordinate returns true if the Fault has no Coordinator or if its Coordinator allows injecting
+// into r.
+func (f *Fault) coordinate(r *http.Request) bool {
+	return f.coordinator == nil || f.coordinator.Allow(r.Context(), f.name)
+}
+
+// Counter is a counter shared between instances, such as a key in Redis. See the faultredis package
+// for a Redis implementation.
+type Counter interface {
+	// Incr increments the counter at key and returns the new value. A key that does not exist
+	// starts at 0 and is removed ttl after it is created.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// CounterCoordinator is a Coordinator that allows at most a limited number of injections per
+// window for each Fault, counted across every instance that shares a Counter.
+type CounterCoordinator struct {
+	counter Counter
+	limit   int64
+	window  time.Duration
+	timeout time.Duration
+	nowF    func() time.Time
+
+	// budgetKey, if set, is counted instead of the name of each Fault.
+	budgetKey string
+
+	// localLimit, if set, is the limit per window for each key counted locally while the Counter
+	// returns errors.
+	localLimit  int64
+	local       map[string]int64
+	localWindow int64
+
+	// localMtx protects CounterCoordinator.local and CounterCoordinator.localWindow.
+	localMtx sync.Mutex
+}
+
+// CounterCoordinatorOption configures a CounterCoordinator.
+type CounterCoordinatorOption interface {
+	applyCounterCoordinator(c *CounterCoordinator) error
+}
+
+func (o nowFuncOption) applyCounterCoordinator(c *CounterCoordinator) error {
+	c.nowF = o
+	return nil
+}
+
+type budgetKeyOption string
+
+func (o budgetKeyOption) applyCounterCoordinator(c *CounterCoordinator) error {
+	c.budgetKey = string(o)
+	return nil
+}
+
+// WithBudgetKey counts the injections of every Fault under key instead of counting each Fault
+// separately, making the limit a single budget shared by every Fault using the CounterCoordinator.
+func WithBudgetKey(key string) CounterCoordinatorOption {
+	return budgetKeyOption(key)
+}
+
+type localFallbackOption int64
+
+func (o localFallbackOption) applyCounterCoordinator(c *CounterCoordinator) error {
+	if o < 1 {
+		return &OptionError{Option: "WithLocalFallback", Value: int64(o), Err: ErrInvalidCount}
+	}
+	c.localLimit = int64(o)
+	return nil
+}
+
+// WithLocalFallback allows up to limit injections per window, counted by this instance alone, while
+// the Counter returns errors, for example because the store is unreachable. Choose limit as this
+// instance's share of the shared limit. By default no injections are allowed while the Counter
+// returns errors.
+func WithLocalFallback(limit int64) CounterCoordinatorOption {
+	return localFallbackOption(limit)
+}
+
+type counterTimeoutOption time.Duration
+
+func (o counterTimeoutOption) applyCounterCoordinator(c *CounterCoordinator) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithCounterTimeout", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	c.timeout = time.Duration(o)
+	return nil
+}
+
+// WithCounterTimeout sets the longest that Allow waits for the Counter before treating it as
+// unreachable, so that a store that never replies falls back like one that returns errors instead
+// of holding up requests. Default 1s.
+func WithCounterTimeout(d time.Duration) CounterCoordinatorOption {
+	return counterTimeoutOption(d)
+}
+
+// NewCounterCoordinator returns a CounterCoordinator that allows limit injections per window for
+// each Fault. Windows start at multiples of window since the Unix epoch, so a window of 24 hours
+// resets at midnight UTC.
+func NewCounterCoordinator(
+	c Counter,
+	limit int64,
+	window time.Duration,
+	opts ...CounterCoordinatorOption,
+) (*CounterCoordinator, error) {
+	if c == nil {
+		return nil, &OptionError{Option: "NewCounterCoordinator", Value: nil, Err: ErrNilCounter}
+	}
+	if limit < 1 {
+		return nil, &OptionError{Option: "NewCounterCoordinator", Value: limit, Err: ErrInvalidCount}
+	}
+	if window <= 0 {
+		return nil, &OptionError{Option: "NewCounterCoordinator", Value: window, Err: ErrInvalidDuration}
+	}
+
+	// set defaults
+	cc := &CounterCoordinator{
+		counter: c,
+		limit:   limit,
+		window:  window,
+		timeout: defaultCounterTimeout,
+		nowF:    time.Now,
+		local:   make(map[string]int64),
+	}
+
+	// apply options
+	err := applyOptions(opts, CounterCoordinatorOption.applyCounterCoordinator, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// Allow increments the counter for the current window and returns true if the limit has not been
+// exceeded. If the Counter returns an error, or does not return within the timeout set with
+// WithCounterTimeout, Allow falls back to the local limit set by WithLocalFallback, or returns false
+// if there is none.
+func (c *CounterCoordinator) Allow(ctx context.Context, name string) bool {
+	if c.budgetKey != "" {
+		name = c.budgetKey
+	}
+	window := c.nowF().UnixNano() / int64(c.window)
+	key := name + ":" + strconv.FormatInt(window, 10)
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	n, err := c.incr(ctx, key)
+	if err != nil {
+		return c.allowLocal(key, window)
+	}
+
+	return n <= c.limit
+}
+
+// incr increments key in the Counter. It returns the error of ctx once ctx is done, even if the
+// Counter ignores ctx and has not returned yet.
+func (c *CounterCoordinator) incr(ctx context.Context, key string) (int64, error) {
+	type result struct {
+		n   int64
+		err error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		n, err := c.counter.Incr(ctx, key, c.window)
+		results <- result{n: n, err: err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.n, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// allowLocal increments the local count for key and returns true if the local limit has not been
+// exceeded. Counts from earlier windows are discarded.
+func (c *CounterCoordinator) allowLocal(key string, window int64) bool {
+	if c.localLimit == 0 {
+		return false
+	}
+
+	c.localMtx.Lock()
+	defer c.localMtx.Unlock()
+
+	if window != c.localWindow {
+		c.local = make(map[string]int64)
+		c.localWindow = window
+	}
+	c.local[key]++
+
+	return c.local[key] <= c.localLimit
+}
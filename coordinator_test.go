@@ -0,0 +1,319 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestCounter = errors.New("error from test counter")
+)
+
+// testCoordinator allows injections if allow is true and records the names it is called with.
+type testCoordinator struct {
+	allow bool
+	names []string
+}
+
+// Allow records name and returns c.allow.
+func (c *testCoordinator) Allow(ctx context.Context, name string) bool {
+	c.names = append(c.names, name)
+	return c.allow
+}
+
+// testCounter is an in memory Counter that returns err if set.
+type testCounter struct {
+	err    error
+	counts map[string]int64
+	ttls   map[string]time.Duration
+	mtx    sync.Mutex
+}
+
+// newTestCounter returns a testCounter.
+func newTestCounter(err error) *testCounter {
+	return &testCounter{
+		err:    err,
+		counts: make(map[string]int64),
+		ttls:   make(map[string]time.Duration),
+	}
+}
+
+// Incr increments the count for key.
+func (c *testCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+	c.counts[key]++
+	c.ttls[key] = ttl
+	return c.counts[key], nil
+}
+
+// testBlockingCounter is a Counter that ignores ctx and blocks until release is closed, like a
+// store that never replies.
+type testBlockingCounter struct {
+	release chan struct{}
+}
+
+// Incr blocks until c.release is closed.
+func (c *testBlockingCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	<-c.release
+	return 1, nil
+}
+
+// TestWithCoordinator tests WithCoordinator.
+func TestWithCoordinator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveAllow   bool
+		giveOptions []Option
+		wantNames   []string
+		wantCode    int
+	}{
+		{
+			name:        "allow",
+			giveAllow:   true,
+			giveOptions: []Option{WithParticipation(1.0)},
+			wantNames:   []string{"testInjector500s"},
+			wantCode:    http.StatusInternalServerError,
+		},
+		{
+			name:        "deny",
+			giveAllow:   false,
+			giveOptions: []Option{WithParticipation(1.0)},
+			wantNames:   []string{"testInjector500s"},
+			wantCode:    testHandlerCode,
+		},
+		{
+			name:        "not selected",
+			giveAllow:   true,
+			giveOptions: []Option{WithParticipation(0.0)},
+			wantNames:   nil,
+			wantCode:    testHandlerCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &testCoordinator{allow: tt.giveAllow}
+			opts := append([]Option{WithEnabled(true), WithCoordinator(c)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			rr := testRequest(t, f)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantNames, c.names)
+		})
+	}
+}
+
+// TestWithCoordinatorNil tests WithCoordinator with a nil Coordinator.
+func TestWithCoordinatorNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithCoordinator(nil))
+
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithCoordinator", Value: nil, Err: ErrNilCoordinator}, err)
+}
+
+// TestNewCounterCoordinator tests NewCounterCoordinator.
+func TestNewCounterCoordinator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveCounter Counter
+		giveLimit   int64
+		giveWindow  time.Duration
+		giveOptions []CounterCoordinatorOption
+		wantErr     error
+	}{
+		{
+			name:        "valid",
+			giveCounter: newTestCounter(nil),
+			giveLimit:   10,
+			giveWindow:  time.Hour,
+			giveOptions: []CounterCoordinatorOption{
+				WithNowFunc(time.Now),
+				WithBudgetKey("budget"),
+				WithLocalFallback(1),
+				WithCounterTimeout(time.Minute),
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "invalid counter timeout",
+			giveCounter: newTestCounter(nil),
+			giveLimit:   10,
+			giveWindow:  time.Hour,
+			giveOptions: []CounterCoordinatorOption{WithCounterTimeout(0)},
+			wantErr:     &OptionError{Option: "WithCounterTimeout", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:        "invalid local fallback",
+			giveCounter: newTestCounter(nil),
+			giveLimit:   10,
+			giveWindow:  time.Hour,
+			giveOptions: []CounterCoordinatorOption{WithLocalFallback(0)},
+			wantErr:     &OptionError{Option: "WithLocalFallback", Value: int64(0), Err: ErrInvalidCount},
+		},
+		{
+			name:        "nil counter",
+			giveCounter: nil,
+			giveLimit:   10,
+			giveWindow:  time.Hour,
+			wantErr:     &OptionError{Option: "NewCounterCoordinator", Value: nil, Err: ErrNilCounter},
+		},
+		{
+			name:        "invalid limit",
+			giveCounter: newTestCounter(nil),
+			giveLimit:   0,
+			giveWindow:  time.Hour,
+			wantErr:     &OptionError{Option: "NewCounterCoordinator", Value: int64(0), Err: ErrInvalidCount},
+		},
+		{
+			name:        "invalid window",
+			giveCounter: newTestCounter(nil),
+			giveLimit:   10,
+			giveWindow:  0,
+			wantErr:     &OptionError{Option: "NewCounterCoordinator", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:        "option error",
+			giveCounter: newTestCounter(nil),
+			giveLimit:   10,
+			giveWindow:  time.Hour,
+			giveOptions: []CounterCoordinatorOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cc, err := NewCounterCoordinator(tt.giveCounter, tt.giveLimit, tt.giveWindow, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Nil(t, cc)
+			}
+		})
+	}
+}
+
+// TestCounterCoordinatorAllow tests CounterCoordinator.Allow.
+func TestCounterCoordinatorAllow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	counter := newTestCounter(nil)
+
+	cc, err := NewCounterCoordinator(counter, 2, time.Hour, WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.False(t, cc.Allow(ctx, "one"))
+	assert.True(t, cc.Allow(ctx, "two"))
+
+	// a new window resets the limit
+	now = now.Add(time.Hour)
+	assert.True(t, cc.Allow(ctx, "one"))
+
+	assert.Equal(t, map[string]int64{"one:0": 3, "one:1": 1, "two:0": 1}, counter.counts)
+	assert.Equal(t, time.Hour, counter.ttls["one:0"])
+
+	// errors deny injection
+	cc, err = NewCounterCoordinator(newTestCounter(errTestCounter), 2, time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, cc.Allow(ctx, "one"))
+}
+
+// TestCounterCoordinatorBudgetKey tests CounterCoordinator.Allow with WithBudgetKey.
+func TestCounterCoordinatorBudgetKey(t *testing.T) {
+	t.Parallel()
+
+	counter := newTestCounter(nil)
+	cc, err := NewCounterCoordinator(counter, 2, 24*time.Hour,
+		WithBudgetKey("daily"),
+		WithNowFunc(func() time.Time { return time.Unix(0, 0) }),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.True(t, cc.Allow(ctx, "two"))
+	assert.False(t, cc.Allow(ctx, "one"))
+	assert.False(t, cc.Allow(ctx, "two"))
+
+	assert.Equal(t, map[string]int64{"daily:0": 4}, counter.counts)
+}
+
+// TestCounterCoordinatorLocalFallback tests CounterCoordinator.Allow with WithLocalFallback.
+func TestCounterCoordinatorLocalFallback(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	counter := newTestCounter(errTestCounter)
+	cc, err := NewCounterCoordinator(counter, 100, time.Hour,
+		WithLocalFallback(2),
+		WithNowFunc(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.False(t, cc.Allow(ctx, "one"))
+	assert.True(t, cc.Allow(ctx, "two"))
+
+	// a new window resets the local limit
+	now = now.Add(time.Hour)
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.Equal(t, map[string]int64{"one:1": 1}, cc.local)
+
+	// the shared limit applies once the Counter recovers
+	counter.err = nil
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.Equal(t, map[string]int64{"one:1": 1}, counter.counts)
+}
+
+// TestCounterCoordinatorTimeout tests that CounterCoordinator.Allow falls back when the Counter does
+// not return in time.
+func TestCounterCoordinatorTimeout(t *testing.T) {
+	t.Parallel()
+
+	counter := &testBlockingCounter{release: make(chan struct{})}
+	defer close(counter.release)
+
+	cc, err := NewCounterCoordinator(counter, 100, time.Hour,
+		WithCounterTimeout(10*time.Millisecond),
+		WithLocalFallback(1),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.True(t, cc.Allow(ctx, "one"))
+	assert.False(t, cc.Allow(ctx, "one"))
+
+	// without a local fallback nothing is allowed
+	cc, err = NewCounterCoordinator(counter, 100, time.Hour, WithCounterTimeout(10*time.Millisecond))
+	assert.NoError(t, err)
+	assert.False(t, cc.Allow(ctx, "one"))
+}
@@ -0,0 +1,71 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrInjected when an Injector stops a function passed to Do from running.
+	ErrInjected = errors.New("fault injected")
+)
+
+// Do runs fn, first evaluating the Fault as if fn were an http.Handler. Use Do to inject faults
+// into code that does not serve http requests, such as background jobs, queue consumers, or cache
+// lookups.
+//
+// The Fault evaluates against a request with an empty path and no headers. Blocklists therefore
+// never match and any non-empty allowlist prevents injection. If the Injector continues the
+// request (for example the SlowInjector) fn runs and its error is returned. If the Injector
+// responds or aborts instead (for example the ErrorInjector or RejectInjector) fn does not run and
+// Do returns an error wrapping ErrInjected.
+func Do(ctx context.Context, f *Fault, fn func(ctx context.Context) error) error {
+	var called bool
+	var err error
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		err = fn(r.Context())
+	})
+
+	req := (&http.Request{
+		URL:    &url.URL{},
+		Header: make(http.Header),
+	}).WithContext(ctx)
+
+	rw, abortErr := f.serve(req, next)
+	if abortErr != nil {
+		return abortErr
+	}
+	if !called {
+		return fmt.Errorf("%w: %d %s", ErrInjected, rw.StatusCode(), strings.TrimSpace(string(rw.Body())))
+	}
+
+	return err
+}
+
+// serve evaluates the Fault for req with next as the handler, holding the response instead of
+// writing it. It returns an error wrapping ErrInjected if the Injector aborts the request and
+// panics again with any other panic.
+func (f *Fault) serve(req *http.Request, next http.Handler) (rw *ResponseRecorderWriter, err error) {
+	// the response is held and never written
+	rw = newInterceptor(&discardWriter{header: make(http.Header)})
+
+	defer func() {
+		if r := recover(); r != nil {
+			rErr, ok := r.(error)
+			if !ok || !errors.Is(rErr, http.ErrAbortHandler) {
+				panic(r)
+			}
+			err = fmt.Errorf("%w: %w", ErrInjected, rErr)
+		}
+	}()
+
+	f.Handler(next).ServeHTTP(rw, req)
+
+	return rw, nil
+}
@@ -0,0 +1,172 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestDo = errors.New("error from function passed to Do")
+)
+
+// testDoContextKey is used to verify that Do passes its context to the function.
+type testDoContextKey struct{}
+
+// testInjectorPanic is an injector that panics with the provided value.
+type testInjectorPanic struct {
+	value any
+}
+
+// Handler panics with i.value.
+func (i *testInjectorPanic) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(i.value)
+	})
+}
+
+// TestDo tests Do.
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveOptions  []Option
+		giveErr      error
+		wantCalled   bool
+		wantErr      []error
+	}{
+		{
+			name:         "not enabled",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(false),
+				WithParticipation(1.0),
+			},
+			giveErr:    nil,
+			wantCalled: true,
+			wantErr:    nil,
+		},
+		{
+			name:         "not enabled function error",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(false),
+				WithParticipation(1.0),
+			},
+			giveErr:    errTestDo,
+			wantCalled: true,
+			wantErr:    []error{errTestDo},
+		},
+		{
+			name:         "100 percent inject nothing",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(1.0),
+			},
+			giveErr:    errTestDo,
+			wantCalled: true,
+			wantErr:    []error{errTestDo},
+		},
+		{
+			name:         "100 percent 500s",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(1.0),
+			},
+			giveErr:    nil,
+			wantCalled: false,
+			wantErr:    []error{ErrInjected},
+		},
+		{
+			name:         "100 percent 500s with allowlist",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(1.0),
+				WithPathAllowlist([]string{"/"}),
+			},
+			giveErr:    nil,
+			wantCalled: true,
+			wantErr:    nil,
+		},
+		{
+			name:         "100 percent reject",
+			giveInjector: &testInjectorPanic{value: http.ErrAbortHandler},
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(1.0),
+			},
+			giveErr:    nil,
+			wantCalled: false,
+			wantErr:    []error{ErrInjected, http.ErrAbortHandler},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(tt.giveInjector, tt.giveOptions...)
+			assert.NoError(t, err)
+
+			ctx := context.WithValue(context.Background(), testDoContextKey{}, tt.name)
+
+			var called bool
+			err = Do(ctx, f, func(ctx context.Context) error {
+				called = true
+				assert.Equal(t, tt.name, ctx.Value(testDoContextKey{}))
+				return tt.giveErr
+			})
+
+			assert.Equal(t, tt.wantCalled, called)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+			for _, wantErr := range tt.wantErr {
+				assert.ErrorIs(t, err, wantErr)
+			}
+		})
+	}
+}
+
+// TestDoPanic tests that Do does not recover panics other than http.ErrAbortHandler.
+func TestDoPanic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		givePanic any
+	}{
+		{
+			name:      "error",
+			givePanic: errTestDo,
+		},
+		{
+			name:      "not an error",
+			givePanic: "not an error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(&testInjectorPanic{value: tt.givePanic},
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			assert.PanicsWithValue(t, tt.givePanic, func() {
+				assert.NoError(t, Do(context.Background(), f, func(ctx context.Context) error { return nil }))
+			})
+		})
+	}
+}
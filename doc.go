@@ -32,24 +32,212 @@ response will produce:
 	$ curl https://github.com
 	curl: (52) Empty reply from server
 
+Pass WithRejectDelay() to hold the request for a time before rejecting it, like a server that
+accepts a connection and then hangs before dropping it.
+
+Pass WithRejectMode() to choose how the request is dropped. RejectAbort (the default) aborts the
+handler, RejectClose hijacks and closes the connection, and RejectReset hijacks the connection and
+resets it so that the client sees "connection reset by peer". Run an experiment once with each
+RejectMode to test every way a client can lose a request.
+
+# IdleInjector
+
+Use fault.IdleInjector to accept a request and then never read its body or respond until a timeout,
+after which the request is rejected like the RejectInjector. This simulates an overloaded upstream
+that accepted the connection but stopped servicing it, a different failure than a slow but working
+upstream, and tests that clients enforce their own timeouts.
+
+# PanicInjector
+
+Use fault.PanicInjector to panic while handling the request. The panic value defaults to
+ErrInjectedPanic and can be set with WithPanicValue(). Unlike the RejectInjector the panic is a
+real panic, which makes the PanicInjector useful for exercising recovery middleware, panic logging,
+and alerting.
+
 # ErrorInjector
 
 Use fault.ErrorInjector to immediately return a valid http status code of your choosing along with
 the standard HTTP response body for that code. For example, you can return a 200, 301, 418, 500, or
 any other valid status code to test how your clients respond to different statuses. Pass the
-WithStatusText() option to customize the response text.
+WithStatusText() option to customize the response text. Pass the WithSOAPFault() option to respond
+with a text/xml SOAP fault envelope with a custom faultcode and faultstring instead, for clients that
+speak XML. Pass WithBodyBytes() or WithBody() to respond with any other payload, such as a JSON error
+document or a recorded response read from a file, or WithEmptyBody() to respond with no body at all.
+
+# PayloadInjector
+
+Use fault.PayloadInjector to respond with a status code and a raw byte payload, such as a serialized
+protobuf error or deliberately garbled protobuf, to simulate failures of binary APIs. Pass
+WithPayloadContentType() to set the content type, which defaults to application/octet-stream.
+
+# GzipBombInjector
+
+Use fault.GzipBombInjector to respond with a small gzip encoded body that decompresses to a very
+large size, to check that clients limit decompression. As a safety cap the size is limited to
+GzipBombLimit (100 MiB) unless you opt in with WithLargeGzipBomb(), which raises the limit to
+GzipBombLargeLimit (10 GiB). Only target clients that are expected to enforce a limit.
 
 # SlowInjector
 
 Use fault.SlowInjector to wait a configured time.Duration before proceeding with the request. For
 example, you can use the SlowInjector to add a 10ms delay to your requests.
 
+Pass WithSlowAfterHandler() to wait after your handler returns instead, which delays the completion
+of the response rather than the start of the handler. The wait is then outside of any handler side
+timeouts, which better models a slow network between your service and its clients.
+
+# BandwidthInjector
+
+Use fault.BandwidthInjector to limit how fast the response body is written, such as 1024 bytes per
+second, to test clients on slow or congested networks. The body is written and flushed in chunks of a
+tenth of a second of bandwidth, so clients see a steady trickle rather than one long pause.
+
+# SlicerInjector
+
+Use fault.SlicerInjector to write the response body in many small slices, flushing each one, to test
+clients and parsers that assume a whole message arrives in one read. Pass WithSliceVariation() to
+vary the size of each slice randomly and WithSliceDelay() to wait after each slice.
+
+# LoadLatencyInjector
+
+Use fault.LoadLatencyInjector to add a delay that grows with the number of requests in flight, to
+simulate queueing under load instead of a fixed latency. Pass a curve from the number of requests in
+flight to a time.Duration, such as fault.LinearLatency(10*time.Millisecond, 5*time.Millisecond, 0),
+which adds 5ms for each additional concurrent request.
+
+# RequestHeaderInjector
+
+Use fault.RequestHeaderInjector to remove or rewrite request headers before your handler runs. Pass
+WithRemoveHeaders() to drop headers such as Authorization and WithSetHeaders() to overwrite headers
+such as Content-Type. This simulates broken intermediaries and tests your server-side validation.
+
+# RequestBodyInjector
+
+Use fault.RequestBodyInjector to truncate or corrupt the request body read by your handler. Pass
+WithTruncateBody() to end the body early, simulating a partial upload, and WithCorruptBodyFunc() to
+rewrite the body, for example to produce invalid JSON. Only the first 10 MiB of the body is read
+into memory and corrupted, pass WithMaxCorruptBody() to change the limit.
+
+# ConnectionCloseInjector
+
+Use fault.ConnectionCloseInjector to set "Connection: close" on responses. The http.Server closes
+the connection after the response, breaking client keep-alive pooling so that you can load test how
+clients handle connection churn. HTTP/2 ignores the header.
+
+# StaleCacheInjector
+
+Use fault.StaleCacheInjector to make responses look stale. The StaleCacheInjector rewrites the Date,
+Age, ETag, and Last-Modified headers of the response, by default to look 24 hours old, to test
+client cache validation against a misbehaving origin. Pass WithStaleAge() and WithStaleETag() to
+choose the headers and WithStaleNotModified() to answer every conditional request with an incorrect
+304 Not Modified.
+
+# MalformedHeaderInjector
+
+Use fault.MalformedHeaderInjector to add oversized, duplicate, or invalid headers to responses, to
+test proxies and clients that enforce header limits. Pass WithOversizedHeader() for a header with a
+very long value, WithDuplicateHeader() to repeat a header, and WithInvalidHeader() for a header
+value with control characters and invalid UTF-8. By default it adds a 16 KiB X-Fault-Oversized
+header.
+
+# DoubleWriteHeaderInjector
+
+Use fault.DoubleWriteHeaderInjector to call WriteHeader again after the handler returns, and with
+WithLateWrite() to write more of the body as well. Place it inside logging middleware and custom
+ResponseWriter wrappers to check how they handle a "superfluous response.WriteHeader call". Set the
+status code of the extra calls with WithSuperfluousStatus() (default: 500).
+
+# DowngradeInjector
+
+Use fault.DowngradeInjector on h2c or HTTP/2 servers to serve selected requests with HTTP/1.1
+semantics. The handler sees an HTTP/1.1 request, server push is not available, and response
+trailers are dropped, which reveals clients and handlers that assume HTTP/2-only features. The
+connection itself still uses the negotiated protocol.
+
+# CharsetInjector
+
+Use fault.CharsetInjector to corrupt the text encoding of responses, testing how clients decode and
+sanitize text. WithInvalidUTF8() (the default) turns every non-ASCII character in the body into an
+invalid UTF-8 sequence while keeping ASCII intact, and WithCharset() replaces the charset parameter
+of the Content-Type so that it no longer matches the body.
+
+# PushInjector
+
+Use fault.PushInjector to make HTTP/2 server push fail or wait, verifying that handlers treat push
+as best effort. By default every http.Pusher.Push returns http.ErrNotSupported, as it does when the
+client disables push. WithPushError() returns a different error and WithPushDelay() waits before
+each push, which then continues unless WithPushError() is also set.
+
+# DuplicateRequestInjector
+
+Use fault.DuplicateRequestInjector to send each request to your handler more than once, discarding
+the responses to the duplicates. This verifies that handlers are idempotent under at-least-once
+delivery. Pass WithDuplicates() to send more than one duplicate and WithDuplicateFirst() to send the
+duplicates before the original request. Request bodies up to 10 MiB are read into memory to be
+duplicated, and larger requests continue without duplicates; pass WithMaxDuplicateBody() to change
+the limit.
+
+# CPUInjector
+
+Use fault.CPUInjector to keep the CPU busy for a configured time.Duration before proceeding with the
+request. Unlike the SlowInjector, which waits without doing any work, the CPUInjector simulates noisy
+neighbors or garbage collection pressure. Pass WithCPUWorkers() to spin more than one goroutine.
+
 # RandomInjector
 
 Use fault.RandomInjector to randomly choose one of the above faults to inject. Pass a list of
 Injector to fault.NewRandomInjector and when RandomInjector is evaluated it will randomly run one of
 the injectors that you passed.
 
+Pass WithSelectionMode() to change how the RandomInjector chooses. SelectionRoundRobin cycles
+through the injectors in order and SelectionShuffle runs every injector once in a random order
+before repeating. Both give even coverage of all injectors during short test runs.
+
+For quick smoke tests, NewClientErrorInjector() and NewServerErrorInjector() return a RandomInjector
+that responds with a status code chosen from every 4xx or 5xx code for each request, without listing
+the codes yourself. Pass WithErrorInjectorOptions() to configure each ErrorInjector, such as with
+WithReporter(). Each ErrorInjector reports under its name with the status code appended, such as
+"ErrorInjector-503".
+
+# OutageInjector
+
+Use fault.OutageInjector to simulate a full outage. The OutageInjector responds 503 to every request
+for a configured time.Duration, starting with the first request it handles, and then recovers. Pass
+WithOutageRepeat() to repeat the outage periodically and WithOutageInjector() to run a different
+Injector during the outage. Use it to test alerting, retries, and failover against the shape of a
+real outage rather than uniform random errors.
+
+# FlappingInjector
+
+Use fault.FlappingInjector to model a dependency whose health flaps. The FlappingInjector runs an
+Injector on every request for a bad time.Duration and then continues every request for a good
+time.Duration, repeatedly, such as 30 seconds bad and 90 seconds good. Flapping dependencies are
+notoriously hard on circuit breakers.
+
+# SequenceInjector
+
+Use fault.SequenceInjector to script exactly what happens to successive requests. Pass a list of
+Injector to fault.NewSequenceInjector and the first Injector runs on the first request, the second
+Injector on the second request, and so on. A nil Injector in the list lets that request continue
+without injecting. Once the sequence ends requests continue without injection unless you pass
+WithRepeat(true). This is useful for testing client retry behavior precisely.
+
+# ResponseInjector
+
+Use fault.ResponseInjector to run another Injector only when the response from your handler
+matches a function of the status code and headers. The ResponseInjector runs your handler first and
+buffers its response, so you can for example return errors only in place of 200s or add latency only
+to cache hits identified by a header. The buffered response is written when the Injector continues
+the request or when the response does not match. Because of the buffering, flushes from your handler
+are ignored, but hijacked connections such as websocket upgrades pass through untouched.
+
+# ConditionalInjector
+
+Use fault.ConditionalInjector to choose between two Injectors based on the request. Pass a function
+of the request and two Injectors to fault.NewConditionalInjector and the first Injector runs when
+the function returns true while the second runs otherwise. Pass a nil Injector to continue the
+request without injecting on that branch.
+
 # Combining Faults
 
 It is easy to combine any of the Injectors into a chained action. There are two ways you might want
@@ -57,7 +245,8 @@ to combine Injectors.
 
 First, you can create separate Faults for each Injector that are sequential but independent of each
 other. For example, you can chain Faults such that 1% of requests will return a 500 error and
-another 1% of requests will be rejected.
+another 1% of requests will be rejected. Use fault.Chain() to combine Faults this way instead of
+nesting Fault.Handler calls by hand. The first Fault passed to Chain evaluates first.
 
 Second, you might want to combine Faults such that 1% of requests will be slowed for 10ms and then
 rejected. You want these Faults to depend on each other. For this use the special ChainInjector,
@@ -65,6 +254,128 @@ which consolidates any number of Injectors into a single Injector that runs each
 Injectors sequentially. When you add the ChainInjector to a Fault the entire chain will always
 execute together.
 
+Each Injector in a ChainInjector can also carry its own participation percentage. For example, pass
+WithChildParticipation(1, 0.1) to a chain of a SlowInjector and an ErrorInjector to always slow
+requests by 100ms and additionally return a 500 for 10% of those requests.
+
+By default a ChainInjector stops when one of its Injectors responds or otherwise does not continue
+the request. Pass WithStopOnWrite(false) to keep running the remaining Injectors in the chain, for
+example so that reporting or header Injectors still run after an ErrorInjector responds. The handler
+after the chain never runs once an Injector has stopped the request.
+
+To choose between many Injectors without nesting a Fault for each of them, list them as FaultRules
+in a single Faults middleware with NewFaults(). Each rule has a matcher, an Injector, and its own
+participation. By default the first rule that matches a request wins: it runs if it participates,
+and the rest are skipped either way, so put more specific rules first. Pass WithAllMatch() to
+instead run every rule that matches and participates, in order, as if each were a separate Fault.
+
+	fs, err := fault.NewFaults([]fault.FaultRule{
+		{Match: isCheckout, Injector: ei, Participation: 0.05},
+		{Match: nil, Injector: si, Participation: 0.01},
+	})
+	handler := fs.Handler(mainHandler)
+
+# Deterministic Participation
+
+By default a Fault randomly chooses which requests participate using WithParticipation(). Pass
+WithEveryNth(n) to NewFault to instead run the Injector on exactly every Nth request that passes the
+Fault's other checks. Deterministic participation makes low rate experiments and integration tests
+far more predictable.
+
+WithParticipation() takes a float32, which cannot precisely express very low rates. Pass
+WithParticipationRatio(k, n) to randomly run the Injector on k in every n requests instead, such as
+WithParticipationRatio(1, 100000). The ratio is rolled with integers so that tiny probabilities are
+exact.
+
+Percentages are awkward when traffic varies 100x over a day. Pass WithTargetRate(perSecond) to
+inject an absolute number of faults per second instead, such as 2 per second, and the effective
+participation adapts to the traffic.
+
+Real partial outages are often bursty. Pass WithBurst(on, off) to run the Injector on on consecutive
+requests and then skip the next off requests, repeatedly. WithBurstDuration(on, off) does the same
+using durations, injecting into all requests for on and then none for off, starting when the Fault
+is created.
+
+# Brownout
+
+Pass WithBrownout(low, high) to NewFault() to scale participation with the number of requests in
+flight through the Fault. The Fault does not inject with low or fewer requests in flight, and its
+participation rises linearly to the full participation at high requests in flight. More traffic
+means more injection, which is useful for rehearsing brownout and load shedding strategies.
+
+# Fault Groups
+
+Each Fault decides independently which requests to inject into, so two Faults at 1% participation
+affect two different 1% slices of traffic. To run a combined experiment, such as a slow database and
+an error from a downstream service on the same requests, create a FaultGroup with NewFaultGroup()
+and pass WithGroup() to each Fault. The first Fault in the group to handle a request rolls for the
+whole group and later Faults reuse that decision. Each request also gets a correlation ID that you
+can read with FaultGroup.CorrelationID() to tie the injections together in logs.
+
+	g, err := fault.NewFaultGroup(0.01)
+	slow, err := fault.NewFault(si, fault.WithEnabled(true), fault.WithGroup(g))
+	fail, err := fault.NewFault(ei, fault.WithEnabled(true), fault.WithGroup(g))
+
+# Experiment Arms
+
+To compare injected requests with a control group, create a Bucketing with NewBucketing(). It hashes
+an identifier of each request, such as a user ID, to consistently assign the request to one of a set
+of weighted arms. Pass WithArm() to a Fault to inject into every request in one arm, such as
+"treatment", and no others. Wrap your handler with Bucketing.Handler() to tag every request with its
+arm, which downstream handlers read with Bucketing.Arm(), and to report every arm to the Reporter of
+the Bucketing, such as "checkout/control" and "checkout/treatment", so that the arms can be compared.
+
+	b, err := fault.NewBucketing(func(r *http.Request) string { return r.Header.Get("X-User-Id") },
+		[]fault.Arm{{Name: "control", Weight: 95}, {Name: "treatment", Weight: 5}},
+		fault.WithName("checkout"), fault.WithReporter(metrics))
+	f, err := fault.NewFault(si, fault.WithEnabled(true), fault.WithArm(b, "treatment"))
+	handler := b.Handler(f.Handler(mux))
+
+# Bad Instances
+
+A common production failure is one bad replica behind a load balancer, where the same clients keep
+failing while everyone else is fine. Random participation cannot reproduce this. Pass
+WithBadInstances(n, bad...) to NewFault to hash each request into one of n virtual backend instances
+and inject into every request on the bad ones, such as WithBadInstances(10, 3) for "1 of 10 replicas
+is bad". Requests are hashed by their client connection by default, like a load balancer with
+keep-alive connections. Pass WithInstanceKeyFunc() to hash a client or session ID instead.
+
+# Warmup
+
+Injecting faults into a service that is starting up can collide with cold starts and deployment
+health checks. Pass WithWarmup(n) to NewFault to prevent injection until the Fault has handled n
+requests, or WithWarmupDuration(d) to prevent injection until d has passed since the Fault was
+created. WithStartupGrace(d) prevents injection until d has passed since the process started, so
+that readiness gates pass during deploys even for Faults created or reloaded after startup.
+
+# Guards
+
+Pass WithGuard() to NewFault() to add a Guard that must allow every injection after the Fault selects
+a request. Use Guards to automatically back off when injecting would be unsafe, such as while the
+service is already unhealthy. NewSLOGuard() returns a Guard that reads a signal from your SLO
+monitoring, such as the error budget burn rate, and stops injection while the signal is over a limit.
+The SLOGuard reads the signal in the background every WithSLOInterval(), with a timeout set by
+WithSLOTimeout(), so that only the first requests wait for it.
+
+# Coordinating Across Instances
+
+Each Fault decides on its own which requests to inject. On a large fleet, especially with sticky
+routing, a percentage per instance can add up to far more injections than intended. Pass
+WithCoordinator() to NewFault() to require a Coordinator to allow every injection after the Fault
+selects a request. Requests the Coordinator does not allow are skipped.
+
+NewCounterCoordinator() returns a Coordinator that allows a limited number of injections per window
+for each Fault, counted in a Counter shared by every instance. The faultredis package provides a
+Counter backed by Redis that has no dependencies outside of the standard library. It gives up on
+Redis after one second by default, set with faultredis.WithTimeout(), so that an unreachable Redis
+does not hold up requests.
+
+Pass WithBudgetKey() to NewCounterCoordinator() to share one budget between every Fault, such as at
+most 10,000 injections per day across the whole deployment. By default no injections are allowed
+while the Counter is unreachable. Pass WithLocalFallback() to instead allow a limited number of
+injections per window counted by each instance on its own. A Counter that does not return within
+one second, set with WithCounterTimeout(), is treated as unreachable.
+
 # Allowing And Blocking Paths
 
 The NewFault() constructor has WithPathBlocklist() and WithPathAllowlist() options. Any path you
@@ -74,35 +385,226 @@ PathAllowlist. The PathBlocklist take priority over the PathAllowlist, a path in
 never have a fault run against it. The paths that you include must match exactly the path in
 req.URL.Path, including leading and trailing slashes.
 
+Percent-encoding is unescaped in both the lists and the request before comparing, so "/foo%2Fbar"
+and "/foo/bar" match each other and an escaped request path cannot bypass a blocklist. Pass
+WithEscapedPathMatch(true) to instead compare paths as the client escaped them.
+
 Simmilarly, you may also use WithHeaderBlocklist() and WithHeaderAllowlist() to block or allow
 faults based on a map of header keys to values. These lists behave in the same way as the path
-allowlists and blocklists except that they operate on headers. Header equality is determined using
-http.Header.Get(key) which automatically canonicalizes your keys and does not support multi-value
-headers. Keep these limitations in mind when working with header allowlists and blocklists.
+allowlists and blocklists except that they operate on headers. Header equality is determined like
+http.Header.Get(key), which canonicalizes your keys and does not support multi-value headers. Keys
+are canonicalized once when the Fault is created, so that requests are checked without allocating.
+Keep these limitations in mind when working with header allowlists and blocklists. Pass
+WithHeaderExactMatch(true) to look up keys exactly as given instead, for headers stored under
+non-canonical keys.
+
+Exact path matching does not work well for parameterized routes. Use WithPatternBlocklist() and
+WithPatternAllowlist() to instead match the route pattern that handled the request, such as
+"/users/{id}". By default patterns come from the http.ServeMux (see ServeMuxPattern), which only
+sets the pattern after routing, so wrap the handlers you register on the mux with Fault.Handler
+rather than the mux itself. Pass WithPatternFunc() to get patterns from other routers such as chi.
+
+Some routing schemes distinguish behavior purely by query string. Use WithURIBlocklist() and
+WithURIAllowlist() to match the request URI, the path and query such as "/search?q=test", instead.
+Query parameters are sorted and escaped consistently before comparing, so the order the client sends
+them in does not matter. Pass WithURIExactMatch(true) to compare URIs exactly as sent. Fragments are
+never sent to the server and cannot be matched.
+
+Faults that inject into health checks can cause orchestrators to restart or remove healthy
+instances. Pass WithSkipHealthEndpoints() to never run faults against the common health, readiness,
+and metrics paths (/healthz, /livez, /readyz, /health, /ping, and /metrics) plus any extra paths you
+provide.
+
+Similarly, failed CORS preflight requests surface in browsers as confusing CORS errors rather than
+the failure being tested. Pass WithSkipPreflight() to never run faults against OPTIONS requests with
+an Access-Control-Request-Method header.
 
 Specifying very large lists of paths or headers may cause memory or performance issues. If you're
 running into these problems you should instead consider using your http router to enable the
 middleware on only a subset of your routes.
 
+For blocklists of 100,000 or more paths, patterns, or URIs, such as ones loaded from configuration,
+pass WithBlocklistBloomFilter(true) to NewFault to check requests against a compact bloom filter
+before the exact lookup. Most requests that are not in a blocklist are then ruled out by reading a
+single word of memory, which keeps the cost of the check predictable as the lists grow.
+
+Every list is compiled once when NewFault() creates the Fault: paths are unescaped, URIs have their
+query parameters sorted, header keys are canonicalized, and bloom filters are built. Checking a
+request then does no parsing of the lists, only of the request itself, such as normalizing its URI
+for the URI lists. NewFault() returns an OptionError wrapping ErrInvalidListEntry for every path or
+URI in a list that is not validly escaped, such as "/100%". Escape a literal percent sign as "%25".
+
+# Outside Of HTTP
+
+Use fault.Do() to evaluate a Fault around any function instead of an http.Handler. This lets you
+inject faults into background jobs, queue consumers, cache lookups, and other code paths that do not
+serve http requests. Injectors that continue the request (such as the SlowInjector) run before the
+function, while Injectors that respond or abort (such as the ErrorInjector and RejectInjector)
+prevent the function from running and cause Do to return an error wrapping ErrInjected.
+
+Http clients can use fault.NewRoundTripper() to evaluate a Fault around the requests they send.
+Injectors that continue the request run before it is sent, Injectors that respond return their
+response without sending the request, and Injectors that abort cause the client to return an error
+wrapping ErrInjected. Pass WithTracePhase() to choose where the time spent injecting appears to
+httptrace based client instrumentation: TraceDNS and TraceConnect wrap the injection in DNS or
+connect events, and TraceFirstByte sends the request first and holds back GotFirstResponseByte
+until the Fault has run, so that injected latency shows up as time to first byte.
+
+Message consumers can use fault.MessageMiddleware() and fault.MessageResultMiddleware() to wrap a
+message handler, such as one consuming from Kafka or SQS. The SlowInjector delays messages, the
+ErrorInjector causes the handler to return an error, and the RejectInjector drops messages without
+processing them.
+
+File and stream processing code can be tested with fault.Reader() and fault.Writer(), which wrap an
+io.Reader or io.Writer and inject into a participation percent of reads or writes. WithStreamDelay()
+slows each operation, WithShortIO() reads or writes only part of the buffer, and WithStreamError()
+fails the operation. The wrappers accept the same WithParticipation(), WithRandSeed(),
+WithRandFloat32Func(), and WithSlowFunc() options as Faults and Injectors. fault.FS() takes the same
+options and wraps an fs.FS, such as an embed.FS, so that opens fail with fs.ErrNotExist or
+fs.ErrPermission and opens and reads are slowed or shortened.
+
+# Request Context
+
+Every Fault records whether it evaluated a request in the request context. The names of Faults that
+injected are stored as a []string under ContextKeyInjected and the names of Faults that evaluated
+without injecting are stored under ContextKeySkipped. Disabled Faults and Faults that are warming up
+leave no trace. A Fault is named after the type of its Injector by default, such as "ErrorInjector".
+Pass WithName() to NewFault() to use a custom name. Annotation allocates a new context for every
+request the Fault evaluates. Pass WithContextAnnotation(false) to NewFault() on extremely hot paths
+to skip annotation without changing which requests are injected.
+
+Downstream handlers can call InjectedNames(r) to read the names of the Faults that injected into a
+request without knowing the context key, for example to skip billing for synthetic failures.
+Call InjectedDelay(r) to read the total time that Injectors such as the SlowInjector waited before
+the request reached the handler, stored as a time.Duration under ContextKeyDelay.
+
+Middleware that wraps a Fault cannot see the context values the Fault adds. To mark injected
+requests in access logs, wrap your Faults with an AccessLogger from NewAccessLogger(), which logs a
+line such as "fault: GET /search 503 22 1.2ms injected=ErrorInjector" for every request. To add the
+same information to an existing access log middleware, call TrackInjections(r) in that middleware
+before calling the next handler and read the names after it returns.
+
+The faulttest package provides faulttest.AssertInjected() and faulttest.AssertSkipped(), which
+inspect these context values so that integration tests can verify that faults did or did not fire
+without relying on the response.
+
+For lightweight inline hooks, such as incrementing an in-house metric, pass WithOnInject() and
+WithOnSkip() to NewFault(). They are called synchronously with the request each time the Fault
+injects or skips a request, without implementing a Reporter.
+
+To find out why a Fault did or did not fire, pass WithDebugTrace(true) to NewFault(). The Fault then
+records a Trace for every request it handles, including while disabled or warming up, with the
+Reason for its decision, such as ReasonPathBlocklist or ReasonHeaderAllowlist, and the random value
+rolled for participation along with the participation it was compared to. Use the roll to verify
+that a custom WithRandFloat32Func() behaves as intended in production. Traces are stored as a []Trace
+under ContextKeyTrace and sent with an Event to the Fault's EventReporter.
+
+	traces, _ := r.Context().Value(fault.ContextKeyTrace).([]fault.Trace)
+	for _, t := range traces {
+		log.Println(t) // ErrorInjector: participation (roll 0.73 >= 0.50)
+	}
+
+Pass WithInjectionID(header) to NewFault() to give each injection a correlation ID, so that a single
+injected failure can be found in client logs, server logs, and APM. Read the ID with
+Fault.InjectionID() in downstream handlers, from Event.InjectionID in an EventReporter such as the
+AuditReporter, or, if header is not empty, from that response header on the client. Faults in a
+FaultGroup use the correlation ID of the group.
+
 # Custom Injectors
 
 The fault package provides an Injector interface and you can satisfy that interface to provide your
 own Injector. Use custom injectors to add additional logic to the package-provided injectors or to
 create your own completely new Injector that can still be managed by a Fault.
 
+Custom injectors that need to know how a Fault decided to run them can implement the optional
+InjectorV2 interface. A Fault calls InjectorV2.ServeInjection() with an InjectionContext that holds
+the name of the Fault, the Trace of its decision, the Reporter passed to NewFault() with
+WithReporter(), and the next handler, so every custom injector reports and annotates the same way.
+Wrap an InjectorV2 with AdaptInjectorV2() to pass it to NewFault() and wrap an existing Injector with
+AdaptInjector() to use it where an InjectorV2 is expected.
+
+	v2 := fault.InjectorV2Func(func(ic fault.InjectionContext, w http.ResponseWriter, r *http.Request) {
+		ic.Report(fault.StateStarted)
+		w.Header().Set("X-Fault", ic.Fault)
+		ic.Next.ServeHTTP(w, r)
+	})
+	f, err := fault.NewFault(fault.AdaptInjectorV2(v2), fault.WithReporter(reporter))
+
+Custom injectors that inspect or modify responses can wrap the ResponseWriter with
+NewResponseRecorderWriter(), which records the status code, bytes written, and optionally the body,
+while passing http.Flusher, http.Hijacker, and http.Pusher through to the wrapped ResponseWriter so
+that streaming and websocket upgrades keep working. Pass WithIntercept() to hold the response until
+ResponseRecorderWriter.Commit() so that it can be replaced with SetBody() first.
+
+Every package Injector that continues a request passes a ResponseWriter that supports http.Flusher,
+http.Hijacker, and http.Pusher to the next handler whenever the original ResponseWriter does, so
+fault middleware does not break streaming responses or websocket upgrades. There are three
+deliberate exceptions: the DowngradeInjector hides http.Pusher, the ResponseInjector ignores
+flushes because it buffers the response, and the duplicates sent by the DuplicateRequestInjector
+write to a ResponseWriter that discards the response.
+
 # Reporter
 
 The package provides a Reporter interface that can be added to Faults and Injectors using the
 WithReporter option. A Reporter will receive events when the state of the Injector changes. For
 example, Reporter.Report(InjectorName, StateStarted) is run at the beginning of all Injectors. The
 Reporter is meant to be provided by the consumer of the package and integrate with services like
-stats and logging. The default Reporter throws away all events.
+stats and logging. The default Reporter throws away all events. Injectors that end a request
+abnormally report StateAborted instead of StateFinished, and also StateErrored if they could not
+inject as configured, so that Reporters can tell abnormal terminations apart. Each state is
+reported separately, so a Reporter may receive the states of one injection in any order. Every
+InjectorState has a String() method, such as "started" or "aborted", for logging.
+
+Reporters are called in their own goroutines, and a Reporter that panics does not take down request
+handling. A Reporter whose reporting can fail, such as one that sends events over the network, can
+also implement ReporterV2. Its ReportV2 method returns an error instead, and its ReportFailed method
+is called with the error, or with an error wrapping ErrReporterPanic if ReportV2 panicked, so that
+failures can be counted or logged elsewhere.
+
+A Reporter that also implements DelayReporter receives the actual delay each time an Injector
+waits, such as the SlowInjector or the LoadLatencyInjector, so that analysis can correlate the size
+of injected delays with how clients behave.
+
+The package provides Reporters that log events to common loggers without adding dependencies.
+NewPrintfReporter() logs to any logger with a Printf method, such as *log.Logger. NewZapReporter()
+and NewKeyValueReporter() log structured events to a *zap.SugaredLogger or any logger with Infow,
+Warnw, and Errorw methods. NewZerologReporter() logs structured events to a *zerolog.Logger. The
+structured Reporters log StateErrored at the error level, StateAborted at the warn level, and every
+other state at the info level.
+
+Wrap any Reporter with NewRateLimitedReporter() to pass at most a number of events per second for
+each name and state, protecting logging backends when a Fault with high participation is enabled on
+a hot endpoint. The number of events dropped in each second is passed, under the name of the
+dropped events, to ReportDropped if the wrapped Reporter is a DroppedReporter, or to the function
+set with WithDroppedFunc(). Drops are summarized by the next event for any name once their second
+has ended, and Flush() summarizes the drops that are still pending, such as before shutdown.
+
+Faults also accept an EventReporter using the WithEventReporter option, which receives an Event
+every time the Fault injects, with the time, the Fault and Injector names, and the request. The
+AuditReporter is an EventReporter that appends one JSON line per injection to an io.Writer, such
+as a RotatingFile that starts a new file when it grows past a size limit.
+
+	rf, err := fault.NewRotatingFile("/var/log/fault-audit.log", 10<<20)
+	ar, err := fault.NewAuditReporter(rf, fault.WithRequestIDHeader("X-Request-Id"))
+	f, err := fault.NewFault(i, fault.WithEventReporter(ar))
+
+The faulttest package provides a thread safe Reporter that records every event it receives, along
+with assertions such as faulttest.Reporter.AssertReported(), for use in your own tests.
+
+Injectors report using the name of their type by default, such as "ErrorInjector". Pass WithName()
+to an Injector to report with a custom name instead, for example to label metrics. The ChainInjector
+reports when the chain starts and finishes in addition to any reporting by the Injectors within it.
 
 # Random Seeds
 
 By default all randomness is seeded with defaultRandSeed(1), the same default as math/rand. This
 helps you reproduce any errors you see when running an Injector. If you prefer, you can also
-customize the seed passing WithRandSeed() to NewFault and NewRandomInjector.
+customize the seed passing WithRandSeed() to NewFault, NewRandomInjector, NewChainInjector, and
+NewFaults.
+
+A Fault draws its random numbers from one source behind a mutex, which concurrent requests wait on.
+Pass WithSplitRand(true) to NewFault to instead derive the random number for each request from the
+seed and a count of requests, which is just as reproducible and never waits.
 
 # Custom Injector Functions
 
@@ -112,8 +614,11 @@ own. For example, modify the SlowInjector function to slow in a rancom distribut
 fixed duration. Be careful when you use these options that your return values fall within the same
 range of values expected by the default functions to avoid panics or other undesirable begavior.
 
-Customize the function a Fault uses to determine participation (default: rand.Float32) by passing
-WithRandFloat32Func() to NewFault().
+Customize the function a Fault or ChainInjector uses to determine participation (default:
+rand.Float32) by passing WithRandFloat32Func() to NewFault() or NewChainInjector().
+
+Customize the function a Fault uses to get the current time (default: time.Now) by passing
+WithNowFunc() to NewFault().
 
 Customize the function a RandomInjector uses to choose which injector to run (default: rand.Intn) by
 passing WithRandIntFunc() to NewRandomInjector().
@@ -125,8 +630,82 @@ to NewSlowInjector().
 
 Configuration for the fault package is done through options passed to NewFault and NewInjector. Once
 a Fault is created its enabled state and participation percentage can be updated with SetEnabled()
-and SetParticipation(). There is no other way to manage configuration for the package. It is up to
-the user of the fault package to manage how the options are generated. Common options are feature
-flags, environment variables, or code changes in deploys.
+and SetParticipation(). SetEnabled() is safe to call while the Fault is serving, and a disabled
+Fault costs a single atomic load per request, so Faults can stay in latency-sensitive services
+permanently. There is no other way to manage configuration for the package. It is up to the user
+of the fault package to manage how the options are generated. Common options are feature flags,
+environment variables, or code changes in deploys.
+
+To decide per request whether a Fault evaluates, for example with a feature flag lookup or a remote
+kill switch, pass WithEnabledFunc() to NewFault(). The Fault evaluates a request only when it is
+enabled and the function returns true. Similarly, pass WithParticipationFunc() to compute the
+participation percentage per request, such as per tenant, per path, or by time of day, instead of
+creating many near-identical Faults.
+
+Faults can also be described declaratively with a Config, for example one decoded from JSON. Use
+Validate() to check a Config, such as in a CI pipeline that gates configuration changes. Validate
+returns every problem at once instead of stopping at the first. NewFaultFromConfig() validates a
+Config and returns the Fault it describes. Durations in JSON are strings such as "1.5s", parsed by
+time.ParseDuration(), or integer numbers of nanoseconds.
+
+To reuse chaos experiments that are already defined for a service mesh or proxy, ParseHTTPChaos()
+converts a Chaos Mesh HTTPChaos object into a Config for each of its actions, and ParseIstioFault()
+converts the delay and abort of an Istio HTTPFaultInjection, so that app-level and mesh-level
+experiments share one definition. ParseIstioFault() accepts YAML or JSON, and ParseHTTPChaos()
+accepts JSON, so convert its YAML with a tool such as yq first.
+ParseToxics() and MarshalToxics() convert between Configs and Toxiproxy latency, timeout,
+bandwidth, and slicer toxics, for teams replacing an external proxy with in-process Faults.
+Settings that have no equivalent in the package return an error that wraps ErrUnsupported rather
+than being dropped. Check the returned Configs with Validate() like any other Config.
+
+Invalid options and constructor arguments return an *OptionError that records the name of the
+option and the invalid value, and wraps an error such as ErrInvalidPercent. Use errors.As() to
+report exactly which setting was invalid and errors.Is() to check the reason. When more than one
+option is invalid, the error joins an *OptionError for each of them (see errors.Join), so that every
+problem in a configuration is reported at once.
+
+Faults and all package Injectors implement fmt.Stringer and describe their configuration, such as
+"ErrorInjector(503) @ 5% on /api, blocklist=/health". Use this to show what is actually configured in
+debug logs and admin pages.
+
+# Registry
+
+A Registry holds named Faults so that they can be inspected together. Add Faults with Register() or
+replace all of them from a list of Config with Load(), such as after reloading a configuration file.
+Each Fault counts the requests it injected and skipped in its Stats(). Registry.StatusHandler()
+returns a read-only http.Handler that reports the number of Faults, which are enabled, their Stats,
+and when the Registry was last loaded as JSON, for wiring into existing ops dashboards. Pass
+WithRouteStats(limit) to NewFault() to also break down the Stats by route pattern or path, so that
+experiment reports show which endpoints absorbed the injected faults. Routes beyond the limit are
+counted together under OtherRoute. Pass WithDelayStats(fault.DefaultDelayBuckets()) to count the
+delays injected by Injectors such as the SlowInjector in a histogram in the Stats, to confirm that
+the injected latency matches what you configured.
+
+Large services can delegate control of their Faults to each team with Registry.Scope(), which
+returns a Registry scoped under another, such as "payments". Faults registered in a scope are part
+of its parent under names prefixed with the scope, such as "payments/a", so one StatusHandler shows
+every team's Faults. Call SetEnabled(false) on a scope to stop all of its Faults at once, and pass
+WithCoordinator() to Scope() to give the scope a shared budget, such as a CounterCoordinator with
+WithBudgetKey().
+
+Registry.Subscribe() calls a function with a read-only Snapshot of the Faults each time they are
+registered, loaded, enabled, disabled, or have their participation set, so that dashboards and
+config exporters can follow runtime changes without polling. Registry.Snapshot() returns the
+current Snapshot.
+
+Registry.Toggle() disables every Fault if any are enabled and otherwise enables them all. In
+environments without an admin port, Registry.ToggleOnSignal(syscall.SIGUSR2) toggles the Faults
+each time the process receives the signal, such as from kill -USR2.
+
+To report on a game day, create an Experiment for the Registry with NewExperiment() and run each
+stage with Experiment.Stage(). The ExperimentReport from Experiment.Report() records how long each
+stage ran and how many requests each Fault evaluated and injected during it. To also count the
+events of each Injector, such as the Injectors in a ChainInjector or RandomInjector, pass
+Experiment.Reporter() to the Injectors with WithReporter(). It encodes to JSON, or to CSV with
+WriteCSV(), so that results can be attached to postmortems without scraping logs.
+
+Call Registry.Drain() during graceful shutdown, before http.Server.Shutdown(). Drain disables every
+Fault and waits for injections in progress, such as a SlowInjector that is still waiting, to finish
+so that injected faults do not slow down shutdowns and deployment rollouts.
 */
 package fault
@@ -0,0 +1,50 @@
+package fault
+
+import (
+	"context"
+	"time"
+)
+
+// drainPollInterval is how often Drain checks for injections in progress.
+const drainPollInterval = 10 * time.Millisecond
+
+// Drain disables every Fault in the Registry and then waits until none of them are running an
+// Injector, such as a SlowInjector that is still waiting, or until ctx is done. Call Drain before
+// http.Server.Shutdown so that injected faults do not interfere with graceful shutdown and
+// deployment rollouts. Drain returns ctx.Err() if ctx is done first, and the Faults stay disabled.
+func (r *Registry) Drain(ctx context.Context) error {
+	faults := r.Faults()
+	for _, f := range faults {
+		f.setEnabled(false)
+	}
+
+	tick, stop := r.drainTickF()
+	defer stop()
+
+	for injecting(faults) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+		}
+	}
+
+	return nil
+}
+
+// drainTicker returns a channel that receives every drainPollInterval and a function that stops it.
+func drainTicker() (<-chan time.Time, func()) {
+	ticker := time.NewTicker(drainPollInterval)
+	return ticker.C, ticker.Stop
+}
+
+// injecting returns true if any of faults is running its Injector.
+func injecting(faults []*Fault) bool {
+	for _, f := range faults {
+		if f.injecting.Load() > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testInjectorBlock is an injector that blocks until released.
+type testInjectorBlock struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+// Handler signals that it started and blocks until released before continuing.
+func (i *testInjectorBlock) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i.started <- struct{}{}
+		<-i.release
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TestRegistryDrain tests Registry.Drain.
+func TestRegistryDrain(t *testing.T) {
+	t.Parallel()
+
+	bi := &testInjectorBlock{started: make(chan struct{}), release: make(chan struct{})}
+	f, err := NewFault(bi, WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(f, testRegistryFault(t, "idle")))
+
+	// Drain checks for injections in progress each time the test ticks
+	tick := make(chan time.Time)
+	reg.drainTickF = func() (<-chan time.Time, func()) { return tick, func() {} }
+
+	done := make(chan struct{})
+	go func() {
+		testRequest(t, f)
+		close(done)
+	}()
+	<-bi.started
+
+	// the injection is still running when ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, reg.Drain(ctx))
+	assert.False(t, f.enabled.Load())
+
+	drained := make(chan error)
+	go func() {
+		drained <- reg.Drain(context.Background())
+	}()
+
+	// Drain keeps waiting while the injection is still running
+	tick <- time.Time{}
+	tick <- time.Time{}
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned %v while injecting", err)
+	default:
+	}
+
+	// Drain returns at its next check once the injection finishes
+	close(bi.release)
+	<-done
+	select {
+	case tick <- time.Time{}:
+		assert.NoError(t, <-drained)
+	case err := <-drained:
+		assert.NoError(t, err)
+	}
+
+	// disabled Faults no longer inject
+	testRequest(t, f)
+	assert.Equal(t, Stats{Injected: 1}, f.Stats())
+}
+
+// TestRegistryDrainTicker tests that Registry.Drain polls with its default ticker until the
+// injections in progress finish.
+func TestRegistryDrainTicker(t *testing.T) {
+	t.Parallel()
+
+	bi := &testInjectorBlock{started: make(chan struct{}), release: make(chan struct{})}
+	f, err := NewFault(bi, WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(f))
+
+	go testRequest(t, f)
+	<-bi.started
+
+	drained := make(chan error)
+	go func() {
+		drained <- reg.Drain(context.Background())
+	}()
+
+	close(bi.release)
+	assert.NoError(t, <-drained)
+	assert.NoError(t, reg.Drain(context.Background()))
+}
@@ -0,0 +1,72 @@
+package fault
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OptionError is returned when an option or a constructor argument is invalid. It records which
+// setting was invalid and wraps the error describing the problem, such as ErrInvalidPercent, so
+// errors.Is and errors.As can be used to inspect it.
+type OptionError struct {
+	// Option is the name of the option or constructor that received Value, such as
+	// "WithParticipation".
+	Option string
+	// Value is the invalid value.
+	Value any
+	// Err describes why Value is invalid.
+	Err error
+}
+
+// Error returns the option, value, and problem, such as "WithParticipation(100): percent must be
+// 0.0 <= percent <= 1.0".
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("%s(%v): %v", e.Option, e.Value, e.Err)
+}
+
+// Unwrap returns e.Err.
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// applyOptions applies every option in opts to t with apply. It returns nil if every option is
+// valid, the error if one option is not, or an error that joins the errors of every invalid option
+// so that they can all be fixed at once.
+func applyOptions[O, T any](opts []O, apply func(O, T) error, t T) error {
+	var errs []error
+	for _, opt := range opts {
+		err := apply(opt, t)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// joinErrors returns nil if errs is empty, the error if errs has one error, or an error that joins
+// every error in errs.
+func joinErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// splitErrors returns the errors joined in err, or nil if err is nil.
+func splitErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, splitErrors(e)...)
+	}
+	return errs
+}
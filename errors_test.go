@@ -0,0 +1,45 @@
+package fault
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOptionError tests OptionError.
+func TestOptionError(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFault(newTestInjectorNoop(), WithParticipation(100.0))
+
+	var oErr *OptionError
+	assert.True(t, errors.As(err, &oErr))
+	assert.Equal(t, "WithParticipation", oErr.Option)
+	assert.Equal(t, float32(100.0), oErr.Value)
+	assert.ErrorIs(t, err, ErrInvalidPercent)
+	assert.EqualError(t, err, "WithParticipation(100): percent must be 0.0 <= percent <= 1.0")
+}
+
+// TestOptionErrorJoined tests that every invalid option is reported.
+func TestOptionErrorJoined(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithParticipation(2.0),
+		WithEnabled(true),
+		WithEveryNth(0),
+		WithEnabledFunc(nil),
+	)
+	assert.Nil(t, f)
+	assert.ErrorIs(t, err, ErrInvalidPercent)
+	assert.ErrorIs(t, err, ErrInvalidCount)
+	assert.ErrorIs(t, err, ErrNilFunc)
+	assert.EqualError(t, err, "WithParticipation(2): percent must be 0.0 <= percent <= 1.0\n"+
+		"WithEveryNth(0): count must be greater than 0\n"+
+		"WithEnabledFunc(<nil>): function cannot be nil")
+
+	var joined interface{ Unwrap() []error }
+	assert.True(t, errors.As(err, &joined))
+	assert.Len(t, joined.Unwrap(), 3)
+}
@@ -0,0 +1,77 @@
+package fault
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNilReporter when a nil Reporter or EventReporter is provided.
+	ErrNilReporter = errors.New("reporter cannot be nil")
+)
+
+// Event describes a Fault injecting into a request, or with WithDebugTrace deciding not to.
+type Event struct {
+	// Time is when the Fault decided to inject.
+	Time time.Time
+	// Fault is the name of the Fault.
+	Fault string
+	// Injector describes the Injector, see Fault.String.
+	Injector string
+	// InjectionID, if the Fault has WithInjectionID and injected, is the correlation ID of the
+	// injection.
+	InjectionID string
+	// Request is the request the Fault injected into. EventReporters must not modify it.
+	Request *http.Request
+	// Trace, if the Fault has WithDebugTrace, records how the Fault decided whether to inject.
+	Trace *Trace
+}
+
+// EventReporter receives an Event each time a Fault injects into a request, and for every other
+// request the Fault handles if it has WithDebugTrace. Unlike a Reporter, an
+// EventReporter is called synchronously and receives the request, so it can record details such as
+// the path.
+type EventReporter interface {
+	ReportEvent(e Event)
+}
+
+type eventReporterOption struct {
+	reporter EventReporter
+}
+
+func (o eventReporterOption) applyFault(f *Fault) error {
+	if o.reporter == nil {
+		return &OptionError{Option: "WithEventReporter", Value: nil, Err: ErrNilReporter}
+	}
+	f.eventReporter = o.reporter
+	return nil
+}
+
+// WithEventReporter sets an EventReporter that receives an Event each time the Fault injects into
+// a request, such as an AuditReporter.
+func WithEventReporter(r EventReporter) Option {
+	return eventReporterOption{reporter: r}
+}
+
+// reportEvent sends an Event for r to the EventReporter, if there is one, including t if the Fault
+// has WithDebugTrace.
+func (f *Fault) reportEvent(r *http.Request, t Trace) {
+	if f.eventReporter == nil {
+		return
+	}
+
+	e := Event{
+		Time:        f.nowF(),
+		Fault:       f.name,
+		Injector:    injectorString(f.injector),
+		InjectionID: f.InjectionID(r.Context()),
+		Request:     r,
+	}
+	if f.debugTrace {
+		trace := t
+		e.Trace = &trace
+	}
+
+	f.eventReporter.ReportEvent(e)
+}
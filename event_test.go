@@ -0,0 +1,80 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testEventReporter records every Event it receives.
+type testEventReporter struct {
+	events []Event
+}
+
+// ReportEvent records e.
+func (r *testEventReporter) ReportEvent(e Event) {
+	r.events = append(r.events, e)
+}
+
+// TestWithEventReporter tests WithEventReporter.
+func TestWithEventReporter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100, 0)
+
+	tests := []struct {
+		name              string
+		giveParticipation float32
+		wantEvents        int
+	}{
+		{
+			name:              "injected",
+			giveParticipation: 1.0,
+			wantEvents:        1,
+		},
+		{
+			name:              "skipped",
+			giveParticipation: 0.0,
+			wantEvents:        0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			er := &testEventReporter{}
+			f, err := NewFault(newTestInjector500s(),
+				WithEnabled(true),
+				WithParticipation(tt.giveParticipation),
+				WithEventReporter(er),
+				WithName("custom"),
+				WithNowFunc(func() time.Time { return now }),
+			)
+			assert.NoError(t, err)
+
+			testRequest(t, f)
+
+			assert.Len(t, er.events, tt.wantEvents)
+			for _, e := range er.events {
+				assert.Equal(t, now, e.Time)
+				assert.Equal(t, "custom", e.Fault)
+				assert.Equal(t, "testInjector500s", e.Injector)
+				assert.Equal(t, http.MethodGet, e.Request.Method)
+				assert.Equal(t, testHeaderVal, e.Request.Header.Get(testHeaderKey))
+			}
+		})
+	}
+}
+
+// TestWithEventReporterNil tests WithEventReporter with a nil EventReporter.
+func TestWithEventReporterNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithEventReporter(nil))
+
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithEventReporter", Value: nil, Err: ErrNilReporter}, err)
+}
@@ -1,6 +1,8 @@
 package fault_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -103,3 +105,152 @@ func ExampleNewSlowInjector() {
 	fmt.Print(err)
 	// Output: <nil>
 }
+
+// ExampleDo shows how to inject a Fault into a function.
+func ExampleDo() {
+	ei, err := fault.NewErrorInjector(http.StatusInternalServerError)
+	fmt.Print(err)
+
+	f, err := fault.NewFault(ei,
+		fault.WithEnabled(true),
+		fault.WithParticipation(1.0),
+	)
+	fmt.Print(err)
+
+	err = fault.Do(context.Background(), f, func(ctx context.Context) error {
+		return nil
+	})
+
+	fmt.Print(errors.Is(err, fault.ErrInjected))
+	// Output: <nil><nil>true
+}
+
+// ExampleMessageMiddleware shows how to inject a Fault into a message handler.
+func ExampleMessageMiddleware() {
+	ri, err := fault.NewRejectInjector()
+	fmt.Print(err)
+
+	f, err := fault.NewFault(ri,
+		fault.WithEnabled(true),
+		fault.WithParticipation(1.0),
+	)
+	fmt.Print(err)
+
+	handler := fault.MessageMiddleware[string](f)(func(ctx context.Context, msg string) error {
+		fmt.Print(msg)
+		return nil
+	})
+
+	// The message is dropped and the handler does not run
+	err = handler(context.Background(), "message")
+
+	fmt.Print(err)
+	// Output: <nil><nil><nil>
+}
+
+// ExampleNewResponseInjector shows how to create a new ResponseInjector.
+func ExampleNewResponseInjector() {
+	ei, err := fault.NewErrorInjector(http.StatusInternalServerError)
+	fmt.Print(err)
+
+	// Only replace 200s with the error
+	_, err = fault.NewResponseInjector(ei, func(code int, header http.Header) bool {
+		return code == http.StatusOK
+	})
+
+	fmt.Print(err)
+	// Output: <nil><nil>
+}
+
+// ExampleNewConditionalInjector shows how to create a new ConditionalInjector.
+func ExampleNewConditionalInjector() {
+	ei, err := fault.NewErrorInjector(http.StatusInternalServerError)
+	fmt.Print(err)
+	si, err := fault.NewSlowInjector(time.Second)
+	fmt.Print(err)
+
+	// Return errors to POST requests and slow all other requests
+	_, err = fault.NewConditionalInjector(func(r *http.Request) bool {
+		return r.Method == http.MethodPost
+	}, ei, si)
+
+	fmt.Print(err)
+	// Output: <nil><nil><nil>
+}
+
+// ExampleNewSequenceInjector shows how to create a new SequenceInjector.
+func ExampleNewSequenceInjector() {
+	ei, err := fault.NewErrorInjector(http.StatusServiceUnavailable)
+	fmt.Print(err)
+	si, err := fault.NewSlowInjector(time.Second * 2)
+	fmt.Print(err)
+
+	// Return a 503, then wait 2s, then let the third request pass
+	_, err = fault.NewSequenceInjector([]fault.Injector{ei, si, nil})
+
+	fmt.Print(err)
+	// Output: <nil><nil><nil>
+}
+
+// ExampleChain shows how to combine many independent Faults.
+func ExampleChain() {
+	ei, err := fault.NewErrorInjector(http.StatusInternalServerError)
+	fmt.Print(err)
+	ri, err := fault.NewRejectInjector()
+	fmt.Print(err)
+
+	ef, err := fault.NewFault(ei,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.01),
+	)
+	fmt.Print(err)
+	rf, err := fault.NewFault(ri,
+		fault.WithEnabled(true),
+		fault.WithParticipation(0.01),
+	)
+	fmt.Print(err)
+
+	mainHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "OK", http.StatusOK)
+	})
+
+	// Return an error to 1% of requests and reject another 1% of requests
+	_ = fault.Chain(mainHandler, ef, rf)
+
+	// Output: <nil><nil><nil><nil>
+}
+
+// ExampleNewCPUInjector shows how to create a new CPUInjector.
+func ExampleNewCPUInjector() {
+	_, err := fault.NewCPUInjector(time.Millisecond*100, fault.WithCPUWorkers(2))
+
+	fmt.Print(err)
+	// Output: <nil>
+}
+
+// ExampleNewPanicInjector shows how to create a new PanicInjector.
+func ExampleNewPanicInjector() {
+	_, err := fault.NewPanicInjector(fault.WithPanicValue("injected panic"))
+
+	fmt.Print(err)
+	// Output: <nil>
+}
+
+// ExampleValidate shows how to check a Config for problems.
+func ExampleValidate() {
+	errs := fault.Validate(fault.Config{
+		Enabled:       true,
+		Participation: 5.0,
+		Injector: &fault.InjectorConfig{
+			Type:       fault.InjectorTypeError,
+			StatusCode: 999,
+		},
+	})
+
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	// Output:
+	// WithParticipation(5): percent must be 0.0 <= percent <= 1.0
+	// injector error: NewErrorInjector(999): not a valid http status code
+}
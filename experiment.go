@@ -0,0 +1,261 @@
+package fault
+
+import (
+	"encoding/csv"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Experiment records how the Faults in a Registry behaved during each stage of an experiment, such
+// as a game day, and summarizes them in an ExperimentReport.
+//
+// Pass the Reporter returned by Experiment.Reporter to the Injectors of the Faults with WithReporter
+// to count the events of each Injector during each stage, keyed by the name the Injector reports
+// under, so that the report shows which Injector of a ChainInjector or RandomInjector produced each
+// outcome.
+type Experiment struct {
+	registry *Registry
+	nowF     func() time.Time
+
+	// stages are the recorded stages in the order they finished.
+	stages []StageReport
+	// injectors counts the events of each Injector during the current stage, or is nil between
+	// stages.
+	injectors map[string]*InjectorStageReport
+	// mtx protects stages and injectors.
+	mtx sync.Mutex
+}
+
+// ExperimentOption configures an Experiment.
+type ExperimentOption interface {
+	applyExperiment(e *Experiment) error
+}
+
+func (o nowFuncOption) applyExperiment(e *Experiment) error {
+	e.nowF = o
+	return nil
+}
+
+// ExperimentReport summarizes the stages of an Experiment. It encodes to JSON with encoding/json
+// and to CSV with WriteCSV.
+type ExperimentReport struct {
+	// Stages are the stages of the Experiment in the order they finished.
+	Stages []StageReport `json:"stages"`
+}
+
+// StageReport summarizes one stage of an Experiment.
+type StageReport struct {
+	// Name is the name of the stage.
+	Name string `json:"name"`
+	// Start is when the stage started.
+	Start time.Time `json:"start"`
+	// Duration is how long the stage ran, in nanoseconds in JSON.
+	Duration time.Duration `json:"duration"`
+	// Evaluated is the number of requests the Faults evaluated during the stage.
+	Evaluated uint64 `json:"evaluated"`
+	// Injected is the number of requests the Faults injected during the stage.
+	Injected uint64 `json:"injected"`
+	// Faults breaks down the counts by Fault, in the order the Faults were registered.
+	Faults []FaultStageReport `json:"faults"`
+	// Injectors counts the events that each Injector reported to the Reporter of the Experiment
+	// during the stage, ordered by name.
+	Injectors []InjectorStageReport `json:"injectors"`
+}
+
+// FaultStageReport counts the requests that one Fault evaluated during a stage.
+type FaultStageReport struct {
+	// Name is the name of the Fault.
+	Name string `json:"name"`
+	// Evaluated is the number of requests the Fault evaluated during the stage.
+	Evaluated uint64 `json:"evaluated"`
+	// Injected is the number of requests the Fault injected during the stage.
+	Injected uint64 `json:"injected"`
+}
+
+// InjectorStageReport counts the events that one Injector reported during a stage.
+type InjectorStageReport struct {
+	// Name is the name the Injector reported under.
+	Name string `json:"name"`
+	// Started is the number of StateStarted events, which is how many times the Injector ran.
+	Started uint64 `json:"started"`
+	// Finished is the number of StateFinished events.
+	Finished uint64 `json:"finished"`
+	// Skipped is the number of StateSkipped events.
+	Skipped uint64 `json:"skipped"`
+	// Errored is the number of StateErrored events.
+	Errored uint64 `json:"errored"`
+	// Aborted is the number of StateAborted events.
+	Aborted uint64 `json:"aborted"`
+}
+
+// NewExperiment returns an Experiment that records the Faults in reg.
+func NewExperiment(reg *Registry, opts ...ExperimentOption) (*Experiment, error) {
+	if reg == nil {
+		return nil, &OptionError{Option: "NewExperiment", Value: nil, Err: ErrNilRegistry}
+	}
+
+	// set defaults
+	e := &Experiment{
+		registry: reg,
+		nowF:     time.Now,
+	}
+
+	// apply options
+	err := applyOptions(opts, ExperimentOption.applyExperiment, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Stage runs f as a stage named name and records the requests that each Fault in the Registry
+// evaluated and injected, and the events each Injector reported to the Experiment, until f
+// returns. Use f to set up the stage and wait for it to finish, for example by enabling a Fault
+// with SetParticipation and sleeping while load is applied. Injectors report in the background, so
+// wait in f for the requests of the stage to finish. Stages run one after another, so do not call
+// Stage from f.
+func (e *Experiment) Stage(name string, f func()) StageReport {
+	start := e.nowF()
+	before := make(map[*Fault]Stats)
+	for _, entry := range e.registry.entries() {
+		before[entry.fault] = entry.fault.Stats()
+	}
+
+	e.mtx.Lock()
+	e.injectors = make(map[string]*InjectorStageReport)
+	e.mtx.Unlock()
+
+	f()
+
+	s := StageReport{
+		Name:      name,
+		Start:     start,
+		Faults:    []FaultStageReport{},
+		Injectors: []InjectorStageReport{},
+	}
+	for _, entry := range e.registry.entries() {
+		flt := entry.fault
+		after := flt.Stats()
+		fs := FaultStageReport{
+			Name:      entry.name,
+			Evaluated: after.Injected + after.Skipped - before[flt].Injected - before[flt].Skipped,
+			Injected:  after.Injected - before[flt].Injected,
+		}
+		s.Evaluated += fs.Evaluated
+		s.Injected += fs.Injected
+		s.Faults = append(s.Faults, fs)
+	}
+	s.Duration = e.nowF().Sub(start)
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	for _, name := range slices.Sorted(maps.Keys(e.injectors)) {
+		s.Injectors = append(s.Injectors, *e.injectors[name])
+	}
+	e.injectors = nil
+
+	e.stages = append(e.stages, s)
+	return s
+}
+
+// Reporter returns a Reporter that counts the events of each Injector during the stages of the
+// Experiment and passes every event to next, if it is not nil. Pass it to Injectors with
+// WithReporter, wrapping the Reporter they would otherwise use.
+func (e *Experiment) Reporter(next Reporter) Reporter {
+	return &experimentReporter{experiment: e, next: next}
+}
+
+// experimentReporter is the Reporter returned by Experiment.Reporter.
+type experimentReporter struct {
+	experiment *Experiment
+	next       Reporter
+}
+
+// Report counts the event for the Injector named name if a stage is running, and passes it to the
+// next Reporter.
+func (r *experimentReporter) Report(name string, state InjectorState) {
+	r.experiment.count(name, state)
+	if r.next != nil {
+		report(r.next, name, state)
+	}
+}
+
+// count counts the event for the Injector named name if a stage is running.
+func (e *Experiment) count(name string, state InjectorState) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.injectors == nil {
+		return
+	}
+
+	is, ok := e.injectors[name]
+	if !ok {
+		is = &InjectorStageReport{Name: name}
+		e.injectors[name] = is
+	}
+	switch state {
+	case StateStarted:
+		is.Started++
+	case StateFinished:
+		is.Finished++
+	case StateSkipped:
+		is.Skipped++
+	case StateErrored:
+		is.Errored++
+	case StateAborted:
+		is.Aborted++
+	}
+}
+
+// Report returns an ExperimentReport of the stages recorded so far.
+func (e *Experiment) Report() ExperimentReport {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	return ExperimentReport{Stages: append([]StageReport{}, e.stages...)}
+}
+
+// WriteCSV writes the report to w as CSV with a header row. Each stage has a row with its totals
+// and empty fault and injector columns, followed by a row for each Fault and then a row for each
+// Injector, so that results can be attached to a postmortem or opened in a spreadsheet. Injector
+// rows count the times the Injector started as injected and also started or skipped as evaluated,
+// and only they fill the errored and aborted columns.
+func (r ExperimentReport) WriteCSV(w io.Writer) error {
+	records := [][]string{{
+		"stage", "start", "duration_seconds", "fault", "injector", "evaluated", "injected", "errored", "aborted",
+	}}
+	for _, s := range r.Stages {
+		records = append(records, s.record("", "", s.Evaluated, s.Injected, "", ""))
+		for _, f := range s.Faults {
+			records = append(records, s.record(f.Name, "", f.Evaluated, f.Injected, "", ""))
+		}
+		for _, i := range s.Injectors {
+			records = append(records, s.record("", i.Name, i.Started+i.Skipped, i.Started,
+				strconv.FormatUint(i.Errored, 10), strconv.FormatUint(i.Aborted, 10)))
+		}
+	}
+
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+// record returns a CSV record for the stage with the counts of a fault or injector.
+func (s StageReport) record(fault, injector string, evaluated, injected uint64, errored, aborted string) []string {
+	return []string{
+		s.Name,
+		s.Start.Format(time.RFC3339Nano),
+		strconv.FormatFloat(s.Duration.Seconds(), 'f', -1, 64),
+		fault,
+		injector,
+		strconv.FormatUint(evaluated, 10),
+		strconv.FormatUint(injected, 10),
+		errored,
+		aborted,
+	}
+}
@@ -0,0 +1,212 @@
+package fault
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewExperiment tests NewExperiment.
+func TestNewExperiment(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	e, err := NewExperiment(reg, WithNowFunc(time.Now))
+	assert.NoError(t, err)
+	assert.NotNil(t, e.nowF)
+	assert.Empty(t, e.Report().Stages)
+
+	e, err = NewExperiment(nil)
+	assert.Nil(t, e)
+	assert.Equal(t, &OptionError{Option: "NewExperiment", Value: nil, Err: ErrNilRegistry}, err)
+
+	e, err = NewExperiment(reg, withError())
+	assert.Nil(t, e)
+	assert.Equal(t, errErrorOption, err)
+}
+
+// TestExperimentStage tests that Experiment.Stage counts only the requests during the stage.
+func TestExperimentStage(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	injected, err := NewFault(newTestInjectorNoop(), WithEnabled(true), WithParticipation(1.0), WithName("a"))
+	assert.NoError(t, err)
+	skipped := testRegistryFault(t, "b")
+	assert.NoError(t, reg.Register(injected, skipped))
+
+	e, err := NewExperiment(reg, WithNowFunc(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}))
+	assert.NoError(t, err)
+
+	// requests before the stage are not counted
+	testRequest(t, injected)
+
+	s := e.Stage("baseline", func() {
+		testRequest(t, injected)
+		testRequest(t, injected)
+		testRequest(t, skipped)
+	})
+
+	want := StageReport{
+		Name:      "baseline",
+		Start:     time.Date(2020, 1, 1, 0, 0, 1, 0, time.UTC),
+		Duration:  time.Second,
+		Evaluated: 3,
+		Injected:  2,
+		Faults: []FaultStageReport{
+			{Name: "a", Evaluated: 2, Injected: 2},
+			{Name: "b", Evaluated: 1, Injected: 0},
+		},
+		Injectors: []InjectorStageReport{},
+	}
+	assert.Equal(t, want, s)
+
+	e.Stage("idle", func() {})
+	r := e.Report()
+	assert.Len(t, r.Stages, 2)
+	assert.Equal(t, want, r.Stages[0])
+	assert.Equal(t, uint64(0), r.Stages[1].Evaluated)
+
+	data, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `{"name":"a","evaluated":2,"injected":2}`)
+}
+
+// TestExperimentReporter tests that Experiment.Stage counts the events reported to the Reporter of
+// the Experiment by each Injector during the stage.
+func TestExperimentReporter(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	e, err := NewExperiment(reg)
+	assert.NoError(t, err)
+
+	next := &testChanReporter{states: make(chan string, 100)}
+	rep := e.Reporter(next)
+	header, err := NewRequestHeaderInjector(WithReporter(rep), WithName("header"))
+	assert.NoError(t, err)
+	errInjector, err := NewErrorInjector(http.StatusInternalServerError, WithReporter(rep), WithName("error"))
+	assert.NoError(t, err)
+	chain, err := NewChainInjector([]Injector{header, errInjector})
+	assert.NoError(t, err)
+	f, err := NewFault(chain, WithEnabled(true), WithParticipation(1.0), WithName("chain"))
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(f))
+
+	// events outside of a stage are passed on but not counted
+	rep.Report("header", StateSkipped)
+	assert.Equal(t, "header skipped", <-next.states)
+
+	s := e.Stage("chain", func() {
+		testRequest(t, f)
+		testRequest(t, f)
+		rep.Report("error", StateErrored)
+		rep.Report("error", StateAborted)
+
+		// wait for the events that the Injectors report in the background
+		for range 10 {
+			<-next.states
+		}
+	})
+
+	assert.Equal(t, []FaultStageReport{{Name: "chain", Evaluated: 2, Injected: 2}}, s.Faults)
+	assert.Equal(t, []InjectorStageReport{
+		{Name: "error", Started: 2, Finished: 2, Errored: 1, Aborted: 1},
+		{Name: "header", Started: 2, Finished: 2},
+	}, s.Injectors)
+
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data),
+		`{"name":"header","started":2,"finished":2,"skipped":0,"errored":0,"aborted":0}`)
+
+	// a nil next Reporter only counts
+	assert.NotPanics(t, func() { e.Reporter(nil).Report("header", StateStarted) })
+}
+
+// TestExperimentReporterSkipped tests that Experiment.Stage counts the requests that a Fault skips
+// and the StateSkipped events of its Injector, and that both appear in the JSON and CSV reports.
+func TestExperimentReporterSkipped(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	e, err := NewExperiment(reg, WithNowFunc(func() time.Time { return start }))
+	assert.NoError(t, err)
+
+	next := &testChanReporter{states: make(chan string, 100)}
+	di, err := NewDuplicateRequestInjector(WithMaxDuplicateBody(1), WithReporter(e.Reporter(next)),
+		WithName("duplicate"))
+	assert.NoError(t, err)
+	f, err := NewFault(di, WithEnabled(true), WithParticipation(1.0), WithName("dup"),
+		WithPathBlocklist([]string{"/skip"}))
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(f))
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s := e.Stage("skip", func() {
+		// the Fault skips the blocklisted request and the Injector skips the large body
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/skip", strings.NewReader("large")))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("large")))
+
+		// wait for the event that the Injector reports in the background
+		assert.Equal(t, "duplicate skipped", <-next.states)
+	})
+
+	assert.Equal(t, []FaultStageReport{{Name: "dup", Evaluated: 2, Injected: 1}}, s.Faults)
+	assert.Equal(t, []InjectorStageReport{{Name: "duplicate", Skipped: 1}}, s.Injectors)
+
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data),
+		`{"name":"duplicate","started":0,"finished":0,"skipped":1,"errored":0,"aborted":0}`)
+
+	var buf bytes.Buffer
+	assert.NoError(t, e.Report().WriteCSV(&buf))
+	assert.Equal(t, "stage,start,duration_seconds,fault,injector,evaluated,injected,errored,aborted\n"+
+		"skip,2020-01-01T00:00:00Z,0,,,2,1,,\n"+
+		"skip,2020-01-01T00:00:00Z,0,dup,,2,1,,\n"+
+		"skip,2020-01-01T00:00:00Z,0,,duplicate,1,0,0,0\n", buf.String())
+}
+
+// TestExperimentReportWriteCSV tests ExperimentReport.WriteCSV.
+func TestExperimentReportWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	r := ExperimentReport{Stages: []StageReport{
+		{
+			Name:      "ramp",
+			Start:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Duration:  1500 * time.Millisecond,
+			Evaluated: 10,
+			Injected:  4,
+			Faults:    []FaultStageReport{{Name: "a", Evaluated: 10, Injected: 4}},
+			Injectors: []InjectorStageReport{{Name: "slow", Started: 4, Finished: 3, Skipped: 1, Aborted: 1}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteCSV(&buf))
+	assert.Equal(t, "stage,start,duration_seconds,fault,injector,evaluated,injected,errored,aborted\n"+
+		"ramp,2020-01-01T00:00:00Z,1.5,,,10,4,,\n"+
+		"ramp,2020-01-01T00:00:00Z,1.5,a,,10,4,,\n"+
+		"ramp,2020-01-01T00:00:00Z,1.5,,slow,5,4,0,1\n", buf.String())
+
+	assert.Equal(t, errTestWrite, r.WriteCSV(testErrWriter{}))
+}
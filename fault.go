@@ -5,6 +5,8 @@ import (
 	"math/rand"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -12,23 +14,165 @@ const (
 	defaultRandSeed = 1
 )
 
+// processStart is approximately when the process started, which is when the package was initialized.
+var processStart = time.Now() //nolint:gochecknoglobals // must be captured once when the process starts
+
 var (
 	// ErrNilInjector when a nil Injector is passed.
 	ErrNilInjector = errors.New("injector cannot be nil")
+	// ErrNilFunc when a nil function is passed.
+	ErrNilFunc = errors.New("function cannot be nil")
 	// ErrInvalidPercent when a percent is outside of [0.0,1.0).
 	ErrInvalidPercent = errors.New("percent must be 0.0 <= percent <= 1.0")
+	// ErrInvalidCount when a count is less than 1.
+	ErrInvalidCount = errors.New("count must be greater than 0")
+	// ErrInvalidDuration when a duration is less than or equal to 0.
+	ErrInvalidDuration = errors.New("duration must be greater than 0")
 )
 
 // Fault combines an Injector with options on when to use that Injector.
 type Fault struct {
-	// enabled determines if the fault should evaluate.
-	enabled bool
+	// enabled determines if the fault should evaluate. It is atomic so that SetEnabled is safe while
+	// the Fault handles requests and a disabled Fault costs a single load per request.
+	enabled atomic.Bool
+	// enabledF, if set, must also return true for the fault to evaluate a request.
+	enabledF func(r *http.Request) bool
 
 	// injector is the Injector that will be injected.
 	injector Injector
-
-	// participation is the percent of requests that run the injector. 0.0 <= p <= 1.0.
-	participation float32
+	// injectorV2, if the injector implements InjectorV2, is run instead of injector.Handler.
+	injectorV2 InjectorV2
+
+	// reporter is passed to an InjectorV2 in its InjectionContext.
+	reporter Reporter
+
+	// name identifies the Fault in request contexts.
+	name string
+	// annotate determines if the Fault records whether it injected in request contexts.
+	annotate bool
+
+	// rate is the participation of the Fault, or nil for 0.0. It is atomic so that
+	// SetParticipation is safe while the Fault handles requests.
+	rate atomic.Pointer[participationRate]
+	// participationF, if set, returns the participation for each request instead.
+	participationF func(r *http.Request) float32
+
+	// group, if set, decides participation instead, sharing its decision with other Faults.
+	group *FaultGroup
+
+	// guards must all allow each injection that participation selects.
+	guards []Guard
+
+	// coordinator, if set, must allow each injection that participation selects.
+	coordinator Coordinator
+
+	// injectionIDs determines if the Fault assigns a correlation ID to each injection, which is also
+	// set in the response header injectionIDHeader if it is not empty.
+	injectionIDs      bool
+	injectionIDHeader string
+	// idF returns a new injection ID.
+	idF func() string
+
+	// eventReporter, if set, receives an Event for each injection.
+	eventReporter EventReporter
+
+	// onInject and onSkip, if set, are called with each request the Fault injects or skips.
+	onInject func(r *http.Request)
+	onSkip   func(r *http.Request)
+
+	// debugTrace determines if the Fault records a Trace of its decision for every request.
+	debugTrace bool
+
+	// everyNth, if set, deterministically runs the injector on every Nth evaluated request instead
+	// of using participation.
+	everyNth uint64
+
+	// burstOn and burstOff, if set, run the injector on burstOn consecutive evaluated requests and
+	// then skip burstOff evaluated requests, repeatedly.
+	burstOn  uint64
+	burstOff uint64
+
+	// burstOnDuration and burstOffDuration, if set, run the injector on all evaluated requests for
+	// burstOnDuration and then skip all evaluated requests for burstOffDuration, repeatedly.
+	burstOnDuration  time.Duration
+	burstOffDuration time.Duration
+
+	// targetRate, if set, runs the injector on targetRate requests per second regardless of traffic.
+	targetRate float64
+	// nextInjection is when the next injection is due while targetRate is set.
+	nextInjection time.Time
+	// rateMtx protects nextInjection.
+	rateMtx sync.Mutex
+
+	// brownoutLow and brownoutHigh, if set, scale participation with the requests in flight.
+	brownoutLow  int64
+	brownoutHigh int64
+
+	// inFlight counts the requests in flight through the Fault while brownoutHigh is set.
+	inFlight atomic.Int64
+
+	// warmupRequests, if set, is the number of requests the Fault handles before it can inject.
+	warmupRequests uint64
+
+	// warmupDuration, if set, is how long after creation the Fault waits before it can inject.
+	warmupDuration time.Duration
+
+	// startupGrace, if set, is how long after the process started the Fault waits before it can
+	// inject.
+	startupGrace time.Duration
+
+	// handled counts the requests handled by the Fault while warming up.
+	handled atomic.Uint64
+
+	// evaluated counts the requests that reached the participation decision.
+	evaluated atomic.Uint64
+
+	// injected and skipped count the requests the Fault injected and evaluated without injecting.
+	injected atomic.Uint64
+	skipped  atomic.Uint64
+
+	// routeStatsLimit, if set, is the number of routes that routeStats counts separately.
+	routeStatsLimit int
+	// routeStats counts the injected and skipped requests by route.
+	routeStats map[string]RouteStats
+	// routeMtx protects routeStats.
+	routeMtx sync.Mutex
+
+	// delayBuckets, if set, are the upper bounds of the buckets that delayCounts counts injected
+	// delays in.
+	delayBuckets []time.Duration
+	// delayCounts counts the injected requests with a delay in each bucket, with one more count for
+	// longer delays.
+	delayCounts []atomic.Uint64
+
+	// registries are the Registries the Fault is registered in, which are notified when it changes.
+	registries []*Registry
+	// bucketing, if set, decides participation instead, injecting into requests in arm.
+	bucketing *Bucketing
+	arm       string
+	// badInstances, if set, decides participation instead, injecting into requests whose key from
+	// instanceKeyF hashes to a virtual instance that is true.
+	badInstances []bool
+	instanceKeyF func(r *http.Request) string
+
+	// registriesMtx protects registries.
+	registriesMtx sync.Mutex
+	// registry is the Registry the Fault was last registered in, whose scope the Fault is in.
+	registry atomic.Pointer[Registry]
+
+	// injecting counts the requests the Injector is running on.
+	injecting atomic.Int64
+
+	// start is when the Fault was created.
+	start time.Time
+
+	// nowF is a function that returns the current time.
+	nowF func() time.Time
+
+	// skipPaths is a map of health check paths that the Injector will never run against.
+	skipPaths map[string]bool
+	// skipPreflight determines if the Injector never runs against CORS preflight requests.
+	skipPreflight bool
 
 	// pathBlocklist is a map of paths that the Injector will never run against.
 	pathBlocklist map[string]bool
@@ -36,12 +180,50 @@ type Fault struct {
 	// pathAllowlist, if set, is a map of the only paths that the Injector will run against.
 	pathAllowlist map[string]bool
 
+	// patternBlocklist is a map of route patterns that the Injector will never run against.
+	patternBlocklist map[string]bool
+
+	// patternAllowlist, if set, is a map of the only route patterns that the Injector will run
+	// against.
+	patternAllowlist map[string]bool
+
+	// uriBlocklist is a map of request URIs that the Injector will never run against.
+	uriBlocklist map[string]bool
+
+	// uriAllowlist, if set, is a map of the only request URIs that the Injector will run against.
+	uriAllowlist map[string]bool
+
+	// uriExact determines if request URIs are compared without normalization.
+	uriExact bool
+
+	// escapedPaths determines if paths are compared as the client escaped them instead of
+	// unescaped.
+	escapedPaths bool
+
+	// patternF is a function that returns the route pattern that matched the request.
+	patternF func(r *http.Request) string
+
 	// headerBlocklist is a map of headers that the Injector will never run against.
 	headerBlocklist map[string]string
 
 	// headerAllowlist, if set, is a map of the only headers the Injector will run against.
 	headerAllowlist map[string]string
 
+	// headerExact determines if header keys are looked up without canonicalization.
+	headerExact bool
+
+	// blocklistBloom determines if the path, pattern, and URI blocklists are checked with the bloom
+	// filters pathBloom, patternBloom, and uriBloom before their maps.
+	blocklistBloom bool
+	pathBloom      *bloomFilter
+	patternBloom   *bloomFilter
+	uriBloom       *bloomFilter
+
+	// headerBlockRules and headerAllowRules are headerBlocklist and headerAllowlist as they are
+	// checked against requests.
+	headerBlockRules []headerRule
+	headerAllowRules []headerRule
+
 	// randSeed is a number to seed rand with.
 	randSeed int64
 
@@ -53,6 +235,13 @@ type Fault struct {
 
 	// randMtx protects Fault.rand, which is not thread safe.
 	randMtx sync.Mutex
+
+	// splitRand determines if participation rolls are derived from randSeed and splitCounter instead
+	// of drawn from rand.
+	splitRand bool
+
+	// splitCounter is the number of participation rolls derived with splitRand.
+	splitCounter atomic.Uint64
 }
 
 // Option configures a Fault.
@@ -63,7 +252,7 @@ type Option interface {
 type enabledOption bool
 
 func (o enabledOption) applyFault(f *Fault) error {
-	f.enabled = bool(o)
+	f.enabled.Store(bool(o))
 	return nil
 }
 
@@ -72,21 +261,191 @@ func WithEnabled(e bool) Option {
 	return enabledOption(e)
 }
 
+type enabledFuncOption func(r *http.Request) bool
+
+func (o enabledFuncOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithEnabledFunc", Value: nil, Err: ErrNilFunc}
+	}
+	f.enabledF = o
+	return nil
+}
+
+// WithEnabledFunc sets a function that decides per request if the Fault should evaluate, such as a
+// feature flag lookup or a remote kill switch. The Fault evaluates a request only if it is enabled
+// (see WithEnabled) and f returns true. Like a disabled Fault, requests for which f returns false
+// are neither injected nor skipped. f is called for every request so it should be fast.
+func WithEnabledFunc(f func(r *http.Request) bool) Option {
+	return enabledFuncOption(f)
+}
+
+// ParticipationOption configures a Fault or a stream wrapper with WithParticipation.
+type ParticipationOption interface {
+	Option
+	StreamOption
+}
+
+// participationRate is the participation of a Fault. It is replaced as a whole so that requests
+// always read a consistent rate.
+type participationRate struct {
+	// p is the percent of requests that run the injector. 0.0 <= p <= 1.0.
+	p float32
+	// k and n, if n is set, run the injector on k in every n requests instead of using p.
+	k int64
+	n int64
+}
+
+// participation returns the participation of the Fault.
+func (f *Fault) participation() participationRate {
+	if rate := f.rate.Load(); rate != nil {
+		return *rate
+	}
+	return participationRate{}
+}
+
 type participationOption float32
 
 func (o participationOption) applyFault(f *Fault) error {
 	if o < 0.0 || o > 1.0 {
-		return ErrInvalidPercent
+		return &OptionError{Option: "WithParticipation", Value: float32(o), Err: ErrInvalidPercent}
 	}
-	f.participation = float32(o)
+	f.rate.Store(&participationRate{p: float32(o)})
 	return nil
 }
 
 // WithParticipation sets the percent of requests that run the Injector. 0.0 <= p <= 1.0.
-func WithParticipation(p float32) Option {
+func WithParticipation(p float32) ParticipationOption {
 	return participationOption(p)
 }
 
+type participationFuncOption func(r *http.Request) float32
+
+func (o participationFuncOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithParticipationFunc", Value: nil, Err: ErrNilFunc}
+	}
+	f.participationF = o
+	return nil
+}
+
+// WithParticipationFunc sets a function that returns the percent of requests that run the Injector
+// for each request, such as a different rate per tenant, path, or time of day. It takes priority
+// over WithParticipation. f should return 0.0 <= p <= 1.0, greater values never run the Injector.
+// Deterministic modes such as WithEveryNth take priority over WithParticipationFunc.
+func WithParticipationFunc(f func(r *http.Request) float32) Option {
+	return participationFuncOption(f)
+}
+
+type everyNthOption int
+
+func (o everyNthOption) applyFault(f *Fault) error {
+	if o < 1 {
+		return &OptionError{Option: "WithEveryNth", Value: int(o), Err: ErrInvalidCount}
+	}
+	f.everyNth = uint64(o)
+	return nil
+}
+
+// WithEveryNth deterministically runs the Injector on every Nth request that passes the other
+// checks, instead of randomly choosing requests using WithParticipation. n must be at least 1.
+func WithEveryNth(n int) Option {
+	return everyNthOption(n)
+}
+
+type burstOption struct {
+	on  int
+	off int
+}
+
+func (o burstOption) applyFault(f *Fault) error {
+	if o.on < 1 || o.off < 0 {
+		return &OptionError{Option: "WithBurst", Value: []int{o.on, o.off}, Err: ErrInvalidCount}
+	}
+	f.burstOn = uint64(o.on)
+	f.burstOff = uint64(o.off)
+	return nil
+}
+
+// WithBurst runs the Injector on on consecutive requests that pass the other checks and then skips
+// the next off requests, repeatedly. on must be at least 1 and off must not be negative. This
+// models the bursty nature of real partial outages better than WithParticipation. WithEveryNth
+// takes priority over WithBurst.
+func WithBurst(on, off int) Option {
+	return burstOption{on: on, off: off}
+}
+
+type burstDurationOption struct {
+	on  time.Duration
+	off time.Duration
+}
+
+func (o burstDurationOption) applyFault(f *Fault) error {
+	if o.on <= 0 || o.off < 0 {
+		return &OptionError{Option: "WithBurstDuration", Value: []time.Duration{o.on, o.off}, Err: ErrInvalidDuration}
+	}
+	f.burstOnDuration = o.on
+	f.burstOffDuration = o.off
+	return nil
+}
+
+// WithBurstDuration runs the Injector on all requests that pass the other checks for the on
+// duration and then skips all requests for the off duration, repeatedly, starting when the Fault is
+// created. on must be greater than 0 and off must not be negative. WithEveryNth and WithBurst take
+// priority over WithBurstDuration.
+func WithBurstDuration(on, off time.Duration) Option {
+	return burstDurationOption{on: on, off: off}
+}
+
+type warmupOption int
+
+func (o warmupOption) applyFault(f *Fault) error {
+	if o < 1 {
+		return &OptionError{Option: "WithWarmup", Value: int(o), Err: ErrInvalidCount}
+	}
+	f.warmupRequests = uint64(o)
+	return nil
+}
+
+// WithWarmup prevents the Fault from injecting until after it has handled n requests, including
+// requests that would not otherwise be injected. n must be at least 1.
+func WithWarmup(n int) Option {
+	return warmupOption(n)
+}
+
+type warmupDurationOption time.Duration
+
+func (o warmupDurationOption) applyFault(f *Fault) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithWarmupDuration", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	f.warmupDuration = time.Duration(o)
+	return nil
+}
+
+// WithWarmupDuration prevents the Fault from injecting until d has passed since the Fault was
+// created. d must be greater than 0.
+func WithWarmupDuration(d time.Duration) Option {
+	return warmupDurationOption(d)
+}
+
+type startupGraceOption time.Duration
+
+func (o startupGraceOption) applyFault(f *Fault) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithStartupGrace", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	f.startupGrace = time.Duration(o)
+	return nil
+}
+
+// WithStartupGrace prevents the Fault from injecting until d has passed since the process started,
+// so that injected failures do not fail readiness checks during deploys. Unlike WithWarmupDuration
+// the grace period does not restart when a Fault is created later, such as by Registry.Load. d must
+// be greater than 0.
+func WithStartupGrace(d time.Duration) Option {
+	return startupGraceOption(d)
+}
+
 type pathBlocklistOption []string
 
 func (o pathBlocklistOption) applyFault(f *Fault) error {
@@ -156,6 +515,11 @@ func WithHeaderAllowlist(allowlist map[string]string) Option {
 type RandSeedOption interface {
 	Option
 	RandomInjectorOption
+	ChainInjectorOption
+	FaultGroupOption
+	StreamOption
+	FaultsOption
+	SlicerInjectorOption
 }
 
 type randSeedOption int64
@@ -170,6 +534,15 @@ func WithRandSeed(s int64) RandSeedOption {
 	return randSeedOption(s)
 }
 
+// RandFloat32FuncOption configures things that can set a random float32 function.
+type RandFloat32FuncOption interface {
+	Option
+	ChainInjectorOption
+	FaultGroupOption
+	StreamOption
+	FaultsOption
+}
+
 type randFloat32FuncOption func() float32
 
 func (o randFloat32FuncOption) applyFault(f *Fault) error {
@@ -178,30 +551,65 @@ func (o randFloat32FuncOption) applyFault(f *Fault) error {
 }
 
 // WithRandFloat32Func sets the function that will be used to randomly get our float value. Default
-// rand.Float32. Always returns a float32 between [0.0,1.0) to avoid errors.
-func WithRandFloat32Func(f func() float32) Option {
+// rand.Float32. Always returns a float32 between [0.0,1.0) to avoid errors. With WithDebugTrace each
+// value is recorded as the Roll of the Trace of the request, next to the Participation it was
+// compared to, so that a custom function can be verified in production.
+func WithRandFloat32Func(f func() float32) RandFloat32FuncOption {
 	return randFloat32FuncOption(f)
 }
 
+// NowFuncOption configures things that can set a function to get the current time.
+type NowFuncOption interface {
+	Option
+	CounterCoordinatorOption
+	SLOGuardOption
+	StaleCacheInjectorOption
+	OutageInjectorOption
+	FlappingInjectorOption
+	RegistryOption
+	RateLimitedReporterOption
+	ExperimentOption
+	AccessLoggerOption
+}
+
+type nowFuncOption func() time.Time
+
+func (o nowFuncOption) applyFault(f *Fault) error {
+	f.nowF = o
+	return nil
+}
+
+// WithNowFunc sets the function that will be used to get the current time. Default time.Now.
+func WithNowFunc(f func() time.Time) NowFuncOption {
+	return nowFuncOption(f)
+}
+
 // NewFault sets/validates the Injector and Options and returns a usable Fault.
 func NewFault(i Injector, opts ...Option) (*Fault, error) {
 	if i == nil {
-		return nil, ErrNilInjector
+		return nil, &OptionError{Option: "NewFault", Value: i, Err: ErrNilInjector}
 	}
 
 	// set defaults
 	f := &Fault{
-		injector: i,
-		randSeed: defaultRandSeed,
-		randF:    nil,
+		injector:     i,
+		reporter:     NewNoopReporter(),
+		name:         typeName(i),
+		annotate:     true,
+		randSeed:     defaultRandSeed,
+		randF:        nil,
+		idF:          newCorrelationID,
+		nowF:         time.Now,
+		patternF:     ServeMuxPattern,
+		instanceKeyF: RemoteAddrKey,
 	}
 
-	// apply options
-	for _, opt := range opts {
-		err := opt.applyFault(f)
-		if err != nil {
-			return nil, err
-		}
+	// apply options and compile lists, returning the problems of both at once
+	errs := splitErrors(applyOptions(opts, Option.applyFault, f))
+	errs = append(errs, splitErrors(f.compileLists())...)
+	err := joinErrors(errs)
+	if err != nil {
+		return nil, err
 	}
 
 	// set seeded rand source and function
@@ -210,76 +618,245 @@ func NewFault(i Injector, opts ...Option) (*Fault, error) {
 		f.randF = f.rand.Float32
 	}
 
+	f.injectorV2, _ = i.(InjectorV2)
+	f.start = f.nowF()
+
 	return f, nil
 }
 
-// Handler determines if the Injector should execute and runs it if so.
+// Handler determines if the Injector should execute and runs it if so. The Injector's handler is
+// composed once, so requests that are not injected do not allocate anything beyond their context
+// annotation.
 func (f *Fault) Handler(next http.Handler) http.Handler {
+	injected := f.injector.Handler(next)
+
+	// passive Faults do not need to count or trace requests while disabled
+	passive := f.warmupRequests == 0 && !f.debugTrace
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// By default faults do not evaluate. Here we go through conditions where faults
-		// will evaluate, if everything is configured correctly.
-		var shouldEvaluate bool
+		// fast path for disabled Faults
+		if !f.enabled.Load() && passive {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-		shouldEvaluate = f.enabled
+		if f.brownoutHigh > 0 {
+			f.inFlight.Add(1)
+			defer f.inFlight.Add(-1)
+		}
+
+		// disabled Faults do not roll for their FaultGroup
+		if f.group != nil && f.enabled.Load() && f.scopeEnabled() {
+			r = f.group.withDecision(r)
+		}
 
-		shouldEvaluate = shouldEvaluate && f.checkAllowBlockLists(shouldEvaluate, r)
+		t := Trace{Fault: f.name}
+		t.Reason = f.decide(r, &t)
 
-		// false if not selected for participation
-		shouldEvaluate = shouldEvaluate && f.participate()
+		if f.debugTrace {
+			r = f.traceRequest(r, t)
+		}
 
-		// run the injector or pass
-		if shouldEvaluate {
-			f.injector.Handler(next).ServeHTTP(w, r)
-		} else {
+		// run the injector or pass, recording the result in the request context
+		switch t.Reason {
+		case ReasonInjected:
+			f.count(r, true)
+			f.injecting.Add(1)
+			defer f.injecting.Add(-1)
+			r = f.withInjectionID(w, r)
+			f.reportEvent(r, t)
+			r = f.annotateRequest(r, ContextKeyInjected)
+			trackInjection(r, f.name)
+			callHook(f.onInject, r)
+			r, countDelay := f.withDelayRecorder(r)
+			defer countDelay()
+			f.inject(w, r, next, injected, t)
+		case ReasonWarmup, ReasonDisabled, ReasonEnabledFunc:
+			// pass without a trace if the Fault is not evaluating
+			next.ServeHTTP(w, r)
+		default:
+			f.count(r, false)
+			r = f.annotateRequest(r, ContextKeySkipped)
+			callHook(f.onSkip, r)
 			next.ServeHTTP(w, r)
 		}
 	})
 }
 
-// SetEnabled updates the enabled state of the Fault.
-func (f *Fault) SetEnabled(o enabledOption) error {
-	return o.applyFault(f)
+// decide returns the Reason that the Fault does not inject into r, or ReasonInjected if it does. By
+// default faults do not evaluate, so decide goes through the conditions where faults will evaluate,
+// recording the participation roll in t.
+func (f *Fault) decide(r *http.Request, t *Trace) Reason {
+	reason := f.evaluate(r)
+	if reason != reasonNone {
+		return reason
+	}
+
+	reason = f.checkSkip(r)
+	if reason == reasonNone {
+		reason = f.checkAllowBlockLists(r)
+	}
+	if reason == reasonNone {
+		reason = f.checkPatternLists(r)
+	}
+	if reason == reasonNone {
+		reason = f.checkURILists(r)
+	}
+
+	switch {
+	case reason != reasonNone:
+		return reason
+	case !f.participate(r, t):
+		return ReasonParticipation
+	case !f.guard(r):
+		return ReasonGuard
+	case !f.coordinate(r) || !f.scopeAllow(r):
+		return ReasonCoordinator
+	}
+
+	return ReasonInjected
 }
 
-// SetParticipation updates the participation percentage of the Fault.
-func (f *Fault) SetParticipation(o participationOption) error {
-	return o.applyFault(f)
+// evaluate returns the Reason that the Fault is not evaluating r, or reasonNone if it is.
+func (f *Fault) evaluate(r *http.Request) Reason {
+	switch {
+	// checked first so that every request counts
+	case !f.warm():
+		return ReasonWarmup
+	case !f.enabled.Load() || !f.scopeEnabled():
+		return ReasonDisabled
+	case f.enabledF != nil && !f.enabledF(r):
+		return ReasonEnabledFunc
+	}
+
+	return reasonNone
+}
+
+// Chain wraps h with each of the Faults in order. The first Fault is the outermost middleware and
+// evaluates first, so a request passes through faults[0], then faults[1], and so on before reaching
+// h. Each Fault evaluates independently of the others.
+func Chain(h http.Handler, faults ...*Fault) http.Handler {
+	for idx := len(faults) - 1; idx >= 0; idx-- {
+		h = faults[idx].Handler(h)
+	}
+
+	return h
+}
+
+// SetEnabled updates the enabled state of the Fault. It is safe to call while the Fault is handling
+// requests.
+func (f *Fault) SetEnabled(e bool) {
+	f.setEnabled(e)
+}
+
+// SetParticipation updates the participation percentage of the Fault. 0.0 <= p <= 1.0. If p is not
+// valid the Fault is not changed. It is safe to call while the Fault is handling requests.
+func (f *Fault) SetParticipation(p float32) error {
+	if p < 0.0 || p > 1.0 {
+		return &OptionError{Option: "SetParticipation", Value: p, Err: ErrInvalidPercent}
+	}
+	defer f.changed()
+
+	return participationOption(p).applyFault(f)
 }
 
 // checkAllowBlockLists checks the request against the provided allowlists and blocklists, returning
-// true if the request may proceed and false otherwise.
-func (f *Fault) checkAllowBlockLists(shouldEvaluate bool, r *http.Request) bool {
-	// false if path is in pathBlocklist
-	shouldEvaluate = shouldEvaluate && !f.pathBlocklist[r.URL.Path]
+// the Reason the request may not proceed or reasonNone if it may.
+func (f *Fault) checkAllowBlockLists(r *http.Request) Reason {
+	path := f.requestPath(r)
+	if f.pathBloom.mayContain(path) && f.pathBlocklist[path] {
+		return ReasonPathBlocklist
+	}
 
-	// false if pathAllowlist exists and path is not in it
-	if len(f.pathAllowlist) > 0 {
-		shouldEvaluate = shouldEvaluate && f.pathAllowlist[r.URL.Path]
+	if len(f.pathAllowlist) > 0 && !f.pathAllowlist[path] {
+		return ReasonPathAllowlist
 	}
 
-	// false if any headers match headerBlocklist
-	for key, val := range f.headerBlocklist {
-		shouldEvaluate = shouldEvaluate && !(r.Header.Get(key) == val)
+	for _, rule := range f.headerBlockRules {
+		if headerValue(r.Header, rule.key) == rule.val {
+			return ReasonHeaderBlocklist
+		}
 	}
 
-	// false if headerAllowlist exists and headers are not in it
-	if len(f.headerAllowlist) > 0 {
-		for key, val := range f.headerAllowlist {
-			shouldEvaluate = shouldEvaluate && (r.Header.Get(key) == val)
+	for _, rule := range f.headerAllowRules {
+		if headerValue(r.Header, rule.key) != rule.val {
+			return ReasonHeaderAllowlist
 		}
 	}
 
-	return shouldEvaluate
+	return reasonNone
+}
+
+// warm returns true once the Fault has handled f.warmupRequests requests, f.warmupDuration has
+// passed since the Fault was created, and f.startupGrace has passed since the process started.
+func (f *Fault) warm() bool {
+	if f.warmupRequests > 0 && f.handled.Add(1) <= f.warmupRequests {
+		return false
+	}
+
+	if f.warmupDuration > 0 && f.nowF().Sub(f.start) < f.warmupDuration {
+		return false
+	}
+
+	if f.startupGrace > 0 && f.nowF().Sub(processStart) < f.startupGrace {
+		return false
+	}
+
+	return true
 }
 
-// participate randomly decides (returns true) if the Injector should run based on f.participation.
-// Numbers outside of [0.0,1.0] will always return false.
-func (f *Fault) participate() bool {
-	f.randMtx.Lock()
-	rn := f.randF()
-	f.randMtx.Unlock()
+// participate randomly decides (returns true) if the Injector should run based on the participation
+// of f or its ratio, or the result of f.participationF for r if set. Numbers outside of
+// [0.0,1.0] will always return false. The participation is scaled by the requests in flight if
+// WithBrownout is set. If a deterministic mode such as f.everyNth is set participate instead
+// decides based on that mode, and if the Fault is in a FaultGroup the group decides. Random
+// decisions are recorded in t.
+func (f *Fault) participate(r *http.Request, t *Trace) bool {
+	n := f.evaluated.Add(1)
+
+	if f.bucketing != nil {
+		return f.bucketing.Arm(r) == f.arm
+	}
+
+	if f.badInstances != nil {
+		return f.badInstances[f.instance(r)]
+	}
+
+	if f.group != nil {
+		d := f.group.decision(r.Context())
+		t.Rolled, t.Roll, t.Participation = true, d.roll, f.group.participation
+		return d.participate
+	}
+
+	switch {
+	case f.everyNth > 0:
+		return n%f.everyNth == 0
+	case f.burstOn > 0:
+		return (n-1)%(f.burstOn+f.burstOff) < f.burstOn
+	case f.burstOnDuration > 0:
+		return f.nowF().Sub(f.start)%(f.burstOnDuration+f.burstOffDuration) < f.burstOnDuration
+	case f.targetRate > 0:
+		return f.participateRate()
+	}
+
+	rate := f.participation()
+	if f.participationF == nil && rate.n > 0 {
+		return f.participateRatio(rate, t)
+	}
+
+	p := rate.p
+	if f.participationF != nil {
+		p = f.participationF(r)
+	}
+	if p <= 1.0 {
+		p *= f.brownoutFactor()
+	}
+
+	rn := f.roll()
+
+	t.Rolled, t.Roll, t.Participation = true, rn, p
 
-	if rn < f.participation && f.participation <= 1.0 {
+	if rn < p && p <= 1.0 {
 		return true
 	}
 
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,44 +18,98 @@ func TestNewFault(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name         string
-		giveInjector Injector
-		giveOptions  []Option
-		wantFault    *Fault
-		wantErr      error
+		name              string
+		giveInjector      Injector
+		giveOptions       []Option
+		wantEnabled       bool
+		wantParticipation float32
+		wantFault         *Fault
+		wantErr           error
 	}{
 		{
 			name:         "all options",
 			giveInjector: newTestInjectorNoop(),
 			giveOptions: []Option{
 				WithEnabled(true),
+				WithEnabledFunc(func(r *http.Request) bool { return true }),
 				WithParticipation(1.0),
+				WithParticipationFunc(func(r *http.Request) float32 { return 1.0 }),
+				WithEveryNth(2),
+				WithBurst(3, 4),
+				WithBurstDuration(time.Second, time.Minute),
+				WithTargetRate(2),
+				WithBrownout(1, 3),
+				WithWarmup(5),
+				WithWarmupDuration(time.Hour),
+				WithStartupGrace(time.Minute),
+				WithRouteStats(10),
+				WithSkipHealthEndpoints("/status"),
+				WithSkipPreflight(),
 				WithPathBlocklist([]string{"/donotinject"}),
 				WithPathAllowlist([]string{"/onlyinject"}),
+				WithPatternBlocklist([]string{"/donotinject/{id}"}),
+				WithPatternAllowlist([]string{"/onlyinject/{id}"}),
 				WithHeaderBlocklist(map[string]string{"block": "yes"}),
 				WithHeaderAllowlist(map[string]string{"allow": "yes"}),
 				WithRandSeed(100),
 				WithRandFloat32Func(func() float32 { return 0.0 }),
+				WithName("custom"),
+				WithContextAnnotation(false),
 			},
+			wantEnabled:       true,
+			wantParticipation: 1.0,
 			wantFault: &Fault{
-				enabled:       true,
-				injector:      newTestInjectorNoop(),
-				participation: 1.0,
+				injector: newTestInjectorNoop(),
+				reporter: NewNoopReporter(),
+				name:     "custom",
+				annotate: false,
+				everyNth: 2,
+				burstOn:  3,
+				burstOff: 4,
+				skipPaths: map[string]bool{
+					"/healthz": true,
+					"/livez":   true,
+					"/readyz":  true,
+					"/health":  true,
+					"/ping":    true,
+					"/metrics": true,
+					"/status":  true,
+				},
+				skipPreflight: true,
 				pathBlocklist: map[string]bool{
 					"/donotinject": true,
 				},
 				pathAllowlist: map[string]bool{
 					"/onlyinject": true,
 				},
+				patternBlocklist: map[string]bool{
+					"/donotinject/{id}": true,
+				},
+				patternAllowlist: map[string]bool{
+					"/onlyinject/{id}": true,
+				},
 				headerBlocklist: map[string]string{
 					"block": "yes",
 				},
 				headerAllowlist: map[string]string{
 					"allow": "yes",
 				},
-				randSeed: 100,
-				rand:     rand.New(rand.NewSource(100)),
-				randF:    func() float32 { return 0.0 },
+				headerBlockRules: []headerRule{{key: "Block", val: "yes"}},
+				headerAllowRules: []headerRule{{key: "Allow", val: "yes"}},
+				randSeed:         100,
+				rand:             rand.New(rand.NewSource(100)),
+				randF:            func() float32 { return 0.0 },
+
+				burstOnDuration:  time.Second,
+				burstOffDuration: time.Minute,
+				targetRate:       2,
+				brownoutLow:      1,
+				brownoutHigh:     3,
+				warmupRequests:   5,
+				warmupDuration:   time.Hour,
+				startupGrace:     time.Minute,
+				routeStatsLimit:  10,
+				routeStats:       map[string]RouteStats{},
 			},
 			wantErr: nil,
 		},
@@ -61,7 +118,25 @@ func TestNewFault(t *testing.T) {
 			giveInjector: nil,
 			giveOptions:  nil,
 			wantFault:    nil,
-			wantErr:      ErrNilInjector,
+			wantErr:      &OptionError{Option: "NewFault", Value: nil, Err: ErrNilInjector},
+		},
+		{
+			name:         "nil enabled function",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithEnabledFunc(nil),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithEnabledFunc", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:         "nil participation function",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithParticipationFunc(nil),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithParticipationFunc", Value: nil, Err: ErrNilFunc},
 		},
 		{
 			name:         "invalid percent",
@@ -70,7 +145,96 @@ func TestNewFault(t *testing.T) {
 				WithParticipation(100.0),
 			},
 			wantFault: nil,
-			wantErr:   ErrInvalidPercent,
+			wantErr:   &OptionError{Option: "WithParticipation", Value: float32(100.0), Err: ErrInvalidPercent},
+		},
+		{
+			name:         "invalid every nth",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithEveryNth(0),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithEveryNth", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name:         "invalid burst on",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithBurst(0, 1),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithBurst", Value: []int{0, 1}, Err: ErrInvalidCount},
+		},
+		{
+			name:         "invalid burst off",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithBurst(1, -1),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithBurst", Value: []int{1, -1}, Err: ErrInvalidCount},
+		},
+		{
+			name:         "invalid burst duration on",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithBurstDuration(0, time.Second),
+			},
+			wantFault: nil,
+			wantErr: &OptionError{
+				Option: "WithBurstDuration",
+				Value:  []time.Duration{0, time.Second},
+				Err:    ErrInvalidDuration,
+			},
+		},
+		{
+			name:         "invalid burst duration off",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithBurstDuration(time.Second, -time.Second),
+			},
+			wantFault: nil,
+			wantErr: &OptionError{
+				Option: "WithBurstDuration",
+				Value:  []time.Duration{time.Second, -time.Second},
+				Err:    ErrInvalidDuration,
+			},
+		},
+		{
+			name:         "invalid brownout",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithBrownout(2, 2),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithBrownout", Value: []int{2, 2}, Err: ErrInvalidCount},
+		},
+		{
+			name:         "invalid warmup",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithWarmup(0),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithWarmup", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name:         "invalid warmup duration",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithWarmupDuration(0),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithWarmupDuration", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:         "invalid startup grace",
+			giveInjector: newTestInjectorNoop(),
+			giveOptions: []Option{
+				WithStartupGrace(0),
+			},
+			wantFault: nil,
+			wantErr:   &OptionError{Option: "WithStartupGrace", Value: time.Duration(0), Err: ErrInvalidDuration},
 		},
 		{
 			name:         "option error",
@@ -86,9 +250,10 @@ func TestNewFault(t *testing.T) {
 			giveInjector: newTestInjectorNoop(),
 			giveOptions:  []Option{},
 			wantFault: &Fault{
-				enabled:       false,
 				injector:      newTestInjectorNoop(),
-				participation: 0.0,
+				reporter:      NewNoopReporter(),
+				name:          "testInjectorNoop",
+				annotate:      true,
 				pathBlocklist: nil,
 				pathAllowlist: nil,
 				randSeed:      defaultRandSeed,
@@ -105,10 +270,22 @@ func TestNewFault(t *testing.T) {
 
 			f, err := NewFault(tt.giveInjector, tt.giveOptions...)
 
-			// Function equality cannot be determined so set to nil before comparing
+			// Function equality cannot be determined so set to nil before comparing. The start
+			// time depends on when the test runs so set to zero before comparing. Atomic pointers
+			// compare by address so compare the participation separately.
 			if tt.wantFault != nil {
+				tt.wantFault.enabled.Store(tt.wantEnabled)
+				assert.Equal(t, tt.wantParticipation, f.participation().p)
+				f.rate.Store(nil)
 				f.randF = nil
 				tt.wantFault.randF = nil
+				f.enabledF = nil
+				f.participationF = nil
+				f.nowF = nil
+				f.patternF = nil
+				f.idF = nil
+				f.instanceKeyF = nil
+				f.start = time.Time{}
 			}
 
 			assert.Equal(t, tt.wantErr, err)
@@ -169,6 +346,71 @@ func TestFaultHandler(t *testing.T) {
 			wantCode: testHandlerCode,
 			wantBody: testHandlerBody,
 		},
+		{
+			name:         "100 percent 500s enabled function false",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithEnabledFunc(func(r *http.Request) bool { return r.Header.Get(testHeaderKey) == "other" }),
+				WithParticipation(1.0),
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name:         "100 percent 500s enabled function true",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithEnabledFunc(func(r *http.Request) bool { return r.Header.Get(testHeaderKey) == testHeaderVal }),
+				WithParticipation(1.0),
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "100 percent 500s not enabled enabled function true",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(false),
+				WithEnabledFunc(func(r *http.Request) bool { return true }),
+				WithParticipation(1.0),
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name:         "0 percent 500s participation function",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(1.0),
+				WithParticipationFunc(func(r *http.Request) float32 { return 0.0 }),
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name:         "100 percent 500s participation function",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(0.0),
+				WithParticipationFunc(func(r *http.Request) float32 { return 1.0 }),
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "invalid percent participation function",
+			giveInjector: newTestInjector500s(),
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipationFunc(func(r *http.Request) float32 { return 1.5 }),
+			},
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
 		{
 			name:         "100 percent 500s with blocklist root",
 			giveInjector: newTestInjector500s(),
@@ -312,14 +554,41 @@ func TestFaultSetEnabled(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	assert.Equal(t, http.StatusText(http.StatusInternalServerError), strings.TrimSpace(rr.Body.String()))
 
-	err = f.SetEnabled(false)
-	assert.NoError(t, err)
+	f.SetEnabled(false)
 
 	rr = testRequest(t, f)
 	assert.Equal(t, testHandlerCode, rr.Code)
 	assert.Equal(t, testHandlerBody, strings.TrimSpace(rr.Body.String()))
 }
 
+// TestFaultSetEnabledConcurrent tests that Fault.SetEnabled() is safe while the Fault handles
+// requests.
+func TestFaultSetEnabledConcurrent(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjector500s(),
+		WithEnabled(false),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rr := testRequest(t, f)
+				assert.Contains(t, []int{testHandlerCode, http.StatusInternalServerError}, rr.Code)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		f.SetEnabled(i%2 == 0)
+	}
+	wg.Wait()
+}
+
 // TestFaultSetParticipation tests Fault.SetParticipation().
 func TestFaultSetParticipation(t *testing.T) {
 	t.Parallel()
@@ -340,6 +609,46 @@ func TestFaultSetParticipation(t *testing.T) {
 	rr = testRequest(t, f)
 	assert.Equal(t, testHandlerCode, rr.Code)
 	assert.Equal(t, testHandlerBody, strings.TrimSpace(rr.Body.String()))
+
+	// invalid values do not change the Fault
+	err = f.SetParticipation(1.1)
+	assert.Equal(t, &OptionError{Option: "SetParticipation", Value: float32(1.1), Err: ErrInvalidPercent}, err)
+	assert.Equal(t, float32(0.0), f.participation().p)
+}
+
+// TestFaultSetParticipationConcurrent tests that Fault.SetParticipation() is safe while the Fault
+// handles requests and is described.
+func TestFaultSetParticipationConcurrent(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjector500s(),
+		WithEnabled(true),
+		WithParticipationRatio(1, 2),
+	)
+	assert.NoError(t, err)
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(f))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rr := testRequest(t, f)
+				assert.Contains(t, []int{testHandlerCode, http.StatusInternalServerError}, rr.Code)
+				assert.NotEmpty(t, f.String())
+				assert.Len(t, reg.Status().FaultStatuses, 1)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, f.SetParticipation(float32(i%2)))
+	}
+	wg.Wait()
+
+	assert.Equal(t, participationRate{p: 1.0}, f.participation())
 }
 
 // TestFaultPercentDo tests the internal Fault.participate().
@@ -370,7 +679,7 @@ func TestFaultPercentDo(t *testing.T) {
 
 			var trueC, totalC float32
 			for totalC <= 100000 {
-				result := f.participate()
+				result := f.participate(nil, &Trace{})
 				if result {
 					trueC++
 				}
@@ -386,3 +695,234 @@ func TestFaultPercentDo(t *testing.T) {
 		})
 	}
 }
+
+// TestFaultEveryNth tests that WithEveryNth injects deterministically.
+func TestFaultEveryNth(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjector500s(),
+		WithEnabled(true),
+		WithParticipation(0.0),
+		WithEveryNth(3),
+	)
+	assert.NoError(t, err)
+
+	var codes []int
+	for range 6 {
+		codes = append(codes, testRequest(t, f).Code)
+	}
+
+	assert.Equal(t, []int{
+		testHandlerCode, testHandlerCode, http.StatusInternalServerError,
+		testHandlerCode, testHandlerCode, http.StatusInternalServerError,
+	}, codes)
+}
+
+// TestFaultBurst tests that WithBurst and WithBurstDuration inject in bursts.
+func TestFaultBurst(t *testing.T) {
+	t.Parallel()
+
+	t.Run("count", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := NewFault(newTestInjector500s(),
+			WithEnabled(true),
+			WithBurst(2, 1),
+		)
+		assert.NoError(t, err)
+
+		var codes []int
+		for range 6 {
+			codes = append(codes, testRequest(t, f).Code)
+		}
+
+		assert.Equal(t, []int{
+			http.StatusInternalServerError, http.StatusInternalServerError, testHandlerCode,
+			http.StatusInternalServerError, http.StatusInternalServerError, testHandlerCode,
+		}, codes)
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Unix(0, 0)
+
+		f, err := NewFault(newTestInjector500s(),
+			WithEnabled(true),
+			WithBurstDuration(time.Second, time.Minute),
+			WithNowFunc(func() time.Time { return now }),
+		)
+		assert.NoError(t, err)
+
+		var codes []int
+		for _, elapsed := range []time.Duration{0, time.Second, time.Minute, time.Minute + time.Second} {
+			now = time.Unix(0, 0).Add(elapsed)
+			codes = append(codes, testRequest(t, f).Code)
+		}
+
+		assert.Equal(t, []int{
+			http.StatusInternalServerError, testHandlerCode, testHandlerCode, http.StatusInternalServerError,
+		}, codes)
+	})
+}
+
+// TestFaultBrownout tests that WithBrownout scales participation with the requests in flight.
+func TestFaultBrownout(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithParticipation(1.0),
+		WithBrownout(1, 3),
+		WithRandFloat32Func(func() float32 { return 0.4 }),
+		WithDebugTrace(true),
+	)
+	assert.NoError(t, err)
+
+	// each request starts another request while it is in flight, up to 4 in flight
+	var got any
+	var h http.Handler
+	h = f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.InFlight() < 4 {
+			h.ServeHTTP(w, r)
+			return
+		}
+		got = r.Context().Value(ContextKeyTrace)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	name := "testInjectorNoop"
+	assert.Equal(t, []Trace{
+		{Fault: name, Reason: ReasonParticipation, Rolled: true, Roll: 0.4, Participation: 0.0},
+		{Fault: name, Reason: ReasonInjected, Rolled: true, Roll: 0.4, Participation: 0.5},
+		{Fault: name, Reason: ReasonInjected, Rolled: true, Roll: 0.4, Participation: 1.0},
+		{Fault: name, Reason: ReasonInjected, Rolled: true, Roll: 0.4, Participation: 1.0},
+	}, got)
+	assert.Equal(t, 0, f.InFlight())
+}
+
+// TestFaultWarmup tests that WithWarmup, WithWarmupDuration, and WithStartupGrace delay injection.
+func TestFaultWarmup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("count", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := NewFault(newTestInjector500s(),
+			WithEnabled(true),
+			WithParticipation(1.0),
+			WithWarmup(2),
+		)
+		assert.NoError(t, err)
+
+		var codes []int
+		for range 3 {
+			codes = append(codes, testRequest(t, f).Code)
+		}
+
+		assert.Equal(t, []int{testHandlerCode, testHandlerCode, http.StatusInternalServerError}, codes)
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Unix(0, 0)
+
+		f, err := NewFault(newTestInjector500s(),
+			WithEnabled(true),
+			WithParticipation(1.0),
+			WithWarmupDuration(time.Minute),
+			WithNowFunc(func() time.Time { return now }),
+		)
+		assert.NoError(t, err)
+
+		assert.Equal(t, testHandlerCode, testRequest(t, f).Code)
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, http.StatusInternalServerError, testRequest(t, f).Code)
+	})
+
+	t.Run("startup grace", func(t *testing.T) {
+		t.Parallel()
+
+		// the grace period counts from process start, not from when the Fault is created
+		now := processStart.Add(time.Hour)
+
+		f, err := NewFault(newTestInjector500s(),
+			WithEnabled(true),
+			WithParticipation(1.0),
+			WithStartupGrace(2*time.Hour),
+			WithNowFunc(func() time.Time { return now }),
+		)
+		assert.NoError(t, err)
+
+		assert.Equal(t, testHandlerCode, testRequest(t, f).Code)
+
+		now = now.Add(time.Hour)
+		assert.Equal(t, http.StatusInternalServerError, testRequest(t, f).Code)
+	})
+}
+
+// TestChain tests Chain.
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveFault []Injector
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			name:      "none",
+			giveFault: nil,
+			wantCode:  testHandlerCode,
+			wantBody:  testHandlerBody,
+		},
+		{
+			name: "one two",
+			giveFault: []Injector{
+				newTestInjectorOneOK(),
+				newTestInjectorTwoTeapot(),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "one" + "two" + testHandlerBody,
+		},
+		{
+			name: "two 500s one",
+			giveFault: []Injector{
+				newTestInjectorTwoTeapot(),
+				newTestInjector500s(),
+				newTestInjectorOneOK(),
+			},
+			wantCode: http.StatusTeapot,
+			wantBody: "two" + http.StatusText(http.StatusInternalServerError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var faults []*Fault
+			for _, i := range tt.giveFault {
+				f, err := NewFault(i,
+					WithEnabled(true),
+					WithParticipation(1.0),
+				)
+				assert.NoError(t, err)
+				faults = append(faults, f)
+			}
+
+			h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, testHandlerBody, testHandlerCode)
+			}), faults...)
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// Package faultredis provides a fault.Counter backed by Redis, so that a fault.CounterCoordinator
+// can limit injections across every instance of a service. It speaks the Redis protocol directly
+// and has no dependencies outside of the standard library.
+package faultredis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lingrino/go-fault"
+)
+
+var (
+	// ErrUnexpectedReply when Redis replies with a type or value that the Counter does not expect.
+	ErrUnexpectedReply = errors.New("unexpected reply from redis")
+	// ErrRedis when Redis replies with an error.
+	ErrRedis = errors.New("redis error")
+)
+
+// incrScript increments a key and sets its expiration when the key is created, atomically.
+const incrScript = "local v = redis.call('INCR', KEYS[1]) " +
+	"if v == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end " +
+	"return v"
+
+// defaultKeyPrefix is prepended to every key by default.
+const defaultKeyPrefix = "go-fault:"
+
+// defaultTimeout bounds each call to Counter.Incr by default.
+const defaultTimeout = time.Second
+
+// Counter is a fault.Counter backed by Redis. Counter uses a single connection and sends one
+// command at a time, reconnecting after any error.
+type Counter struct {
+	addr     string
+	prefix   string
+	password string
+	timeout  time.Duration
+	dialF    func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	conn net.Conn
+	rd   *bufio.Reader
+
+	// sem holds one value while Counter.conn and Counter.rd are in use. It is a channel rather than
+	// a mutex so that callers can stop waiting for it when their context is done.
+	sem chan struct{}
+}
+
+// Option configures a Counter.
+type Option interface {
+	applyCounter(c *Counter) error
+}
+
+type keyPrefixOption string
+
+func (o keyPrefixOption) applyCounter(c *Counter) error {
+	c.prefix = string(o)
+	return nil
+}
+
+// WithKeyPrefix sets the prefix of every key the Counter increments. Default "go-fault:".
+func WithKeyPrefix(p string) Option {
+	return keyPrefixOption(p)
+}
+
+type passwordOption string
+
+func (o passwordOption) applyCounter(c *Counter) error {
+	c.password = string(o)
+	return nil
+}
+
+// WithPassword sets the password the Counter sends with AUTH after connecting.
+func WithPassword(p string) Option {
+	return passwordOption(p)
+}
+
+type timeoutOption time.Duration
+
+func (o timeoutOption) applyCounter(c *Counter) error {
+	if o <= 0 {
+		return &fault.OptionError{Option: "WithTimeout", Value: time.Duration(o), Err: fault.ErrInvalidDuration}
+	}
+	c.timeout = time.Duration(o)
+	return nil
+}
+
+// WithTimeout sets the longest that Incr waits for Redis, including connecting, authenticating, and
+// running the command, so that an unreachable or hung Redis does not block requests. The deadline
+// of the context passed to Incr applies if it is sooner. Default 1s.
+func WithTimeout(d time.Duration) Option {
+	return timeoutOption(d)
+}
+
+type dialFuncOption func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (o dialFuncOption) applyCounter(c *Counter) error {
+	if o == nil {
+		return &fault.OptionError{Option: "WithDialFunc", Value: nil, Err: fault.ErrNilFunc}
+	}
+	c.dialF = o
+	return nil
+}
+
+// WithDialFunc sets the function used to connect to Redis, for example to use TLS. Default
+// net.Dialer.DialContext.
+func WithDialFunc(f func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return dialFuncOption(f)
+}
+
+// NewCounter returns a Counter for the Redis server at addr, such as "localhost:6379". The Counter
+// connects on first use.
+func NewCounter(addr string, opts ...Option) (*Counter, error) {
+	// set defaults
+	c := &Counter{
+		addr:    addr,
+		prefix:  defaultKeyPrefix,
+		timeout: defaultTimeout,
+		dialF:   (&net.Dialer{}).DialContext,
+		sem:     make(chan struct{}, 1),
+	}
+
+	// apply options, reporting every invalid option at once
+	var errs []error
+	for _, opt := range opts {
+		err := opt.applyCounter(c)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Incr increments the counter at key and returns the new value. A key that does not exist starts
+// at 0 and expires ttl after it is created. Incr returns an error wrapping the error of ctx if ctx
+// is done or the timeout set with WithTimeout passes before Redis replies, including while waiting
+// for another call to Incr to finish.
+func (c *Counter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	defer func() { <-c.sem }()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	n, err := c.incr(ctx, key, ttl)
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			// the connection deadline is the deadline of ctx, which may not have been marked done yet
+			<-ctx.Done()
+		}
+		err = errors.Join(ctx.Err(), err, c.close())
+	}
+
+	return n, err
+}
+
+// Close closes the connection to Redis. The Counter reconnects if it is used again.
+func (c *Counter) Close() error {
+	c.sem <- struct{}{}
+	defer func() { <-c.sem }()
+
+	return c.close()
+}
+
+// incr connects if needed and runs incrScript. ctx must have a deadline, which applies to every
+// command.
+func (c *Counter) incr(ctx context.Context, key string, ttl time.Duration) (n int64, err error) {
+	dialed, err := c.connect(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	err = c.conn.SetDeadline(deadline)
+	if err != nil {
+		return 0, err
+	}
+	stop := c.interrupt(ctx)
+	defer func() { err = errors.Join(err, stop()) }()
+
+	if dialed {
+		err = c.auth()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	reply, err := c.do("EVAL", incrScript, "1", c.prefix+key, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(reply, ":") {
+		return 0, fmt.Errorf("%w: %q", ErrUnexpectedReply, reply)
+	}
+
+	return strconv.ParseInt(reply[1:], 10, 64)
+}
+
+// connect dials Redis if there is no open connection and returns true if it did.
+func (c *Counter) connect(ctx context.Context) (bool, error) {
+	if c.conn != nil {
+		return false, nil
+	}
+
+	conn, err := c.dialF(ctx, "tcp", c.addr)
+	if err != nil {
+		return false, err
+	}
+	c.conn = conn
+	c.rd = bufio.NewReader(conn)
+
+	return true, nil
+}
+
+// interrupt moves the deadline of the connection into the past when ctx is done, so that a command
+// in progress returns. Call the returned function to stop it once the command is done, which
+// returns the error from moving the deadline, if any.
+func (c *Counter) interrupt(ctx context.Context) func() error {
+	conn := c.conn
+	errs := make(chan error, 1)
+	stop := context.AfterFunc(ctx, func() {
+		errs <- conn.SetDeadline(time.Unix(1, 0))
+	})
+
+	return func() error {
+		if stop() {
+			return nil
+		}
+		return <-errs
+	}
+}
+
+// auth authenticates the connection if the Counter has a password.
+func (c *Counter) auth() error {
+	if c.password == "" {
+		return nil
+	}
+
+	reply, err := c.do("AUTH", c.password)
+	if err != nil {
+		return err
+	}
+	if reply != "+OK" {
+		return fmt.Errorf("%w: %q", ErrUnexpectedReply, reply)
+	}
+
+	return nil
+}
+
+// close closes and forgets the connection.
+func (c *Counter) close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	c.rd = nil
+
+	return err
+}
+
+// do sends a command and returns the first line of the reply, or an error wrapping ErrRedis if
+// Redis replies with an error.
+func (c *Counter) do(args ...string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	_, err := io.WriteString(c.conn, b.String())
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := c.rd.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	reply = strings.TrimSuffix(reply, "\r\n")
+
+	if strings.HasPrefix(reply, "-") {
+		return "", fmt.Errorf("%w: %s", ErrRedis, reply[1:])
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,423 @@
+package faultredis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lingrino/go-fault"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestDial     = errors.New("error dialing test server")
+	errTestDeadline = errors.New("error setting deadline")
+	errTestWrite    = errors.New("error writing")
+)
+
+// testServer serves the Redis protocol over net.Pipe connections, replying to each command with
+// reply(args). An empty reply closes the connection.
+type testServer struct {
+	reply func(args []string) string
+
+	commands  [][]string
+	dials     int
+	closeErrs []error
+	mtx       sync.Mutex
+}
+
+// newTestServer returns a testServer that counts EVAL keys and accepts AUTH.
+func newTestServer() *testServer {
+	counts := make(map[string]int64)
+	return &testServer{
+		reply: func(args []string) string {
+			if args[0] == "AUTH" {
+				return "+OK\r\n"
+			}
+			counts[args[3]]++
+			return ":" + strconv.FormatInt(counts[args[3]], 10) + "\r\n"
+		},
+	}
+}
+
+// dial returns a connection to the testServer.
+func (s *testServer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	s.mtx.Lock()
+	s.dials++
+	s.mtx.Unlock()
+
+	client, server := net.Pipe()
+	go s.serve(server)
+
+	return client, nil
+}
+
+// serve handles conn and then closes it.
+func (s *testServer) serve(conn net.Conn) {
+	s.handle(conn)
+
+	err := conn.Close()
+	s.mtx.Lock()
+	s.closeErrs = append(s.closeErrs, err)
+	s.mtx.Unlock()
+}
+
+// handle reads commands from conn and writes replies until conn is closed.
+func (s *testServer) handle(conn net.Conn) {
+	rd := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			return
+		}
+
+		s.mtx.Lock()
+		s.commands = append(s.commands, args)
+		reply := s.reply(args)
+		s.mtx.Unlock()
+
+		if reply == "" {
+			return
+		}
+		_, err = io.WriteString(conn, reply)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a Redis protocol array of bulk strings.
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, n)
+	for range n {
+		line, err = rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+		_, err = io.ReadFull(rd, buf)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
+
+// testErrConn is a net.Conn that returns deadlineErr from SetDeadline and writeErr from Write.
+type testErrConn struct {
+	net.Conn
+	deadlineErr error
+	writeErr    error
+}
+
+// SetDeadline returns c.deadlineErr.
+func (c *testErrConn) SetDeadline(t time.Time) error {
+	return c.deadlineErr
+}
+
+// Write returns c.writeErr.
+func (c *testErrConn) Write(b []byte) (int, error) {
+	return 0, c.writeErr
+}
+
+// TestNewCounter tests NewCounter.
+func TestNewCounter(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCounter("localhost:6379")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:6379", c.addr)
+	assert.Equal(t, defaultKeyPrefix, c.prefix)
+	assert.Empty(t, c.password)
+	assert.Equal(t, defaultTimeout, c.timeout)
+	assert.NotNil(t, c.dialF)
+
+	c, err = NewCounter("localhost:6379", WithKeyPrefix("custom:"), WithPassword("secret"), WithTimeout(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "custom:", c.prefix)
+	assert.Equal(t, "secret", c.password)
+	assert.Equal(t, time.Minute, c.timeout)
+
+	c, err = NewCounter("localhost:6379", WithTimeout(0))
+	assert.Nil(t, c)
+	assert.Equal(t,
+		&fault.OptionError{Option: "WithTimeout", Value: time.Duration(0), Err: fault.ErrInvalidDuration}, err)
+
+	c, err = NewCounter("localhost:6379", WithDialFunc(nil))
+	assert.Nil(t, c)
+	assert.Equal(t, &fault.OptionError{Option: "WithDialFunc", Value: nil, Err: fault.ErrNilFunc}, err)
+
+	c, err = NewCounter("localhost:6379", WithDialFunc(nil), WithKeyPrefix("custom:"), WithDialFunc(nil))
+	assert.Nil(t, c)
+	assert.ErrorIs(t, err, fault.ErrNilFunc)
+	assert.EqualError(t, err,
+		"WithDialFunc(<nil>): function cannot be nil\nWithDialFunc(<nil>): function cannot be nil")
+}
+
+// TestCounterIncr tests Counter.Incr.
+func TestCounterIncr(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer()
+	c, err := NewCounter("redis:6379", WithDialFunc(s.dial), WithPassword("secret"))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for want := range int64(3) {
+		n, err := c.Incr(ctx, "one", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, want+1, n)
+	}
+	n, err := c.Incr(context.Background(), "two", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, c.Close())
+
+	n, err = c.Incr(ctx, "one", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	assert.Equal(t, 2, s.dials)
+	assert.Equal(t, []string{"AUTH", "secret"}, s.commands[0])
+	assert.Equal(t, []string{"EVAL", incrScript, "1", "go-fault:one", "60000"}, s.commands[1])
+}
+
+// TestCounterIncrErrors tests that Counter.Incr returns errors and reconnects after them.
+func TestCounterIncrErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveReply   string
+		giveAuth    string
+		giveOptions []Option
+		wantErr     error
+	}{
+		{
+			name:      "redis error",
+			giveReply: "-ERR unknown command\r\n",
+			wantErr:   ErrRedis,
+		},
+		{
+			name:      "unexpected reply",
+			giveReply: "$1\r\n",
+			wantErr:   ErrUnexpectedReply,
+		},
+		{
+			name:      "not an integer",
+			giveReply: ":one\r\n",
+			wantErr:   strconv.ErrSyntax,
+		},
+		{
+			name:      "closed before reply",
+			giveReply: "",
+			wantErr:   io.EOF,
+		},
+		{
+			name:        "auth error",
+			giveAuth:    "-WRONGPASS invalid password\r\n",
+			giveOptions: []Option{WithPassword("wrong")},
+			wantErr:     ErrRedis,
+		},
+		{
+			name:        "auth unexpected reply",
+			giveAuth:    ":1\r\n",
+			giveOptions: []Option{WithPassword("wrong")},
+			wantErr:     ErrUnexpectedReply,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &testServer{reply: func(args []string) string {
+				if args[0] == "AUTH" {
+					return tt.giveAuth
+				}
+				return tt.giveReply
+			}}
+			opts := append([]Option{WithDialFunc(s.dial)}, tt.giveOptions...)
+			c, err := NewCounter("redis:6379", opts...)
+			assert.NoError(t, err)
+
+			for range 2 {
+				_, err = c.Incr(context.Background(), "key", time.Minute)
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, 2, s.dials)
+		})
+	}
+}
+
+// TestCounterIncrConnErrors tests Counter.Incr when the connection fails.
+func TestCounterIncrConnErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveDial func(ctx context.Context, network, addr string) (net.Conn, error)
+		wantErr  error
+	}{
+		{
+			name: "dial",
+			giveDial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return nil, errTestDial
+			},
+			wantErr: errTestDial,
+		},
+		{
+			name: "deadline",
+			giveDial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				client, _ := net.Pipe()
+				return &testErrConn{Conn: client, deadlineErr: errTestDeadline}, nil
+			},
+			wantErr: errTestDeadline,
+		},
+		{
+			name: "write",
+			giveDial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				client, _ := net.Pipe()
+				return &testErrConn{Conn: client, writeErr: errTestWrite}, nil
+			},
+			wantErr: errTestWrite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCounter("redis:6379", WithDialFunc(tt.giveDial))
+			assert.NoError(t, err)
+
+			_, err = c.Incr(context.Background(), "key", time.Minute)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, c.conn)
+		})
+	}
+}
+
+// testHungDial returns a connection to a server that reads every command and never replies.
+func testHungDial(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, server)
+		errs <- errors.Join(err, server.Close())
+	}()
+
+	return client, nil
+}
+
+// TestCounterIncrTimeout tests that Counter.Incr gives up when Redis does not reply in time.
+func TestCounterIncrTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveDial    func(ctx context.Context, network, addr string) (net.Conn, error)
+		giveOptions []Option
+	}{
+		{
+			name: "dial",
+			giveDial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+		{
+			name:        "auth",
+			giveDial:    testHungDial,
+			giveOptions: []Option{WithPassword("secret")},
+		},
+		{
+			name:     "command",
+			giveDial: testHungDial,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithDialFunc(tt.giveDial), WithTimeout(10 * time.Millisecond)}, tt.giveOptions...)
+			c, err := NewCounter("redis:6379", opts...)
+			assert.NoError(t, err)
+
+			_, err = c.Incr(context.Background(), "key", time.Minute)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Nil(t, c.conn)
+		})
+	}
+}
+
+// TestCounterIncrCanceled tests that Counter.Incr returns when its context is canceled, both while
+// waiting for Redis and while waiting for another call to Incr.
+func TestCounterIncrCanceled(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCounter("redis:6379", WithDialFunc(testHungDial), WithTimeout(time.Hour))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := c.Incr(ctx, "key", time.Minute)
+		errs <- err
+	}()
+
+	// wait until the first call holds the connection
+	assert.Eventually(t, func() bool { return len(c.sem) == 1 }, time.Second, time.Millisecond)
+
+	queued, cancelQueued := context.WithCancel(context.Background())
+	cancelQueued()
+	_, err = c.Incr(queued, "key", time.Minute)
+	assert.Equal(t, context.Canceled, err)
+
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.Nil(t, c.conn)
+}
+
+// TestCounterCoordinator tests a Counter with a fault.CounterCoordinator.
+func TestCounterCoordinator(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer()
+	c, err := NewCounter("redis:6379", WithDialFunc(s.dial))
+	assert.NoError(t, err)
+
+	cc, err := fault.NewCounterCoordinator(c, 1, time.Hour)
+	assert.NoError(t, err)
+
+	assert.True(t, cc.Allow(context.Background(), "ErrorInjector"))
+	assert.False(t, cc.Allow(context.Background(), "ErrorInjector"))
+}
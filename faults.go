@@ -0,0 +1,174 @@
+package fault
+
+import (
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FaultRule is one rule of a Faults list. A request that Match returns true for runs Injector on
+// Participation percent of those requests.
+type FaultRule struct {
+	// Match returns true for the requests the rule applies to. A nil Match matches every request.
+	Match func(r *http.Request) bool
+	// Injector runs on the requests that match and participate.
+	Injector Injector
+	// Participation is the percent of matching requests that run Injector. 0.0 <= p <= 1.0. A rule
+	// with a Participation of 0 never runs.
+	Participation float32
+}
+
+// Faults evaluates an ordered list of FaultRules in a single middleware. By default the first rule
+// that matches a request wins: it runs if it participates and the rest are skipped either way. With
+// WithAllMatch every rule that matches and participates runs, in order.
+type Faults struct {
+	rules    []FaultRule
+	allMatch bool
+
+	randSeed int64
+	rand     *rand.Rand
+	randF    func() float32
+
+	// *rand.Rand is not thread safe. This mutex protects our random source
+	randMtx sync.Mutex
+}
+
+// FaultsOption configures Faults.
+type FaultsOption interface {
+	applyFaults(fs *Faults) error
+}
+
+func (o randSeedOption) applyFaults(fs *Faults) error {
+	fs.randSeed = int64(o)
+	return nil
+}
+
+func (o randFloat32FuncOption) applyFaults(fs *Faults) error {
+	fs.randF = o
+	return nil
+}
+
+type allMatchOption struct{}
+
+func (o allMatchOption) applyFaults(fs *Faults) error {
+	fs.allMatch = true
+	return nil
+}
+
+// WithAllMatch runs every rule that matches and participates instead of only the first. Each rule
+// continues to the next matching rule, and the last one continues to the next handler. A rule
+// whose Injector does not continue the request stops the rest of the list.
+func WithAllMatch() FaultsOption {
+	return allMatchOption{}
+}
+
+// NewFaults returns Faults that evaluate rules in order. Every rule must have an Injector and a
+// valid Participation. NewFaults copies rules, so changing the slice later does not change the
+// Faults.
+func NewFaults(rules []FaultRule, opts ...FaultsOption) (*Faults, error) {
+	for _, rule := range rules {
+		if rule.Injector == nil {
+			return nil, &OptionError{Option: "NewFaults", Value: nil, Err: ErrNilInjector}
+		}
+		if rule.Participation < 0.0 || rule.Participation > 1.0 {
+			return nil, &OptionError{Option: "NewFaults", Value: rule.Participation, Err: ErrInvalidPercent}
+		}
+	}
+
+	// set defaults
+	fs := &Faults{
+		rules:    slices.Clone(rules),
+		randSeed: defaultRandSeed,
+		randF:    nil,
+	}
+
+	// apply options
+	err := applyOptions(opts, FaultsOption.applyFaults, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	// set seeded rand source and function
+	fs.rand = rand.New(rand.NewSource(fs.randSeed))
+	if fs.randF == nil {
+		fs.randF = fs.rand.Float32
+	}
+
+	return fs, nil
+}
+
+// Handler evaluates the rules for each request and runs the Injectors of the rules that are
+// chosen, ending with next. The Injector handlers are built once, when Handler is called.
+func (fs *Faults) Handler(next http.Handler) http.Handler {
+	handlers := make([]http.Handler, len(fs.rules))
+
+	// from returns a handler that runs the first chosen rule at or after idx, or next if there is
+	// none.
+	from := func(idx int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := fs.choose(idx, r)
+			if k < 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			handlers[k].ServeHTTP(w, r)
+		})
+	}
+
+	for idx, rule := range fs.rules {
+		cont := next
+		if fs.allMatch {
+			cont = from(idx + 1)
+		}
+		handlers[idx] = rule.Injector.Handler(cont)
+	}
+
+	return from(0)
+}
+
+// choose returns the index of the rule at or after idx to run on r, or -1 if there is none.
+// Participation is only rolled for rules that match. By default only the first rule that matches
+// is rolled for, and with WithAllMatch the first rule that matches and participates is chosen.
+func (fs *Faults) choose(idx int, r *http.Request) int {
+	for ; idx < len(fs.rules); idx++ {
+		rule := fs.rules[idx]
+		if rule.Match != nil && !rule.Match(r) {
+			continue
+		}
+		if fs.participate(rule.Participation) {
+			return idx
+		}
+		if !fs.allMatch {
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// participate randomly decides (returns true) if a rule with participation p should run.
+func (fs *Faults) participate(p float32) bool {
+	fs.randMtx.Lock()
+	rn := fs.randF()
+	fs.randMtx.Unlock()
+
+	return rn < p
+}
+
+// String describes the Faults and their rules, such as
+// "Faults(first-match)[ErrorInjector(503) @ 5%, SlowInjector(1s) @ 10%]".
+func (fs *Faults) String() string {
+	mode := "first-match"
+	if fs.allMatch {
+		mode = "all-match"
+	}
+
+	ss := make([]string, 0, len(fs.rules))
+	for _, rule := range fs.rules {
+		ss = append(ss, injectorString(rule.Injector)+rateSeparator+percentString(rule.Participation))
+	}
+
+	return "Faults(" + mode + ")[" + strings.Join(ss, ", ") + "]"
+}
@@ -0,0 +1,223 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewFaults tests NewFaults.
+func TestNewFaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveRules   []FaultRule
+		giveOptions []FaultsOption
+		wantErr     error
+	}{
+		{
+			name:        "nil",
+			giveRules:   nil,
+			giveOptions: nil,
+			wantErr:     nil,
+		},
+		{
+			name: "all options",
+			giveRules: []FaultRule{
+				{Injector: newTestInjectorNoop(), Participation: 1.0},
+				{Match: func(*http.Request) bool { return true }, Injector: newTestInjector500s()},
+			},
+			giveOptions: []FaultsOption{
+				WithAllMatch(),
+				WithRandSeed(100),
+				WithRandFloat32Func(func() float32 { return 0.0 }),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "nil injector",
+			giveRules: []FaultRule{
+				{Injector: nil, Participation: 1.0},
+			},
+			giveOptions: nil,
+			wantErr:     &OptionError{Option: "NewFaults", Value: nil, Err: ErrNilInjector},
+		},
+		{
+			name: "invalid participation",
+			giveRules: []FaultRule{
+				{Injector: newTestInjectorNoop(), Participation: 1.1},
+			},
+			giveOptions: nil,
+			wantErr:     &OptionError{Option: "NewFaults", Value: float32(1.1), Err: ErrInvalidPercent},
+		},
+		{
+			name:        "option error",
+			giveRules:   nil,
+			giveOptions: []FaultsOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs, err := NewFaults(tt.giveRules, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.NotNil(t, fs)
+				assert.NotNil(t, fs.randF)
+			} else {
+				assert.Nil(t, fs)
+			}
+		})
+	}
+}
+
+// TestFaultsHandler tests Faults.Handler.
+func TestFaultsHandler(t *testing.T) {
+	t.Parallel()
+
+	matchA := func(r *http.Request) bool { return r.URL.Path == "/a" }
+	rules := []FaultRule{
+		{Match: matchA, Injector: newTestInjectorOneOK(), Participation: 0.1},
+		{Match: matchA, Injector: newTestInjectorTwoTeapot(), Participation: 1.0},
+		{Match: nil, Injector: newTestInjectorOneOK(), Participation: 1.0},
+		{Match: nil, Injector: newTestInjector500s(), Participation: 1.0},
+	}
+
+	tests := []struct {
+		name        string
+		giveRules   []FaultRule
+		giveOptions []FaultsOption
+		giveRand    float32
+		givePath    string
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name:      "no rules",
+			giveRules: nil,
+			giveRand:  0.0,
+			givePath:  "/a",
+			wantCode:  http.StatusOK,
+			wantBody:  "next",
+		},
+		{
+			name:      "first match",
+			giveRules: rules,
+			giveRand:  0.0,
+			givePath:  "/a",
+			wantCode:  http.StatusOK,
+			wantBody:  "onenext",
+		},
+		{
+			name:      "first match does not participate",
+			giveRules: rules,
+			giveRand:  0.5,
+			givePath:  "/a",
+			wantCode:  http.StatusOK,
+			wantBody:  "next",
+		},
+		{
+			name:      "first match without matcher",
+			giveRules: rules,
+			giveRand:  0.0,
+			givePath:  "/b",
+			wantCode:  http.StatusOK,
+			wantBody:  "onenext",
+		},
+		{
+			name:      "no participation",
+			giveRules: rules[:2],
+			giveRand:  1.0,
+			givePath:  "/a",
+			wantCode:  http.StatusOK,
+			wantBody:  "next",
+		},
+		{
+			name:        "all match",
+			giveRules:   rules[:3],
+			giveOptions: []FaultsOption{WithAllMatch()},
+			giveRand:    0.0,
+			givePath:    "/a",
+			wantCode:    http.StatusOK,
+			wantBody:    "onetwoonenext",
+		},
+		{
+			name:        "all match skips rules",
+			giveRules:   rules[:3],
+			giveOptions: []FaultsOption{WithAllMatch()},
+			giveRand:    0.5,
+			givePath:    "/b",
+			wantCode:    http.StatusOK,
+			wantBody:    "onenext",
+		},
+		{
+			name:        "all match stops",
+			giveRules:   rules,
+			giveOptions: []FaultsOption{WithAllMatch()},
+			giveRand:    0.5,
+			givePath:    "/a",
+			wantCode:    http.StatusTeapot,
+			wantBody:    "twoone" + http.StatusText(http.StatusInternalServerError) + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]FaultsOption{WithRandFloat32Func(func() float32 { return tt.giveRand })}, tt.giveOptions...)
+			fs, err := NewFaults(tt.giveRules, opts...)
+			assert.NoError(t, err)
+
+			h := fs.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "next")
+			}))
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.givePath, nil))
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+// TestFaultsString tests Faults.String.
+func TestFaultsString(t *testing.T) {
+	t.Parallel()
+
+	rules := []FaultRule{
+		{Injector: newTestInjectorNoop(), Participation: 0.05},
+		{Injector: newTestInjector500s(), Participation: 1.0},
+	}
+
+	fs, err := NewFaults(rules)
+	assert.NoError(t, err)
+	assert.Equal(t, "Faults(first-match)[testInjectorNoop @ 5%, testInjector500s @ 100%]", fs.String())
+
+	fs, err = NewFaults(rules[:1], WithAllMatch())
+	assert.NoError(t, err)
+	assert.Equal(t, "Faults(all-match)[testInjectorNoop @ 5%]", fs.String())
+}
+
+// TestNewFaultsCopiesRules tests that changing the rules passed to NewFaults does not change the
+// Faults.
+func TestNewFaultsCopiesRules(t *testing.T) {
+	t.Parallel()
+
+	rules := []FaultRule{{Injector: newTestInjector500s(), Participation: 1.0}}
+	fs, err := NewFaults(rules)
+	assert.NoError(t, err)
+
+	rules[0] = FaultRule{Injector: newTestInjectorNoop(), Participation: 0.0}
+
+	assert.Equal(t, "Faults(first-match)[testInjector500s @ 100%]", fs.String())
+}
@@ -0,0 +1,68 @@
+package faulttest
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/lingrino/go-fault"
+)
+
+// AssertInjected asserts that each named Fault injected into r. With no names it asserts that at
+// least one Fault injected into r. It returns true if the assertion passed.
+//
+// Use AssertInjected on the request received by the handler that a Fault wraps, for example from
+// a test handler that stores the request it serves.
+func AssertInjected(t testing.TB, r *http.Request, names ...string) bool {
+	t.Helper()
+
+	injected := contextNames(r, fault.ContextKeyInjected)
+
+	if len(names) == 0 && len(injected) == 0 {
+		t.Errorf("faulttest: no faults injected")
+		return false
+	}
+
+	return assertContains(t, "injected", injected, names)
+}
+
+// AssertSkipped asserts that each named Fault evaluated r without injecting. With no names it
+// asserts that no Fault injected into r. It returns true if the assertion passed.
+//
+// Disabled Faults and Faults that are warming up do not evaluate requests and are neither
+// injected nor skipped.
+func AssertSkipped(t testing.TB, r *http.Request, names ...string) bool {
+	t.Helper()
+
+	if len(names) == 0 {
+		injected := contextNames(r, fault.ContextKeyInjected)
+		if len(injected) > 0 {
+			t.Errorf("faulttest: faults injected: %v", injected)
+			return false
+		}
+		return true
+	}
+
+	return assertContains(t, "skipped", contextNames(r, fault.ContextKeySkipped), names)
+}
+
+// contextNames returns the Fault names stored in the context of r under key.
+func contextNames(r *http.Request, key fault.ContextKey) []string {
+	names, _ := r.Context().Value(key).([]string)
+	return names
+}
+
+// assertContains asserts that got contains every name in want.
+func assertContains(t testing.TB, kind string, got []string, want []string) bool {
+	t.Helper()
+
+	ok := true
+	for _, name := range want {
+		if !slices.Contains(got, name) {
+			t.Errorf("faulttest: fault %q not %s, got %v", name, kind, got)
+			ok = false
+		}
+	}
+
+	return ok
+}
@@ -0,0 +1,141 @@
+package faulttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lingrino/go-fault"
+	"github.com/stretchr/testify/assert"
+)
+
+// testServe serves a request through faults and returns the request received by the final
+// handler.
+func testServe(t *testing.T, faults ...*fault.Fault) *http.Request {
+	t.Helper()
+
+	var got *http.Request
+	h := fault.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}), faults...)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return got
+}
+
+// testFault returns a Fault with a SlowInjector so that the request always continues.
+func testFault(t *testing.T, name string, enabled bool, participation float32) *fault.Fault {
+	t.Helper()
+
+	si, err := fault.NewSlowInjector(0)
+	assert.NoError(t, err)
+
+	f, err := fault.NewFault(si,
+		fault.WithName(name),
+		fault.WithEnabled(enabled),
+		fault.WithParticipation(participation),
+	)
+	assert.NoError(t, err)
+
+	return f
+}
+
+// TestAssertInjectedSkipped tests AssertInjected and AssertSkipped.
+func TestAssertInjectedSkipped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		giveFaults     func(t *testing.T) []*fault.Fault
+		giveNames      []string
+		wantInjectedOK bool
+		wantSkippedOK  bool
+	}{
+		{
+			name:           "no faults no names",
+			giveFaults:     func(t *testing.T) []*fault.Fault { return nil },
+			giveNames:      nil,
+			wantInjectedOK: false,
+			wantSkippedOK:  true,
+		},
+		{
+			name: "injected no names",
+			giveFaults: func(t *testing.T) []*fault.Fault {
+				return []*fault.Fault{testFault(t, "slow", true, 1.0)}
+			},
+			giveNames:      nil,
+			wantInjectedOK: true,
+			wantSkippedOK:  false,
+		},
+		{
+			name: "injected",
+			giveFaults: func(t *testing.T) []*fault.Fault {
+				return []*fault.Fault{testFault(t, "slow", true, 1.0)}
+			},
+			giveNames:      []string{"slow"},
+			wantInjectedOK: true,
+			wantSkippedOK:  false,
+		},
+		{
+			name: "skipped",
+			giveFaults: func(t *testing.T) []*fault.Fault {
+				return []*fault.Fault{testFault(t, "slow", true, 0.0)}
+			},
+			giveNames:      []string{"slow"},
+			wantInjectedOK: false,
+			wantSkippedOK:  true,
+		},
+		{
+			name: "disabled",
+			giveFaults: func(t *testing.T) []*fault.Fault {
+				return []*fault.Fault{testFault(t, "slow", false, 1.0)}
+			},
+			giveNames:      []string{"slow"},
+			wantInjectedOK: false,
+			wantSkippedOK:  false,
+		},
+		{
+			name: "one injected one skipped",
+			giveFaults: func(t *testing.T) []*fault.Fault {
+				return []*fault.Fault{
+					testFault(t, "one", true, 1.0),
+					testFault(t, "two", true, 0.0),
+				}
+			},
+			giveNames:      []string{"one", "two"},
+			wantInjectedOK: false,
+			wantSkippedOK:  false,
+		},
+		{
+			name: "both injected",
+			giveFaults: func(t *testing.T) []*fault.Fault {
+				return []*fault.Fault{
+					testFault(t, "one", true, 1.0),
+					testFault(t, "two", true, 1.0),
+				}
+			},
+			giveNames:      []string{"one", "two"},
+			wantInjectedOK: true,
+			wantSkippedOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := testServe(t, tt.giveFaults(t)...)
+
+			tbInjected := &testTB{TB: t}
+			ok := AssertInjected(tbInjected, r, tt.giveNames...)
+			assert.Equal(t, tt.wantInjectedOK, ok)
+			assert.Equal(t, !tt.wantInjectedOK, tbInjected.failed)
+
+			tbSkipped := &testTB{TB: t}
+			ok = AssertSkipped(tbSkipped, r, tt.giveNames...)
+			assert.Equal(t, tt.wantSkippedOK, ok)
+			assert.Equal(t, !tt.wantSkippedOK, tbSkipped.failed)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package faulttest provides utilities for testing code that uses the fault package.
+package faulttest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lingrino/go-fault"
+)
+
+const (
+	// ReportTimeout is how long AssertReported waits for an event. Injectors report
+	// asynchronously, so events may arrive shortly after a request completes.
+	ReportTimeout = time.Second
+
+	// reportPollInterval is how often AssertReported checks for an event.
+	reportPollInterval = time.Millisecond
+)
+
+// Event is a single event received by a Reporter.
+type Event struct {
+	Name  string
+	State fault.InjectorState
+}
+
+// Reporter is a thread safe fault.Reporter that records every event it receives.
+type Reporter struct {
+	events []Event
+
+	// eventsMtx protects Reporter.events.
+	eventsMtx sync.Mutex
+}
+
+// NewReporter returns a new Reporter.
+func NewReporter() *Reporter {
+	return &Reporter{}
+}
+
+// Report records the event.
+func (r *Reporter) Report(name string, state fault.InjectorState) {
+	r.eventsMtx.Lock()
+	defer r.eventsMtx.Unlock()
+
+	r.events = append(r.events, Event{Name: name, State: state})
+}
+
+// Events returns a copy of all recorded events in the order they were received. Injectors report
+// asynchronously so the order received may differ from the order the events happened.
+func (r *Reporter) Events() []Event {
+	r.eventsMtx.Lock()
+	defer r.eventsMtx.Unlock()
+
+	return append([]Event{}, r.events...)
+}
+
+// Reset removes all recorded events.
+func (r *Reporter) Reset() {
+	r.eventsMtx.Lock()
+	defer r.eventsMtx.Unlock()
+
+	r.events = nil
+}
+
+// AssertReported asserts that the Reporter received an event with name and state, waiting up to
+// ReportTimeout for the event to arrive. It returns true if the event was received.
+func (r *Reporter) AssertReported(t testing.TB, name string, state fault.InjectorState) bool {
+	t.Helper()
+
+	deadline := time.Now().Add(ReportTimeout)
+	for {
+		if r.reported(name, state) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("faulttest: event {%s %s} not reported, got %v", name, state, r.Events())
+			return false
+		}
+		time.Sleep(reportPollInterval)
+	}
+}
+
+// reported returns true if the Reporter has received an event with name and state.
+func (r *Reporter) reported(name string, state fault.InjectorState) bool {
+	for _, e := range r.Events() {
+		if e.Name == name && e.State == state {
+			return true
+		}
+	}
+
+	return false
+}
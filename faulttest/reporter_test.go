@@ -0,0 +1,135 @@
+package faulttest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/lingrino/go-fault"
+	"github.com/stretchr/testify/assert"
+)
+
+// testTB records failures instead of failing the test.
+type testTB struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+// Helper does nothing.
+func (t *testTB) Helper() {}
+
+// Errorf records that the test failed and the formatted message.
+func (t *testTB) Errorf(format string, args ...any) {
+	t.failed = true
+	t.msg = fmt.Sprintf(format, args...)
+}
+
+// TestReporter tests Reporter.Report, Reporter.Events, and Reporter.Reset.
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	r := NewReporter()
+	assert.Empty(t, r.Events())
+
+	var wg sync.WaitGroup
+	for idx := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Report(fmt.Sprint(idx), fault.StateStarted)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, r.Events(), 10)
+
+	r.Reset()
+	assert.Empty(t, r.Events())
+
+	r.Report("first", fault.StateStarted)
+	r.Report("second", fault.StateFinished)
+	assert.Equal(t, []Event{
+		{Name: "first", State: fault.StateStarted},
+		{Name: "second", State: fault.StateFinished},
+	}, r.Events())
+}
+
+// TestReporterAssertReported tests Reporter.AssertReported.
+func TestReporterAssertReported(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		giveEvents []Event
+		giveName   string
+		giveState  fault.InjectorState
+		wantOK     bool
+		wantMsg    string
+	}{
+		{
+			name:       "reported",
+			giveEvents: []Event{{Name: "ErrorInjector", State: fault.StateStarted}},
+			giveName:   "ErrorInjector",
+			giveState:  fault.StateStarted,
+			wantOK:     true,
+		},
+		{
+			name:       "wrong state",
+			giveEvents: []Event{{Name: "ErrorInjector", State: fault.StateStarted}},
+			giveName:   "ErrorInjector",
+			giveState:  fault.StateFinished,
+			wantOK:     false,
+			wantMsg:    "faulttest: event {ErrorInjector finished} not reported, got [{ErrorInjector started}]",
+		},
+		{
+			name:       "none",
+			giveEvents: nil,
+			giveName:   "ErrorInjector",
+			giveState:  fault.StateStarted,
+			wantOK:     false,
+			wantMsg:    "faulttest: event {ErrorInjector started} not reported, got []",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewReporter()
+			for _, e := range tt.giveEvents {
+				r.Report(e.Name, e.State)
+			}
+
+			tb := &testTB{TB: t}
+			ok := r.AssertReported(tb, tt.giveName, tt.giveState)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, !tt.wantOK, tb.failed)
+			assert.Equal(t, tt.wantMsg, tb.msg)
+		})
+	}
+}
+
+// TestReporterInjector tests a Reporter with an Injector.
+func TestReporterInjector(t *testing.T) {
+	t.Parallel()
+
+	r := NewReporter()
+
+	ei, err := fault.NewErrorInjector(500, fault.WithReporter(r))
+	assert.NoError(t, err)
+
+	f, err := fault.NewFault(ei,
+		fault.WithEnabled(true),
+		fault.WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	err = fault.Do(context.Background(), f, func(ctx context.Context) error { return nil })
+	assert.ErrorIs(t, err, fault.ErrInjected)
+
+	r.AssertReported(t, "ErrorInjector", fault.StateStarted)
+	r.AssertReported(t, "ErrorInjector", fault.StateFinished)
+}
@@ -0,0 +1,76 @@
+package fault
+
+import (
+	"io"
+	"io/fs"
+)
+
+// faultFS is an fs.FS that injects faults into opens and reads.
+type faultFS struct {
+	fsys fs.FS
+	s    *stream
+}
+
+// FS returns an fs.FS that opens files from fsys and injects faults into a participation percent of
+// opens and reads. It accepts the same options as Reader. WithStreamError fails opens with an
+// *fs.PathError wrapping the error, such as fs.ErrNotExist or fs.ErrPermission, while
+// WithStreamDelay slows both opens and reads and WithShortIO shortens reads. Directories are
+// returned without wrapping. Use FS to test services that serve embedded or on-disk assets.
+func FS(fsys fs.FS, opts ...StreamOption) (fs.FS, error) {
+	s, err := newStream(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultFS{fsys: fsys, s: s}, nil
+}
+
+// Open opens the named file from the wrapped fs.FS, injecting faults.
+func (fsys *faultFS) Open(name string) (fs.File, error) {
+	if fsys.s.participate() {
+		fsys.s.wait()
+		if fsys.s.err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fsys.s.err}
+		}
+	}
+
+	f, err := fsys.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the mode rather than fs.ReadDirFile because some files, such as *os.File, implement
+	// ReadDir whether or not they are directories.
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		return f, nil
+	}
+
+	ff := &faultFile{File: f, s: fsys.s}
+	if seeker, ok := f.(io.Seeker); ok {
+		return &faultSeekFile{faultFile: ff, Seeker: seeker}, nil
+	}
+	return ff, nil
+}
+
+// faultFile is an fs.File that injects faults into reads.
+type faultFile struct {
+	fs.File
+	s *stream
+}
+
+// Read reads from the wrapped fs.File, injecting faults.
+func (f *faultFile) Read(p []byte) (int, error) {
+	if f.s.participate() {
+		f.s.wait()
+		p = f.s.shorten(p)
+	}
+
+	return f.File.Read(p)
+}
+
+// faultSeekFile is a faultFile that keeps the io.Seeker of the wrapped fs.File, which is required to
+// serve the file with http.FS.
+type faultSeekFile struct {
+	*faultFile
+	io.Seeker
+}
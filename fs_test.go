@@ -0,0 +1,181 @@
+package fault
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testFS returns an fs.FS with one file and one directory.
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"dir/file.txt": &fstest.MapFile{Data: []byte("abcdef")},
+	}
+}
+
+// noSeekFS is an fs.FS whose files cannot seek.
+type noSeekFS struct {
+	fsys fs.FS
+}
+
+// Open opens the named file and hides its io.Seeker.
+func (fsys noSeekFS) Open(name string) (fs.File, error) {
+	f, err := fsys.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ fs.File }{f}, nil
+}
+
+// TestFS tests FS.
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []StreamOption
+		wantReads   []string
+		wantErr     error
+		wantSlept   time.Duration
+	}{
+		{
+			name:        "no participation",
+			giveOptions: []StreamOption{WithShortIO(), WithStreamError(fs.ErrNotExist)},
+			wantReads:   []string{"abcd", "ef"},
+			wantErr:     nil,
+			wantSlept:   0,
+		},
+		{
+			name:        "slow",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamDelay(time.Second)},
+			wantReads:   []string{"abcd", "ef"},
+			wantErr:     nil,
+			wantSlept:   4 * time.Second,
+		},
+		{
+			name:        "short",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithShortIO()},
+			wantReads:   []string{"ab", "cd", "ef"},
+			wantErr:     nil,
+			wantSlept:   0,
+		},
+		{
+			name:        "not exist",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamError(fs.ErrNotExist)},
+			wantReads:   nil,
+			wantErr:     &fs.PathError{Op: "open", Path: "dir/file.txt", Err: fs.ErrNotExist},
+			wantSlept:   0,
+		},
+		{
+			name:        "permission",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamError(fs.ErrPermission)},
+			wantReads:   nil,
+			wantErr:     &fs.PathError{Op: "open", Path: "dir/file.txt", Err: fs.ErrPermission},
+			wantSlept:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var slept time.Duration
+			fsys, err := FS(testFS(), append(tt.giveOptions,
+				WithSlowFunc(func(d time.Duration) { slept += d }),
+			)...)
+			assert.NoError(t, err)
+
+			f, err := fsys.Open("dir/file.txt")
+			assert.Equal(t, tt.wantErr, err)
+
+			var reads []string
+			if f != nil {
+				buf := make([]byte, 4)
+				for {
+					n, err := f.Read(buf)
+					if err != nil {
+						assert.Equal(t, io.EOF, err)
+						break
+					}
+					reads = append(reads, string(buf[:n]))
+				}
+				assert.NoError(t, f.Close())
+			}
+
+			assert.Equal(t, tt.wantReads, reads)
+			assert.Equal(t, tt.wantSlept, slept)
+		})
+	}
+}
+
+// TestFSWrappedFiles tests that FS keeps the interfaces of the files it opens.
+func TestFSWrappedFiles(t *testing.T) {
+	t.Parallel()
+
+	fsys, err := FS(testFS())
+	assert.NoError(t, err)
+
+	f, err := fsys.Open("dir/file.txt")
+	assert.NoError(t, err)
+	assert.Implements(t, (*io.Seeker)(nil), f)
+
+	entries, err := fs.ReadDir(fsys, "dir")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = fsys.Open("missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	fsys, err = FS(noSeekFS{fsys: testFS()})
+	assert.NoError(t, err)
+
+	f, err = fsys.Open("dir/file.txt")
+	assert.NoError(t, err)
+	assert.IsType(t, &faultFile{}, f)
+
+	b, err := fs.ReadFile(fsys, "dir/file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdef", string(b))
+}
+
+// TestFSDirFS tests that FS injects faults into the files of an os.DirFS, whose files implement
+// fs.ReadDirFile.
+func TestFSDirFS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "dir", "file.txt"), []byte("abcdef"), 0o600))
+
+	fsys, err := FS(os.DirFS(dir), WithParticipation(1.0), WithShortIO())
+	assert.NoError(t, err)
+
+	f, err := fsys.Open("dir/file.txt")
+	assert.NoError(t, err)
+	assert.IsType(t, &faultSeekFile{}, f)
+
+	buf := make([]byte, 4)
+	n, err := f.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", string(buf[:n]))
+	assert.NoError(t, f.Close())
+
+	entries, err := fs.ReadDir(fsys, "dir")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+// TestFSOptionError tests FS with invalid options.
+func TestFSOptionError(t *testing.T) {
+	t.Parallel()
+
+	fsys, err := FS(testFS(), withError())
+	assert.Nil(t, fsys)
+	assert.Equal(t, errErrorOption, err)
+}
@@ -0,0 +1,182 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"net/http"
+	"sync"
+)
+
+var (
+	// ErrNilGroup when a nil FaultGroup is provided.
+	ErrNilGroup = errors.New("group cannot be nil")
+)
+
+// FaultGroup makes a single participation decision per request that is shared by every Fault added
+// to it with WithGroup, so that Faults in different middlewares inject into the same requests
+// instead of independent slices of traffic. Each request the group decides on also gets a
+// correlation ID.
+type FaultGroup struct {
+	name          string
+	participation float32
+
+	// idF returns a new correlation ID.
+	idF func() string
+
+	randSeed int64
+	rand     *rand.Rand
+	randF    func() float32
+
+	// randMtx protects FaultGroup.rand, which is not thread safe.
+	randMtx sync.Mutex
+}
+
+// FaultGroupOption configures a FaultGroup.
+type FaultGroupOption interface {
+	applyFaultGroup(g *FaultGroup) error
+}
+
+func (o nameOption) applyFaultGroup(g *FaultGroup) error {
+	g.name = string(o)
+	return nil
+}
+
+func (o randSeedOption) applyFaultGroup(g *FaultGroup) error {
+	g.randSeed = int64(o)
+	return nil
+}
+
+func (o randFloat32FuncOption) applyFaultGroup(g *FaultGroup) error {
+	g.randF = o
+	return nil
+}
+
+type correlationIDFuncOption func() string
+
+func (o correlationIDFuncOption) applyFaultGroup(g *FaultGroup) error {
+	if o == nil {
+		return &OptionError{Option: "WithCorrelationIDFunc", Value: nil, Err: ErrNilFunc}
+	}
+	g.idF = o
+	return nil
+}
+
+// CorrelationIDFuncOption configures things that can set a function to get a new correlation ID.
+type CorrelationIDFuncOption interface {
+	Option
+	FaultGroupOption
+}
+
+// WithCorrelationIDFunc sets the function that returns a new correlation ID for each request of a
+// FaultGroup or each injection of a Fault with WithInjectionID. Default 16 random hex characters.
+func WithCorrelationIDFunc(f func() string) CorrelationIDFuncOption {
+	return correlationIDFuncOption(f)
+}
+
+// NewFaultGroup returns a FaultGroup whose Faults inject into participation percent of requests.
+func NewFaultGroup(participation float32, opts ...FaultGroupOption) (*FaultGroup, error) {
+	if participation < 0 || participation > 1.0 {
+		return nil, &OptionError{Option: "NewFaultGroup", Value: participation, Err: ErrInvalidPercent}
+	}
+
+	// set defaults
+	g := &FaultGroup{
+		name:          "FaultGroup",
+		participation: participation,
+		idF:           newCorrelationID,
+		randSeed:      defaultRandSeed,
+		randF:         nil,
+	}
+
+	// apply options
+	err := applyOptions(opts, FaultGroupOption.applyFaultGroup, g)
+	if err != nil {
+		return nil, err
+	}
+
+	// set seeded rand source and function
+	g.rand = rand.New(rand.NewSource(g.randSeed))
+	if g.randF == nil {
+		g.randF = g.rand.Float32
+	}
+
+	return g, nil
+}
+
+// CorrelationID returns the correlation ID the FaultGroup assigned to the request with ctx, or ""
+// if none of its Faults have handled the request.
+func (g *FaultGroup) CorrelationID(ctx context.Context) string {
+	return g.decision(ctx).id
+}
+
+// String describes the FaultGroup, such as "FaultGroup @ 1%".
+func (g *FaultGroup) String() string {
+	return g.name + rateSeparator + percentString(g.participation)
+}
+
+// groupContextKey is the request context key for the groupDecision of a FaultGroup.
+type groupContextKey struct {
+	group *FaultGroup
+}
+
+// groupDecision is the participation decision of a FaultGroup for one request.
+type groupDecision struct {
+	participate bool
+	roll        float32
+	id          string
+}
+
+// withDecision returns r with a decision from the FaultGroup in its context. The first Fault in the
+// group to handle a request decides, and later Faults reuse that decision.
+func (g *FaultGroup) withDecision(r *http.Request) *http.Request {
+	key := groupContextKey{g}
+	if _, ok := r.Context().Value(key).(groupDecision); ok {
+		return r
+	}
+
+	g.randMtx.Lock()
+	rn := g.randF()
+	g.randMtx.Unlock()
+
+	d := groupDecision{
+		participate: rn < g.participation,
+		roll:        rn,
+		id:          g.idF(),
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), key, d))
+}
+
+// decision returns the decision in ctx, see withDecision.
+func (g *FaultGroup) decision(ctx context.Context) groupDecision {
+	d, _ := ctx.Value(groupContextKey{g}).(groupDecision)
+	return d
+}
+
+// newCorrelationID returns a random 64 bit number as 16 hex characters. It uses the randomly seeded
+// math/rand/v2 source so that instances sharing a seed do not share correlation IDs.
+func newCorrelationID() string {
+	return fmt.Sprintf("%016x", randv2.Uint64())
+}
+
+type groupOption struct {
+	group *FaultGroup
+}
+
+func (o groupOption) applyFault(f *Fault) error {
+	if o.group == nil {
+		return &OptionError{Option: "WithGroup", Value: nil, Err: ErrNilGroup}
+	}
+	f.group = o.group
+	return nil
+}
+
+// WithGroup adds the Fault to a FaultGroup. The group decides whether the Fault participates in
+// each request instead of the Fault's own participation or deterministic mode. Every other check,
+// such as allowlists and Guards, still applies to each Fault independently.
+func WithGroup(g *FaultGroup) Option {
+	return groupOption{group: g}
+}
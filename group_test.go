@@ -0,0 +1,216 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewFaultGroup tests NewFaultGroup.
+func TestNewFaultGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		giveParticipation float32
+		giveOptions       []FaultGroupOption
+		wantString        string
+		wantErr           error
+	}{
+		{
+			name:              "defaults",
+			giveParticipation: 0.01,
+			giveOptions:       nil,
+			wantString:        "FaultGroup @ 1%",
+			wantErr:           nil,
+		},
+		{
+			name:              "options",
+			giveParticipation: 0.5,
+			giveOptions: []FaultGroupOption{
+				WithName("checkout"),
+				WithRandSeed(100),
+				WithRandFloat32Func(func() float32 { return 0.0 }),
+				WithCorrelationIDFunc(func() string { return "id" }),
+			},
+			wantString: "checkout @ 50%",
+			wantErr:    nil,
+		},
+		{
+			name:              "invalid participation",
+			giveParticipation: 1.1,
+			wantErr:           &OptionError{Option: "NewFaultGroup", Value: float32(1.1), Err: ErrInvalidPercent},
+		},
+		{
+			name:              "negative participation",
+			giveParticipation: -0.1,
+			wantErr:           &OptionError{Option: "NewFaultGroup", Value: float32(-0.1), Err: ErrInvalidPercent},
+		},
+		{
+			name:              "nil correlation id func",
+			giveParticipation: 0.5,
+			giveOptions:       []FaultGroupOption{WithCorrelationIDFunc(nil)},
+			wantErr:           &OptionError{Option: "WithCorrelationIDFunc", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:              "option error",
+			giveParticipation: 0.5,
+			giveOptions:       []FaultGroupOption{withError()},
+			wantErr:           errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := NewFaultGroup(tt.giveParticipation, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantString, g.String())
+			} else {
+				assert.Nil(t, g)
+			}
+		})
+	}
+}
+
+// TestFaultGroup tests that Faults in a FaultGroup share one decision and correlation ID per
+// request.
+func TestFaultGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveRolls    []float32
+		wantInjected [][]string
+	}{
+		{
+			name:         "all selected",
+			giveRolls:    []float32{0.1, 0.2},
+			wantInjected: [][]string{{"slow", "error"}, {"slow", "error"}},
+		},
+		{
+			name:         "none selected",
+			giveRolls:    []float32{0.9, 0.6},
+			wantInjected: [][]string{nil, nil},
+		},
+		{
+			name:         "mixed",
+			giveRolls:    []float32{0.9, 0.1},
+			wantInjected: [][]string{nil, {"slow", "error"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var roll, ids int
+			g, err := NewFaultGroup(0.5,
+				WithRandFloat32Func(func() float32 {
+					roll++
+					return tt.giveRolls[roll-1]
+				}),
+				WithCorrelationIDFunc(func() string {
+					ids++
+					return string(rune('a' + ids - 1))
+				}),
+			)
+			assert.NoError(t, err)
+
+			// the Faults' own participation is ignored
+			slow, err := NewFault(newTestInjectorNoop(), WithName("slow"), WithEnabled(true), WithGroup(g))
+			assert.NoError(t, err)
+			errf, err := NewFault(newTestInjectorNoop(), WithName("error"), WithEnabled(true), WithGroup(g),
+				WithParticipation(1.0))
+			assert.NoError(t, err)
+
+			for idx, want := range tt.wantInjected {
+				var got any
+				var id string
+				h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					got = r.Context().Value(ContextKeyInjected)
+					id = g.CorrelationID(r.Context())
+				}), slow, errf)
+				h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+				if want == nil {
+					assert.Nil(t, got)
+				} else {
+					assert.Equal(t, want, got)
+				}
+				assert.Equal(t, string(rune('a'+idx)), id)
+			}
+
+			assert.Equal(t, len(tt.giveRolls), roll)
+		})
+	}
+}
+
+// TestFaultGroupTrace tests that a Fault in a FaultGroup records the group's roll in its Trace.
+func TestFaultGroupTrace(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewFaultGroup(0.25, WithRandFloat32Func(func() float32 { return 0.5 }))
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjectorNoop(), WithEnabled(true), WithGroup(g), WithDebugTrace(true))
+	assert.NoError(t, err)
+
+	var got any
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context().Value(ContextKeyTrace)
+		assert.Len(t, g.CorrelationID(r.Context()), 16)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []Trace{{
+		Fault:         "testInjectorNoop",
+		Reason:        ReasonParticipation,
+		Rolled:        true,
+		Roll:          0.5,
+		Participation: 0.25,
+	}}, got)
+	assert.Equal(t, "testInjectorNoop @ 25% in FaultGroup", f.String())
+	assert.Equal(t, "", g.CorrelationID(context.Background()))
+}
+
+// TestFaultGroupDisabled tests that a disabled Fault in a FaultGroup does not roll for the group.
+func TestFaultGroupDisabled(t *testing.T) {
+	t.Parallel()
+
+	var roll int
+	g, err := NewFaultGroup(0.5, WithRandFloat32Func(func() float32 {
+		roll++
+		return 0.1
+	}))
+	assert.NoError(t, err)
+
+	// debug tracing keeps the disabled Fault off of the fast path
+	f, err := NewFault(newTestInjectorNoop(), WithEnabled(false), WithGroup(g), WithDebugTrace(true))
+	assert.NoError(t, err)
+
+	var id string
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id = g.CorrelationID(r.Context())
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, 0, roll)
+	assert.Equal(t, "", id)
+}
+
+// TestWithGroupNil tests WithGroup with a nil FaultGroup.
+func TestWithGroupNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithGroup(nil))
+
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithGroup", Value: nil, Err: ErrNilGroup}, err)
+}
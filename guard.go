@@ -0,0 +1,200 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrNilGuard when a nil Guard is provided.
+	ErrNilGuard = errors.New("guard cannot be nil")
+)
+
+const (
+	// defaultSLOInterval is how often an SLOGuard reads its signal by default.
+	defaultSLOInterval = 10 * time.Second
+	// defaultSLOTimeout is how long an SLOGuard waits for its signal by default.
+	defaultSLOTimeout = 5 * time.Second
+)
+
+// Guard decides whether a Fault may inject into a request that it selected, for example to back
+// off while the service is already unhealthy.
+type Guard interface {
+	// Allow returns true if the Fault may inject into r. Allow is called for every request the
+	// Fault selects so it should be fast and respect ctx.
+	Allow(ctx context.Context, r *http.Request) bool
+}
+
+type guardOption struct {
+	guard Guard
+}
+
+func (o guardOption) applyFault(f *Fault) error {
+	if o.guard == nil {
+		return &OptionError{Option: "WithGuard", Value: nil, Err: ErrNilGuard}
+	}
+	f.guards = append(f.guards, o.guard)
+	return nil
+}
+
+// WithGuard adds a Guard that must allow every injection after the Fault selects a request. Pass
+// WithGuard more than once to add more Guards, all of which must allow the injection. Requests that
+// a Guard does not allow are skipped. Guards are consulted before any Coordinator.
+func WithGuard(g Guard) Option {
+	return guardOption{guard: g}
+}
+
+// guard returns true if every Guard allows injecting into r.
+func (f *Fault) guard(r *http.Request) bool {
+	for _, g := range f.guards {
+		if !g.Allow(r.Context(), r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SLOGuard is a Guard that stops injection while a service is burning through its error budget,
+// based on a signal from your SLO monitoring such as the current burn rate.
+type SLOGuard struct {
+	signalF  func(ctx context.Context) (float64, error)
+	limit    float64
+	interval time.Duration
+	timeout  time.Duration
+	nowF     func() time.Time
+
+	// allow is the result of the last read of the signal.
+	allow atomic.Bool
+
+	// read is true once the signal has been read.
+	read bool
+	// checked is when the last read of the signal started.
+	checked time.Time
+	// reading, if not nil, is closed when the read of the signal in progress finishes.
+	reading chan struct{}
+
+	// checkMtx protects SLOGuard.read, SLOGuard.checked, and SLOGuard.reading.
+	checkMtx sync.Mutex
+}
+
+// SLOGuardOption configures an SLOGuard.
+type SLOGuardOption interface {
+	applySLOGuard(g *SLOGuard) error
+}
+
+type sloIntervalOption time.Duration
+
+func (o sloIntervalOption) applySLOGuard(g *SLOGuard) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithSLOInterval", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	g.interval = time.Duration(o)
+	return nil
+}
+
+// WithSLOInterval sets how often the SLOGuard reads its signal. Default 10 seconds.
+func WithSLOInterval(d time.Duration) SLOGuardOption {
+	return sloIntervalOption(d)
+}
+
+type sloTimeoutOption time.Duration
+
+func (o sloTimeoutOption) applySLOGuard(g *SLOGuard) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithSLOTimeout", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	g.timeout = time.Duration(o)
+	return nil
+}
+
+// WithSLOTimeout sets the timeout of the context passed to each read of the signal. A read that
+// fails, such as with context.DeadlineExceeded, denies injection until the next read. Default 5
+// seconds.
+func WithSLOTimeout(d time.Duration) SLOGuardOption {
+	return sloTimeoutOption(d)
+}
+
+func (o nowFuncOption) applySLOGuard(g *SLOGuard) error {
+	g.nowF = o
+	return nil
+}
+
+// NewSLOGuard returns an SLOGuard that allows injection while signal returns at most limit. For
+// example, use the error budget burn rate as the signal with a limit of 1.0 to stop injecting when
+// the service is burning budget faster than it can sustain. The SLOGuard does not allow injection
+// while signal returns an error.
+func NewSLOGuard(
+	signal func(ctx context.Context) (float64, error),
+	limit float64,
+	opts ...SLOGuardOption,
+) (*SLOGuard, error) {
+	if signal == nil {
+		return nil, &OptionError{Option: "NewSLOGuard", Value: nil, Err: ErrNilFunc}
+	}
+
+	// set defaults
+	g := &SLOGuard{
+		signalF:  signal,
+		limit:    limit,
+		interval: defaultSLOInterval,
+		timeout:  defaultSLOTimeout,
+		nowF:     time.Now,
+	}
+
+	// apply options
+	err := applyOptions(opts, SLOGuardOption.applySLOGuard, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Allow returns true if the last signal read was at most the limit. Once the interval has passed
+// since the last read, Allow starts reading the signal again in the background and returns the
+// result of the last read until the new read finishes, so that requests never wait on the signal
+// after the first read. Only one read runs at a time, and reads use their own context with the
+// timeout of the SLOGuard rather than ctx. Until the first read finishes Allow waits for it or for
+// ctx to be done, in which case it returns false.
+func (g *SLOGuard) Allow(ctx context.Context, r *http.Request) bool {
+	g.checkMtx.Lock()
+	now := g.nowF()
+	if g.reading == nil && (!g.read || now.Sub(g.checked) >= g.interval) {
+		g.reading = make(chan struct{})
+		g.checked = now
+		go g.readSignal(g.reading)
+	}
+	read, reading := g.read, g.reading
+	g.checkMtx.Unlock()
+
+	if !read {
+		select {
+		case <-reading:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return g.allow.Load()
+}
+
+// readSignal reads the signal, stores whether it allows injection, and closes done.
+func (g *SLOGuard) readSignal(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	v, err := g.signalF(ctx)
+	g.allow.Store(err == nil && v <= g.limit)
+
+	g.checkMtx.Lock()
+	g.read = true
+	g.reading = nil
+	g.checkMtx.Unlock()
+
+	close(done)
+}
@@ -0,0 +1,301 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestSignal = errors.New("error from test signal")
+)
+
+// testGuard allows injections if allow is true and counts how often it is called.
+type testGuard struct {
+	allow bool
+	calls int
+}
+
+// Allow counts the call and returns g.allow.
+func (g *testGuard) Allow(ctx context.Context, r *http.Request) bool {
+	g.calls++
+	return g.allow
+}
+
+// TestWithGuard tests WithGuard.
+func TestWithGuard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		giveGuards        []bool
+		giveParticipation float32
+		wantCalls         []int
+		wantCode          int
+	}{
+		{
+			name:              "allow",
+			giveGuards:        []bool{true, true},
+			giveParticipation: 1.0,
+			wantCalls:         []int{1, 1},
+			wantCode:          http.StatusInternalServerError,
+		},
+		{
+			name:              "first denies",
+			giveGuards:        []bool{false, true},
+			giveParticipation: 1.0,
+			wantCalls:         []int{1, 0},
+			wantCode:          testHandlerCode,
+		},
+		{
+			name:              "second denies",
+			giveGuards:        []bool{true, false},
+			giveParticipation: 1.0,
+			wantCalls:         []int{1, 1},
+			wantCode:          testHandlerCode,
+		},
+		{
+			name:              "not selected",
+			giveGuards:        []bool{true},
+			giveParticipation: 0.0,
+			wantCalls:         []int{0},
+			wantCode:          testHandlerCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []Option{WithEnabled(true), WithParticipation(tt.giveParticipation)}
+			var guards []*testGuard
+			for _, allow := range tt.giveGuards {
+				g := &testGuard{allow: allow}
+				guards = append(guards, g)
+				opts = append(opts, WithGuard(g))
+			}
+
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			rr := testRequest(t, f)
+			assert.Equal(t, tt.wantCode, rr.Code)
+
+			for idx, g := range guards {
+				assert.Equal(t, tt.wantCalls[idx], g.calls)
+			}
+		})
+	}
+}
+
+// TestWithGuardNil tests WithGuard with a nil Guard.
+func TestWithGuardNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithGuard(nil))
+
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithGuard", Value: nil, Err: ErrNilGuard}, err)
+}
+
+// TestNewSLOGuard tests NewSLOGuard.
+func TestNewSLOGuard(t *testing.T) {
+	t.Parallel()
+
+	signal := func(ctx context.Context) (float64, error) { return 0, nil }
+
+	tests := []struct {
+		name         string
+		giveSignal   func(ctx context.Context) (float64, error)
+		giveOptions  []SLOGuardOption
+		wantInterval time.Duration
+		wantTimeout  time.Duration
+		wantErr      error
+	}{
+		{
+			name:         "defaults",
+			giveSignal:   signal,
+			giveOptions:  nil,
+			wantInterval: defaultSLOInterval,
+			wantTimeout:  defaultSLOTimeout,
+			wantErr:      nil,
+		},
+		{
+			name:         "interval",
+			giveSignal:   signal,
+			giveOptions:  []SLOGuardOption{WithSLOInterval(time.Minute), WithNowFunc(time.Now)},
+			wantInterval: time.Minute,
+			wantTimeout:  defaultSLOTimeout,
+			wantErr:      nil,
+		},
+		{
+			name:         "timeout",
+			giveSignal:   signal,
+			giveOptions:  []SLOGuardOption{WithSLOTimeout(time.Second)},
+			wantInterval: defaultSLOInterval,
+			wantTimeout:  time.Second,
+			wantErr:      nil,
+		},
+		{
+			name:        "nil signal",
+			giveSignal:  nil,
+			giveOptions: nil,
+			wantErr:     &OptionError{Option: "NewSLOGuard", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:        "invalid interval",
+			giveSignal:  signal,
+			giveOptions: []SLOGuardOption{WithSLOInterval(0)},
+			wantErr:     &OptionError{Option: "WithSLOInterval", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:        "invalid timeout",
+			giveSignal:  signal,
+			giveOptions: []SLOGuardOption{WithSLOTimeout(-time.Second)},
+			wantErr:     &OptionError{Option: "WithSLOTimeout", Value: -time.Second, Err: ErrInvalidDuration},
+		},
+		{
+			name:        "option error",
+			giveSignal:  signal,
+			giveOptions: []SLOGuardOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := NewSLOGuard(tt.giveSignal, 1.0, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantInterval, g.interval)
+				assert.Equal(t, tt.wantTimeout, g.timeout)
+				assert.Equal(t, 1.0, g.limit)
+			}
+		})
+	}
+}
+
+// TestSLOGuardAllow tests SLOGuard.Allow.
+func TestSLOGuardAllow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	var (
+		value   atomic.Value
+		failing atomic.Bool
+		reads   atomic.Int64
+	)
+	value.Store(0.5)
+
+	g, gErr := NewSLOGuard(func(ctx context.Context) (float64, error) {
+		reads.Add(1)
+		if failing.Load() {
+			return 0, errTestSignal
+		}
+		return value.Load().(float64), nil
+	}, 1.0,
+		WithSLOInterval(time.Minute),
+		WithNowFunc(func() time.Time { return now }),
+	)
+	assert.NoError(t, gErr)
+
+	ctx := context.Background()
+	allowEventually := func(want bool) {
+		t.Helper()
+		assert.Eventually(t, func() bool { return g.Allow(ctx, nil) == want }, time.Second, time.Millisecond)
+	}
+
+	// the first read is waited for and within the limit
+	assert.True(t, g.Allow(ctx, nil))
+
+	// the signal is not read again until the interval passes
+	value.Store(2.0)
+	assert.True(t, g.Allow(ctx, nil))
+	assert.Equal(t, int64(1), reads.Load())
+
+	// over the limit
+	now = now.Add(time.Minute)
+	allowEventually(false)
+	assert.Equal(t, int64(2), reads.Load())
+
+	// at the limit
+	now = now.Add(time.Minute)
+	value.Store(1.0)
+	allowEventually(true)
+
+	// errors deny injection
+	now = now.Add(time.Minute)
+	failing.Store(true)
+	allowEventually(false)
+	assert.Equal(t, int64(4), reads.Load())
+}
+
+// TestSLOGuardAllowBackground tests that SLOGuard.Allow reads the signal once at a time with its
+// own context and returns the last result while a read is in progress.
+func TestSLOGuardAllowBackground(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	var reads atomic.Int64
+	block := make(chan struct{})
+	deadlines := make(chan bool, 2)
+
+	g, err := NewSLOGuard(func(ctx context.Context) (float64, error) {
+		_, ok := ctx.Deadline()
+		deadlines <- ok
+		if reads.Add(1) == 1 {
+			return 0, nil
+		}
+		<-block
+		return 2.0, nil
+	}, 1.0,
+		WithSLOInterval(time.Minute),
+		WithSLOTimeout(time.Minute),
+		WithNowFunc(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+
+	// the read does not use the canceled request context
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.True(t, g.Allow(ctx, nil))
+	cancel()
+	assert.True(t, <-deadlines)
+
+	// requests do not wait for a slow read and do not start another
+	now = now.Add(time.Minute)
+	for range 10 {
+		assert.True(t, g.Allow(ctx, nil))
+	}
+	assert.True(t, <-deadlines)
+	assert.Equal(t, int64(2), reads.Load())
+
+	close(block)
+	assert.Eventually(t, func() bool { return !g.Allow(ctx, nil) }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(2), reads.Load())
+}
+
+// TestSLOGuardAllowTimeout tests that SLOGuard.Allow denies injection when a read times out and
+// that requests waiting for the first read stop waiting when their context is done.
+func TestSLOGuardAllowTimeout(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewSLOGuard(func(ctx context.Context) (float64, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, 1.0, WithSLOTimeout(50*time.Millisecond))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, g.Allow(ctx, nil))
+
+	assert.False(t, g.Allow(context.Background(), nil))
+}
@@ -0,0 +1,53 @@
+package fault
+
+import (
+	"net/http"
+)
+
+type headerExactMatchOption bool
+
+func (o headerExactMatchOption) applyFault(f *Fault) error {
+	f.headerExact = bool(o)
+	return nil
+}
+
+// WithHeaderExactMatch sets if the header allowlist and blocklist look up their keys in the request
+// headers exactly as given, with direct map access instead of http.Header.Get. Default false, which
+// canonicalizes keys so that "x-chaos" matches "X-Chaos". Use it for services whose headers are
+// stored under non-canonical keys, such as by middleware that sets the http.Header map directly.
+func WithHeaderExactMatch(e bool) Option {
+	return headerExactMatchOption(e)
+}
+
+// headerRule is an entry of the header allowlist or blocklist, with its key as it is looked up in
+// the request headers.
+type headerRule struct {
+	key string
+	val string
+}
+
+// headerRules returns the entries of m as headerRules, canonicalizing their keys unless
+// WithHeaderExactMatch is set, so that requests are checked without canonicalizing keys or
+// iterating over a map. It returns nil if m is empty.
+func (f *Fault) headerRules(m map[string]string) []headerRule {
+	if len(m) == 0 {
+		return nil
+	}
+
+	rules := make([]headerRule, 0, len(m))
+	for key, val := range m {
+		if !f.headerExact {
+			key = http.CanonicalHeaderKey(key)
+		}
+		rules = append(rules, headerRule{key: key, val: val})
+	}
+	return rules
+}
+
+// headerValue returns the first value of the header key in h, looking up key exactly as given.
+func headerValue(h http.Header, key string) string {
+	if vals := h[key]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
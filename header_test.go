@@ -0,0 +1,79 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHeaderExactMatch tests that header keys are canonicalized before they are looked up unless
+// WithHeaderExactMatch is set.
+func TestHeaderExactMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveOptions  []Option
+		giveHeader   http.Header
+		wantInjected bool
+	}{
+		{
+			name:         "canonical allowlist",
+			giveOptions:  []Option{WithHeaderAllowlist(map[string]string{"x-chaos": "on"})},
+			giveHeader:   http.Header{"X-Chaos": {"on"}},
+			wantInjected: true,
+		},
+		{
+			name:         "canonical allowlist non-canonical header",
+			giveOptions:  []Option{WithHeaderAllowlist(map[string]string{"x-chaos": "on"})},
+			giveHeader:   http.Header{"x-chaos": {"on"}},
+			wantInjected: false,
+		},
+		{
+			name: "exact allowlist",
+			giveOptions: []Option{
+				WithHeaderAllowlist(map[string]string{"x-chaos": "on"}),
+				WithHeaderExactMatch(true),
+			},
+			giveHeader:   http.Header{"x-chaos": {"on", "off"}},
+			wantInjected: true,
+		},
+		{
+			name: "exact allowlist canonical header",
+			giveOptions: []Option{
+				WithHeaderAllowlist(map[string]string{"x-chaos": "on"}),
+				WithHeaderExactMatch(true),
+			},
+			giveHeader:   http.Header{"X-Chaos": {"on"}},
+			wantInjected: false,
+		},
+		{
+			name: "exact blocklist",
+			giveOptions: []Option{
+				WithHeaderBlocklist(map[string]string{"x-internal": "true"}),
+				WithHeaderExactMatch(true),
+			},
+			giveHeader:   http.Header{"x-internal": {"true"}},
+			wantInjected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithParticipation(1.0)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = tt.giveHeader
+			rr := httptest.NewRecorder()
+			f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantInjected, rr.Code == http.StatusInternalServerError)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const (
@@ -144,6 +145,41 @@ type errorOption interface {
 	RejectInjectorOption
 	ErrorInjectorOption
 	SlowInjectorOption
+	ResponseInjectorOption
+	ConditionalInjectorOption
+	SequenceInjectorOption
+	CPUInjectorOption
+	PanicInjectorOption
+	RequestHeaderInjectorOption
+	RequestBodyInjectorOption
+	CounterCoordinatorOption
+	SLOGuardOption
+	AuditReporterOption
+	FaultGroupOption
+	ResponseRecorderWriterOption
+	ConnectionCloseInjectorOption
+	StaleCacheInjectorOption
+	MalformedHeaderInjectorOption
+	OutageInjectorOption
+	FlappingInjectorOption
+	LoadLatencyInjectorOption
+	DuplicateRequestInjectorOption
+	PayloadInjectorOption
+	IdleInjectorOption
+	RegistryOption
+	RateLimitedReporterOption
+	StreamOption
+	ExperimentOption
+	DoubleWriteHeaderInjectorOption
+	DowngradeInjectorOption
+	GzipBombInjectorOption
+	CharsetInjectorOption
+	FaultsOption
+	AccessLoggerOption
+	PushInjectorOption
+	BucketingOption
+	BandwidthInjectorOption
+	SlicerInjectorOption
 }
 
 type errorOptionBool bool
@@ -172,6 +208,146 @@ func (o errorOptionBool) applySlowInjector(f *SlowInjector) error {
 	return errErrorOption
 }
 
+func (o errorOptionBool) applyResponseInjector(f *ResponseInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyConditionalInjector(f *ConditionalInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applySequenceInjector(f *SequenceInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyCPUInjector(f *CPUInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyPanicInjector(f *PanicInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyRequestHeaderInjector(f *RequestHeaderInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyRequestBodyInjector(f *RequestBodyInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyCounterCoordinator(c *CounterCoordinator) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applySLOGuard(g *SLOGuard) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyAuditReporter(r *AuditReporter) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyFaultGroup(g *FaultGroup) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyResponseRecorderWriter(rw *ResponseRecorderWriter) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyConnectionCloseInjector(i *ConnectionCloseInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyMalformedHeaderInjector(i *MalformedHeaderInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyOutageInjector(i *OutageInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyFlappingInjector(i *FlappingInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyLoadLatencyInjector(i *LoadLatencyInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyDuplicateRequestInjector(i *DuplicateRequestInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyPayloadInjector(i *PayloadInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyIdleInjector(i *IdleInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyRegistry(r *Registry) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyRateLimitedReporter(r *RateLimitedReporter) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyStream(s *stream) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyExperiment(e *Experiment) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyDoubleWriteHeaderInjector(i *DoubleWriteHeaderInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyDowngradeInjector(i *DowngradeInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyGzipBombInjector(i *GzipBombInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyCharsetInjector(i *CharsetInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyFaults(fs *Faults) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyAccessLogger(a *AccessLogger) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyPushInjector(i *PushInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyBucketing(b *Bucketing) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applyBandwidthInjector(i *BandwidthInjector) error {
+	return errErrorOption
+}
+
+func (o errorOptionBool) applySlicerInjector(i *SlicerInjector) error {
+	return errErrorOption
+}
+
 func withError() errorOption {
 	return errorOptionBool(true)
 }
@@ -186,3 +362,19 @@ func newTestReporter() *testReporter {
 
 // Report does nothing.
 func (r *testReporter) Report(name string, state InjectorState) {}
+
+// testDelayReporter is a DelayReporter that sends each delay on a channel.
+type testDelayReporter struct {
+	testReporter
+	delays chan time.Duration
+}
+
+// newTestDelayReporter returns a new testDelayReporter.
+func newTestDelayReporter() *testDelayReporter {
+	return &testDelayReporter{delays: make(chan time.Duration, 10)}
+}
+
+// ReportDelay sends d on r.delays.
+func (r *testDelayReporter) ReportDelay(name string, d time.Duration) {
+	r.delays <- d
+}
@@ -0,0 +1,47 @@
+package fault
+
+import (
+	"net/http"
+)
+
+type onInjectOption func(r *http.Request)
+
+func (o onInjectOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithOnInject", Value: nil, Err: ErrNilFunc}
+	}
+	f.onInject = o
+	return nil
+}
+
+// WithOnInject sets a function that is called with the request each time the Fault injects, just
+// before the Injector runs. Use it for lightweight inline hooks, such as incrementing an in-house
+// metric, without implementing a Reporter. f is called synchronously and must not modify r.
+func WithOnInject(f func(r *http.Request)) Option {
+	return onInjectOption(f)
+}
+
+type onSkipOption func(r *http.Request)
+
+func (o onSkipOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithOnSkip", Value: nil, Err: ErrNilFunc}
+	}
+	f.onSkip = o
+	return nil
+}
+
+// WithOnSkip sets a function that is called with the request each time the Fault evaluates a
+// request without injecting, the same requests that are recorded under ContextKeySkipped. It is
+// not called while the Fault is disabled or warming up. f is called synchronously and must not
+// modify r.
+func WithOnSkip(f func(r *http.Request)) Option {
+	return onSkipOption(f)
+}
+
+// callHook calls hook with r if hook is set.
+func callHook(hook func(r *http.Request), r *http.Request) {
+	if hook != nil {
+		hook(r)
+	}
+}
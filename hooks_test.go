@@ -0,0 +1,83 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithOnInjectOnSkip tests WithOnInject and WithOnSkip.
+func TestWithOnInjectOnSkip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		wantInject  int
+		wantSkip    int
+	}{
+		{
+			name:        "injected",
+			giveOptions: []Option{WithEnabled(true), WithParticipation(1.0)},
+			wantInject:  1,
+			wantSkip:    0,
+		},
+		{
+			name:        "skipped",
+			giveOptions: []Option{WithEnabled(true), WithParticipation(0.0)},
+			wantInject:  0,
+			wantSkip:    1,
+		},
+		{
+			name:        "disabled",
+			giveOptions: []Option{WithEnabled(false), WithParticipation(1.0)},
+			wantInject:  0,
+			wantSkip:    0,
+		},
+		{
+			name:        "warmup",
+			giveOptions: []Option{WithEnabled(true), WithParticipation(1.0), WithWarmup(1)},
+			wantInject:  0,
+			wantSkip:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var injected, skipped int
+			f, err := NewFault(newTestInjector500s(), append(tt.giveOptions,
+				WithName("custom"),
+				WithOnInject(func(r *http.Request) {
+					injected++
+					assert.Equal(t, []string{"custom"}, r.Context().Value(ContextKeyInjected))
+				}),
+				WithOnSkip(func(r *http.Request) {
+					skipped++
+					assert.Equal(t, []string{"custom"}, r.Context().Value(ContextKeySkipped))
+				}),
+			)...)
+			assert.NoError(t, err)
+
+			testRequest(t, f)
+
+			assert.Equal(t, tt.wantInject, injected)
+			assert.Equal(t, tt.wantSkip, skipped)
+		})
+	}
+}
+
+// TestWithOnInjectOnSkipNil tests WithOnInject and WithOnSkip with nil functions.
+func TestWithOnInjectOnSkipNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithOnInject(nil))
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithOnInject", Value: nil, Err: ErrNilFunc}, err)
+
+	f, err = NewFault(newTestInjectorNoop(), WithOnSkip(nil))
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithOnSkip", Value: nil, Err: ErrNilFunc}, err)
+}
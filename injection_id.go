@@ -0,0 +1,66 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+)
+
+type injectionIDOption struct {
+	header string
+}
+
+func (o injectionIDOption) applyFault(f *Fault) error {
+	f.injectionIDs = true
+	f.injectionIDHeader = o.header
+	return nil
+}
+
+// WithInjectionID assigns a correlation ID to each request the Fault injects into, so that a single
+// injection can be traced across client logs, server logs, and APM. The ID is available from
+// Fault.InjectionID, in the Event sent to the EventReporter and the InjectionContext of an
+// InjectorV2, and, if header is not empty, in the response header named header. A Fault in a
+// FaultGroup uses the correlation ID of the group. Default disabled.
+func WithInjectionID(header string) Option {
+	return injectionIDOption{header: header}
+}
+
+func (o correlationIDFuncOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithCorrelationIDFunc", Value: nil, Err: ErrNilFunc}
+	}
+	f.idF = o
+	return nil
+}
+
+// injectionIDContextKey is the request context key for the injection ID of a Fault.
+type injectionIDContextKey struct {
+	fault *Fault
+}
+
+// withInjectionID returns r with a new injection ID in its context and sets the ID in the response
+// header, or returns r unchanged if the Fault does not have WithInjectionID.
+func (f *Fault) withInjectionID(w http.ResponseWriter, r *http.Request) *http.Request {
+	if !f.injectionIDs {
+		return r
+	}
+
+	var id string
+	if f.group != nil {
+		id = f.group.CorrelationID(r.Context())
+	} else {
+		id = f.idF()
+	}
+
+	if f.injectionIDHeader != "" {
+		w.Header().Set(f.injectionIDHeader, id)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), injectionIDContextKey{f}, id))
+}
+
+// InjectionID returns the correlation ID the Fault assigned when it injected into the request with
+// ctx, or "" if the Fault did not inject or does not have WithInjectionID.
+func (f *Fault) InjectionID(ctx context.Context) string {
+	id, _ := ctx.Value(injectionIDContextKey{f}).(string)
+	return id
+}
@@ -0,0 +1,145 @@
+package fault
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithInjectionID tests WithInjectionID.
+func TestWithInjectionID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		giveOptions       []Option
+		giveParticipation float32
+		wantID            string
+		wantHeader        string
+	}{
+		{
+			name:              "disabled",
+			giveOptions:       nil,
+			giveParticipation: 1.0,
+			wantID:            "",
+			wantHeader:        "",
+		},
+		{
+			name:              "without header",
+			giveOptions:       []Option{WithInjectionID("")},
+			giveParticipation: 1.0,
+			wantID:            "id-1",
+			wantHeader:        "",
+		},
+		{
+			name:              "with header",
+			giveOptions:       []Option{WithInjectionID("X-Fault-Id")},
+			giveParticipation: 1.0,
+			wantID:            "id-1",
+			wantHeader:        "id-1",
+		},
+		{
+			name:              "skipped",
+			giveOptions:       []Option{WithInjectionID("X-Fault-Id")},
+			giveParticipation: 0.0,
+			wantID:            "",
+			wantHeader:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			er := &testEventReporter{}
+			opts := append([]Option{
+				WithEnabled(true),
+				WithParticipation(tt.giveParticipation),
+				WithEventReporter(er),
+				WithCorrelationIDFunc(func() string { return "id-1" }),
+			}, tt.giveOptions...)
+
+			var f *Fault
+			var gotV2 string
+			f, err := NewFault(AdaptInjectorV2(InjectorV2Func(
+				func(ic InjectionContext, w http.ResponseWriter, r *http.Request) {
+					gotV2 = ic.InjectionID
+					assert.Equal(t, ic.InjectionID, f.InjectionID(r.Context()))
+					ic.Next.ServeHTTP(w, r)
+				})), opts...)
+			assert.NoError(t, err)
+
+			rr := testRequest(t, f)
+
+			assert.Equal(t, tt.wantID, gotV2)
+			assert.Equal(t, tt.wantHeader, rr.Header().Get("X-Fault-Id"))
+			for _, e := range er.events {
+				assert.Equal(t, tt.wantID, e.InjectionID)
+			}
+		})
+	}
+}
+
+// TestWithInjectionIDGroup tests that Faults in a FaultGroup use the correlation ID of the group.
+func TestWithInjectionIDGroup(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewFaultGroup(1.0, WithCorrelationIDFunc(func() string { return "group" }))
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithGroup(g),
+		WithInjectionID("X-Fault-Id"),
+		WithCorrelationIDFunc(func() string { return "fault" }),
+	)
+	assert.NoError(t, err)
+
+	var got string
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = f.InjectionID(r.Context())
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "group", got)
+	assert.Equal(t, "group", rr.Header().Get("X-Fault-Id"))
+	assert.Equal(t, "", f.InjectionID(context.Background()))
+}
+
+// TestWithInjectionIDAudit tests that an AuditReporter records the injection ID.
+func TestWithInjectionIDAudit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ar, err := NewAuditReporter(&buf)
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithParticipation(1.0),
+		WithEventReporter(ar),
+		WithInjectionID(""),
+		WithCorrelationIDFunc(func() string { return "abc" }),
+	)
+	assert.NoError(t, err)
+
+	testRequest(t, f)
+
+	assert.Contains(t, buf.String(), `"injectionId":"abc"`)
+}
+
+// TestWithCorrelationIDFuncNil tests WithCorrelationIDFunc with a nil function on a Fault.
+func TestWithCorrelationIDFuncNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithCorrelationIDFunc(nil))
+
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithCorrelationIDFunc", Value: nil, Err: ErrNilFunc}, err)
+}
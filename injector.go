@@ -2,6 +2,7 @@ package fault
 
 import (
 	"net/http"
+	"strconv"
 )
 
 // InjectorState represents the states an injector can be in.
@@ -14,8 +15,34 @@ const (
 	StateFinished
 	// StateSkipped when an Injector is skipped.
 	StateSkipped
+	// StateErrored when an Injector could not inject as configured, such as when the RejectInjector
+	// cannot hijack the connection to close it. The Injector also reports the state it ended in.
+	StateErrored
+	// StateAborted when an Injector ends the request by panicking instead of finishing, such as the
+	// RejectInjector, the IdleInjector, and any Injector that cannot write its response because the
+	// client is gone.
+	StateAborted
 )
 
+// String returns the name of the state, such as "started", so that Reporters can log readable
+// states.
+func (s InjectorState) String() string {
+	switch s {
+	case StateStarted:
+		return "started"
+	case StateFinished:
+		return "finished"
+	case StateSkipped:
+		return "skipped"
+	case StateErrored:
+		return "errored"
+	case StateAborted:
+		return "aborted"
+	default:
+		return "InjectorState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
 // Injector are added to Faults and run as middleware in a request.
 type Injector interface {
 	Handler(next http.Handler) http.Handler
@@ -0,0 +1,116 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// bandwidthChunksPerSecond is how many chunks a second of bandwidth is split into, so that the
+// response trickles out instead of waiting once per write.
+const bandwidthChunksPerSecond = 10
+
+// BandwidthInjector continues the request and limits how fast the response body is written.
+type BandwidthInjector struct {
+	rate     int64
+	slowF    func(t time.Duration)
+	reporter Reporter
+	name     string
+}
+
+// BandwidthInjectorOption configures a BandwidthInjector.
+type BandwidthInjectorOption interface {
+	applyBandwidthInjector(i *BandwidthInjector) error
+}
+
+func (o slowFunctionOption) applyBandwidthInjector(i *BandwidthInjector) error {
+	i.slowF = o
+	return nil
+}
+
+func (o reporterOption) applyBandwidthInjector(i *BandwidthInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyBandwidthInjector(i *BandwidthInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewBandwidthInjector returns a BandwidthInjector that writes the response body at most rate bytes
+// per second. rate must be greater than 0.
+func NewBandwidthInjector(rate int64, opts ...BandwidthInjectorOption) (*BandwidthInjector, error) {
+	if rate <= 0 {
+		return nil, &OptionError{Option: "NewBandwidthInjector", Value: rate, Err: ErrInvalidRate}
+	}
+
+	// set defaults
+	bi := &BandwidthInjector{
+		rate:     rate,
+		slowF:    time.Sleep,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(BandwidthInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, BandwidthInjectorOption.applyBandwidthInjector, bi)
+	if err != nil {
+		return nil, err
+	}
+
+	return bi, nil
+}
+
+// Handler continues the request and writes the response body in chunks of a tenth of a second of
+// bandwidth, flushing each chunk and then waiting as long as the chunk would take at the set rate.
+// Use the BandwidthInjector to test clients on slow or congested networks, such as timeouts that
+// only cover the start of a response.
+func (i *BandwidthInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		next.ServeHTTP(&bandwidthWriter{ResponseRecorderWriter: &ResponseRecorderWriter{w: w}, injector: i}, r)
+
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// String describes the BandwidthInjector, such as "BandwidthInjector(1024B/s)".
+func (i *BandwidthInjector) String() string {
+	return i.name + "(" + strconv.FormatInt(i.rate, 10) + "B/s)"
+}
+
+// chunkSize returns how many bytes are written before each wait.
+func (i *BandwidthInjector) chunkSize() int {
+	return int(max(i.rate/bandwidthChunksPerSecond, 1))
+}
+
+// bandwidthWriter is an http.ResponseWriter that limits how fast the body is written with a
+// BandwidthInjector. Flushing, hijacking, server push, and Unwrap pass through it.
+type bandwidthWriter struct {
+	*ResponseRecorderWriter
+	injector *BandwidthInjector
+}
+
+// Write writes b in chunks, flushing and waiting after each chunk.
+func (w *bandwidthWriter) Write(b []byte) (int, error) {
+	if len(b) == 0 {
+		return w.ResponseRecorderWriter.Write(b)
+	}
+
+	var written int
+	for len(b) > 0 {
+		chunk := b[:min(len(b), w.injector.chunkSize())]
+		n, err := w.ResponseRecorderWriter.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		w.Flush()
+		w.injector.slowF(time.Duration(n) * time.Second / time.Duration(w.injector.rate))
+		b = b[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,177 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewBandwidthInjector tests NewBandwidthInjector.
+func TestNewBandwidthInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveRate    int64
+		giveOptions []BandwidthInjectorOption
+		want        *BandwidthInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveRate:    1024,
+			giveOptions: nil,
+			want: &BandwidthInjector{
+				rate:     1024,
+				reporter: NewNoopReporter(),
+				name:     "BandwidthInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "all options",
+			giveRate: 1,
+			giveOptions: []BandwidthInjectorOption{
+				WithSlowFunc(func(time.Duration) {}),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &BandwidthInjector{
+				rate:     1,
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "invalid rate",
+			giveRate:    0,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewBandwidthInjector", Value: int64(0), Err: ErrInvalidRate},
+		},
+		{
+			name:     "option error",
+			giveRate: 1024,
+			giveOptions: []BandwidthInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bi, err := NewBandwidthInjector(tt.giveRate, tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing
+			if bi != nil {
+				bi.slowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, bi)
+		})
+	}
+}
+
+// TestBandwidthInjectorHandler tests BandwidthInjector.Handler.
+func TestBandwidthInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveRate  int64
+		giveBody  string
+		wantSlept []time.Duration
+	}{
+		{
+			name:      "chunks",
+			giveRate:  40,
+			giveBody:  "abcdefghij",
+			wantSlept: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 50 * time.Millisecond},
+		},
+		{
+			name:      "one byte chunks",
+			giveRate:  5,
+			giveBody:  "abc",
+			wantSlept: []time.Duration{200 * time.Millisecond, 200 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:      "one chunk",
+			giveRate:  1000,
+			giveBody:  "abc",
+			wantSlept: []time.Duration{3 * time.Millisecond},
+		},
+		{
+			name:      "empty",
+			giveRate:  1000,
+			giveBody:  "",
+			wantSlept: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var slept []time.Duration
+			bi, err := NewBandwidthInjector(tt.giveRate, WithSlowFunc(func(d time.Duration) {
+				slept = append(slept, d)
+			}))
+			assert.NoError(t, err)
+
+			h := bi.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n, err := w.Write([]byte(tt.giveBody))
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.giveBody), n)
+			}))
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.giveBody, rr.Body.String())
+			assert.Equal(t, tt.wantSlept, slept)
+			assert.Equal(t, len(tt.wantSlept) > 0, rr.Flushed)
+		})
+	}
+}
+
+// TestBandwidthInjectorHandlerWriteError tests that the http.ResponseWriter that a
+// BandwidthInjector passes to the next handler stops at the first failed write.
+func TestBandwidthInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	var slept int
+	bi, err := NewBandwidthInjector(10, WithSlowFunc(func(time.Duration) { slept++ }))
+	assert.NoError(t, err)
+
+	h := bi.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := w.Write([]byte(strings.Repeat("a", 10)))
+		assert.Equal(t, 0, n)
+		assert.Equal(t, errTestWrite, err)
+	}))
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.code)
+	assert.Equal(t, 0, slept)
+}
+
+// TestBandwidthInjectorString tests BandwidthInjector.String.
+func TestBandwidthInjectorString(t *testing.T) {
+	t.Parallel()
+
+	bi, err := NewBandwidthInjector(1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "BandwidthInjector(1024B/s)", bi.String())
+}
@@ -1,10 +1,40 @@
 package fault
 
-import "net/http"
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidIndex when an index does not refer to a provided Injector.
+	ErrInvalidIndex = errors.New("index out of range")
+)
 
 // ChainInjector combines many Injectors into a single Injector that runs them in order.
 type ChainInjector struct {
+	injectors   []Injector
 	middlewares []func(next http.Handler) http.Handler
+
+	// participation maps the index of an Injector to the percent of requests it runs on. Injectors
+	// without an entry always run.
+	participation map[int]float32
+
+	// stopOnWrite determines if the chain stops when an Injector does not continue the request.
+	stopOnWrite bool
+
+	randSeed int64
+	rand     *rand.Rand
+	randF    func() float32
+
+	// *rand.Rand is not thread safe. This mutex protects our random source
+	randMtx sync.Mutex
+
+	reporter Reporter
+	name     string
 }
 
 // ChainInjectorOption configures a ChainInjector.
@@ -12,35 +42,160 @@ type ChainInjectorOption interface {
 	applyChainInjector(i *ChainInjector) error
 }
 
+func (o randSeedOption) applyChainInjector(i *ChainInjector) error {
+	i.randSeed = int64(o)
+	return nil
+}
+
+func (o randFloat32FuncOption) applyChainInjector(i *ChainInjector) error {
+	i.randF = o
+	return nil
+}
+
+func (o reporterOption) applyChainInjector(i *ChainInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyChainInjector(i *ChainInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+type stopOnWriteOption bool
+
+func (o stopOnWriteOption) applyChainInjector(i *ChainInjector) error {
+	i.stopOnWrite = bool(o)
+	return nil
+}
+
+// WithStopOnWrite sets if the chain stops when an Injector responds or otherwise does not continue
+// the request. Default true. When false the remaining Injectors in the chain still run, for example
+// so that reporting or header Injectors run after an ErrorInjector responds, but the handler after
+// the chain does not run.
+func WithStopOnWrite(s bool) ChainInjectorOption {
+	return stopOnWriteOption(s)
+}
+
+type childParticipationOption struct {
+	idx int
+	p   float32
+}
+
+func (o childParticipationOption) applyChainInjector(i *ChainInjector) error {
+	if o.p < 0.0 || o.p > 1.0 {
+		return &OptionError{Option: "WithChildParticipation", Value: o.p, Err: ErrInvalidPercent}
+	}
+	i.participation[o.idx] = o.p
+	return nil
+}
+
+// WithChildParticipation sets the percent of requests that run the Injector at index idx of the
+// chain. 0.0 <= p <= 1.0. By default every Injector in the chain runs.
+func WithChildParticipation(idx int, p float32) ChainInjectorOption {
+	return childParticipationOption{idx: idx, p: p}
+}
+
 // NewChainInjector combines many Injectors into a single Injector that runs them in order.
 func NewChainInjector(is []Injector, opts ...ChainInjectorOption) (*ChainInjector, error) {
 	// set defaults
-	ci := &ChainInjector{}
+	ci := &ChainInjector{
+		participation: make(map[int]float32),
+		stopOnWrite:   true,
+		randSeed:      defaultRandSeed,
+		randF:         nil,
+		reporter:      NewNoopReporter(),
+		name:          reflect.TypeOf(ChainInjector{}).Name(),
+	}
 
 	// apply options
-	for _, opt := range opts {
-		err := opt.applyChainInjector(ci)
-		if err != nil {
-			return nil, err
-		}
+	err := applyOptions(opts, ChainInjectorOption.applyChainInjector, ci)
+	if err != nil {
+		return nil, err
 	}
 
 	// set middleware
+	ci.injectors = is
 	for _, i := range is {
 		ci.middlewares = append(ci.middlewares, i.Handler)
 	}
 
+	// check options
+	for idx := range ci.participation {
+		if idx < 0 || idx >= len(ci.middlewares) {
+			return nil, &OptionError{Option: "WithChildParticipation", Value: idx, Err: ErrInvalidIndex}
+		}
+	}
+
+	// set seeded rand source and function
+	ci.rand = rand.New(rand.NewSource(ci.randSeed))
+	if ci.randF == nil {
+		ci.randF = ci.rand.Float32
+	}
+
 	return ci, nil
 }
 
 // Handler executes ChainInjector.middlewares in order and then returns.
 func (i *ChainInjector) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Loop in reverse to preserve handler order
-		for idx := len(i.middlewares) - 1; idx >= 0; idx-- {
-			next = i.middlewares[idx](next)
-		}
+		go report(i.reporter, i.name, StateStarted)
+		i.serve(0, next, w, r)
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
 
+// serve runs the Injector at idx with a next handler that runs the rest of the chain, ending with
+// next. If the Injector does not continue the request and ChainInjector.stopOnWrite is false the
+// rest of the chain runs without next.
+func (i *ChainInjector) serve(idx int, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	if idx >= len(i.middlewares) {
 		next.ServeHTTP(w, r)
-	})
+		return
+	}
+
+	if !i.participate(idx) {
+		i.serve(idx+1, next, w, r)
+		return
+	}
+
+	var continued bool
+	i.middlewares[idx](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		continued = true
+		i.serve(idx+1, next, w, r)
+	})).ServeHTTP(w, r)
+
+	if !continued && !i.stopOnWrite {
+		i.serve(idx+1, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), w, r)
+	}
+}
+
+// participate randomly decides (returns true) if the Injector at idx should run based on
+// ChainInjector.participation.
+func (i *ChainInjector) participate(idx int) bool {
+	p, ok := i.participation[idx]
+	if !ok {
+		return true
+	}
+
+	i.randMtx.Lock()
+	rn := i.randF()
+	i.randMtx.Unlock()
+
+	return rn < p
+}
+
+// String describes the ChainInjector and the Injectors it runs, such as
+// "ChainInjector[SlowInjector(1s), ErrorInjector(503) @ 10%]".
+func (i *ChainInjector) String() string {
+	ss := make([]string, 0, len(i.injectors))
+	for idx, inj := range i.injectors {
+		s := injectorString(inj)
+		if p, ok := i.participation[idx]; ok {
+			s += rateSeparator + percentString(p)
+		}
+		ss = append(ss, s)
+	}
+
+	return i.name + "[" + strings.Join(ss, ", ") + "]"
 }
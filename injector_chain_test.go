@@ -2,8 +2,10 @@ package fault
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,6 +18,7 @@ func TestNewChainInjector(t *testing.T) {
 		name         string
 		giveInjector []Injector
 		giveOptions  []ChainInjectorOption
+		wantName     string
 		wantErr      error
 	}{
 		{
@@ -47,6 +50,71 @@ func TestNewChainInjector(t *testing.T) {
 			giveOptions: []ChainInjectorOption{},
 			wantErr:     nil,
 		},
+		{
+			name: "reporter and name",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			wantName: "custom",
+			wantErr:  nil,
+		},
+		{
+			name: "stop on write",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithStopOnWrite(false),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "child participation",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+				newTestInjector500s(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithChildParticipation(1, 0.1),
+				WithRandSeed(100),
+				WithRandFloat32Func(func() float32 { return 0.0 }),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid child participation percent",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithChildParticipation(0, 1.1),
+			},
+			wantErr: &OptionError{Option: "WithChildParticipation", Value: float32(1.1), Err: ErrInvalidPercent},
+		},
+		{
+			name: "invalid child participation index",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithChildParticipation(1, 0.5),
+			},
+			wantErr: &OptionError{Option: "WithChildParticipation", Value: 1, Err: ErrInvalidIndex},
+		},
+		{
+			name: "invalid child participation negative index",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithChildParticipation(-1, 0.5),
+			},
+			wantErr: &OptionError{Option: "WithChildParticipation", Value: -1, Err: ErrInvalidIndex},
+		},
 		{
 			name: "option error",
 			giveInjector: []Injector{
@@ -69,6 +137,11 @@ func TestNewChainInjector(t *testing.T) {
 
 			if tt.wantErr == nil {
 				assert.Equal(t, len(tt.giveInjector), len(ci.middlewares))
+				if tt.wantName != "" {
+					assert.Equal(t, tt.wantName, ci.name)
+				} else {
+					assert.Equal(t, "ChainInjector", ci.name)
+				}
 			} else {
 				assert.Nil(t, ci)
 			}
@@ -151,6 +224,71 @@ func TestChainInjectorHandler(t *testing.T) {
 			wantCode:    http.StatusOK,
 			wantBody:    "one",
 		},
+		{
+			name: "one stop two continue",
+			giveInjector: []Injector{
+				newTestInjectorOneOK(),
+				newTestInjectorStop(),
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithStopOnWrite(false),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "one" + "two",
+		},
+		{
+			name: "500s two continue",
+			giveInjector: []Injector{
+				newTestInjector500s(),
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithStopOnWrite(false),
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: http.StatusText(http.StatusInternalServerError) + "\ntwo",
+		},
+		{
+			name: "one two continue",
+			giveInjector: []Injector{
+				newTestInjectorOneOK(),
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithStopOnWrite(false),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "one" + "two" + testHandlerBody,
+		},
+		{
+			name: "one stop two child participation skip",
+			giveInjector: []Injector{
+				newTestInjectorOneOK(),
+				newTestInjectorStop(),
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithChildParticipation(1, 0.5),
+				WithRandFloat32Func(func() float32 { return 0.5 }),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "one" + "two" + testHandlerBody,
+		},
+		{
+			name: "one stop two child participation run",
+			giveInjector: []Injector{
+				newTestInjectorOneOK(),
+				newTestInjectorStop(),
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: []ChainInjectorOption{
+				WithChildParticipation(1, 0.5),
+				WithRandFloat32Func(func() float32 { return 0.4 }),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "one",
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,3 +311,45 @@ func TestChainInjectorHandler(t *testing.T) {
 		})
 	}
 }
+
+// TestChainInjectorHandlerReuse tests that a ChainInjector handler can serve many requests without
+// running its Injectors more than once per request.
+func TestChainInjectorHandlerReuse(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewChainInjector([]Injector{
+		newTestInjectorOneOK(),
+	})
+	assert.NoError(t, err)
+
+	f, err := NewFault(ci,
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, testHandlerBody, testHandlerCode)
+	}))
+
+	for range 3 {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "one"+testHandlerBody, strings.TrimSpace(rr.Body.String()))
+	}
+}
+
+// TestChainInjectorString tests ChainInjector.String.
+func TestChainInjectorString(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewSlowInjector(time.Second)
+	assert.NoError(t, err)
+	ei, err := NewErrorInjector(http.StatusServiceUnavailable)
+	assert.NoError(t, err)
+
+	ci, err := NewChainInjector([]Injector{si, ei}, WithChildParticipation(1, 0.1))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ChainInjector[SlowInjector(1s), ErrorInjector(503) @ 10%]", ci.String())
+}
@@ -0,0 +1,159 @@
+package fault
+
+import (
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharsetInjector continues the request and corrupts the text encoding of the response, by making
+// its UTF-8 invalid or by changing the charset parameter of its Content-Type.
+type CharsetInjector struct {
+	invalidUTF8 bool
+	charset     string
+	reporter    Reporter
+	name        string
+}
+
+// CharsetInjectorOption configures a CharsetInjector.
+type CharsetInjectorOption interface {
+	applyCharsetInjector(i *CharsetInjector) error
+}
+
+type invalidUTF8Option struct{}
+
+func (o invalidUTF8Option) applyCharsetInjector(i *CharsetInjector) error {
+	i.invalidUTF8 = true
+	return nil
+}
+
+// WithInvalidUTF8 drops the continuation bytes of every multi-byte UTF-8 sequence in the body, so
+// that each non-ASCII character becomes an invalid sequence while ASCII, including the structure
+// of formats such as JSON and HTML, is unchanged. Bodies that are only ASCII are not changed.
+func WithInvalidUTF8() CharsetInjectorOption {
+	return invalidUTF8Option{}
+}
+
+type charsetOption string
+
+func (o charsetOption) applyCharsetInjector(i *CharsetInjector) error {
+	i.charset = string(o)
+	return nil
+}
+
+// WithCharset replaces the charset parameter of the Content-Type of the response with charset,
+// such as "iso-8859-1", without changing the body. Responses without a valid Content-Type are not
+// changed.
+func WithCharset(charset string) CharsetInjectorOption {
+	return charsetOption(charset)
+}
+
+func (o reporterOption) applyCharsetInjector(i *CharsetInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyCharsetInjector(i *CharsetInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewCharsetInjector returns a CharsetInjector. Without WithInvalidUTF8 or WithCharset it behaves as
+// if WithInvalidUTF8 was set.
+func NewCharsetInjector(opts ...CharsetInjectorOption) (*CharsetInjector, error) {
+	// set defaults
+	ci := &CharsetInjector{
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(CharsetInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, CharsetInjectorOption.applyCharsetInjector, ci)
+	if err != nil {
+		return nil, err
+	}
+
+	if ci.charset == "" {
+		ci.invalidUTF8 = true
+	}
+
+	return ci, nil
+}
+
+// Handler continues the request and corrupts the response as it is written. Use the
+// CharsetInjector to test how clients decode and sanitize text that is not what it claims to be.
+func (i *CharsetInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+		go report(i.reporter, i.name, StateFinished)
+
+		rw := &ResponseRecorderWriter{w: w, onWriteHeader: i.setHeader}
+		next.ServeHTTP(&charsetWriter{ResponseRecorderWriter: rw, injector: i}, r)
+	})
+}
+
+// String describes the CharsetInjector, such as "CharsetInjector(invalidUTF8, charset=iso-8859-1)".
+func (i *CharsetInjector) String() string {
+	var details []string
+	if i.invalidUTF8 {
+		details = append(details, "invalidUTF8")
+	}
+	if i.charset != "" {
+		details = append(details, "charset="+i.charset)
+	}
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
+
+// setHeader changes the header of the response before it is written.
+func (i *CharsetInjector) setHeader(h http.Header) {
+	if i.invalidUTF8 {
+		h.Del("Content-Length")
+	}
+
+	if i.charset == "" {
+		return
+	}
+
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return
+	}
+	params["charset"] = i.charset
+	h.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+}
+
+// corrupt returns b without UTF-8 continuation bytes if WithInvalidUTF8 is set.
+func (i *CharsetInjector) corrupt(b []byte) []byte {
+	if !i.invalidUTF8 {
+		return b
+	}
+
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		if utf8.RuneStart(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// charsetWriter is an http.ResponseWriter that corrupts the response with a CharsetInjector. The
+// header is changed by the ResponseRecorderWriter just before it is written, and flushing,
+// hijacking, server push, and Unwrap pass through it.
+type charsetWriter struct {
+	*ResponseRecorderWriter
+	injector *CharsetInjector
+}
+
+// Write corrupts b and writes it, writing an http.StatusOK header first if no header was written.
+// It returns len(b) if the corrupted bytes were written.
+func (w *charsetWriter) Write(b []byte) (int, error) {
+	_, err := w.ResponseRecorderWriter.Write(w.injector.corrupt(b))
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,209 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCharsetInjector tests NewCharsetInjector.
+func TestNewCharsetInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []CharsetInjectorOption
+		want        *CharsetInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &CharsetInjector{
+				invalidUTF8: true,
+				reporter:    NewNoopReporter(),
+				name:        "CharsetInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "charset only",
+			giveOptions: []CharsetInjectorOption{
+				WithCharset("iso-8859-1"),
+			},
+			want: &CharsetInjector{
+				charset:  "iso-8859-1",
+				reporter: NewNoopReporter(),
+				name:     "CharsetInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []CharsetInjectorOption{
+				WithInvalidUTF8(),
+				WithCharset("utf-16"),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &CharsetInjector{
+				invalidUTF8: true,
+				charset:     "utf-16",
+				reporter:    newTestReporter(),
+				name:        "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "option error",
+			giveOptions: []CharsetInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewCharsetInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, ci)
+		})
+	}
+}
+
+// TestCharsetInjectorHandler tests CharsetInjector.Handler.
+func TestCharsetInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		giveOptions     []CharsetInjectorOption
+		giveContentType string
+		wantContentType string
+		wantBody        string
+		wantLength      string
+	}{
+		{
+			name:            "invalid utf8",
+			giveOptions:     nil,
+			giveContentType: "application/json",
+			wantContentType: "application/json",
+			wantBody:        "{\"name\":\"cr\xc3me br\xc3l\xc3e\"}",
+		},
+		{
+			name:            "charset",
+			giveOptions:     []CharsetInjectorOption{WithCharset("iso-8859-1")},
+			giveContentType: "text/html; charset=utf-8",
+			wantContentType: "text/html; charset=iso-8859-1",
+			wantBody:        `{"name":"crème brûlée"}`,
+			wantLength:      "26",
+		},
+		{
+			name:            "invalid content type",
+			giveOptions:     []CharsetInjectorOption{WithCharset("iso-8859-1")},
+			giveContentType: "",
+			wantContentType: "",
+			wantBody:        `{"name":"crème brûlée"}`,
+			wantLength:      "26",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewCharsetInjector(tt.giveOptions...)
+			assert.NoError(t, err)
+
+			h := ci.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.giveContentType != "" {
+					w.Header().Set("Content-Type", tt.giveContentType)
+				}
+				w.Header().Set("Content-Length", "26")
+				_, err := w.Write([]byte(`{"name":"crème brûlée"}`))
+				assert.NoError(t, err)
+			}))
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.wantContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+			assert.Equal(t, tt.wantLength, rr.Header().Get("Content-Length"))
+			assert.Equal(t, tt.wantLength != "", utf8.Valid(rr.Body.Bytes()))
+		})
+	}
+}
+
+// TestCharsetInjectorHandlerWriter tests the http.ResponseWriter that a CharsetInjector passes to
+// the next handler.
+func TestCharsetInjectorHandlerWriter(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewCharsetInjector()
+	assert.NoError(t, err)
+
+	h := ci.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.(http.Flusher).Flush()
+
+		n, err := w.Write([]byte("é"))
+		assert.Equal(t, 0, n)
+		assert.Equal(t, errTestWrite, err)
+	}))
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTeapot, w.code)
+
+	rr := httptest.NewRecorder()
+	h = ci.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	}))
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// TestCharsetInjectorHandlerPassthrough tests that hijacking, server push, and Unwrap pass through
+// the http.ResponseWriter that a CharsetInjector passes to the next handler.
+func TestCharsetInjectorHandlerPassthrough(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewCharsetInjector(WithCharset("iso-8859-1"))
+	assert.NoError(t, err)
+
+	fw := &testFullWriter{ResponseRecorder: httptest.NewRecorder()}
+	h := ci.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.NoError(t, err)
+		assert.Equal(t, errTestPush, w.(http.Pusher).Push("/style.css", nil))
+		assert.Same(t, fw, w.(interface{ Unwrap() http.ResponseWriter }).Unwrap())
+	}))
+	h.ServeHTTP(fw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, fw.hijacked)
+	assert.Equal(t, "/style.css", fw.pushed)
+}
+
+// TestCharsetInjectorString tests CharsetInjector.String.
+func TestCharsetInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewCharsetInjector()
+	assert.NoError(t, err)
+	assert.Equal(t, "CharsetInjector(invalidUTF8)", ci.String())
+
+	ci, err = NewCharsetInjector(WithInvalidUTF8(), WithCharset("iso-8859-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "CharsetInjector(invalidUTF8, charset=iso-8859-1)", ci.String())
+}
@@ -0,0 +1,97 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// ConditionalInjector runs one of two Injectors depending on the request.
+type ConditionalInjector struct {
+	condF func(r *http.Request) bool
+	then  Injector
+	els   Injector
+
+	reporter Reporter
+	name     string
+}
+
+// ConditionalInjectorOption configures a ConditionalInjector.
+type ConditionalInjectorOption interface {
+	applyConditionalInjector(i *ConditionalInjector) error
+}
+
+func (o reporterOption) applyConditionalInjector(i *ConditionalInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyConditionalInjector(i *ConditionalInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewConditionalInjector returns a ConditionalInjector that runs then when cond returns true for
+// the request and els otherwise. A nil Injector continues the request without injecting.
+func NewConditionalInjector(
+	cond func(r *http.Request) bool,
+	then Injector,
+	els Injector,
+	opts ...ConditionalInjectorOption,
+) (*ConditionalInjector, error) {
+	if cond == nil {
+		return nil, &OptionError{Option: "NewConditionalInjector", Value: nil, Err: ErrNilFunc}
+	}
+
+	// set defaults
+	ci := &ConditionalInjector{
+		condF:    cond,
+		then:     then,
+		els:      els,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(ConditionalInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, ConditionalInjectorOption.applyConditionalInjector, ci)
+	if err != nil {
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+// Handler runs ConditionalInjector.then if ConditionalInjector.condF returns true and
+// ConditionalInjector.els otherwise. It reports StateStarted and StateFinished around the Injector
+// it runs, and nothing when that Injector is nil.
+func (i *ConditionalInjector) Handler(next http.Handler) http.Handler {
+	then := i.branch(i.then, next)
+	els := i.branch(i.els, next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if i.condF(r) {
+			then.ServeHTTP(w, r)
+		} else {
+			els.ServeHTTP(w, r)
+		}
+	})
+}
+
+// branch returns the handler of inj that reports its states, or next if inj is nil.
+func (i *ConditionalInjector) branch(inj Injector, next http.Handler) http.Handler {
+	if inj == nil {
+		return next
+	}
+
+	h := inj.Handler(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+		h.ServeHTTP(w, r)
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// String describes the ConditionalInjector and the Injectors it chooses between, such as
+// "ConditionalInjector(then=ErrorInjector(503), else=nil)". A nil Injector is "nil".
+func (i *ConditionalInjector) String() string {
+	return i.name + "(then=" + injectorString(i.then) + ", else=" + injectorString(i.els) + ")"
+}
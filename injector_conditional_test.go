@@ -0,0 +1,203 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCondHeader returns true if the request has the test header.
+func testCondHeader(r *http.Request) bool {
+	return r.Header.Get(testHeaderKey) == testHeaderVal
+}
+
+// TestNewConditionalInjector tests NewConditionalInjector.
+func TestNewConditionalInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveCond    func(*http.Request) bool
+		giveThen    Injector
+		giveElse    Injector
+		giveOptions []ConditionalInjectorOption
+		wantErr     error
+	}{
+		{
+			name:        "valid",
+			giveCond:    testCondHeader,
+			giveThen:    newTestInjector500s(),
+			giveElse:    newTestInjectorNoop(),
+			giveOptions: nil,
+			wantErr:     nil,
+		},
+		{
+			name:        "nil injectors",
+			giveCond:    testCondHeader,
+			giveThen:    nil,
+			giveElse:    nil,
+			giveOptions: nil,
+			wantErr:     nil,
+		},
+		{
+			name:        "nil cond",
+			giveCond:    nil,
+			giveThen:    newTestInjector500s(),
+			giveElse:    newTestInjectorNoop(),
+			giveOptions: nil,
+			wantErr:     &OptionError{Option: "NewConditionalInjector", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:     "option error",
+			giveCond: testCondHeader,
+			giveThen: newTestInjector500s(),
+			giveElse: newTestInjectorNoop(),
+			giveOptions: []ConditionalInjectorOption{
+				withError(),
+			},
+			wantErr: errErrorOption,
+		},
+		{
+			name:     "reporter and name",
+			giveCond: testCondHeader,
+			giveThen: newTestInjector500s(),
+			giveElse: nil,
+			giveOptions: []ConditionalInjectorOption{
+				WithReporter(NewNoopReporter()),
+				WithName("custom"),
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewConditionalInjector(tt.giveCond, tt.giveThen, tt.giveElse, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.giveThen, ci.then)
+				assert.Equal(t, tt.giveElse, ci.els)
+			} else {
+				assert.Nil(t, ci)
+			}
+		})
+	}
+}
+
+// TestConditionalInjectorHandler tests ConditionalInjector.Handler.
+func TestConditionalInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveCond func(*http.Request) bool
+		giveThen Injector
+		giveElse Injector
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "true",
+			giveCond: testCondHeader,
+			giveThen: newTestInjector500s(),
+			giveElse: newTestInjectorTwoTeapot(),
+			wantCode: http.StatusInternalServerError,
+			wantBody: http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:     "false",
+			giveCond: func(*http.Request) bool { return false },
+			giveThen: newTestInjector500s(),
+			giveElse: newTestInjectorTwoTeapot(),
+			wantCode: http.StatusTeapot,
+			wantBody: "two" + testHandlerBody,
+		},
+		{
+			name:     "true nil",
+			giveCond: testCondHeader,
+			giveThen: nil,
+			giveElse: newTestInjector500s(),
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+		{
+			name:     "false nil",
+			giveCond: func(*http.Request) bool { return false },
+			giveThen: newTestInjector500s(),
+			giveElse: nil,
+			wantCode: testHandlerCode,
+			wantBody: testHandlerBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewConditionalInjector(tt.giveCond, tt.giveThen, tt.giveElse)
+			assert.NoError(t, err)
+
+			f, err := NewFault(ci,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			rr := testRequest(t, f)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+// TestConditionalInjectorHandlerReport tests that ConditionalInjector.Handler reports its states
+// around the Injector it runs and not when that Injector is nil.
+func TestConditionalInjectorHandlerReport(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 4)}
+	ci, err := NewConditionalInjector(testCondHeader, newTestInjector500s(), nil,
+		WithReporter(rep), WithName("custom"))
+	assert.NoError(t, err)
+
+	h := ci.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, testHandlerBody, testHandlerCode)
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, testHandlerCode, rr.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(testHeaderKey, testHeaderVal)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	assert.ElementsMatch(t, []string{"custom started", "custom finished"},
+		[]string{<-rep.states, <-rep.states})
+	assert.Empty(t, rep.states)
+}
+
+// TestConditionalInjectorString tests ConditionalInjector.String.
+func TestConditionalInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewConditionalInjector(func(r *http.Request) bool { return true }, newTestInjectorNoop(), nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ConditionalInjector(then=testInjectorNoop, else=nil)", ci.String())
+
+	ci, err = NewConditionalInjector(func(r *http.Request) bool { return true }, nil, nil, WithName("custom"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "custom(then=nil, else=nil)", ci.String())
+}
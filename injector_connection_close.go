@@ -0,0 +1,65 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// ConnectionCloseInjector sets "Connection: close" on the response and then continues the request.
+type ConnectionCloseInjector struct {
+	reporter Reporter
+	name     string
+}
+
+// ConnectionCloseInjectorOption configures a ConnectionCloseInjector.
+type ConnectionCloseInjectorOption interface {
+	applyConnectionCloseInjector(i *ConnectionCloseInjector) error
+}
+
+func (o reporterOption) applyConnectionCloseInjector(i *ConnectionCloseInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyConnectionCloseInjector(i *ConnectionCloseInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewConnectionCloseInjector returns a ConnectionCloseInjector.
+func NewConnectionCloseInjector(opts ...ConnectionCloseInjectorOption) (*ConnectionCloseInjector, error) {
+	// set defaults
+	ci := &ConnectionCloseInjector{
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(ConnectionCloseInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, ConnectionCloseInjectorOption.applyConnectionCloseInjector, ci)
+	if err != nil {
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+// Handler sets "Connection: close" on the response before continuing the request. The http.Server
+// closes an HTTP/1.x connection after writing a response with this header, so clients cannot reuse
+// the connection and must dial again. Use the ConnectionCloseInjector to load test how clients
+// handle connection churn. HTTP/2 does not allow the Connection header and ignores it.
+func (i *ConnectionCloseInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		w.Header().Set("Connection", "close")
+
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// String describes the ConnectionCloseInjector, such as "ConnectionCloseInjector".
+func (i *ConnectionCloseInjector) String() string {
+	return i.name
+}
@@ -0,0 +1,115 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewConnectionCloseInjector tests NewConnectionCloseInjector.
+func TestNewConnectionCloseInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []ConnectionCloseInjectorOption
+		want        *ConnectionCloseInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &ConnectionCloseInjector{
+				reporter: NewNoopReporter(),
+				name:     "ConnectionCloseInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []ConnectionCloseInjectorOption{
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &ConnectionCloseInjector{
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "option error",
+			giveOptions: []ConnectionCloseInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewConnectionCloseInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, ci)
+		})
+	}
+}
+
+// TestConnectionCloseInjectorHandler tests ConnectionCloseInjector.Handler.
+func TestConnectionCloseInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewConnectionCloseInjector()
+	assert.NoError(t, err)
+
+	f, err := NewFault(ci,
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+	assert.Equal(t, testHandlerCode, rr.Code)
+	assert.Equal(t, "close", rr.Header().Get("Connection"))
+}
+
+// TestConnectionCloseInjectorServer tests that a server closes the connection after a response from
+// a ConnectionCloseInjector.
+func TestConnectionCloseInjectorServer(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewConnectionCloseInjector()
+	assert.NoError(t, err)
+
+	srv := httptest.NewServer(ci.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(testHandlerCode)
+	})))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	assert.NoError(t, err)
+
+	resp, err := srv.Client().Do(req)
+	assert.NoError(t, err)
+
+	assert.Equal(t, testHandlerCode, resp.StatusCode)
+	assert.True(t, resp.Close)
+	assert.NoError(t, resp.Body.Close())
+}
+
+// TestConnectionCloseInjectorString tests ConnectionCloseInjector.String.
+func TestConnectionCloseInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewConnectionCloseInjector()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ConnectionCloseInjector", ci.String())
+}
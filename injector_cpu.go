@@ -0,0 +1,100 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// CPUInjector keeps the CPU busy for a duration and then continues the request.
+type CPUInjector struct {
+	duration time.Duration
+	workers  int
+	reporter Reporter
+	name     string
+}
+
+// CPUInjectorOption configures a CPUInjector.
+type CPUInjectorOption interface {
+	applyCPUInjector(i *CPUInjector) error
+}
+
+type cpuWorkersOption int
+
+func (o cpuWorkersOption) applyCPUInjector(i *CPUInjector) error {
+	if o < 1 {
+		return &OptionError{Option: "WithCPUWorkers", Value: int(o), Err: ErrInvalidCount}
+	}
+	i.workers = int(o)
+	return nil
+}
+
+// WithCPUWorkers sets the number of goroutines that keep the CPU busy. Default 1.
+func WithCPUWorkers(n int) CPUInjectorOption {
+	return cpuWorkersOption(n)
+}
+
+func (o reporterOption) applyCPUInjector(i *CPUInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyCPUInjector(i *CPUInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewCPUInjector returns a CPUInjector.
+func NewCPUInjector(d time.Duration, opts ...CPUInjectorOption) (*CPUInjector, error) {
+	// set defaults
+	ci := &CPUInjector{
+		duration: d,
+		workers:  1,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(CPUInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, CPUInjectorOption.applyCPUInjector, ci)
+	if err != nil {
+		return nil, err
+	}
+
+	return ci, nil
+}
+
+// Handler spins CPUInjector.workers goroutines for the set duration and then continues. This
+// simulates noisy neighbors or garbage collection pressure rather than pure latency.
+func (i *CPUInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+		i.burn()
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// burn keeps CPUInjector.workers goroutines busy until CPUInjector.duration has passed.
+func (i *CPUInjector) burn() {
+	deadline := time.Now().Add(i.duration)
+
+	var wg sync.WaitGroup
+	for range i.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				// spin
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// String describes the CPUInjector, such as "CPUInjector(1s, workers=2)".
+func (i *CPUInjector) String() string {
+	return fmt.Sprintf("%s(%s, workers=%d)", i.name, i.duration, i.workers)
+}
@@ -0,0 +1,136 @@
+package fault
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCPUInjector tests NewCPUInjector.
+func TestNewCPUInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveDuration time.Duration
+		giveOptions  []CPUInjectorOption
+		want         *CPUInjector
+		wantErr      error
+	}{
+		{
+			name:         "nil",
+			giveDuration: 0,
+			giveOptions:  nil,
+			want: &CPUInjector{
+				duration: 0,
+				workers:  1,
+				reporter: NewNoopReporter(),
+				name:     "CPUInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "all options",
+			giveDuration: time.Minute,
+			giveOptions: []CPUInjectorOption{
+				WithCPUWorkers(4),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &CPUInjector{
+				duration: time.Minute,
+				workers:  4,
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "invalid workers",
+			giveDuration: time.Minute,
+			giveOptions: []CPUInjectorOption{
+				WithCPUWorkers(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithCPUWorkers", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name:         "option error",
+			giveDuration: time.Minute,
+			giveOptions: []CPUInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewCPUInjector(tt.giveDuration, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, ci)
+		})
+	}
+}
+
+// TestCPUInjectorHandler tests CPUInjector.Handler.
+func TestCPUInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveDuration time.Duration
+		giveOptions  []CPUInjectorOption
+	}{
+		{
+			name:         "zero",
+			giveDuration: 0,
+			giveOptions:  nil,
+		},
+		{
+			name:         "two workers",
+			giveDuration: time.Millisecond,
+			giveOptions: []CPUInjectorOption{
+				WithCPUWorkers(2),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ci, err := NewCPUInjector(tt.giveDuration, tt.giveOptions...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(ci,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			start := time.Now()
+			rr := testRequest(t, f)
+
+			assert.GreaterOrEqual(t, time.Since(start), tt.giveDuration)
+			assert.Equal(t, testHandlerCode, rr.Code)
+			assert.Equal(t, testHandlerBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+// TestCPUInjectorString tests CPUInjector.String.
+func TestCPUInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewCPUInjector(time.Second, WithCPUWorkers(2))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "CPUInjector(1s, workers=2)", ci.String())
+}
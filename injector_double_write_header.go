@@ -0,0 +1,117 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// DoubleWriteHeaderInjector continues the request and then misuses the http.ResponseWriter by
+// calling WriteHeader again and optionally writing more of the body after the handler returns.
+type DoubleWriteHeaderInjector struct {
+	statusCode int
+	lateWrite  []byte
+	reporter   Reporter
+	name       string
+}
+
+// DoubleWriteHeaderInjectorOption configures a DoubleWriteHeaderInjector.
+type DoubleWriteHeaderInjectorOption interface {
+	applyDoubleWriteHeaderInjector(i *DoubleWriteHeaderInjector) error
+}
+
+type superfluousStatusOption int
+
+func (o superfluousStatusOption) applyDoubleWriteHeaderInjector(i *DoubleWriteHeaderInjector) error {
+	if http.StatusText(int(o)) == "" {
+		return &OptionError{Option: "WithSuperfluousStatus", Value: int(o), Err: ErrInvalidHTTPCode}
+	}
+	i.statusCode = int(o)
+	return nil
+}
+
+// WithSuperfluousStatus sets the status code passed to the extra WriteHeader calls. Default
+// http.StatusInternalServerError.
+func WithSuperfluousStatus(code int) DoubleWriteHeaderInjectorOption {
+	return superfluousStatusOption(code)
+}
+
+type lateWriteOption []byte
+
+func (o lateWriteOption) applyDoubleWriteHeaderInjector(i *DoubleWriteHeaderInjector) error {
+	i.lateWrite = append([]byte{}, o...)
+	return nil
+}
+
+// WithLateWrite writes body to the response after the handler returns and WriteHeader is called
+// again. The body is copied.
+func WithLateWrite(body []byte) DoubleWriteHeaderInjectorOption {
+	return lateWriteOption(body)
+}
+
+func (o reporterOption) applyDoubleWriteHeaderInjector(i *DoubleWriteHeaderInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyDoubleWriteHeaderInjector(i *DoubleWriteHeaderInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewDoubleWriteHeaderInjector returns a DoubleWriteHeaderInjector.
+func NewDoubleWriteHeaderInjector(opts ...DoubleWriteHeaderInjectorOption) (*DoubleWriteHeaderInjector, error) {
+	// set defaults
+	di := &DoubleWriteHeaderInjector{
+		statusCode: http.StatusInternalServerError,
+		reporter:   NewNoopReporter(),
+		name:       reflect.TypeOf(DoubleWriteHeaderInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, DoubleWriteHeaderInjectorOption.applyDoubleWriteHeaderInjector, di)
+	if err != nil {
+		return nil, err
+	}
+
+	return di, nil
+}
+
+// Handler continues the request and, after the handler returns, calls WriteHeader on the
+// http.ResponseWriter so that it is called at least twice, and then writes the late write if one is
+// set. Place the DoubleWriteHeaderInjector inside logging middleware and custom ResponseWriter
+// wrappers to check that they handle the "http: superfluous response.WriteHeader call" case and do
+// not record the wrong status code or panic. If the handler did not write a header, WriteHeader is
+// called twice, so the client receives the superfluous status code.
+func (i *DoubleWriteHeaderInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &ResponseRecorderWriter{w: w}
+		next.ServeHTTP(rw, r)
+
+		go report(i.reporter, i.name, StateStarted)
+
+		if !rw.WroteHeader() {
+			w.WriteHeader(i.statusCode)
+		}
+		w.WriteHeader(i.statusCode)
+
+		if len(i.lateWrite) > 0 {
+			_, err := w.Write(i.lateWrite)
+			if err != nil {
+				abort(i.reporter, i.name)
+			}
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// String describes the DoubleWriteHeaderInjector, such as
+// "DoubleWriteHeaderInjector(500, lateWrite=5 bytes)".
+func (i *DoubleWriteHeaderInjector) String() string {
+	if len(i.lateWrite) > 0 {
+		return fmt.Sprintf("%s(%d, lateWrite=%d bytes)", i.name, i.statusCode, len(i.lateWrite))
+	}
+
+	return fmt.Sprintf("%s(%d)", i.name, i.statusCode)
+}
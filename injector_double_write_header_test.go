@@ -0,0 +1,168 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testWriteHeaderWriter is an http.ResponseWriter that records every WriteHeader call, like a
+// logging middleware that wraps the ResponseWriter.
+type testWriteHeaderWriter struct {
+	*httptest.ResponseRecorder
+	codes []int
+}
+
+// WriteHeader records code and passes it to the ResponseRecorder.
+func (w *testWriteHeaderWriter) WriteHeader(code int) {
+	w.codes = append(w.codes, code)
+	w.ResponseRecorder.WriteHeader(code)
+}
+
+// TestNewDoubleWriteHeaderInjector tests NewDoubleWriteHeaderInjector.
+func TestNewDoubleWriteHeaderInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []DoubleWriteHeaderInjectorOption
+		want        *DoubleWriteHeaderInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &DoubleWriteHeaderInjector{
+				statusCode: http.StatusInternalServerError,
+				reporter:   NewNoopReporter(),
+				name:       "DoubleWriteHeaderInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []DoubleWriteHeaderInjectorOption{
+				WithSuperfluousStatus(http.StatusTeapot),
+				WithLateWrite([]byte("late")),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &DoubleWriteHeaderInjector{
+				statusCode: http.StatusTeapot,
+				lateWrite:  []byte("late"),
+				reporter:   newTestReporter(),
+				name:       "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid code",
+			giveOptions: []DoubleWriteHeaderInjectorOption{
+				WithSuperfluousStatus(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithSuperfluousStatus", Value: 0, Err: ErrInvalidHTTPCode},
+		},
+		{
+			name: "option error",
+			giveOptions: []DoubleWriteHeaderInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			di, err := NewDoubleWriteHeaderInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, di)
+		})
+	}
+}
+
+// TestDoubleWriteHeaderInjectorHandler tests DoubleWriteHeaderInjector.Handler.
+func TestDoubleWriteHeaderInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []DoubleWriteHeaderInjectorOption
+		giveHandler http.Handler
+		wantCodes   []int
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name: "after handler",
+			giveHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, testHandlerBody, testHandlerCode)
+			}),
+			wantCodes: []int{testHandlerCode, http.StatusInternalServerError},
+			wantCode:  testHandlerCode,
+			wantBody:  testHandlerBody + "\n",
+		},
+		{
+			name:        "handler writes nothing",
+			giveOptions: []DoubleWriteHeaderInjectorOption{WithLateWrite([]byte("late"))},
+			giveHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			wantCodes:   []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantCode:    http.StatusInternalServerError,
+			wantBody:    "late",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			di, err := NewDoubleWriteHeaderInjector(tt.giveOptions...)
+			assert.NoError(t, err)
+
+			rr := &testWriteHeaderWriter{ResponseRecorder: httptest.NewRecorder()}
+			di.Handler(tt.giveHandler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantCodes, rr.codes)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+// TestDoubleWriteHeaderInjectorHandlerWriteError tests that a DoubleWriteHeaderInjector aborts the
+// request when the late write fails.
+func TestDoubleWriteHeaderInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 2)}
+	di, err := NewDoubleWriteHeaderInjector(WithLateWrite([]byte("late")), WithReporter(rep))
+	assert.NoError(t, err)
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	h := di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.ElementsMatch(t, []string{"DoubleWriteHeaderInjector started", "DoubleWriteHeaderInjector aborted"},
+		[]string{<-rep.states, <-rep.states})
+}
+
+// TestDoubleWriteHeaderInjectorString tests DoubleWriteHeaderInjector.String.
+func TestDoubleWriteHeaderInjectorString(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDoubleWriteHeaderInjector()
+	assert.NoError(t, err)
+	assert.Equal(t, "DoubleWriteHeaderInjector(500)", di.String())
+
+	di, err = NewDoubleWriteHeaderInjector(WithLateWrite([]byte("late!")))
+	assert.NoError(t, err)
+	assert.Equal(t, "DoubleWriteHeaderInjector(500, lateWrite=5 bytes)", di.String())
+}
@@ -0,0 +1,122 @@
+package fault
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// DowngradeInjector continues the request with HTTP/1.1 semantics by hiding HTTP/2 server push and
+// dropping response trailers.
+type DowngradeInjector struct {
+	reporter Reporter
+	name     string
+}
+
+// DowngradeInjectorOption configures a DowngradeInjector.
+type DowngradeInjectorOption interface {
+	applyDowngradeInjector(i *DowngradeInjector) error
+}
+
+func (o reporterOption) applyDowngradeInjector(i *DowngradeInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyDowngradeInjector(i *DowngradeInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewDowngradeInjector returns a DowngradeInjector.
+func NewDowngradeInjector(opts ...DowngradeInjectorOption) (*DowngradeInjector, error) {
+	// set defaults
+	di := &DowngradeInjector{
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(DowngradeInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, DowngradeInjectorOption.applyDowngradeInjector, di)
+	if err != nil {
+		return nil, err
+	}
+
+	return di, nil
+}
+
+// Handler continues the request as if it had arrived over HTTP/1.1. The handler sees a request
+// with the Proto "HTTP/1.1", a ResponseWriter that does not implement http.Pusher, and any trailers
+// it declares or sets are dropped from the response. Flushing, hijacking, and Unwrap still pass
+// through, so HTTP/1.1 upgrades such as websockets and http.ResponseController keep working. The
+// connection itself is not changed, because the protocol is negotiated before the handler runs, so
+// use the DowngradeInjector on h2c or HTTP/2 servers to reveal handlers and clients that assume
+// push or trailers, such as gRPC-Web gateways that read the status from a trailer.
+func (i *DowngradeInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		r = r.Clone(r.Context())
+		r.Proto = "HTTP/1.1"
+		r.ProtoMajor = 1
+		r.ProtoMinor = 1
+
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(&downgradeWriter{ResponseWriter: w}, r)
+
+		// trailers set with http.TrailerPrefix are sent when the handler returns
+		h := w.Header()
+		for key := range h {
+			if strings.HasPrefix(key, http.TrailerPrefix) {
+				delete(h, key)
+			}
+		}
+	})
+}
+
+// String describes the DowngradeInjector, such as "DowngradeInjector".
+func (i *DowngradeInjector) String() string {
+	return i.name
+}
+
+// downgradeWriter is an http.ResponseWriter that does not implement http.Pusher and drops the
+// declaration of trailers before the header is written. It implements http.Flusher and
+// http.Hijacker by passing calls through to the wrapped ResponseWriter.
+type downgradeWriter struct {
+	http.ResponseWriter
+}
+
+// WriteHeader drops the Trailer header and writes code.
+func (w *downgradeWriter) WriteHeader(code int) {
+	w.Header().Del("Trailer")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write drops the Trailer header in case b is the first write, and writes b.
+func (w *downgradeWriter) Write(b []byte) (int, error) {
+	w.Header().Del("Trailer")
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush drops the Trailer header in case the header is not written yet, and flushes the wrapped
+// ResponseWriter if it implements http.Flusher.
+func (w *downgradeWriter) Flush() {
+	w.Header().Del("Trailer")
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the wrapped ResponseWriter, or returns http.ErrNotSupported if
+// it cannot be hijacked.
+func (w *downgradeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the wrapped ResponseWriter for use with http.ResponseController.
+func (w *downgradeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,187 @@
+package fault
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewDowngradeInjector tests NewDowngradeInjector.
+func TestNewDowngradeInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []DowngradeInjectorOption
+		want        *DowngradeInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &DowngradeInjector{
+				reporter: NewNoopReporter(),
+				name:     "DowngradeInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []DowngradeInjectorOption{
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &DowngradeInjector{
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "option error",
+			giveOptions: []DowngradeInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			di, err := NewDowngradeInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, di)
+		})
+	}
+}
+
+// TestDowngradeInjectorHandler tests DowngradeInjector.Handler.
+func TestDowngradeInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDowngradeInjector()
+	assert.NoError(t, err)
+
+	h := di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "HTTP/1.1", r.Proto)
+		assert.True(t, r.ProtoAtLeast(1, 1))
+		assert.False(t, r.ProtoAtLeast(2, 0))
+
+		_, ok := w.(http.Pusher)
+		assert.False(t, ok)
+
+		w.Header().Set("Trailer", "X-Status")
+		w.Header().Set(http.TrailerPrefix+"X-Late", "late")
+		w.WriteHeader(testHandlerCode)
+		w.(http.Flusher).Flush()
+		_, err := w.Write([]byte(testHandlerBody))
+		assert.NoError(t, err)
+		w.Header().Set("X-Status", "ok")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, testHandlerCode, rr.Code)
+	assert.Equal(t, testHandlerBody, rr.Body.String())
+	assert.True(t, rr.Flushed)
+	assert.Empty(t, rr.Result().Trailer)
+	assert.Empty(t, rr.Header().Get("Trailer"))
+	assert.Empty(t, rr.Header().Get(http.TrailerPrefix+"X-Late"))
+
+	// writers that cannot flush are not flushed
+	h = di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	}))
+	assert.NotPanics(t, func() {
+		h.ServeHTTP(&testMinimalWriter{header: make(http.Header)}, req)
+	})
+}
+
+// TestDowngradeInjectorHandlerPassthrough tests that flushing, hijacking, and Unwrap pass through
+// the http.ResponseWriter that a DowngradeInjector passes to the next handler, and that it copies
+// the request instead of changing it.
+func TestDowngradeInjectorHandlerPassthrough(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDowngradeInjector()
+	assert.NoError(t, err)
+
+	fw := &testFullWriter{ResponseRecorder: httptest.NewRecorder()}
+	h := di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.NoError(t, err)
+		assert.Same(t, fw, w.(interface{ Unwrap() http.ResponseWriter }).Unwrap())
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		r.Header.Set("X-Changed", "yes")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+	h.ServeHTTP(fw, req)
+
+	assert.True(t, fw.hijacked)
+	assert.True(t, fw.Flushed)
+	assert.Equal(t, "HTTP/2.0", req.Proto)
+	assert.Empty(t, req.Header.Get("X-Changed"))
+
+	// writers that cannot be hijacked are not supported
+	h = di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.ErrorIs(t, err, http.ErrNotSupported)
+	}))
+	h.ServeHTTP(&testMinimalWriter{header: make(http.Header)}, req)
+}
+
+// TestDowngradeInjectorHTTP2 tests that a DowngradeInjector drops trailers from HTTP/2 responses.
+func TestDowngradeInjectorHTTP2(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDowngradeInjector()
+	assert.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Status")
+		_, err := w.Write([]byte(testHandlerBody))
+		assert.NoError(t, err)
+		w.Header().Set("X-Status", "ok")
+		w.Header().Set(http.TrailerPrefix+"X-Late", "late")
+	})))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	assert.NoError(t, err)
+
+	resp, err := srv.Client().Do(req)
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, testHandlerBody, string(body))
+	assert.Empty(t, resp.Trailer)
+}
+
+// TestDowngradeInjectorString tests DowngradeInjector.String.
+func TestDowngradeInjectorString(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDowngradeInjector()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "DowngradeInjector", di.String())
+}
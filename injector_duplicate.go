@@ -0,0 +1,193 @@
+package fault
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxDuplicateBody is the largest request body that a DuplicateRequestInjector reads into
+// memory to duplicate by default.
+const defaultMaxDuplicateBody = 10 << 20 // 10 MiB
+
+// DuplicateRequestInjector continues the request and also sends duplicates of it to the next
+// handler, discarding the responses to the duplicates.
+type DuplicateRequestInjector struct {
+	duplicates int
+	first      bool
+	maxBody    int64
+	reporter   Reporter
+	name       string
+}
+
+// DuplicateRequestInjectorOption configures a DuplicateRequestInjector.
+type DuplicateRequestInjectorOption interface {
+	applyDuplicateRequestInjector(i *DuplicateRequestInjector) error
+}
+
+type duplicatesOption int
+
+func (o duplicatesOption) applyDuplicateRequestInjector(i *DuplicateRequestInjector) error {
+	if o < 1 {
+		return &OptionError{Option: "WithDuplicates", Value: int(o), Err: ErrInvalidCount}
+	}
+	i.duplicates = int(o)
+	return nil
+}
+
+// WithDuplicates sets how many duplicates of each request are sent. n must be at least 1. Default 1.
+func WithDuplicates(n int) DuplicateRequestInjectorOption {
+	return duplicatesOption(n)
+}
+
+type duplicateFirstOption struct{}
+
+func (o duplicateFirstOption) applyDuplicateRequestInjector(i *DuplicateRequestInjector) error {
+	i.first = true
+	return nil
+}
+
+// WithDuplicateFirst sends the duplicates before the original request instead of after, as if the
+// response to an earlier delivery was lost.
+func WithDuplicateFirst() DuplicateRequestInjectorOption {
+	return duplicateFirstOption{}
+}
+
+type maxDuplicateBodyOption int64
+
+func (o maxDuplicateBodyOption) applyDuplicateRequestInjector(i *DuplicateRequestInjector) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithMaxDuplicateBody", Value: int64(o), Err: ErrInvalidCount}
+	}
+	i.maxBody = int64(o)
+	return nil
+}
+
+// WithMaxDuplicateBody sets the largest request body, in bytes, that is read into memory to be
+// duplicated, which bounds the memory used by large uploads. Requests with larger bodies are not
+// duplicated and continue unchanged. n must be greater than 0. Default 10 MiB.
+func WithMaxDuplicateBody(n int64) DuplicateRequestInjectorOption {
+	return maxDuplicateBodyOption(n)
+}
+
+func (o reporterOption) applyDuplicateRequestInjector(i *DuplicateRequestInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyDuplicateRequestInjector(i *DuplicateRequestInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewDuplicateRequestInjector returns a DuplicateRequestInjector.
+func NewDuplicateRequestInjector(opts ...DuplicateRequestInjectorOption) (*DuplicateRequestInjector, error) {
+	// set defaults
+	di := &DuplicateRequestInjector{
+		duplicates: 1,
+		maxBody:    defaultMaxDuplicateBody,
+		reporter:   NewNoopReporter(),
+		name:       reflect.TypeOf(DuplicateRequestInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, DuplicateRequestInjectorOption.applyDuplicateRequestInjector, di)
+	if err != nil {
+		return nil, err
+	}
+
+	return di, nil
+}
+
+// Handler sends the request and its duplicates to next one after another. The request body is read
+// once and each request reads its own copy. Only the response to the original request is written.
+// Requests with a body larger than WithMaxDuplicateBody are skipped and continue unchanged. Use the
+// DuplicateRequestInjector to verify that handlers are idempotent under at-least-once delivery.
+func (i *DuplicateRequestInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		var err error
+		if r.Body != nil {
+			original := r.Body
+			body, err = io.ReadAll(io.LimitReader(original, i.maxBody+1))
+			r = r.Clone(r.Context())
+			if int64(len(body)) > i.maxBody {
+				// too large to duplicate, continue with the buffered and unread body
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), original), original}
+
+				go report(i.reporter, i.name, StateSkipped)
+				next.ServeHTTP(w, r)
+				return
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{bodyReader(body, err), original}
+		}
+
+		go report(i.reporter, i.name, StateStarted)
+
+		if !i.first {
+			next.ServeHTTP(w, r)
+		}
+		for range i.duplicates {
+			next.ServeHTTP(&discardWriter{header: make(http.Header)}, duplicateRequest(r, body, err))
+		}
+		if i.first {
+			next.ServeHTTP(w, r)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// duplicateRequest returns a copy of r whose body reads body and then returns err.
+func duplicateRequest(r *http.Request, body []byte, err error) *http.Request {
+	dup := r.Clone(r.Context())
+	if r.Body != nil {
+		dup.Body = io.NopCloser(bodyReader(body, err))
+	}
+	return dup
+}
+
+// bodyReader returns a reader that reads body and then returns err, or io.EOF if err is nil.
+func bodyReader(body []byte, err error) io.Reader {
+	if err == nil {
+		return bytes.NewReader(body)
+	}
+	return io.MultiReader(bytes.NewReader(body), errReader{err: err})
+}
+
+// discardWriter is an http.ResponseWriter that discards the response.
+type discardWriter struct {
+	header http.Header
+}
+
+// Header returns a header that is never written.
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+// Write discards b.
+func (w *discardWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// WriteHeader does nothing.
+func (w *discardWriter) WriteHeader(int) {}
+
+// String describes the DuplicateRequestInjector, such as "DuplicateRequestInjector(2, first)".
+func (i *DuplicateRequestInjector) String() string {
+	details := []string{strconv.Itoa(i.duplicates)}
+	if i.first {
+		details = append(details, "first")
+	}
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
@@ -0,0 +1,208 @@
+package fault
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewDuplicateRequestInjector tests NewDuplicateRequestInjector.
+func TestNewDuplicateRequestInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []DuplicateRequestInjectorOption
+		want        *DuplicateRequestInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &DuplicateRequestInjector{
+				duplicates: 1,
+				maxBody:    defaultMaxDuplicateBody,
+				reporter:   NewNoopReporter(),
+				name:       "DuplicateRequestInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []DuplicateRequestInjectorOption{
+				WithDuplicates(3),
+				WithDuplicateFirst(),
+				WithMaxDuplicateBody(1024),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &DuplicateRequestInjector{
+				duplicates: 3,
+				first:      true,
+				maxBody:    1024,
+				reporter:   newTestReporter(),
+				name:       "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid duplicates",
+			giveOptions: []DuplicateRequestInjectorOption{
+				WithDuplicates(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithDuplicates", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name: "invalid max body",
+			giveOptions: []DuplicateRequestInjectorOption{
+				WithMaxDuplicateBody(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithMaxDuplicateBody", Value: int64(0), Err: ErrInvalidCount},
+		},
+		{
+			name: "option error",
+			giveOptions: []DuplicateRequestInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			di, err := NewDuplicateRequestInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, di)
+		})
+	}
+}
+
+// TestDuplicateRequestInjectorHandler tests DuplicateRequestInjector.Handler.
+func TestDuplicateRequestInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []DuplicateRequestInjectorOption
+		giveBody    io.Reader
+		wantBodies  []string
+		wantErrs    []error
+		wantBody    string
+	}{
+		{
+			name:        "after",
+			giveOptions: nil,
+			giveBody:    strings.NewReader("body"),
+			wantBodies:  []string{"1:body", "2:body"},
+			wantErrs:    []error{nil, nil},
+			wantBody:    "1:body",
+		},
+		{
+			name:        "first",
+			giveOptions: []DuplicateRequestInjectorOption{WithDuplicates(2), WithDuplicateFirst()},
+			giveBody:    strings.NewReader("body"),
+			wantBodies:  []string{"1:body", "2:body", "3:body"},
+			wantErrs:    []error{nil, nil, nil},
+			wantBody:    "3:body",
+		},
+		{
+			name:        "body error",
+			giveOptions: nil,
+			giveBody:    io.MultiReader(strings.NewReader("bo"), errReader{err: errTestBodyRead}),
+			wantBodies:  []string{"1:bo", "2:bo"},
+			wantErrs:    []error{errTestBodyRead, errTestBodyRead},
+			wantBody:    "1:bo",
+		},
+		{
+			name:        "max body",
+			giveOptions: []DuplicateRequestInjectorOption{WithMaxDuplicateBody(4)},
+			giveBody:    strings.NewReader("body"),
+			wantBodies:  []string{"1:body", "2:body"},
+			wantErrs:    []error{nil, nil},
+			wantBody:    "1:body",
+		},
+		{
+			name:        "over max body",
+			giveOptions: []DuplicateRequestInjectorOption{WithMaxDuplicateBody(3), WithDuplicateFirst()},
+			giveBody:    strings.NewReader("body"),
+			wantBodies:  []string{"1:body"},
+			wantErrs:    []error{nil},
+			wantBody:    "1:body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			di, err := NewDuplicateRequestInjector(tt.giveOptions...)
+			assert.NoError(t, err)
+
+			var bodies []string
+			var errs []error
+			h := di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				body := strconv.Itoa(len(bodies)+1) + ":" + string(b)
+				bodies = append(bodies, body)
+				errs = append(errs, err)
+				w.Header().Set("X-Body", body)
+				w.WriteHeader(testHandlerCode)
+				_, err = w.Write([]byte(body))
+				assert.NoError(t, err)
+			}))
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", tt.giveBody))
+
+			assert.Equal(t, tt.wantBodies, bodies)
+			assert.Equal(t, tt.wantErrs, errs)
+			assert.Equal(t, testHandlerCode, rr.Code)
+			assert.Equal(t, tt.wantBody, rr.Header().Get("X-Body"))
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+// TestDuplicateRequestInjectorNoBody tests DuplicateRequestInjector.Handler with a request that
+// has no body.
+func TestDuplicateRequestInjectorNoBody(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDuplicateRequestInjector()
+	assert.NoError(t, err)
+
+	var bodies []io.ReadCloser
+	h := di.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodies = append(bodies, r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Body = nil
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []io.ReadCloser{nil, nil}, bodies)
+}
+
+// TestDuplicateRequestInjectorString tests DuplicateRequestInjector.String.
+func TestDuplicateRequestInjectorString(t *testing.T) {
+	t.Parallel()
+
+	di, err := NewDuplicateRequestInjector()
+	assert.NoError(t, err)
+	assert.Equal(t, "DuplicateRequestInjector(1)", di.String())
+
+	di, err = NewDuplicateRequestInjector(WithDuplicates(2), WithDuplicateFirst())
+	assert.NoError(t, err)
+	assert.Equal(t, "DuplicateRequestInjector(2, first)", di.String())
+}
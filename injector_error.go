@@ -2,20 +2,33 @@ package fault
 
 import (
 	"errors"
+	"fmt"
+	"html"
+	"io"
 	"net/http"
 	"reflect"
+	"strings"
+	"unicode/utf8"
 )
 
 var (
 	// ErrInvalidHTTPCode when an invalid status code is provided.
 	ErrInvalidHTTPCode = errors.New("not a valid http status code")
+	// ErrNilReader when a nil io.Reader is provided.
+	ErrNilReader = errors.New("reader cannot be nil")
 )
 
 // ErrorInjector responds with an http status code and message.
 type ErrorInjector struct {
 	statusCode int
 	statusText string
-	reporter   Reporter
+	// statusTextSet is true if WithStatusText set statusText, so that it is not replaced with the
+	// default text of statusCode.
+	statusTextSet bool
+	body          []byte
+	contentType   string
+	reporter      Reporter
+	name          string
 }
 
 // ErrorInjectorOption configures an ErrorInjector.
@@ -26,44 +39,125 @@ type ErrorInjectorOption interface {
 type statusTextOption string
 
 func (o statusTextOption) applyErrorInjector(i *ErrorInjector) error {
-	i.statusText = string(o)
+	i.statusText, i.statusTextSet = string(o), true
 	return nil
 }
 
-// WithStatusText sets custom status text to write.
+// WithStatusText sets custom status text to write. Default the text of the status code, such as
+// "Service Unavailable". An empty t responds with an empty body.
 func WithStatusText(t string) ErrorInjectorOption {
 	return statusTextOption(t)
 }
 
+type soapFaultOption struct {
+	code string
+	text string
+}
+
+func (o soapFaultOption) applyErrorInjector(i *ErrorInjector) error {
+	i.body = []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+		`<soap:Body><soap:Fault>` +
+		`<faultcode>` + escapeXML(o.code) + `</faultcode>` +
+		`<faultstring>` + escapeXML(o.text) + `</faultstring>` +
+		`</soap:Fault></soap:Body></soap:Envelope>` + "\n")
+	i.contentType = "text/xml; charset=utf-8"
+	return nil
+}
+
+// WithSOAPFault responds with a SOAP 1.1 fault envelope with the faultcode and faultstring instead
+// of plain text, such as WithSOAPFault("soap:Server", "internal error"). The response has a
+// text/xml content type.
+func WithSOAPFault(faultcode, faultstring string) ErrorInjectorOption {
+	return soapFaultOption{code: faultcode, text: faultstring}
+}
+
+type bodyOption struct {
+	body []byte
+}
+
+func (o bodyOption) applyErrorInjector(i *ErrorInjector) error {
+	i.body = o.body
+	i.contentType = http.DetectContentType(o.body)
+	return nil
+}
+
+// WithBodyBytes responds with body instead of the status text, such as a JSON error document. The
+// content type is detected from body with http.DetectContentType. body is copied.
+func WithBodyBytes(body []byte) ErrorInjectorOption {
+	return bodyOption{body: append([]byte{}, body...)}
+}
+
+// WithEmptyBody responds with a zero-length body instead of the status text.
+func WithEmptyBody() ErrorInjectorOption {
+	return bodyOption{body: []byte{}}
+}
+
+type bodyReaderOption struct {
+	r io.Reader
+}
+
+func (o bodyReaderOption) applyErrorInjector(i *ErrorInjector) error {
+	if o.r == nil {
+		return &OptionError{Option: "WithBody", Value: nil, Err: ErrNilReader}
+	}
+
+	body, err := io.ReadAll(o.r)
+	if err != nil {
+		return &OptionError{Option: "WithBody", Value: o.r, Err: err}
+	}
+
+	return bodyOption{body: body}.applyErrorInjector(i)
+}
+
+// WithBody responds with the contents of r instead of the status text, such as a recorded error
+// response from a file. r is read to the end once by NewErrorInjector, and every injection responds
+// with the same body. The content type is detected like WithBodyBytes.
+func WithBody(r io.Reader) ErrorInjectorOption {
+	return bodyReaderOption{r: r}
+}
+
+// escapeXML escapes s for use as XML character data, replacing characters that XML does not allow
+// with the unicode replacement character.
+func escapeXML(s string) string {
+	return html.EscapeString(strings.Map(func(r rune) rune {
+		if (r < ' ' && r != '\t' && r != '\n' && r != '\r') || r == '\uFFFE' || r == '\uFFFF' {
+			return utf8.RuneError
+		}
+		return r
+	}, s))
+}
+
 func (o reporterOption) applyErrorInjector(i *ErrorInjector) error {
 	i.reporter = o.reporter
 	return nil
 }
 
+func (o nameOption) applyErrorInjector(i *ErrorInjector) error {
+	i.name = string(o)
+	return nil
+}
+
 // NewErrorInjector returns an ErrorInjector that reponds with a status code.
 func NewErrorInjector(code int, opts ...ErrorInjectorOption) (*ErrorInjector, error) {
-	const placeholderStatusText = "go-fault: replace with default code text"
-
 	// set defaults
 	ei := &ErrorInjector{
 		statusCode: code,
-		statusText: placeholderStatusText,
 		reporter:   NewNoopReporter(),
+		name:       reflect.TypeOf(ErrorInjector{}).Name(),
 	}
 
 	// apply options
-	for _, opt := range opts {
-		err := opt.applyErrorInjector(ei)
-		if err != nil {
-			return nil, err
-		}
+	err := applyOptions(opts, ErrorInjectorOption.applyErrorInjector, ei)
+	if err != nil {
+		return nil, err
 	}
 
 	// check options
 	if http.StatusText(ei.statusCode) == "" {
-		return nil, ErrInvalidHTTPCode
+		return nil, &OptionError{Option: "NewErrorInjector", Value: ei.statusCode, Err: ErrInvalidHTTPCode}
 	}
-	if ei.statusText == placeholderStatusText {
+	if !ei.statusTextSet {
 		ei.statusText = http.StatusText(ei.statusCode)
 	}
 
@@ -73,8 +167,51 @@ func NewErrorInjector(code int, opts ...ErrorInjectorOption) (*ErrorInjector, er
 // Handler responds with the configured status code and text.
 func (i *ErrorInjector) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		go i.reporter.Report(reflect.ValueOf(*i).Type().Name(), StateStarted)
-		http.Error(w, i.statusText, i.statusCode)
-		go i.reporter.Report(reflect.ValueOf(*i).Type().Name(), StateFinished)
+		go report(i.reporter, i.name, StateStarted)
+
+		var err error
+		switch {
+		case i.body != nil:
+			err = writeBody(w, i.statusCode, i.contentType, i.body)
+		case i.statusText == "":
+			err = writeBody(w, i.statusCode, "text/plain; charset=utf-8", nil)
+		default:
+			http.Error(w, i.statusText, i.statusCode)
+		}
+		// the client is gone if the body cannot be written
+		if err != nil {
+			abort(i.reporter, i.name)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
 	})
 }
+
+// writeBody responds with code and body, in the same way that http.Error does for text. It returns
+// an error if the body cannot be written because the client is gone.
+func writeBody(w http.ResponseWriter, code int, contentType string, body []byte) error {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+
+	_, err := w.Write(body)
+	return err
+}
+
+// String describes the ErrorInjector, such as "ErrorInjector(503)". Custom status text is included,
+// such as "ErrorInjector(503, "try again")", and so is the content type of a custom body, such as
+// "ErrorInjector(500, text/xml; charset=utf-8)", or "ErrorInjector(503, empty body)".
+func (i *ErrorInjector) String() string {
+	if i.body != nil && len(i.body) == 0 {
+		return fmt.Sprintf("%s(%d, empty body)", i.name, i.statusCode)
+	}
+	if i.body != nil {
+		return fmt.Sprintf("%s(%d, %s)", i.name, i.statusCode, i.contentType)
+	}
+	if i.statusText != http.StatusText(i.statusCode) {
+		return fmt.Sprintf("%s(%d, %q)", i.name, i.statusCode, i.statusText)
+	}
+	return fmt.Sprintf("%s(%d)", i.name, i.statusCode)
+}
@@ -1,7 +1,9 @@
 package fault
 
 import (
+	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -27,6 +29,7 @@ func TestNewErrorInjector(t *testing.T) {
 				statusCode: http.StatusCreated,
 				statusText: http.StatusText(http.StatusCreated),
 				reporter:   NewNoopReporter(),
+				name:       "ErrorInjector",
 			},
 			wantErr: nil,
 		},
@@ -37,9 +40,11 @@ func TestNewErrorInjector(t *testing.T) {
 				WithStatusText(http.StatusText(http.StatusAccepted)),
 			},
 			want: &ErrorInjector{
-				statusCode: http.StatusCreated,
-				statusText: http.StatusText(http.StatusAccepted),
-				reporter:   NewNoopReporter(),
+				statusCode:    http.StatusCreated,
+				statusText:    http.StatusText(http.StatusAccepted),
+				statusTextSet: true,
+				reporter:      NewNoopReporter(),
+				name:          "ErrorInjector",
 			},
 			wantErr: nil,
 		},
@@ -50,9 +55,26 @@ func TestNewErrorInjector(t *testing.T) {
 				WithStatusText("wow very random"),
 			},
 			want: &ErrorInjector{
-				statusCode: http.StatusTeapot,
-				statusText: "wow very random",
-				reporter:   NewNoopReporter(),
+				statusCode:    http.StatusTeapot,
+				statusText:    "wow very random",
+				statusTextSet: true,
+				reporter:      NewNoopReporter(),
+				name:          "ErrorInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "code with empty text",
+			giveCode: http.StatusServiceUnavailable,
+			giveOptions: []ErrorInjectorOption{
+				WithStatusText(""),
+			},
+			want: &ErrorInjector{
+				statusCode:    http.StatusServiceUnavailable,
+				statusText:    "",
+				statusTextSet: true,
+				reporter:      NewNoopReporter(),
+				name:          "ErrorInjector",
 			},
 			wantErr: nil,
 		},
@@ -66,9 +88,109 @@ func TestNewErrorInjector(t *testing.T) {
 				statusCode: http.StatusOK,
 				statusText: http.StatusText(http.StatusOK),
 				reporter:   newTestReporter(),
+				name:       "ErrorInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "custom name",
+			giveCode: http.StatusOK,
+			giveOptions: []ErrorInjectorOption{
+				WithName("custom"),
+			},
+			want: &ErrorInjector{
+				statusCode: http.StatusOK,
+				statusText: http.StatusText(http.StatusOK),
+				reporter:   NewNoopReporter(),
+				name:       "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "soap fault",
+			giveCode: http.StatusInternalServerError,
+			giveOptions: []ErrorInjectorOption{
+				WithSOAPFault("soap:Server", "internal error"),
+			},
+			want: &ErrorInjector{
+				statusCode: http.StatusInternalServerError,
+				statusText: http.StatusText(http.StatusInternalServerError),
+				body: []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+					`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+					`<soap:Body><soap:Fault><faultcode>soap:Server</faultcode>` +
+					`<faultstring>internal error</faultstring></soap:Fault></soap:Body></soap:Envelope>` + "\n"),
+				contentType: "text/xml; charset=utf-8",
+				reporter:    NewNoopReporter(),
+				name:        "ErrorInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "body bytes",
+			giveCode: http.StatusBadRequest,
+			giveOptions: []ErrorInjectorOption{
+				WithBodyBytes([]byte(`{"error":"bad request"}`)),
+			},
+			want: &ErrorInjector{
+				statusCode:  http.StatusBadRequest,
+				statusText:  http.StatusText(http.StatusBadRequest),
+				body:        []byte(`{"error":"bad request"}`),
+				contentType: "text/plain; charset=utf-8",
+				reporter:    NewNoopReporter(),
+				name:        "ErrorInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "body reader",
+			giveCode: http.StatusBadGateway,
+			giveOptions: []ErrorInjectorOption{
+				WithBody(strings.NewReader("<html>bad gateway</html>")),
+			},
+			want: &ErrorInjector{
+				statusCode:  http.StatusBadGateway,
+				statusText:  http.StatusText(http.StatusBadGateway),
+				body:        []byte("<html>bad gateway</html>"),
+				contentType: "text/html; charset=utf-8",
+				reporter:    NewNoopReporter(),
+				name:        "ErrorInjector",
 			},
 			wantErr: nil,
 		},
+		{
+			name:     "empty body",
+			giveCode: http.StatusServiceUnavailable,
+			giveOptions: []ErrorInjectorOption{
+				WithEmptyBody(),
+			},
+			want: &ErrorInjector{
+				statusCode:  http.StatusServiceUnavailable,
+				statusText:  http.StatusText(http.StatusServiceUnavailable),
+				body:        []byte{},
+				contentType: "text/plain; charset=utf-8",
+				reporter:    NewNoopReporter(),
+				name:        "ErrorInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "nil body reader",
+			giveCode: http.StatusBadGateway,
+			giveOptions: []ErrorInjectorOption{
+				WithBody(nil),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithBody", Value: nil, Err: ErrNilReader},
+		},
+		{
+			name:     "body read error",
+			giveCode: http.StatusBadGateway,
+			giveOptions: []ErrorInjectorOption{
+				WithBody(errReader{err: errTestBodyRead}),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithBody", Value: errReader{err: errTestBodyRead}, Err: errTestBodyRead},
+		},
 		{
 			name:     "invalid code",
 			giveCode: 0,
@@ -76,7 +198,7 @@ func TestNewErrorInjector(t *testing.T) {
 				WithStatusText("invalid code"),
 			},
 			want:    nil,
-			wantErr: ErrInvalidHTTPCode,
+			wantErr: &OptionError{Option: "NewErrorInjector", Value: 0, Err: ErrInvalidHTTPCode},
 		},
 		{
 			name:     "option error",
@@ -106,18 +228,20 @@ func TestErrorInjectorHandler(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name        string
-		giveCode    int
-		giveOptions []ErrorInjectorOption
-		wantCode    int
-		wantBody    string
+		name            string
+		giveCode        int
+		giveOptions     []ErrorInjectorOption
+		wantCode        int
+		wantContentType string
+		wantBody        string
 	}{
 		{
-			name:        "only code",
-			giveCode:    http.StatusInternalServerError,
-			giveOptions: nil,
-			wantCode:    http.StatusInternalServerError,
-			wantBody:    http.StatusText(http.StatusInternalServerError),
+			name:            "only code",
+			giveCode:        http.StatusInternalServerError,
+			giveOptions:     nil,
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        http.StatusText(http.StatusInternalServerError),
 		},
 		{
 			name:     "custom text",
@@ -125,8 +249,43 @@ func TestErrorInjectorHandler(t *testing.T) {
 			giveOptions: []ErrorInjectorOption{
 				WithStatusText("very custom text"),
 			},
-			wantCode: http.StatusInternalServerError,
-			wantBody: "very custom text",
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "very custom text",
+		},
+		{
+			name:     "empty text",
+			giveCode: http.StatusServiceUnavailable,
+			giveOptions: []ErrorInjectorOption{
+				WithStatusText(""),
+			},
+			wantCode:        http.StatusServiceUnavailable,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "",
+		},
+		{
+			name:     "body bytes",
+			giveCode: http.StatusBadRequest,
+			giveOptions: []ErrorInjectorOption{
+				WithBodyBytes([]byte("%PDF-1.7")),
+			},
+			wantCode:        http.StatusBadRequest,
+			wantContentType: "application/pdf",
+			wantBody:        "%PDF-1.7",
+		},
+		{
+			name:     "soap fault",
+			giveCode: http.StatusInternalServerError,
+			giveOptions: []ErrorInjectorOption{
+				WithSOAPFault("soap:Client", "<bad> & \"invalid\"\x00\uFFFE\tinput"),
+			},
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/xml; charset=utf-8",
+			wantBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+				`<soap:Body><soap:Fault><faultcode>soap:Client</faultcode>` +
+				"<faultstring>&lt;bad&gt; &amp; &#34;invalid&#34;\uFFFD\uFFFD\tinput</faultstring>" +
+				`</soap:Fault></soap:Body></soap:Envelope>`,
 		},
 	}
 
@@ -146,7 +305,89 @@ func TestErrorInjectorHandler(t *testing.T) {
 			rr := testRequest(t, f)
 
 			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
 			assert.Equal(t, tt.wantBody, strings.TrimSpace(rr.Body.String()))
 		})
 	}
 }
+
+// TestErrorInjectorHandlerEmptyText tests that ErrorInjector.Handler responds with an empty body
+// for empty status text.
+func TestErrorInjectorHandlerEmptyText(t *testing.T) {
+	t.Parallel()
+
+	ei, err := NewErrorInjector(http.StatusServiceUnavailable, WithStatusText(""))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	ei.Handler(nil).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, 0, rr.Body.Len())
+}
+
+// TestErrorInjectorHandlerExactBody tests that ErrorInjector.Handler responds with exactly the
+// bytes of the body, including an empty one.
+func TestErrorInjectorHandlerExactBody(t *testing.T) {
+	t.Parallel()
+
+	body := []byte("  padded body\n")
+	ei, err := NewErrorInjector(http.StatusNotFound, WithBody(bytes.NewReader(body)))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	ei.Handler(nil).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, body, rr.Body.Bytes())
+
+	ei, err = NewErrorInjector(http.StatusServiceUnavailable, WithEmptyBody())
+	assert.NoError(t, err)
+
+	rr = httptest.NewRecorder()
+	ei.Handler(nil).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, 0, rr.Body.Len())
+}
+
+// TestErrorInjectorHandlerWriteError tests that ErrorInjector.Handler aborts the request if a
+// custom body cannot be written.
+func TestErrorInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 2)}
+	ei, err := NewErrorInjector(http.StatusInternalServerError, WithSOAPFault("soap:Server", "error"),
+		WithReporter(rep))
+	assert.NoError(t, err)
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		ei.Handler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.ElementsMatch(t, []string{"ErrorInjector started", "ErrorInjector aborted"},
+		[]string{<-rep.states, <-rep.states})
+}
+
+// TestErrorInjectorString tests ErrorInjector.String.
+func TestErrorInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ei, err := NewErrorInjector(http.StatusServiceUnavailable)
+	assert.NoError(t, err)
+	assert.Equal(t, "ErrorInjector(503)", ei.String())
+
+	ei, err = NewErrorInjector(http.StatusServiceUnavailable, WithStatusText("try again"))
+	assert.NoError(t, err)
+	assert.Equal(t, `ErrorInjector(503, "try again")`, ei.String())
+
+	ei, err = NewErrorInjector(http.StatusServiceUnavailable, WithStatusText(""))
+	assert.NoError(t, err)
+	assert.Equal(t, `ErrorInjector(503, "")`, ei.String())
+
+	ei, err = NewErrorInjector(http.StatusInternalServerError, WithSOAPFault("soap:Server", "error"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ErrorInjector(500, text/xml; charset=utf-8)", ei.String())
+
+	ei, err = NewErrorInjector(http.StatusServiceUnavailable, WithEmptyBody())
+	assert.NoError(t, err)
+	assert.Equal(t, "ErrorInjector(503, empty body)", ei.String())
+}
@@ -0,0 +1,85 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// FlappingInjector alternates between running an Injector on every request for one period and
+// continuing every request for another, like a dependency whose health flaps.
+type FlappingInjector struct {
+	injector Injector
+	window   window
+	reporter Reporter
+	name     string
+}
+
+// FlappingInjectorOption configures a FlappingInjector.
+type FlappingInjectorOption interface {
+	applyFlappingInjector(i *FlappingInjector) error
+}
+
+func (o nowFuncOption) applyFlappingInjector(i *FlappingInjector) error {
+	i.window.nowF = o
+	return nil
+}
+
+func (o reporterOption) applyFlappingInjector(i *FlappingInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyFlappingInjector(i *FlappingInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewFlappingInjector returns a FlappingInjector that runs i for bad and then continues requests
+// for good, repeatedly. The first bad period starts with the first request the FlappingInjector
+// handles.
+func NewFlappingInjector(
+	i Injector,
+	bad, good time.Duration,
+	opts ...FlappingInjectorOption,
+) (*FlappingInjector, error) {
+	if i == nil {
+		return nil, &OptionError{Option: "NewFlappingInjector", Value: nil, Err: ErrNilInjector}
+	}
+	if bad <= 0 {
+		return nil, &OptionError{Option: "NewFlappingInjector", Value: bad, Err: ErrInvalidDuration}
+	}
+	if good <= 0 {
+		return nil, &OptionError{Option: "NewFlappingInjector", Value: good, Err: ErrInvalidDuration}
+	}
+
+	// set defaults
+	fi := &FlappingInjector{
+		injector: i,
+		window:   window{on: bad, period: bad + good, nowF: time.Now},
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(FlappingInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, FlappingInjectorOption.applyFlappingInjector, fi)
+	if err != nil {
+		return nil, err
+	}
+
+	return fi, nil
+}
+
+// Handler runs the Injector during bad periods and otherwise continues the request. Use the
+// FlappingInjector to test circuit breakers against a dependency that repeatedly fails and
+// recovers.
+func (i *FlappingInjector) Handler(next http.Handler) http.Handler {
+	return i.window.handler(next, i.injector.Handler(next), i.reporter, i.name)
+}
+
+// String describes the FlappingInjector and the Injector it runs, such as
+// "FlappingInjector(30s bad, 1m30s good, ErrorInjector(503))".
+func (i *FlappingInjector) String() string {
+	return i.name + "(" + i.window.on.String() + " bad, " + (i.window.period - i.window.on).String() + " good, " +
+		injectorString(i.injector) + ")"
+}
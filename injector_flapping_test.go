@@ -0,0 +1,150 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewFlappingInjector tests NewFlappingInjector.
+func TestNewFlappingInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveBad      time.Duration
+		giveGood     time.Duration
+		giveOptions  []FlappingInjectorOption
+		want         *FlappingInjector
+		wantErr      error
+	}{
+		{
+			name:         "no options",
+			giveInjector: newTestInjectorNoop(),
+			giveBad:      30 * time.Second,
+			giveGood:     90 * time.Second,
+			giveOptions:  nil,
+			want: &FlappingInjector{
+				injector: newTestInjectorNoop(),
+				window:   window{on: 30 * time.Second, period: 2 * time.Minute},
+				reporter: NewNoopReporter(),
+				name:     "FlappingInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "all options",
+			giveInjector: newTestInjectorNoop(),
+			giveBad:      30 * time.Second,
+			giveGood:     90 * time.Second,
+			giveOptions: []FlappingInjectorOption{
+				WithNowFunc(time.Now),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &FlappingInjector{
+				injector: newTestInjectorNoop(),
+				window:   window{on: 30 * time.Second, period: 2 * time.Minute},
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "nil injector",
+			giveInjector: nil,
+			giveBad:      time.Second,
+			giveGood:     time.Second,
+			want:         nil,
+			wantErr:      &OptionError{Option: "NewFlappingInjector", Value: nil, Err: ErrNilInjector},
+		},
+		{
+			name:         "invalid bad",
+			giveInjector: newTestInjectorNoop(),
+			giveBad:      0,
+			giveGood:     time.Second,
+			want:         nil,
+			wantErr:      &OptionError{Option: "NewFlappingInjector", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:         "invalid good",
+			giveInjector: newTestInjectorNoop(),
+			giveBad:      time.Second,
+			giveGood:     -time.Second,
+			want:         nil,
+			wantErr:      &OptionError{Option: "NewFlappingInjector", Value: -time.Second, Err: ErrInvalidDuration},
+		},
+		{
+			name:         "option error",
+			giveInjector: newTestInjectorNoop(),
+			giveBad:      time.Second,
+			giveGood:     time.Second,
+			giveOptions: []FlappingInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fi, err := NewFlappingInjector(tt.giveInjector, tt.giveBad, tt.giveGood, tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing.
+			if fi != nil {
+				fi.window.nowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, fi)
+		})
+	}
+}
+
+// TestFlappingInjectorHandler tests FlappingInjector.Handler.
+func TestFlappingInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	fi, err := NewFlappingInjector(newTestInjector500s(), time.Minute, 2*time.Minute,
+		WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	f, err := NewFault(fi,
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	var codes []int
+	for range 7 {
+		codes = append(codes, testRequest(t, f).Code)
+		now = now.Add(time.Minute)
+	}
+
+	assert.Equal(t, []int{
+		http.StatusInternalServerError,
+		testHandlerCode,
+		testHandlerCode,
+		http.StatusInternalServerError,
+		testHandlerCode,
+		testHandlerCode,
+		http.StatusInternalServerError,
+	}, codes)
+}
+
+// TestFlappingInjectorString tests FlappingInjector.String.
+func TestFlappingInjectorString(t *testing.T) {
+	t.Parallel()
+
+	fi, err := NewFlappingInjector(newTestInjectorNoop(), 30*time.Second, 90*time.Second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "FlappingInjector(30s bad, 1m30s good, testInjectorNoop)", fi.String())
+}
@@ -0,0 +1,147 @@
+package fault
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+var (
+	// ErrSizeTooLarge when a size is larger than its safety limit.
+	ErrSizeTooLarge = errors.New("size exceeds the safety limit")
+)
+
+const (
+	// GzipBombLimit is the largest decompressed size of a GzipBombInjector, 100 MiB, unless
+	// WithLargeGzipBomb is set.
+	GzipBombLimit = 100 << 20
+	// GzipBombLargeLimit is the largest decompressed size of a GzipBombInjector with
+	// WithLargeGzipBomb, 10 GiB.
+	GzipBombLargeLimit = 10 << 30
+
+	// gzipBombChunkSize is the number of zero bytes compressed with each write.
+	gzipBombChunkSize = 32 << 10
+)
+
+// GzipBombInjector responds with a small gzip encoded body that decompresses to a very large size.
+type GzipBombInjector struct {
+	size        int64
+	large       bool
+	contentType string
+	reporter    Reporter
+	name        string
+}
+
+// GzipBombInjectorOption configures a GzipBombInjector.
+type GzipBombInjectorOption interface {
+	applyGzipBombInjector(i *GzipBombInjector) error
+}
+
+type largeGzipBombOption struct{}
+
+func (o largeGzipBombOption) applyGzipBombInjector(i *GzipBombInjector) error {
+	i.large = true
+	return nil
+}
+
+// WithLargeGzipBomb opts in to decompressed sizes up to GzipBombLargeLimit instead of GzipBombLimit.
+// Only use it against clients that are known to limit decompression, because a client without a
+// limit may exhaust its memory.
+func WithLargeGzipBomb() GzipBombInjectorOption {
+	return largeGzipBombOption{}
+}
+
+func (o payloadContentTypeOption) applyGzipBombInjector(i *GzipBombInjector) error {
+	i.contentType = string(o)
+	return nil
+}
+
+func (o reporterOption) applyGzipBombInjector(i *GzipBombInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyGzipBombInjector(i *GzipBombInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewGzipBombInjector returns a GzipBombInjector whose body decompresses to size bytes. size must be
+// greater than zero and at most GzipBombLimit, or GzipBombLargeLimit with WithLargeGzipBomb.
+func NewGzipBombInjector(size int64, opts ...GzipBombInjectorOption) (*GzipBombInjector, error) {
+	// set defaults
+	gi := &GzipBombInjector{
+		size:        size,
+		contentType: "application/octet-stream",
+		reporter:    NewNoopReporter(),
+		name:        reflect.TypeOf(GzipBombInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, GzipBombInjectorOption.applyGzipBombInjector, gi)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int64(GzipBombLimit)
+	if gi.large {
+		limit = GzipBombLargeLimit
+	}
+
+	switch {
+	case size < 1:
+		return nil, &OptionError{Option: "NewGzipBombInjector", Value: size, Err: ErrInvalidCount}
+	case size > limit:
+		return nil, &OptionError{Option: "NewGzipBombInjector", Value: size, Err: ErrSizeTooLarge}
+	}
+
+	return gi, nil
+}
+
+// Handler responds with http.StatusOK and a gzip encoded body of zero bytes that decompresses to
+// the configured size, at a ratio of about 1000 to 1. Use the GzipBombInjector to check that
+// clients limit how much of a response they decompress. The body is compressed for each request,
+// which takes about 2ms of CPU per MiB of decompressed size.
+func (i *GzipBombInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		h := w.Header()
+		h.Del("Content-Length")
+		h.Set("Content-Type", i.contentType)
+		h.Set("Content-Encoding", "gzip")
+		h.Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+
+		// the client is gone if the body cannot be written
+		err := i.writeBody(w)
+		if err != nil {
+			abort(i.reporter, i.name)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// writeBody writes the compressed body to w.
+func (i *GzipBombInjector) writeBody(w http.ResponseWriter) error {
+	zw := gzip.NewWriter(w)
+	chunk := make([]byte, gzipBombChunkSize)
+
+	for remaining := i.size; remaining > 0; remaining -= int64(len(chunk)) {
+		_, err := zw.Write(chunk[:min(remaining, int64(len(chunk)))])
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// String describes the GzipBombInjector with its decompressed size, such as
+// "GzipBombInjector(104857600 bytes)".
+func (i *GzipBombInjector) String() string {
+	return fmt.Sprintf("%s(%d bytes)", i.name, i.size)
+}
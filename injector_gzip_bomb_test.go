@@ -0,0 +1,161 @@
+package fault
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewGzipBombInjector tests NewGzipBombInjector.
+func TestNewGzipBombInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveSize    int64
+		giveOptions []GzipBombInjectorOption
+		want        *GzipBombInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveSize:    GzipBombLimit,
+			giveOptions: nil,
+			want: &GzipBombInjector{
+				size:        GzipBombLimit,
+				contentType: "application/octet-stream",
+				reporter:    NewNoopReporter(),
+				name:        "GzipBombInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "all options",
+			giveSize: GzipBombLargeLimit,
+			giveOptions: []GzipBombInjectorOption{
+				WithLargeGzipBomb(),
+				WithPayloadContentType("application/json"),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &GzipBombInjector{
+				size:        GzipBombLargeLimit,
+				large:       true,
+				contentType: "application/json",
+				reporter:    newTestReporter(),
+				name:        "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "zero size",
+			giveSize:    0,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewGzipBombInjector", Value: int64(0), Err: ErrInvalidCount},
+		},
+		{
+			name:        "too large",
+			giveSize:    GzipBombLimit + 1,
+			giveOptions: nil,
+			want:        nil,
+			wantErr: &OptionError{
+				Option: "NewGzipBombInjector",
+				Value:  int64(GzipBombLimit + 1),
+				Err:    ErrSizeTooLarge,
+			},
+		},
+		{
+			name:        "too large with opt in",
+			giveSize:    GzipBombLargeLimit + 1,
+			giveOptions: []GzipBombInjectorOption{WithLargeGzipBomb()},
+			want:        nil,
+			wantErr: &OptionError{
+				Option: "NewGzipBombInjector",
+				Value:  int64(GzipBombLargeLimit + 1),
+				Err:    ErrSizeTooLarge,
+			},
+		},
+		{
+			name:     "option error",
+			giveSize: 1,
+			giveOptions: []GzipBombInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gi, err := NewGzipBombInjector(tt.giveSize, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, gi)
+		})
+	}
+}
+
+// TestGzipBombInjectorHandler tests GzipBombInjector.Handler.
+func TestGzipBombInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	size := int64(10<<20 + 1)
+	gi, err := NewGzipBombInjector(size, WithPayloadContentType("application/json"))
+	assert.NoError(t, err)
+
+	f, err := NewFault(gi,
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Empty(t, rr.Header().Get("Content-Length"))
+	assert.Less(t, rr.Body.Len(), int(size/500))
+
+	zr, err := gzip.NewReader(rr.Body)
+	assert.NoError(t, err)
+	n, err := io.Copy(io.Discard, zr)
+	assert.NoError(t, err)
+	assert.Equal(t, size, n)
+}
+
+// TestGzipBombInjectorHandlerWriteError tests that a GzipBombInjector aborts the request when the
+// body cannot be written.
+func TestGzipBombInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 2)}
+	gi, err := NewGzipBombInjector(1, WithReporter(rep))
+	assert.NoError(t, err)
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	h := gi.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.ElementsMatch(t, []string{"GzipBombInjector started", "GzipBombInjector aborted"},
+		[]string{<-rep.states, <-rep.states})
+}
+
+// TestGzipBombInjectorString tests GzipBombInjector.String.
+func TestGzipBombInjectorString(t *testing.T) {
+	t.Parallel()
+
+	gi, err := NewGzipBombInjector(GzipBombLimit)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "GzipBombInjector(104857600 bytes)", gi.String())
+}
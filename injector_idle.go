@@ -0,0 +1,78 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// IdleInjector accepts the request and then does nothing until a timeout.
+type IdleInjector struct {
+	timeout  time.Duration
+	reporter Reporter
+	name     string
+}
+
+// IdleInjectorOption configures an IdleInjector.
+type IdleInjectorOption interface {
+	applyIdleInjector(i *IdleInjector) error
+}
+
+func (o reporterOption) applyIdleInjector(i *IdleInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyIdleInjector(i *IdleInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewIdleInjector returns an IdleInjector that holds each request for timeout. timeout must be
+// greater than zero.
+func NewIdleInjector(timeout time.Duration, opts ...IdleInjectorOption) (*IdleInjector, error) {
+	if timeout <= 0 {
+		return nil, &OptionError{Option: "NewIdleInjector", Value: timeout, Err: ErrInvalidDuration}
+	}
+
+	// set defaults
+	ii := &IdleInjector{
+		timeout:  timeout,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(IdleInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, IdleInjectorOption.applyIdleInjector, ii)
+	if err != nil {
+		return nil, err
+	}
+
+	return ii, nil
+}
+
+// Handler holds the request without reading the body or writing a response until the timeout
+// passes or the client gives up, and then rejects the request like the RejectInjector. This
+// simulates an overloaded upstream that accepted the connection but stopped servicing it, which
+// clients should detect with their own timeouts.
+func (i *IdleInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		timer := time.NewTimer(i.timeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+		}
+
+		abort(i.reporter, i.name)
+	})
+}
+
+// String describes the IdleInjector, such as "IdleInjector(30s)".
+func (i *IdleInjector) String() string {
+	return fmt.Sprintf("%s(%s)", i.name, i.timeout)
+}
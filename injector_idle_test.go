@@ -0,0 +1,145 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewIdleInjector tests NewIdleInjector.
+func TestNewIdleInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveTimeout time.Duration
+		giveOptions []IdleInjectorOption
+		want        *IdleInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveTimeout: time.Minute,
+			giveOptions: nil,
+			want: &IdleInjector{
+				timeout:  time.Minute,
+				reporter: NewNoopReporter(),
+				name:     "IdleInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "all options",
+			giveTimeout: time.Second,
+			giveOptions: []IdleInjectorOption{
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &IdleInjector{
+				timeout:  time.Second,
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "invalid timeout",
+			giveTimeout: 0,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewIdleInjector", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:        "option error",
+			giveTimeout: time.Second,
+			giveOptions: []IdleInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ii, err := NewIdleInjector(tt.giveTimeout, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, ii)
+		})
+	}
+}
+
+// TestIdleInjectorHandler tests IdleInjector.Handler.
+func TestIdleInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name        string
+		giveTimeout time.Duration
+		giveContext context.Context
+		wantMin     time.Duration
+	}{
+		{
+			name:        "timeout",
+			giveTimeout: 10 * time.Millisecond,
+			giveContext: context.Background(),
+			wantMin:     10 * time.Millisecond,
+		},
+		{
+			name:        "client gone",
+			giveTimeout: time.Hour,
+			giveContext: canceled,
+			wantMin:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rep := &testChanReporter{states: make(chan string, 2)}
+			ii, err := NewIdleInjector(tt.giveTimeout, WithReporter(rep))
+			assert.NoError(t, err)
+
+			var called bool
+			h := ii.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequestWithContext(tt.giveContext, http.MethodPost, "/", nil)
+
+			start := time.Now()
+			assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+				h.ServeHTTP(rr, req)
+			})
+
+			assert.GreaterOrEqual(t, time.Since(start), tt.wantMin)
+			assert.Less(t, time.Since(start), time.Minute)
+			assert.False(t, called)
+			assert.False(t, rr.Flushed)
+			assert.Empty(t, rr.Body.String())
+			assert.ElementsMatch(t, []string{"IdleInjector started", "IdleInjector aborted"},
+				[]string{<-rep.states, <-rep.states})
+		})
+	}
+}
+
+// TestIdleInjectorString tests IdleInjector.String.
+func TestIdleInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ii, err := NewIdleInjector(30 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "IdleInjector(30s)", ii.String())
+}
@@ -0,0 +1,111 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// LoadLatencyInjector waits for a duration that grows with the number of requests in flight through
+// it and then continues the request.
+type LoadLatencyInjector struct {
+	curve    func(inFlight int) time.Duration
+	slowF    func(t time.Duration)
+	reporter Reporter
+	name     string
+
+	// inFlight counts the requests currently passing through the LoadLatencyInjector.
+	inFlight atomic.Int64
+}
+
+// LoadLatencyInjectorOption configures a LoadLatencyInjector.
+type LoadLatencyInjectorOption interface {
+	applyLoadLatencyInjector(i *LoadLatencyInjector) error
+}
+
+func (o slowFunctionOption) applyLoadLatencyInjector(i *LoadLatencyInjector) error {
+	i.slowF = o
+	return nil
+}
+
+func (o reporterOption) applyLoadLatencyInjector(i *LoadLatencyInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyLoadLatencyInjector(i *LoadLatencyInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewLoadLatencyInjector returns a LoadLatencyInjector that waits curve(inFlight) before continuing
+// each request, where inFlight is the number of requests in flight through the injector including
+// the current one. See LinearLatency for a simple curve.
+func NewLoadLatencyInjector(
+	curve func(inFlight int) time.Duration,
+	opts ...LoadLatencyInjectorOption,
+) (*LoadLatencyInjector, error) {
+	if curve == nil {
+		return nil, &OptionError{Option: "NewLoadLatencyInjector", Value: nil, Err: ErrNilFunc}
+	}
+
+	// set defaults
+	li := &LoadLatencyInjector{
+		curve:    curve,
+		slowF:    time.Sleep,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(LoadLatencyInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, LoadLatencyInjectorOption.applyLoadLatencyInjector, li)
+	if err != nil {
+		return nil, err
+	}
+
+	return li, nil
+}
+
+// LinearLatency returns a curve for NewLoadLatencyInjector that waits base for a single request in
+// flight and step longer for each additional request, up to limit. A limit of 0 has no limit.
+func LinearLatency(base, step, limit time.Duration) func(inFlight int) time.Duration {
+	return func(inFlight int) time.Duration {
+		d := base + time.Duration(inFlight-1)*step
+		if limit > 0 && d > limit {
+			return limit
+		}
+		return d
+	}
+}
+
+// Handler waits for the duration given by the curve for the number of requests in flight and then
+// continues. A request counts as in flight until next returns, so the LoadLatencyInjector simulates
+// queueing and degradation under load instead of a fixed latency. The wait is reported to a
+// DelayReporter and added to the InjectedDelay of the request.
+func (i *LoadLatencyInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := i.inFlight.Add(1)
+		defer i.inFlight.Add(-1)
+
+		d := i.curve(int(n))
+
+		go report(i.reporter, i.name, StateStarted)
+		i.slowF(d)
+		recordDelay(r, d)
+		reportDelay(i.reporter, i.name, d)
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(w, withInjectedDelay(r, d))
+	})
+}
+
+// InFlight returns the number of requests currently in flight through the LoadLatencyInjector.
+func (i *LoadLatencyInjector) InFlight() int {
+	return int(i.inFlight.Load())
+}
+
+// String describes the LoadLatencyInjector, such as "LoadLatencyInjector".
+func (i *LoadLatencyInjector) String() string {
+	return i.name
+}
@@ -0,0 +1,148 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewLoadLatencyInjector tests NewLoadLatencyInjector.
+func TestNewLoadLatencyInjector(t *testing.T) {
+	t.Parallel()
+
+	curve := LinearLatency(time.Millisecond, time.Millisecond, 0)
+
+	tests := []struct {
+		name        string
+		giveCurve   func(inFlight int) time.Duration
+		giveOptions []LoadLatencyInjectorOption
+		want        *LoadLatencyInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveCurve:   curve,
+			giveOptions: nil,
+			want: &LoadLatencyInjector{
+				reporter: NewNoopReporter(),
+				name:     "LoadLatencyInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:      "all options",
+			giveCurve: curve,
+			giveOptions: []LoadLatencyInjectorOption{
+				WithSlowFunc(func(time.Duration) {}),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &LoadLatencyInjector{
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "nil curve",
+			giveCurve:   nil,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewLoadLatencyInjector", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:      "option error",
+			giveCurve: curve,
+			giveOptions: []LoadLatencyInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			li, err := NewLoadLatencyInjector(tt.giveCurve, tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing.
+			if li != nil {
+				li.curve = nil
+				li.slowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, li)
+		})
+	}
+}
+
+// TestLoadLatencyInjectorHandler tests that LoadLatencyInjector.Handler waits longer while more
+// requests are in flight.
+func TestLoadLatencyInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	var waits, delays []time.Duration
+	reporter := newTestDelayReporter()
+	li, err := NewLoadLatencyInjector(LinearLatency(10*time.Millisecond, 5*time.Millisecond, 0),
+		WithSlowFunc(func(d time.Duration) { waits = append(waits, d) }),
+		WithReporter(reporter),
+	)
+	assert.NoError(t, err)
+
+	// each request starts another request while it is in flight, up to 3 in flight
+	var h http.Handler
+	h = li.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delays = append(delays, InjectedDelay(r))
+		if li.InFlight() < 3 {
+			h.ServeHTTP(w, r)
+		}
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []time.Duration{
+		10 * time.Millisecond, 15 * time.Millisecond, 20 * time.Millisecond,
+		10 * time.Millisecond, 15 * time.Millisecond, 20 * time.Millisecond,
+	}, waits)
+	assert.Equal(t, []time.Duration{
+		10 * time.Millisecond, 25 * time.Millisecond, 45 * time.Millisecond,
+		10 * time.Millisecond, 25 * time.Millisecond, 45 * time.Millisecond,
+	}, delays)
+	assert.Equal(t, 0, li.InFlight())
+
+	reported := make([]time.Duration, 0, len(waits))
+	for range waits {
+		reported = append(reported, <-reporter.delays)
+	}
+	assert.ElementsMatch(t, waits, reported)
+}
+
+// TestLinearLatency tests LinearLatency.
+func TestLinearLatency(t *testing.T) {
+	t.Parallel()
+
+	unlimited := LinearLatency(time.Second, time.Second, 0)
+	assert.Equal(t, time.Second, unlimited(1))
+	assert.Equal(t, 10*time.Second, unlimited(10))
+
+	limited := LinearLatency(time.Second, time.Second, 5*time.Second)
+	assert.Equal(t, 2*time.Second, limited(2))
+	assert.Equal(t, 5*time.Second, limited(10))
+}
+
+// TestLoadLatencyInjectorString tests LoadLatencyInjector.String.
+func TestLoadLatencyInjectorString(t *testing.T) {
+	t.Parallel()
+
+	li, err := NewLoadLatencyInjector(LinearLatency(0, time.Millisecond, 0))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "LoadLatencyInjector", li.String())
+}
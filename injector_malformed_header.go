@@ -0,0 +1,174 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultOversizedHeader is the header a MalformedHeaderInjector makes oversized by default.
+	defaultOversizedHeader = "X-Fault-Oversized"
+	// defaultOversizedSize is the size of the default oversized header, larger than the limits of
+	// common proxies.
+	defaultOversizedSize = 16 << 10
+	// invalidHeaderValue contains control characters and invalid UTF-8, which are not allowed in
+	// header values.
+	invalidHeaderValue = "invalid\x00\x7f\xffvalue"
+)
+
+// MalformedHeaderInjector adds oversized, duplicate, or invalid headers to the response and then
+// continues the request.
+type MalformedHeaderInjector struct {
+	oversized map[string]int
+	duplicate map[string]int
+	invalid   []string
+	header    http.Header
+	reporter  Reporter
+	name      string
+}
+
+// MalformedHeaderInjectorOption configures a MalformedHeaderInjector.
+type MalformedHeaderInjectorOption interface {
+	applyMalformedHeaderInjector(i *MalformedHeaderInjector) error
+}
+
+type oversizedHeaderOption struct {
+	key  string
+	size int
+}
+
+func (o oversizedHeaderOption) applyMalformedHeaderInjector(i *MalformedHeaderInjector) error {
+	if o.size < 1 {
+		return &OptionError{Option: "WithOversizedHeader", Value: o.size, Err: ErrInvalidCount}
+	}
+	if i.oversized == nil {
+		i.oversized = make(map[string]int)
+	}
+	i.oversized[o.key] = o.size
+	return nil
+}
+
+// WithOversizedHeader adds a header named key with a value of size bytes. Pass it more than once to
+// add more headers.
+func WithOversizedHeader(key string, size int) MalformedHeaderInjectorOption {
+	return oversizedHeaderOption{key: key, size: size}
+}
+
+type duplicateHeaderOption struct {
+	key   string
+	count int
+}
+
+func (o duplicateHeaderOption) applyMalformedHeaderInjector(i *MalformedHeaderInjector) error {
+	if o.count < 1 {
+		return &OptionError{Option: "WithDuplicateHeader", Value: o.count, Err: ErrInvalidCount}
+	}
+	if i.duplicate == nil {
+		i.duplicate = make(map[string]int)
+	}
+	i.duplicate[o.key] = o.count
+	return nil
+}
+
+// WithDuplicateHeader adds a header named key count times, with the values "duplicate-1",
+// "duplicate-2", and so on. Pass it more than once to duplicate more headers.
+func WithDuplicateHeader(key string, count int) MalformedHeaderInjectorOption {
+	return duplicateHeaderOption{key: key, count: count}
+}
+
+type invalidHeaderOption string
+
+func (o invalidHeaderOption) applyMalformedHeaderInjector(i *MalformedHeaderInjector) error {
+	i.invalid = append(i.invalid, string(o))
+	return nil
+}
+
+// WithInvalidHeader adds a header named key with a value that contains control characters and
+// invalid UTF-8. Pass it more than once to add more headers.
+func WithInvalidHeader(key string) MalformedHeaderInjectorOption {
+	return invalidHeaderOption(key)
+}
+
+func (o reporterOption) applyMalformedHeaderInjector(i *MalformedHeaderInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyMalformedHeaderInjector(i *MalformedHeaderInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewMalformedHeaderInjector returns a MalformedHeaderInjector. Without any of WithOversizedHeader,
+// WithDuplicateHeader, or WithInvalidHeader it adds a 16 KiB X-Fault-Oversized header.
+func NewMalformedHeaderInjector(opts ...MalformedHeaderInjectorOption) (*MalformedHeaderInjector, error) {
+	// set defaults
+	mi := &MalformedHeaderInjector{
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(MalformedHeaderInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, MalformedHeaderInjectorOption.applyMalformedHeaderInjector, mi)
+	if err != nil {
+		return nil, err
+	}
+
+	if mi.oversized == nil && mi.duplicate == nil && mi.invalid == nil {
+		mi.oversized = map[string]int{defaultOversizedHeader: defaultOversizedSize}
+	}
+
+	// build the headers once so that requests do not allocate large values
+	mi.header = mi.buildHeader()
+
+	return mi, nil
+}
+
+// buildHeader returns the headers that the MalformedHeaderInjector adds.
+func (i *MalformedHeaderInjector) buildHeader() http.Header {
+	h := make(http.Header)
+	for key, size := range i.oversized {
+		h[key] = append(h[key], strings.Repeat("a", size))
+	}
+	for key, count := range i.duplicate {
+		for n := 1; n <= count; n++ {
+			h[key] = append(h[key], "duplicate-"+strconv.Itoa(n))
+		}
+	}
+	for _, key := range i.invalid {
+		h[key] = append(h[key], invalidHeaderValue)
+	}
+	return h
+}
+
+// Handler adds the configured headers to the response before continuing the request. Use the
+// MalformedHeaderInjector to test proxies and clients that enforce header limits. Keys are used as
+// given, without canonicalization. The http.Server replaces newlines in header values with spaces
+// and drops headers with invalid names, so neither can be injected.
+func (i *MalformedHeaderInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		h := w.Header()
+		for key, vals := range i.header {
+			h[key] = append(h[key], vals...)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// String describes the MalformedHeaderInjector, such as
+// "MalformedHeaderInjector(oversized=X-Fault-Oversized:16384, duplicate=X-Dup:3, invalid=X-Bad)".
+func (i *MalformedHeaderInjector) String() string {
+	var details []string
+	details = appendDetail(details, "oversized", countStrings(i.oversized))
+	details = appendDetail(details, "duplicate", countStrings(i.duplicate))
+	details = appendDetail(details, "invalid", i.invalid)
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
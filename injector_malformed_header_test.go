@@ -0,0 +1,183 @@
+package fault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewMalformedHeaderInjector tests NewMalformedHeaderInjector.
+func TestNewMalformedHeaderInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []MalformedHeaderInjectorOption
+		want        *MalformedHeaderInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &MalformedHeaderInjector{
+				oversized: map[string]int{"X-Fault-Oversized": 16384},
+				header:    http.Header{"X-Fault-Oversized": {strings.Repeat("a", 16384)}},
+				reporter:  NewNoopReporter(),
+				name:      "MalformedHeaderInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []MalformedHeaderInjectorOption{
+				WithOversizedHeader("X-Big", 4),
+				WithDuplicateHeader("X-Dup", 2),
+				WithInvalidHeader("X-Bad"),
+				WithInvalidHeader("X-Bad"),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &MalformedHeaderInjector{
+				oversized: map[string]int{"X-Big": 4},
+				duplicate: map[string]int{"X-Dup": 2},
+				invalid:   []string{"X-Bad", "X-Bad"},
+				header: http.Header{
+					"X-Big": {"aaaa"},
+					"X-Dup": {"duplicate-1", "duplicate-2"},
+					"X-Bad": {"invalid\x00\x7f\xffvalue", "invalid\x00\x7f\xffvalue"},
+				},
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid size",
+			giveOptions: []MalformedHeaderInjectorOption{
+				WithOversizedHeader("X-Big", 0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithOversizedHeader", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name: "invalid count",
+			giveOptions: []MalformedHeaderInjectorOption{
+				WithDuplicateHeader("X-Dup", 0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithDuplicateHeader", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name: "option error",
+			giveOptions: []MalformedHeaderInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mi, err := NewMalformedHeaderInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, mi)
+		})
+	}
+}
+
+// TestMalformedHeaderInjectorHandler tests MalformedHeaderInjector.Handler.
+func TestMalformedHeaderInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	mi, err := NewMalformedHeaderInjector(
+		WithOversizedHeader("X-Big", 4),
+		WithDuplicateHeader("X-Dup", 3),
+		WithInvalidHeader("X-Bad"),
+	)
+	assert.NoError(t, err)
+
+	f, err := NewFault(mi,
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+	assert.Equal(t, testHandlerCode, rr.Code)
+	assert.Equal(t, []string{"aaaa"}, rr.Header()["X-Big"])
+	assert.Equal(t, []string{"duplicate-1", "duplicate-2", "duplicate-3"}, rr.Header()["X-Dup"])
+	assert.Equal(t, []string{"invalid\x00\x7f\xffvalue"}, rr.Header()["X-Bad"])
+
+	// the injector's headers are not changed by requests
+	rr = testRequest(t, f)
+	assert.Equal(t, []string{"aaaa"}, rr.Header()["X-Big"])
+}
+
+// TestMalformedHeaderInjectorClient tests that clients reject responses from a
+// MalformedHeaderInjector.
+func TestMalformedHeaderInjectorClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []MalformedHeaderInjectorOption
+		wantErr     string
+	}{
+		{
+			name:        "oversized",
+			giveOptions: nil,
+			wantErr:     "server response headers exceeded",
+		},
+		{
+			name:        "invalid",
+			giveOptions: []MalformedHeaderInjectorOption{WithInvalidHeader("X-Bad")},
+			wantErr:     "malformed MIME header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mi, err := NewMalformedHeaderInjector(tt.giveOptions...)
+			assert.NoError(t, err)
+
+			srv := httptest.NewServer(mi.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+			defer srv.Close()
+
+			client := srv.Client()
+			client.Transport.(*http.Transport).MaxResponseHeaderBytes = 4 << 10
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+			assert.NoError(t, err)
+
+			resp, err := client.Do(req)
+			if err == nil {
+				assert.NoError(t, resp.Body.Close())
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestMalformedHeaderInjectorString tests MalformedHeaderInjector.String.
+func TestMalformedHeaderInjectorString(t *testing.T) {
+	t.Parallel()
+
+	mi, err := NewMalformedHeaderInjector(
+		WithOversizedHeader("X-Big", 4),
+		WithDuplicateHeader("X-Dup", 3),
+		WithInvalidHeader("X-Bad"),
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "MalformedHeaderInjector(oversized=X-Big:4, duplicate=X-Dup:3, invalid=X-Bad)", mi.String())
+}
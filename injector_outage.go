@@ -0,0 +1,154 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// OutageInjector simulates a full outage. It runs an Injector on every request for a duration, by
+// default responding 503, and then recovers, optionally repeating the outage.
+type OutageInjector struct {
+	injector Injector
+	window   window
+	reporter Reporter
+	name     string
+}
+
+// OutageInjectorOption configures an OutageInjector.
+type OutageInjectorOption interface {
+	applyOutageInjector(i *OutageInjector) error
+}
+
+type outageInjectorOption struct {
+	injector Injector
+}
+
+func (o outageInjectorOption) applyOutageInjector(i *OutageInjector) error {
+	if o.injector == nil {
+		return &OptionError{Option: "WithOutageInjector", Value: nil, Err: ErrNilInjector}
+	}
+	i.injector = o.injector
+	return nil
+}
+
+// WithOutageInjector sets the Injector that runs during the outage. Default an ErrorInjector that
+// responds 503.
+func WithOutageInjector(inj Injector) OutageInjectorOption {
+	return outageInjectorOption{injector: inj}
+}
+
+type outageRepeatOption time.Duration
+
+func (o outageRepeatOption) applyOutageInjector(i *OutageInjector) error {
+	if time.Duration(o) <= i.window.on {
+		return &OptionError{Option: "WithOutageRepeat", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	i.window.period = time.Duration(o)
+	return nil
+}
+
+// WithOutageRepeat starts a new outage every period, which must be longer than the outage. Default
+// the outage happens once.
+func WithOutageRepeat(period time.Duration) OutageInjectorOption {
+	return outageRepeatOption(period)
+}
+
+func (o nowFuncOption) applyOutageInjector(i *OutageInjector) error {
+	i.window.nowF = o
+	return nil
+}
+
+func (o reporterOption) applyOutageInjector(i *OutageInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyOutageInjector(i *OutageInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewOutageInjector returns an OutageInjector with an outage that lasts d. The outage starts with the
+// first request the OutageInjector handles, so that it lines up with enabling the Fault.
+func NewOutageInjector(d time.Duration, opts ...OutageInjectorOption) (*OutageInjector, error) {
+	if d <= 0 {
+		return nil, &OptionError{Option: "NewOutageInjector", Value: d, Err: ErrInvalidDuration}
+	}
+
+	// a valid status code and no options cannot fail
+	ei, _ := NewErrorInjector(http.StatusServiceUnavailable)
+
+	// set defaults
+	oi := &OutageInjector{
+		injector: ei,
+		window:   window{on: d, nowF: time.Now},
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(OutageInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, OutageInjectorOption.applyOutageInjector, oi)
+	if err != nil {
+		return nil, err
+	}
+
+	return oi, nil
+}
+
+// Handler runs the Injector during the outage and otherwise continues the request. Use the
+// OutageInjector to test alerting, retries, and failover against a realistic outage instead of
+// uniform random errors.
+func (i *OutageInjector) Handler(next http.Handler) http.Handler {
+	return i.window.handler(next, i.injector.Handler(next), i.reporter, i.name)
+}
+
+// String describes the OutageInjector and the Injector it runs, such as
+// "OutageInjector(5m0s every 1h0m0s, ErrorInjector(503))".
+func (i *OutageInjector) String() string {
+	s := i.name + "(" + i.window.on.String()
+	if i.window.period > 0 {
+		s += " every " + i.window.period.String()
+	}
+	return s + ", " + injectorString(i.injector) + ")"
+}
+
+// window is a span of time that starts with the first call to active, lasts on, and repeats every
+// period if period is set.
+type window struct {
+	on     time.Duration
+	period time.Duration
+	nowF   func() time.Time
+
+	start     time.Time
+	startOnce sync.Once
+}
+
+// active returns true if the current time is within the window.
+func (w *window) active() bool {
+	now := w.nowF()
+	w.startOnce.Do(func() { w.start = now })
+
+	since := now.Sub(w.start)
+	if w.period > 0 {
+		since %= w.period
+	}
+
+	return since < w.on
+}
+
+// handler returns a handler that runs injected while the window is active, reporting to reporter
+// under name, and otherwise next.
+func (w *window) handler(next, injected http.Handler, reporter Reporter, name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !w.active() {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		go report(reporter, name, StateStarted)
+		injected.ServeHTTP(rw, r)
+		go report(reporter, name, StateFinished)
+	})
+}
@@ -0,0 +1,183 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewOutageInjector tests NewOutageInjector.
+func TestNewOutageInjector(t *testing.T) {
+	t.Parallel()
+
+	ei, err := NewErrorInjector(http.StatusServiceUnavailable)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		giveD       time.Duration
+		giveOptions []OutageInjectorOption
+		want        *OutageInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveD:       time.Minute,
+			giveOptions: nil,
+			want: &OutageInjector{
+				injector: ei,
+				window:   window{on: time.Minute},
+				reporter: NewNoopReporter(),
+				name:     "OutageInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "all options",
+			giveD: time.Minute,
+			giveOptions: []OutageInjectorOption{
+				WithOutageInjector(newTestInjectorNoop()),
+				WithOutageRepeat(time.Hour),
+				WithNowFunc(time.Now),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &OutageInjector{
+				injector: newTestInjectorNoop(),
+				window:   window{on: time.Minute, period: time.Hour},
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "invalid duration",
+			giveD:       0,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewOutageInjector", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:  "nil injector",
+			giveD: time.Minute,
+			giveOptions: []OutageInjectorOption{
+				WithOutageInjector(nil),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithOutageInjector", Value: nil, Err: ErrNilInjector},
+		},
+		{
+			name:  "repeat not longer than outage",
+			giveD: time.Minute,
+			giveOptions: []OutageInjectorOption{
+				WithOutageRepeat(time.Minute),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithOutageRepeat", Value: time.Minute, Err: ErrInvalidDuration},
+		},
+		{
+			name:  "option error",
+			giveD: time.Minute,
+			giveOptions: []OutageInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			oi, err := NewOutageInjector(tt.giveD, tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing.
+			if oi != nil {
+				oi.window.nowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, oi)
+		})
+	}
+}
+
+// TestOutageInjectorHandler tests OutageInjector.Handler.
+func TestOutageInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []OutageInjectorOption
+		wantCodes   []int
+	}{
+		{
+			name:        "once",
+			giveOptions: nil,
+			wantCodes: []int{
+				http.StatusServiceUnavailable,
+				http.StatusServiceUnavailable,
+				testHandlerCode,
+				testHandlerCode,
+				testHandlerCode,
+			},
+		},
+		{
+			name: "repeat",
+			giveOptions: []OutageInjectorOption{
+				WithOutageRepeat(3 * time.Minute),
+			},
+			wantCodes: []int{
+				http.StatusServiceUnavailable,
+				http.StatusServiceUnavailable,
+				testHandlerCode,
+				http.StatusServiceUnavailable,
+				http.StatusServiceUnavailable,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Unix(0, 0)
+			oi, err := NewOutageInjector(2*time.Minute, append(tt.giveOptions,
+				WithNowFunc(func() time.Time { return now }))...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(oi,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			// the outage starts with the first request, not when the injector is created
+			now = now.Add(time.Hour)
+
+			var codes []int
+			for range tt.wantCodes {
+				codes = append(codes, testRequest(t, f).Code)
+				now = now.Add(time.Minute)
+			}
+
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+// TestOutageInjectorString tests OutageInjector.String.
+func TestOutageInjectorString(t *testing.T) {
+	t.Parallel()
+
+	oi, err := NewOutageInjector(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "OutageInjector(1m0s, ErrorInjector(503))", oi.String())
+
+	oi, err = NewOutageInjector(time.Minute, WithOutageRepeat(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "OutageInjector(1m0s every 1h0m0s, ErrorInjector(503))", oi.String())
+}
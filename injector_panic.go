@@ -0,0 +1,84 @@
+package fault
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+var (
+	// ErrInjectedPanic is the default value a PanicInjector panics with.
+	ErrInjectedPanic = errors.New("injected panic")
+)
+
+// PanicInjector panics while handling the request.
+type PanicInjector struct {
+	value    any
+	reporter Reporter
+	name     string
+}
+
+// PanicInjectorOption configures a PanicInjector.
+type PanicInjectorOption interface {
+	applyPanicInjector(i *PanicInjector) error
+}
+
+type panicValueOption struct {
+	value any
+}
+
+func (o panicValueOption) applyPanicInjector(i *PanicInjector) error {
+	i.value = o.value
+	return nil
+}
+
+// WithPanicValue sets the value the PanicInjector panics with. Default ErrInjectedPanic.
+func WithPanicValue(v any) PanicInjectorOption {
+	return panicValueOption{value: v}
+}
+
+func (o reporterOption) applyPanicInjector(i *PanicInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyPanicInjector(i *PanicInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewPanicInjector returns a PanicInjector.
+func NewPanicInjector(opts ...PanicInjectorOption) (*PanicInjector, error) {
+	// set defaults
+	pi := &PanicInjector{
+		value:    ErrInjectedPanic,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(PanicInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, PanicInjectorOption.applyPanicInjector, pi)
+	if err != nil {
+		return nil, err
+	}
+
+	return pi, nil
+}
+
+// Handler panics with the configured value. Use the PanicInjector to exercise recovery middleware,
+// panic logging, and alerting. Unlike the RejectInjector the panic is not http.ErrAbortHandler, so
+// the http.Server logs the panic and its stack trace if nothing else recovers it.
+func (i *PanicInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+		go report(i.reporter, i.name, StateAborted)
+
+		panic(i.value)
+	})
+}
+
+// String describes the PanicInjector, such as "PanicInjector(injected panic)".
+func (i *PanicInjector) String() string {
+	return fmt.Sprintf("%s(%v)", i.name, i.value)
+}
@@ -0,0 +1,119 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewPanicInjector tests NewPanicInjector.
+func TestNewPanicInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []PanicInjectorOption
+		want        *PanicInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: []PanicInjectorOption{},
+			want: &PanicInjector{
+				value:    ErrInjectedPanic,
+				reporter: NewNoopReporter(),
+				name:     "PanicInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []PanicInjectorOption{
+				WithPanicValue("custom value"),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &PanicInjector{
+				value:    "custom value",
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "option error",
+			giveOptions: []PanicInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pi, err := NewPanicInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, pi)
+		})
+	}
+}
+
+// TestPanicInjectorHandler tests PanicInjector.Handler.
+func TestPanicInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []PanicInjectorOption
+		wantPanic   any
+	}{
+		{
+			name:        "default",
+			giveOptions: nil,
+			wantPanic:   ErrInjectedPanic,
+		},
+		{
+			name: "custom value",
+			giveOptions: []PanicInjectorOption{
+				WithPanicValue("custom value"),
+			},
+			wantPanic: "custom value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rep := &testChanReporter{states: make(chan string, 2)}
+			pi, err := NewPanicInjector(append(tt.giveOptions, WithReporter(rep))...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(pi,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			assert.PanicsWithValue(t, tt.wantPanic, func() {
+				testRequest(t, f)
+			})
+			assert.ElementsMatch(t, []string{"PanicInjector started", "PanicInjector aborted"},
+				[]string{<-rep.states, <-rep.states})
+		})
+	}
+}
+
+// TestPanicInjectorString tests PanicInjector.String.
+func TestPanicInjectorString(t *testing.T) {
+	t.Parallel()
+
+	pi, err := NewPanicInjector(WithPanicValue("injected panic"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "PanicInjector(injected panic)", pi.String())
+}
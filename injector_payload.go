@@ -0,0 +1,97 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// PayloadInjector responds with an http status code and a raw byte payload.
+type PayloadInjector struct {
+	statusCode  int
+	payload     []byte
+	contentType string
+	reporter    Reporter
+	name        string
+}
+
+// PayloadInjectorOption configures a PayloadInjector.
+type PayloadInjectorOption interface {
+	applyPayloadInjector(i *PayloadInjector) error
+}
+
+type payloadContentTypeOption string
+
+func (o payloadContentTypeOption) applyPayloadInjector(i *PayloadInjector) error {
+	i.contentType = string(o)
+	return nil
+}
+
+// PayloadContentTypeOption configures things that can set the content type of their payload.
+type PayloadContentTypeOption interface {
+	PayloadInjectorOption
+	GzipBombInjectorOption
+}
+
+// WithPayloadContentType sets the content type of the payload, such as "application/x-protobuf".
+// Default "application/octet-stream".
+func WithPayloadContentType(t string) PayloadContentTypeOption {
+	return payloadContentTypeOption(t)
+}
+
+func (o reporterOption) applyPayloadInjector(i *PayloadInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyPayloadInjector(i *PayloadInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewPayloadInjector returns a PayloadInjector that responds with a status code and payload. The
+// payload is copied.
+func NewPayloadInjector(code int, payload []byte, opts ...PayloadInjectorOption) (*PayloadInjector, error) {
+	if http.StatusText(code) == "" {
+		return nil, &OptionError{Option: "NewPayloadInjector", Value: code, Err: ErrInvalidHTTPCode}
+	}
+
+	// set defaults
+	pi := &PayloadInjector{
+		statusCode:  code,
+		payload:     append([]byte{}, payload...),
+		contentType: "application/octet-stream",
+		reporter:    NewNoopReporter(),
+		name:        reflect.TypeOf(PayloadInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, PayloadInjectorOption.applyPayloadInjector, pi)
+	if err != nil {
+		return nil, err
+	}
+
+	return pi, nil
+}
+
+// Handler responds with the configured status code and payload. Use the PayloadInjector to simulate
+// binary API failures, such as a serialized protobuf error or a deliberately garbled protobuf.
+func (i *PayloadInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		// the client is gone if the payload cannot be written
+		err := writeBody(w, i.statusCode, i.contentType, i.payload)
+		if err != nil {
+			abort(i.reporter, i.name)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// String describes the PayloadInjector with the size of its payload, such as
+// "PayloadInjector(500, application/x-protobuf, 12 bytes)".
+func (i *PayloadInjector) String() string {
+	return fmt.Sprintf("%s(%d, %s, %d bytes)", i.name, i.statusCode, i.contentType, len(i.payload))
+}
@@ -0,0 +1,139 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewPayloadInjector tests NewPayloadInjector.
+func TestNewPayloadInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveCode    int
+		givePayload []byte
+		giveOptions []PayloadInjectorOption
+		want        *PayloadInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveCode:    http.StatusInternalServerError,
+			givePayload: []byte{0x08, 0x0d},
+			giveOptions: nil,
+			want: &PayloadInjector{
+				statusCode:  http.StatusInternalServerError,
+				payload:     []byte{0x08, 0x0d},
+				contentType: "application/octet-stream",
+				reporter:    NewNoopReporter(),
+				name:        "PayloadInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "all options",
+			giveCode:    http.StatusBadRequest,
+			givePayload: nil,
+			giveOptions: []PayloadInjectorOption{
+				WithPayloadContentType("application/x-protobuf"),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &PayloadInjector{
+				statusCode:  http.StatusBadRequest,
+				payload:     []byte{},
+				contentType: "application/x-protobuf",
+				reporter:    newTestReporter(),
+				name:        "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "invalid code",
+			giveCode:    0,
+			givePayload: nil,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewPayloadInjector", Value: 0, Err: ErrInvalidHTTPCode},
+		},
+		{
+			name:        "option error",
+			giveCode:    http.StatusInternalServerError,
+			givePayload: nil,
+			giveOptions: []PayloadInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pi, err := NewPayloadInjector(tt.giveCode, tt.givePayload, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, pi)
+		})
+	}
+}
+
+// TestPayloadInjectorHandler tests PayloadInjector.Handler.
+func TestPayloadInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte{0x08, 0x0d, 0x12, 0xff}
+	pi, err := NewPayloadInjector(http.StatusServiceUnavailable, payload,
+		WithPayloadContentType("application/x-protobuf"),
+	)
+	assert.NoError(t, err)
+
+	// the payload is copied
+	payload[0] = 0x00
+
+	f, err := NewFault(pi,
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "application/x-protobuf", rr.Header().Get("Content-Type"))
+	assert.Equal(t, []byte{0x08, 0x0d, 0x12, 0xff}, rr.Body.Bytes())
+}
+
+// TestPayloadInjectorHandlerWriteError tests that a PayloadInjector aborts the request when the
+// payload cannot be written.
+func TestPayloadInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 2)}
+	pi, err := NewPayloadInjector(http.StatusInternalServerError, []byte{0x08}, WithReporter(rep))
+	assert.NoError(t, err)
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		pi.Handler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.ElementsMatch(t, []string{"PayloadInjector started", "PayloadInjector aborted"},
+		[]string{<-rep.states, <-rep.states})
+}
+
+// TestPayloadInjectorString tests PayloadInjector.String.
+func TestPayloadInjectorString(t *testing.T) {
+	t.Parallel()
+
+	pi, err := NewPayloadInjector(http.StatusInternalServerError, make([]byte, 12),
+		WithPayloadContentType("application/x-protobuf"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "PayloadInjector(500, application/x-protobuf, 12 bytes)", pi.String())
+}
@@ -0,0 +1,161 @@
+package fault
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PushInjector continues the request and makes HTTP/2 server push fail or wait, to exercise code
+// that should treat push as best effort.
+type PushInjector struct {
+	err      error
+	delay    time.Duration
+	slowF    func(t time.Duration)
+	reporter Reporter
+	name     string
+}
+
+// PushInjectorOption configures a PushInjector.
+type PushInjectorOption interface {
+	applyPushInjector(i *PushInjector) error
+}
+
+type pushErrorOption struct {
+	err error
+}
+
+func (o pushErrorOption) applyPushInjector(i *PushInjector) error {
+	if o.err == nil {
+		return &OptionError{Option: "WithPushError", Value: nil, Err: ErrNilError}
+	}
+	i.err = o.err
+	return nil
+}
+
+// WithPushError makes every push fail with err instead of http.ErrNotSupported.
+func WithPushError(err error) PushInjectorOption {
+	return pushErrorOption{err: err}
+}
+
+type pushDelayOption time.Duration
+
+func (o pushDelayOption) applyPushInjector(i *PushInjector) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithPushDelay", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	i.delay = time.Duration(o)
+	return nil
+}
+
+// WithPushDelay waits d before every push. Without WithPushError the push then continues, so that
+// it is only slow.
+func WithPushDelay(d time.Duration) PushInjectorOption {
+	return pushDelayOption(d)
+}
+
+func (o slowFunctionOption) applyPushInjector(i *PushInjector) error {
+	i.slowF = o
+	return nil
+}
+
+func (o reporterOption) applyPushInjector(i *PushInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyPushInjector(i *PushInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewPushInjector returns a PushInjector. Without WithPushError or WithPushDelay every push fails
+// with http.ErrNotSupported, as it does when the client disables push.
+func NewPushInjector(opts ...PushInjectorOption) (*PushInjector, error) {
+	// set defaults
+	pi := &PushInjector{
+		slowF:    time.Sleep,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(PushInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, PushInjectorOption.applyPushInjector, pi)
+	if err != nil {
+		return nil, err
+	}
+
+	if pi.err == nil && pi.delay == 0 {
+		pi.err = http.ErrNotSupported
+	}
+
+	return pi, nil
+}
+
+// Handler continues the request with an http.ResponseWriter whose Push waits and fails as
+// configured. Push returns http.ErrNotSupported if it is not failed and the wrapped ResponseWriter
+// does not implement http.Pusher.
+func (i *PushInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(&pushWriter{ResponseWriter: w, injector: i}, r)
+	})
+}
+
+// String describes the PushInjector, such as "PushInjector(error=feature not supported, delay=1s)".
+func (i *PushInjector) String() string {
+	var details []string
+	if i.err != nil {
+		details = append(details, "error="+i.err.Error())
+	}
+	if i.delay > 0 {
+		details = append(details, "delay="+i.delay.String())
+	}
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
+
+// pushWriter is an http.ResponseWriter whose Push is changed by a PushInjector.
+type pushWriter struct {
+	http.ResponseWriter
+	injector *PushInjector
+}
+
+// Push waits and fails as configured by the PushInjector, or pushes target with the wrapped
+// ResponseWriter.
+func (w *pushWriter) Push(target string, opts *http.PushOptions) error {
+	if w.injector.delay > 0 {
+		w.injector.slowF(w.injector.delay)
+	}
+	if w.injector.err != nil {
+		return w.injector.err
+	}
+
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Flush flushes the wrapped ResponseWriter if it implements http.Flusher.
+func (w *pushWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the wrapped ResponseWriter, or returns http.ErrNotSupported if
+// it cannot be hijacked.
+func (w *pushWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the wrapped ResponseWriter for use with http.ResponseController.
+func (w *pushWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
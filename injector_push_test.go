@@ -0,0 +1,185 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewPushInjector tests NewPushInjector.
+func TestNewPushInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []PushInjectorOption
+		want        *PushInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &PushInjector{
+				err:      http.ErrNotSupported,
+				reporter: NewNoopReporter(),
+				name:     "PushInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "delay only",
+			giveOptions: []PushInjectorOption{WithPushDelay(time.Second)},
+			want: &PushInjector{
+				delay:    time.Second,
+				reporter: NewNoopReporter(),
+				name:     "PushInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []PushInjectorOption{
+				WithPushError(errTestPush),
+				WithPushDelay(time.Second),
+				WithSlowFunc(func(time.Duration) {}),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &PushInjector{
+				err:      errTestPush,
+				delay:    time.Second,
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "nil error",
+			giveOptions: []PushInjectorOption{WithPushError(nil)},
+			want:        nil,
+			wantErr:     &OptionError{Option: "WithPushError", Value: nil, Err: ErrNilError},
+		},
+		{
+			name:        "invalid delay",
+			giveOptions: []PushInjectorOption{WithPushDelay(0)},
+			want:        nil,
+			wantErr:     &OptionError{Option: "WithPushDelay", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:        "option error",
+			giveOptions: []PushInjectorOption{withError()},
+			want:        nil,
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pi, err := NewPushInjector(tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing
+			if tt.want != nil {
+				pi.slowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, pi)
+		})
+	}
+}
+
+// TestPushInjectorHandler tests PushInjector.Handler.
+func TestPushInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []PushInjectorOption
+		giveWriter  http.ResponseWriter
+		wantErr     error
+		wantPushed  string
+		wantSlept   time.Duration
+	}{
+		{
+			name:        "default",
+			giveOptions: nil,
+			giveWriter:  &testFullWriter{ResponseRecorder: httptest.NewRecorder()},
+			wantErr:     http.ErrNotSupported,
+			wantPushed:  "",
+		},
+		{
+			name:        "error and delay",
+			giveOptions: []PushInjectorOption{WithPushError(errTestWrite), WithPushDelay(time.Second)},
+			giveWriter:  &testFullWriter{ResponseRecorder: httptest.NewRecorder()},
+			wantErr:     errTestWrite,
+			wantPushed:  "",
+			wantSlept:   time.Second,
+		},
+		{
+			name:        "delay",
+			giveOptions: []PushInjectorOption{WithPushDelay(time.Second)},
+			giveWriter:  &testFullWriter{ResponseRecorder: httptest.NewRecorder()},
+			wantErr:     errTestPush,
+			wantPushed:  "/style.css",
+			wantSlept:   time.Second,
+		},
+		{
+			name:        "delay without pusher",
+			giveOptions: []PushInjectorOption{WithPushDelay(time.Second)},
+			giveWriter:  httptest.NewRecorder(),
+			wantErr:     http.ErrNotSupported,
+			wantPushed:  "",
+			wantSlept:   time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var slept time.Duration
+			opts := append([]PushInjectorOption{WithSlowFunc(func(d time.Duration) { slept += d })}, tt.giveOptions...)
+
+			pi, err := NewPushInjector(opts...)
+			assert.NoError(t, err)
+
+			h := pi.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantErr, w.(http.Pusher).Push("/style.css", nil))
+				assert.Equal(t, tt.giveWriter, w.(interface{ Unwrap() http.ResponseWriter }).Unwrap())
+				w.(http.Flusher).Flush()
+			}))
+			h.ServeHTTP(tt.giveWriter, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if fw, ok := tt.giveWriter.(*testFullWriter); ok {
+				assert.Equal(t, tt.wantPushed, fw.pushed)
+				assert.True(t, fw.Flushed)
+			}
+			assert.Equal(t, tt.wantSlept, slept)
+		})
+	}
+
+	// flushing a writer that cannot flush does nothing
+	pi, err := NewPushInjector()
+	assert.NoError(t, err)
+	pi.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+	})).ServeHTTP(&testMinimalWriter{header: make(http.Header)}, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// TestPushInjectorString tests PushInjector.String.
+func TestPushInjectorString(t *testing.T) {
+	t.Parallel()
+
+	pi, err := NewPushInjector()
+	assert.NoError(t, err)
+	assert.Equal(t, "PushInjector(error=feature not supported)", pi.String())
+
+	pi, err = NewPushInjector(WithPushDelay(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, "PushInjector(delay=1s)", pi.String())
+}
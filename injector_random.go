@@ -1,15 +1,56 @@
 package fault
 
 import (
+	"errors"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"sync"
 )
 
+var (
+	// ErrInvalidSelectionMode when an invalid SelectionMode is provided.
+	ErrInvalidSelectionMode = errors.New("not a valid selection mode")
+)
+
+// SelectionMode determines how a RandomInjector chooses which Injector to run.
+type SelectionMode int
+
+const (
+	// SelectionRandom chooses a random Injector for every request.
+	SelectionRandom SelectionMode = iota
+	// SelectionRoundRobin cycles through the Injectors in the order they were provided.
+	SelectionRoundRobin
+	// SelectionShuffle chooses Injectors randomly without replacement, running every Injector once
+	// in a random order before starting over with a new order.
+	SelectionShuffle
+)
+
+// String returns the name of the SelectionMode, such as "round robin".
+func (m SelectionMode) String() string {
+	switch m {
+	case SelectionRandom:
+		return "random"
+	case SelectionRoundRobin:
+		return "round robin"
+	case SelectionShuffle:
+		return "shuffle"
+	default:
+		return "SelectionMode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
 // RandomInjector combines many Injectors into a single Injector that runs one randomly.
 type RandomInjector struct {
+	injectors   []Injector
 	middlewares []func(next http.Handler) http.Handler
 
+	mode SelectionMode
+	// next is the index of the next Injector to run with SelectionRoundRobin.
+	next int
+	// perm is the remaining order of Injectors to run with SelectionShuffle.
+	perm []int
+
 	randSeed int64
 	rand     *rand.Rand
 	randF    func(int) int
@@ -41,6 +82,22 @@ func WithRandIntFunc(f func(int) int) RandomInjectorOption {
 	return randIntFuncOption(f)
 }
 
+type selectionModeOption SelectionMode
+
+func (o selectionModeOption) applyRandomInjector(i *RandomInjector) error {
+	if SelectionMode(o) < SelectionRandom || SelectionMode(o) > SelectionShuffle {
+		return &OptionError{Option: "WithSelectionMode", Value: SelectionMode(o), Err: ErrInvalidSelectionMode}
+	}
+	i.mode = SelectionMode(o)
+	return nil
+}
+
+// WithSelectionMode sets how the RandomInjector chooses which Injector to run. Default
+// SelectionRandom.
+func WithSelectionMode(m SelectionMode) RandomInjectorOption {
+	return selectionModeOption(m)
+}
+
 // NewRandomInjector combines many Injectors into a single Injector that runs one randomly.
 func NewRandomInjector(is []Injector, opts ...RandomInjectorOption) (*RandomInjector, error) {
 	// set defaults
@@ -50,14 +107,13 @@ func NewRandomInjector(is []Injector, opts ...RandomInjectorOption) (*RandomInje
 	}
 
 	// apply options
-	for _, opt := range opts {
-		err := opt.applyRandomInjector(ri)
-		if err != nil {
-			return nil, err
-		}
+	err := applyOptions(opts, RandomInjectorOption.applyRandomInjector, ri)
+	if err != nil {
+		return nil, err
 	}
 
 	// set middleware
+	ri.injectors = is
 	for _, i := range is {
 		ri.middlewares = append(ri.middlewares, i.Handler)
 	}
@@ -71,17 +127,50 @@ func NewRandomInjector(is []Injector, opts ...RandomInjectorOption) (*RandomInje
 	return ri, nil
 }
 
-// Handler executes a random Injector from RandomInjector.middlewares.
+// Handler executes an Injector from RandomInjector.middlewares chosen by the SelectionMode.
 func (i *RandomInjector) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if len(i.middlewares) > 0 {
-			i.randMtx.Lock()
-			randIdx := i.randF(len(i.middlewares))
-			i.randMtx.Unlock()
-
-			i.middlewares[randIdx](next).ServeHTTP(w, r)
+			i.middlewares[i.choose()](next).ServeHTTP(w, r)
 		} else {
 			next.ServeHTTP(w, r)
 		}
 	})
 }
+
+// choose returns the index of the next Injector to run based on RandomInjector.mode.
+func (i *RandomInjector) choose() int {
+	i.randMtx.Lock()
+	defer i.randMtx.Unlock()
+
+	n := len(i.middlewares)
+
+	switch i.mode {
+	case SelectionRoundRobin:
+		idx := i.next
+		i.next = (i.next + 1) % n
+		return idx
+	case SelectionShuffle:
+		if len(i.perm) == 0 {
+			i.perm = make([]int, n)
+			for idx := range i.perm {
+				i.perm[idx] = idx
+			}
+			for idx := n - 1; idx > 0; idx-- {
+				swap := i.randF(idx + 1)
+				i.perm[idx], i.perm[swap] = i.perm[swap], i.perm[idx]
+			}
+		}
+		idx := i.perm[0]
+		i.perm = i.perm[1:]
+		return idx
+	default:
+		return i.randF(n)
+	}
+}
+
+// String describes the RandomInjector and the Injectors it chooses from, such as
+// "RandomInjector(random)[RejectInjector, ErrorInjector(503)]".
+func (i *RandomInjector) String() string {
+	return "RandomInjector(" + i.mode.String() + ")[" + injectorsString(i.injectors) + "]"
+}
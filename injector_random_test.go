@@ -77,6 +77,29 @@ func TestNewRandomInjector(t *testing.T) {
 			wantRand: rand.New(rand.NewSource(defaultRandSeed)),
 			wantErr:  nil,
 		},
+		{
+			name: "with selection mode",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+				newTestInjector500s(),
+			},
+			giveOptions: []RandomInjectorOption{
+				WithSelectionMode(SelectionShuffle),
+			},
+			wantRand: rand.New(rand.NewSource(defaultRandSeed)),
+			wantErr:  nil,
+		},
+		{
+			name: "invalid selection mode",
+			giveInjector: []Injector{
+				newTestInjectorNoop(),
+			},
+			giveOptions: []RandomInjectorOption{
+				WithSelectionMode(SelectionMode(-1)),
+			},
+			wantRand: rand.New(rand.NewSource(defaultRandSeed)),
+			wantErr:  &OptionError{Option: "WithSelectionMode", Value: SelectionMode(-1), Err: ErrInvalidSelectionMode},
+		},
 		{
 			name: "option error",
 			giveInjector: []Injector{
@@ -209,3 +232,98 @@ func TestRandomInjectorHandler(t *testing.T) {
 		})
 	}
 }
+
+// TestRandomInjectorSelectionMode tests the Injectors chosen by each SelectionMode over many
+// requests.
+func TestRandomInjectorSelectionMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []RandomInjectorOption
+		wantCodes   []int
+	}{
+		{
+			name: "round robin",
+			giveOptions: []RandomInjectorOption{
+				WithSelectionMode(SelectionRoundRobin),
+			},
+			wantCodes: []int{
+				http.StatusOK, http.StatusTeapot, http.StatusInternalServerError,
+				http.StatusOK, http.StatusTeapot, http.StatusInternalServerError,
+			},
+		},
+		{
+			name: "shuffle",
+			giveOptions: []RandomInjectorOption{
+				WithSelectionMode(SelectionShuffle),
+				WithRandIntFunc(func(int) int { return 0 }),
+			},
+			// Always swapping with the first index rotates the order by one
+			wantCodes: []int{
+				http.StatusTeapot, http.StatusInternalServerError, http.StatusOK,
+				http.StatusTeapot, http.StatusInternalServerError, http.StatusOK,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewRandomInjector([]Injector{
+				newTestInjectorOneOK(),
+				newTestInjectorTwoTeapot(),
+				newTestInjector500s(),
+			}, tt.giveOptions...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(ri,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			var codes []int
+			for range tt.wantCodes {
+				codes = append(codes, testRequest(t, f).Code)
+			}
+
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+// TestRandomInjectorShuffleCoverage tests that SelectionShuffle runs every Injector once before
+// repeating.
+func TestRandomInjectorShuffleCoverage(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRandomInjector([]Injector{
+		newTestInjectorOneOK(),
+		newTestInjectorTwoTeapot(),
+		newTestInjector500s(),
+	}, WithSelectionMode(SelectionShuffle))
+	assert.NoError(t, err)
+
+	for range 10 {
+		var idxs []int
+		for range len(ri.middlewares) {
+			idxs = append(idxs, ri.choose())
+		}
+		assert.ElementsMatch(t, []int{0, 1, 2}, idxs)
+	}
+}
+
+// TestRandomInjectorString tests RandomInjector.String.
+func TestRandomInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRandomInjector([]Injector{newTestInjectorNoop()}, WithSelectionMode(SelectionShuffle))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "RandomInjector(shuffle)[testInjectorNoop]", ri.String())
+	assert.Equal(t, "random", SelectionRandom.String())
+	assert.Equal(t, "round robin", SelectionRoundRobin.String())
+	assert.Equal(t, "SelectionMode(-1)", SelectionMode(-1).String())
+}
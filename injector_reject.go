@@ -1,13 +1,56 @@
 package fault
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
+var (
+	// ErrInvalidRejectMode when an invalid RejectMode is provided.
+	ErrInvalidRejectMode = errors.New("not a valid reject mode")
+)
+
+// RejectMode determines how a RejectInjector drops the request, and so which failure the client
+// sees.
+type RejectMode int
+
+const (
+	// RejectAbort panics with http.ErrAbortHandler. The http.Server closes HTTP/1.x connections and
+	// resets HTTP/2 streams, so the client sees an unexpected EOF or a stream error.
+	RejectAbort RejectMode = iota
+	// RejectClose hijacks the connection and closes it, so the client sees an unexpected EOF.
+	RejectClose
+	// RejectReset hijacks the connection and resets it with a TCP RST, so the client sees
+	// "connection reset by peer".
+	RejectReset
+)
+
+// String returns the name of the RejectMode, such as "reset".
+func (m RejectMode) String() string {
+	switch m {
+	case RejectAbort:
+		return "abort"
+	case RejectClose:
+		return "close"
+	case RejectReset:
+		return "reset"
+	default:
+		return "RejectMode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
 // RejectInjector sends back an empty response.
 type RejectInjector struct {
+	mode     RejectMode
+	delay    time.Duration
 	reporter Reporter
+	name     string
 }
 
 // RejectInjectorOption configures a RejectInjector.
@@ -15,37 +58,130 @@ type RejectInjectorOption interface {
 	applyRejectInjector(i *RejectInjector) error
 }
 
+type rejectDelayOption time.Duration
+
+func (o rejectDelayOption) applyRejectInjector(i *RejectInjector) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithRejectDelay", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	i.delay = time.Duration(o)
+	return nil
+}
+
+// WithRejectDelay holds the request for d before rejecting it, like a server that accepts a
+// connection and hangs before dropping it. d must be greater than zero. Default no delay.
+func WithRejectDelay(d time.Duration) RejectInjectorOption {
+	return rejectDelayOption(d)
+}
+
+type rejectModeOption RejectMode
+
+func (o rejectModeOption) applyRejectInjector(i *RejectInjector) error {
+	if RejectMode(o) < RejectAbort || RejectMode(o) > RejectReset {
+		return &OptionError{Option: "WithRejectMode", Value: RejectMode(o), Err: ErrInvalidRejectMode}
+	}
+	i.mode = RejectMode(o)
+	return nil
+}
+
+// WithRejectMode sets how the RejectInjector drops the request. Default RejectAbort. Use the same
+// RejectInjector with each RejectMode to test every way a client can lose a request.
+func WithRejectMode(m RejectMode) RejectInjectorOption {
+	return rejectModeOption(m)
+}
+
 func (o reporterOption) applyRejectInjector(i *RejectInjector) error {
 	i.reporter = o.reporter
 	return nil
 }
 
+func (o nameOption) applyRejectInjector(i *RejectInjector) error {
+	i.name = string(o)
+	return nil
+}
+
 // NewRejectInjector returns a RejectInjector.
 func NewRejectInjector(opts ...RejectInjectorOption) (*RejectInjector, error) {
 	// set defaults
 	ri := &RejectInjector{
 		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(RejectInjector{}).Name(),
 	}
 
 	// apply options
-	for _, opt := range opts {
-		err := opt.applyRejectInjector(ri)
-		if err != nil {
-			return nil, err
-		}
+	err := applyOptions(opts, RejectInjectorOption.applyRejectInjector, ri)
+	if err != nil {
+		return nil, err
 	}
 
 	return ri, nil
 }
 
-// Handler rejects the request, returning an empty response.
+// Handler rejects the request, returning an empty response, as set by WithRejectMode. With
+// WithRejectDelay the request is held without reading the body or writing a response until the
+// delay passes or the client gives up. RejectClose and RejectReset fall back to RejectAbort if the
+// connection cannot be hijacked, such as with HTTP/2, and RejectReset closes connections that are
+// not TCP without a reset.
 func (i *RejectInjector) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		go i.reporter.Report(reflect.ValueOf(*i).Type().Name(), StateStarted)
+		go report(i.reporter, i.name, StateStarted)
+
+		if i.delay > 0 {
+			timer := time.NewTimer(i.delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+			}
+		}
 
-		// This is a specialized and documented way of sending an interrupted response to
-		// the client without printing the panic stack trace or erroring.
-		// https://golang.org/pkg/net/http/#Handler
-		panic(http.ErrAbortHandler)
+		if i.mode != RejectAbort {
+			if i.hijackAndClose(w) == nil {
+				go report(i.reporter, i.name, StateFinished)
+				return
+			}
+			go report(i.reporter, i.name, StateErrored)
+		}
+
+		abort(i.reporter, i.name)
 	})
 }
+
+// hijackAndClose hijacks the connection of w and closes it, first setting SO_LINGER to zero with
+// RejectReset so that closing sends a TCP RST.
+func (i *RejectInjector) hijackAndClose(w http.ResponseWriter) error {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return err
+	}
+
+	if i.mode == RejectReset {
+		// TLS connections wrap the TCP connection
+		if tc, ok := conn.(interface{ NetConn() net.Conn }); ok {
+			conn = tc.NetConn()
+		}
+		if lc, ok := conn.(interface{ SetLinger(sec int) error }); ok {
+			err = lc.SetLinger(0)
+		}
+	}
+
+	return errors.Join(err, conn.Close())
+}
+
+// String returns the name of the RejectInjector, with its mode and delay if they are not the
+// defaults, such as "RejectInjector(mode=reset, delay=2s)".
+func (i *RejectInjector) String() string {
+	var details []string
+	if i.mode != RejectAbort {
+		details = append(details, "mode="+i.mode.String())
+	}
+	if i.delay > 0 {
+		details = append(details, fmt.Sprintf("delay=%s", i.delay))
+	}
+
+	if len(details) == 0 {
+		return i.name
+	}
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
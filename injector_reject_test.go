@@ -1,7 +1,16 @@
 package fault
 
 import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +30,7 @@ func TestNewRejectInjector(t *testing.T) {
 			giveOptions: []RejectInjectorOption{},
 			want: &RejectInjector{
 				reporter: NewNoopReporter(),
+				name:     "RejectInjector",
 			},
 			wantErr: nil,
 		},
@@ -31,9 +41,61 @@ func TestNewRejectInjector(t *testing.T) {
 			},
 			want: &RejectInjector{
 				reporter: newTestReporter(),
+				name:     "RejectInjector",
 			},
 			wantErr: nil,
 		},
+		{
+			name: "custom name",
+			giveOptions: []RejectInjectorOption{
+				WithName("custom"),
+			},
+			want: &RejectInjector{
+				reporter: NewNoopReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "delay",
+			giveOptions: []RejectInjectorOption{
+				WithRejectDelay(time.Second),
+			},
+			want: &RejectInjector{
+				delay:    time.Second,
+				reporter: NewNoopReporter(),
+				name:     "RejectInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "mode",
+			giveOptions: []RejectInjectorOption{
+				WithRejectMode(RejectReset),
+			},
+			want: &RejectInjector{
+				mode:     RejectReset,
+				reporter: NewNoopReporter(),
+				name:     "RejectInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid mode",
+			giveOptions: []RejectInjectorOption{
+				WithRejectMode(RejectMode(-1)),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithRejectMode", Value: RejectMode(-1), Err: ErrInvalidRejectMode},
+		},
+		{
+			name: "invalid delay",
+			giveOptions: []RejectInjectorOption{
+				WithRejectDelay(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithRejectDelay", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
 		{
 			name: "option error",
 			giveOptions: []RejectInjectorOption{
@@ -63,10 +125,27 @@ func TestRejectInjectorHandler(t *testing.T) {
 	tests := []struct {
 		name        string
 		giveOptions []RejectInjectorOption
+		wantStates  []string
 	}{
 		{
 			name:        "valid",
 			giveOptions: []RejectInjectorOption{},
+			wantStates:  []string{"RejectInjector started", "RejectInjector aborted"},
+		},
+		{
+			name:        "delay",
+			giveOptions: []RejectInjectorOption{WithRejectDelay(time.Millisecond)},
+			wantStates:  []string{"RejectInjector started", "RejectInjector aborted"},
+		},
+		{
+			name:        "close without hijacker",
+			giveOptions: []RejectInjectorOption{WithRejectMode(RejectClose)},
+			wantStates:  []string{"RejectInjector started", "RejectInjector errored", "RejectInjector aborted"},
+		},
+		{
+			name:        "reset without hijacker",
+			giveOptions: []RejectInjectorOption{WithRejectMode(RejectReset)},
+			wantStates:  []string{"RejectInjector started", "RejectInjector errored", "RejectInjector aborted"},
 		},
 	}
 
@@ -74,7 +153,8 @@ func TestRejectInjectorHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			ri, err := NewRejectInjector(tt.giveOptions...)
+			rep := &testChanReporter{states: make(chan string, len(tt.wantStates))}
+			ri, err := NewRejectInjector(append(tt.giveOptions, WithReporter(rep))...)
 			assert.NoError(t, err)
 
 			f, err := NewFault(ri,
@@ -85,6 +165,154 @@ func TestRejectInjectorHandler(t *testing.T) {
 
 			rr := testRequestExpectPanic(t, f)
 			assert.Nil(t, rr)
+
+			states := make([]string, 0, len(tt.wantStates))
+			for range tt.wantStates {
+				states = append(states, <-rep.states)
+			}
+			assert.ElementsMatch(t, tt.wantStates, states)
 		})
 	}
 }
+
+// TestRejectInjectorHandlerDelay tests that RejectInjector.Handler waits for the delay or for the
+// client to give up before rejecting.
+func TestRejectInjectorHandlerDelay(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRejectInjector(WithRejectDelay(50 * time.Millisecond))
+	assert.NoError(t, err)
+	h := ri.Handler(http.NotFoundHandler())
+
+	start := time.Now()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ri, err = NewRejectInjector(WithRejectDelay(time.Hour))
+	assert.NoError(t, err)
+	h = ri.Handler(http.NotFoundHandler())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	})
+}
+
+// TestRejectInjectorHandlerMode tests the failure that clients see with each RejectMode.
+func TestRejectInjectorHandlerMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveMode RejectMode
+		giveTLS  bool
+		giveUnix bool
+		wantErr  error
+	}{
+		{
+			name:     "abort",
+			giveMode: RejectAbort,
+			wantErr:  nil,
+		},
+		{
+			name:     "close",
+			giveMode: RejectClose,
+			wantErr:  nil,
+		},
+		{
+			name:     "reset",
+			giveMode: RejectReset,
+			wantErr:  syscall.ECONNRESET,
+		},
+		{
+			name:     "reset tls",
+			giveMode: RejectReset,
+			giveTLS:  true,
+			wantErr:  syscall.ECONNRESET,
+		},
+		{
+			name:     "reset unix",
+			giveMode: RejectReset,
+			giveUnix: true,
+			wantErr:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewRejectInjector(WithRejectMode(tt.giveMode))
+			assert.NoError(t, err)
+
+			ts := httptest.NewUnstartedServer(ri.Handler(http.NotFoundHandler()))
+			if tt.giveUnix {
+				l, err := net.Listen("unix", filepath.Join(t.TempDir(), "fault.sock"))
+				assert.NoError(t, err)
+				assert.NoError(t, ts.Listener.Close())
+				ts.Listener = l
+			}
+			if tt.giveTLS {
+				ts.StartTLS()
+			} else {
+				ts.Start()
+			}
+			defer ts.Close()
+
+			var conn net.Conn
+			switch {
+			case tt.giveTLS:
+				conn, err = tls.Dial("tcp", ts.Listener.Addr().String(),
+					ts.Client().Transport.(*http.Transport).TLSClientConfig)
+			default:
+				conn, err = net.Dial(ts.Listener.Addr().Network(), ts.Listener.Addr().String())
+			}
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")
+			assert.NoError(t, err)
+
+			b, err := io.ReadAll(conn)
+			assert.Empty(t, b)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRejectModeString tests RejectMode.String.
+func TestRejectModeString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "abort", RejectAbort.String())
+	assert.Equal(t, "close", RejectClose.String())
+	assert.Equal(t, "reset", RejectReset.String())
+	assert.Equal(t, "RejectMode(-1)", RejectMode(-1).String())
+}
+
+// TestRejectInjectorString tests RejectInjector.String.
+func TestRejectInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRejectInjector()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "RejectInjector", ri.String())
+
+	ri, err = NewRejectInjector(WithRejectDelay(2 * time.Second))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "RejectInjector(delay=2s)", ri.String())
+
+	ri, err = NewRejectInjector(WithRejectMode(RejectReset), WithRejectDelay(2*time.Second))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "RejectInjector(mode=reset, delay=2s)", ri.String())
+}
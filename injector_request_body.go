@@ -0,0 +1,173 @@
+package fault
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxCorruptBody is the most of the request body that a RequestBodyInjector reads into
+// memory to corrupt by default.
+const defaultMaxCorruptBody = 10 << 20 // 10 MiB
+
+// RequestBodyInjector truncates or corrupts the request body seen by the next handler.
+type RequestBodyInjector struct {
+	truncate   int64
+	corruptF   func(body []byte) []byte
+	maxCorrupt int64
+	reporter   Reporter
+	name       string
+}
+
+// RequestBodyInjectorOption configures a RequestBodyInjector.
+type RequestBodyInjectorOption interface {
+	applyRequestBodyInjector(i *RequestBodyInjector) error
+}
+
+type truncateBodyOption int64
+
+func (o truncateBodyOption) applyRequestBodyInjector(i *RequestBodyInjector) error {
+	if o < 0 {
+		return &OptionError{Option: "WithTruncateBody", Value: int64(o), Err: ErrInvalidCount}
+	}
+	i.truncate = int64(o)
+	return nil
+}
+
+// WithTruncateBody ends the request body after n bytes, simulating a partial upload. The
+// Content-Length of the request is not changed. n must not be negative.
+func WithTruncateBody(n int64) RequestBodyInjectorOption {
+	return truncateBodyOption(n)
+}
+
+type corruptBodyFuncOption func(body []byte) []byte
+
+func (o corruptBodyFuncOption) applyRequestBodyInjector(i *RequestBodyInjector) error {
+	if o == nil {
+		return &OptionError{Option: "WithCorruptBodyFunc", Value: nil, Err: ErrNilFunc}
+	}
+	i.corruptF = o
+	return nil
+}
+
+// WithCorruptBodyFunc sets a function that receives the request body, up to the limit set by
+// WithMaxCorruptBody, and returns the body that the next handler will read. The rest of a body over
+// the limit is read unchanged after the corrupted body. The body is corrupted before it is
+// truncated.
+func WithCorruptBodyFunc(f func(body []byte) []byte) RequestBodyInjectorOption {
+	return corruptBodyFuncOption(f)
+}
+
+type maxCorruptBodyOption int64
+
+func (o maxCorruptBodyOption) applyRequestBodyInjector(i *RequestBodyInjector) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithMaxCorruptBody", Value: int64(o), Err: ErrInvalidCount}
+	}
+	i.maxCorrupt = int64(o)
+	return nil
+}
+
+// WithMaxCorruptBody sets the most bytes of the request body that are read into memory and passed
+// to the function set by WithCorruptBodyFunc, which bounds the memory used by large or malicious
+// uploads. n must be greater than 0. Default 10 MiB.
+func WithMaxCorruptBody(n int64) RequestBodyInjectorOption {
+	return maxCorruptBodyOption(n)
+}
+
+func (o reporterOption) applyRequestBodyInjector(i *RequestBodyInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyRequestBodyInjector(i *RequestBodyInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewRequestBodyInjector returns a RequestBodyInjector.
+func NewRequestBodyInjector(opts ...RequestBodyInjectorOption) (*RequestBodyInjector, error) {
+	// set defaults
+	ri := &RequestBodyInjector{
+		truncate:   -1,
+		maxCorrupt: defaultMaxCorruptBody,
+		reporter:   NewNoopReporter(),
+		name:       reflect.TypeOf(RequestBodyInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, RequestBodyInjectorOption.applyRequestBodyInjector, ri)
+	if err != nil {
+		return nil, err
+	}
+
+	return ri, nil
+}
+
+// Handler continues the request with a copy of the request that has a corrupted and/or truncated
+// body. Use the RequestBodyInjector to test decoding failures and partial upload handling.
+func (i *RequestBodyInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		if r.Body != nil {
+			body := r.Body
+			r = r.Clone(r.Context())
+			r.Body = i.wrap(body)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wrap returns a body that reads from body with the configured corruption and truncation. Closing
+// the returned body closes body.
+func (i *RequestBodyInjector) wrap(body io.ReadCloser) io.ReadCloser {
+	var reader io.Reader = body
+
+	if i.corruptF != nil {
+		b, err := io.ReadAll(io.LimitReader(body, i.maxCorrupt))
+		var rest io.Reader = body
+		if err != nil {
+			rest = errReader{err: err}
+		}
+		reader = io.MultiReader(bytes.NewReader(i.corruptF(b)), rest)
+	}
+
+	if i.truncate >= 0 {
+		reader = io.LimitReader(reader, i.truncate)
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{reader, body}
+}
+
+// errReader always returns err, which must not be nil.
+type errReader struct {
+	err error
+}
+
+// Read returns errReader.err.
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// String describes the RequestBodyInjector, such as "RequestBodyInjector(truncate=10, corrupt)".
+func (i *RequestBodyInjector) String() string {
+	var details []string
+	if i.truncate >= 0 {
+		details = append(details, "truncate="+strconv.FormatInt(i.truncate, 10))
+	}
+	if i.corruptF != nil {
+		details = append(details, "corrupt")
+	}
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
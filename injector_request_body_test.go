@@ -0,0 +1,272 @@
+package fault
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestBodyRead = errors.New("error reading body")
+)
+
+// testBodyReadError is a request body that returns errTestBodyRead after some content.
+type testBodyReadError struct {
+	io.Reader
+	closed bool
+}
+
+// newTestBodyReadError returns a new testBodyReadError.
+func newTestBodyReadError(content string) *testBodyReadError {
+	return &testBodyReadError{
+		Reader: io.MultiReader(strings.NewReader(content), errReader{err: errTestBodyRead}),
+	}
+}
+
+// Close records that the body was closed.
+func (b *testBodyReadError) Close() error {
+	b.closed = true
+	return nil
+}
+
+// TestNewRequestBodyInjector tests NewRequestBodyInjector.
+func TestNewRequestBodyInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []RequestBodyInjectorOption
+		want        *RequestBodyInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &RequestBodyInjector{
+				truncate:   -1,
+				maxCorrupt: defaultMaxCorruptBody,
+				reporter:   NewNoopReporter(),
+				name:       "RequestBodyInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []RequestBodyInjectorOption{
+				WithTruncateBody(10),
+				WithCorruptBodyFunc(bytes.ToUpper),
+				WithMaxCorruptBody(100),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &RequestBodyInjector{
+				truncate:   10,
+				maxCorrupt: 100,
+				reporter:   newTestReporter(),
+				name:       "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid truncate",
+			giveOptions: []RequestBodyInjectorOption{
+				WithTruncateBody(-1),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithTruncateBody", Value: int64(-1), Err: ErrInvalidCount},
+		},
+		{
+			name: "nil corrupt function",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(nil),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithCorruptBodyFunc", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name: "invalid max corrupt body",
+			giveOptions: []RequestBodyInjectorOption{
+				WithMaxCorruptBody(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithMaxCorruptBody", Value: int64(0), Err: ErrInvalidCount},
+		},
+		{
+			name: "option error",
+			giveOptions: []RequestBodyInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewRequestBodyInjector(tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing
+			if tt.want != nil {
+				ri.corruptF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, ri)
+		})
+	}
+}
+
+// TestRequestBodyInjectorHandler tests RequestBodyInjector.Handler.
+func TestRequestBodyInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []RequestBodyInjectorOption
+		giveBody    io.ReadCloser
+		wantBody    string
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			giveBody:    io.NopCloser(strings.NewReader("hello world")),
+			wantBody:    "hello world",
+			wantErr:     nil,
+		},
+		{
+			name:        "nil body",
+			giveOptions: nil,
+			giveBody:    nil,
+			wantBody:    "",
+			wantErr:     nil,
+		},
+		{
+			name: "truncate",
+			giveOptions: []RequestBodyInjectorOption{
+				WithTruncateBody(5),
+			},
+			giveBody: io.NopCloser(strings.NewReader("hello world")),
+			wantBody: "hello",
+			wantErr:  nil,
+		},
+		{
+			name: "corrupt",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(bytes.ToUpper),
+			},
+			giveBody: io.NopCloser(strings.NewReader("hello world")),
+			wantBody: "HELLO WORLD",
+			wantErr:  nil,
+		},
+		{
+			name: "corrupt and truncate",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(bytes.ToUpper),
+				WithTruncateBody(5),
+			},
+			giveBody: io.NopCloser(strings.NewReader("hello world")),
+			wantBody: "HELLO",
+			wantErr:  nil,
+		},
+		{
+			name: "corrupt read error",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(bytes.ToUpper),
+			},
+			giveBody: newTestBodyReadError("hello"),
+			wantBody: "HELLO",
+			wantErr:  errTestBodyRead,
+		},
+		{
+			name: "corrupt over max",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(bytes.ToUpper),
+				WithMaxCorruptBody(5),
+			},
+			giveBody: io.NopCloser(strings.NewReader("hello world")),
+			wantBody: "HELLO world",
+			wantErr:  nil,
+		},
+		{
+			name: "corrupt over max read error",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(bytes.ToUpper),
+				WithMaxCorruptBody(3),
+			},
+			giveBody: newTestBodyReadError("hello"),
+			wantBody: "HELlo",
+			wantErr:  errTestBodyRead,
+		},
+		{
+			name: "corrupt over max and truncate",
+			giveOptions: []RequestBodyInjectorOption{
+				WithCorruptBodyFunc(bytes.ToUpper),
+				WithMaxCorruptBody(3),
+				WithTruncateBody(5),
+			},
+			giveBody: io.NopCloser(strings.NewReader("hello world")),
+			wantBody: "HELlo",
+			wantErr:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewRequestBodyInjector(tt.giveOptions...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(ri,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			var gotBody []byte
+			var gotErr error
+			h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Body != nil {
+					gotBody, gotErr = io.ReadAll(r.Body)
+					assert.NoError(t, r.Body.Close())
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Body = tt.giveBody
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantBody, string(gotBody))
+			assert.Equal(t, tt.wantErr, gotErr)
+			if b, ok := tt.giveBody.(*testBodyReadError); ok {
+				assert.True(t, b.closed)
+			}
+		})
+	}
+}
+
+// TestRequestBodyInjectorString tests RequestBodyInjector.String.
+func TestRequestBodyInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRequestBodyInjector(
+		WithTruncateBody(10),
+		WithCorruptBodyFunc(func(body []byte) []byte { return body }),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "RequestBodyInjector(truncate=10, corrupt)", ri.String())
+
+	ri, err = NewRequestBodyInjector()
+	assert.NoError(t, err)
+	assert.Equal(t, "RequestBodyInjector()", ri.String())
+}
@@ -0,0 +1,107 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// RequestHeaderInjector removes or rewrites request headers and then continues the request.
+type RequestHeaderInjector struct {
+	remove   []string
+	set      map[string]string
+	reporter Reporter
+	name     string
+}
+
+// RequestHeaderInjectorOption configures a RequestHeaderInjector.
+type RequestHeaderInjectorOption interface {
+	applyRequestHeaderInjector(i *RequestHeaderInjector) error
+}
+
+type removeHeadersOption []string
+
+func (o removeHeadersOption) applyRequestHeaderInjector(i *RequestHeaderInjector) error {
+	i.remove = append([]string{}, o...)
+	return nil
+}
+
+// WithRemoveHeaders is a list of header keys to remove.
+func WithRemoveHeaders(keys []string) RequestHeaderInjectorOption {
+	return removeHeadersOption(keys)
+}
+
+type setHeadersOption map[string]string
+
+func (o setHeadersOption) applyRequestHeaderInjector(i *RequestHeaderInjector) error {
+	set := make(map[string]string, len(o))
+	for key, val := range o {
+		set[key] = val
+	}
+	i.set = set
+	return nil
+}
+
+// WithSetHeaders is a map of header keys to values to set, replacing any existing values. Headers
+// are set after headers from WithRemoveHeaders are removed.
+func WithSetHeaders(headers map[string]string) RequestHeaderInjectorOption {
+	return setHeadersOption(headers)
+}
+
+func (o reporterOption) applyRequestHeaderInjector(i *RequestHeaderInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyRequestHeaderInjector(i *RequestHeaderInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewRequestHeaderInjector returns a RequestHeaderInjector.
+func NewRequestHeaderInjector(opts ...RequestHeaderInjectorOption) (*RequestHeaderInjector, error) {
+	// set defaults
+	ri := &RequestHeaderInjector{
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(RequestHeaderInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, RequestHeaderInjectorOption.applyRequestHeaderInjector, ri)
+	if err != nil {
+		return nil, err
+	}
+
+	return ri, nil
+}
+
+// Handler continues the request with a copy of the request that has the configured headers removed
+// or rewritten. This simulates broken intermediaries, for example one that drops the Authorization
+// header or corrupts the Content-Type.
+func (i *RequestHeaderInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		r = r.Clone(r.Context())
+		for _, key := range i.remove {
+			r.Header.Del(key)
+		}
+		for key, val := range i.set {
+			r.Header.Set(key, val)
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// String describes the RequestHeaderInjector, such as
+// "RequestHeaderInjector(remove=Authorization, set=X-Tenant:other)".
+func (i *RequestHeaderInjector) String() string {
+	var details []string
+	details = appendDetail(details, "remove", i.remove)
+	details = appendDetail(details, "set", headerStrings(i.set))
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
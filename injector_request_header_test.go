@@ -0,0 +1,161 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewRequestHeaderInjector tests NewRequestHeaderInjector.
+func TestNewRequestHeaderInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []RequestHeaderInjectorOption
+		want        *RequestHeaderInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &RequestHeaderInjector{
+				reporter: NewNoopReporter(),
+				name:     "RequestHeaderInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []RequestHeaderInjectorOption{
+				WithRemoveHeaders([]string{"Authorization"}),
+				WithSetHeaders(map[string]string{"Content-Type": "corrupt"}),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &RequestHeaderInjector{
+				remove:   []string{"Authorization"},
+				set:      map[string]string{"Content-Type": "corrupt"},
+				reporter: newTestReporter(),
+				name:     "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "option error",
+			giveOptions: []RequestHeaderInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewRequestHeaderInjector(tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, ri)
+		})
+	}
+}
+
+// TestRequestHeaderInjectorHandler tests RequestHeaderInjector.Handler.
+func TestRequestHeaderInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []RequestHeaderInjectorOption
+		wantHeader  http.Header
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			wantHeader: http.Header{
+				"Authorization": {"secret"},
+				"Content-Type":  {"application/json"},
+			},
+		},
+		{
+			name: "remove",
+			giveOptions: []RequestHeaderInjectorOption{
+				WithRemoveHeaders([]string{"authorization"}),
+			},
+			wantHeader: http.Header{
+				"Content-Type": {"application/json"},
+			},
+		},
+		{
+			name: "set",
+			giveOptions: []RequestHeaderInjectorOption{
+				WithSetHeaders(map[string]string{"content-type": "corrupt", "X-New": "new"}),
+			},
+			wantHeader: http.Header{
+				"Authorization": {"secret"},
+				"Content-Type":  {"corrupt"},
+				"X-New":         {"new"},
+			},
+		},
+		{
+			name: "remove and set",
+			giveOptions: []RequestHeaderInjectorOption{
+				WithRemoveHeaders([]string{"Authorization", "Content-Type"}),
+				WithSetHeaders(map[string]string{"Authorization": "wrong"}),
+			},
+			wantHeader: http.Header{
+				"Authorization": {"wrong"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewRequestHeaderInjector(tt.giveOptions...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(ri,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			var gotHeader http.Header
+			h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "secret")
+			req.Header.Set("Content-Type", "application/json")
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantHeader, gotHeader)
+			assert.Equal(t, "secret", req.Header.Get("Authorization"))
+		})
+	}
+}
+
+// TestRequestHeaderInjectorString tests RequestHeaderInjector.String.
+func TestRequestHeaderInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewRequestHeaderInjector(
+		WithRemoveHeaders([]string{"Authorization"}),
+		WithSetHeaders(map[string]string{"X-Tenant": "other"}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "RequestHeaderInjector(remove=Authorization, set=X-Tenant:other)", ri.String())
+
+	ri, err = NewRequestHeaderInjector()
+	assert.NoError(t, err)
+	assert.Equal(t, "RequestHeaderInjector()", ri.String())
+}
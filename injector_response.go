@@ -0,0 +1,101 @@
+package fault
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// ResponseInjector runs the next handler first and then runs an Injector only if the response
+// matches.
+type ResponseInjector struct {
+	injector Injector
+	matchF   func(code int, header http.Header) bool
+
+	reporter Reporter
+	name     string
+}
+
+// ResponseInjectorOption configures a ResponseInjector.
+type ResponseInjectorOption interface {
+	applyResponseInjector(i *ResponseInjector) error
+}
+
+func (o reporterOption) applyResponseInjector(i *ResponseInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyResponseInjector(i *ResponseInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewResponseInjector returns a ResponseInjector that runs i when match returns true for the status
+// code and headers written by the next handler.
+func NewResponseInjector(
+	i Injector,
+	match func(code int, header http.Header) bool,
+	opts ...ResponseInjectorOption,
+) (*ResponseInjector, error) {
+	if i == nil {
+		return nil, &OptionError{Option: "NewResponseInjector", Value: nil, Err: ErrNilInjector}
+	}
+	if match == nil {
+		return nil, &OptionError{Option: "NewResponseInjector", Value: nil, Err: ErrNilFunc}
+	}
+
+	// set defaults
+	ri := &ResponseInjector{
+		injector: i,
+		matchF:   match,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(ResponseInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, ResponseInjectorOption.applyResponseInjector, ri)
+	if err != nil {
+		return nil, err
+	}
+
+	return ri, nil
+}
+
+// Handler buffers the response from next and runs the Injector if the response matches. The
+// buffered response is written when the Injector continues the request or when the response does
+// not match. It reports StateStarted and StateFinished around the Injector only when the response
+// matches. Because the response is buffered, flushes from next are ignored. Hijacking and server
+// push pass through, and a hijacked response is never matched.
+func (i *ResponseInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := newInterceptor(w)
+		next.ServeHTTP(rw, r)
+
+		// the next handler took over the connection, such as for a websocket
+		if rw.Hijacked() {
+			return
+		}
+
+		replay := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the client is gone if the buffered response cannot be written
+			err := rw.commitTo(w)
+			if err != nil {
+				panic(http.ErrAbortHandler)
+			}
+		})
+
+		if i.matchF(rw.StatusCode(), rw.Header()) {
+			go report(i.reporter, i.name, StateStarted)
+			i.injector.Handler(replay).ServeHTTP(w, r)
+			go report(i.reporter, i.name, StateFinished)
+		} else {
+			replay.ServeHTTP(w, r)
+		}
+	})
+}
+
+// String describes the ResponseInjector and the Injector it runs, such as
+// "ResponseInjector(ErrorInjector(503))".
+func (i *ResponseInjector) String() string {
+	return i.name + "(" + injectorString(i.injector) + ")"
+}
@@ -0,0 +1,222 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMatchCode returns a function for ResponseInjector that matches the provided code.
+func testMatchCode(want int) func(int, http.Header) bool {
+	return func(code int, header http.Header) bool {
+		return code == want
+	}
+}
+
+// TestNewResponseInjector tests NewResponseInjector.
+func TestNewResponseInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveMatch    func(int, http.Header) bool
+		giveOptions  []ResponseInjectorOption
+		wantErr      error
+	}{
+		{
+			name:         "valid",
+			giveInjector: newTestInjectorNoop(),
+			giveMatch:    testMatchCode(http.StatusOK),
+			giveOptions:  nil,
+			wantErr:      nil,
+		},
+		{
+			name:         "nil injector",
+			giveInjector: nil,
+			giveMatch:    testMatchCode(http.StatusOK),
+			giveOptions:  nil,
+			wantErr:      &OptionError{Option: "NewResponseInjector", Value: nil, Err: ErrNilInjector},
+		},
+		{
+			name:         "nil match",
+			giveInjector: newTestInjectorNoop(),
+			giveMatch:    nil,
+			giveOptions:  nil,
+			wantErr:      &OptionError{Option: "NewResponseInjector", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:         "option error",
+			giveInjector: newTestInjectorNoop(),
+			giveMatch:    testMatchCode(http.StatusOK),
+			giveOptions: []ResponseInjectorOption{
+				withError(),
+			},
+			wantErr: errErrorOption,
+		},
+		{
+			name:         "reporter and name",
+			giveInjector: newTestInjectorNoop(),
+			giveMatch:    testMatchCode(http.StatusOK),
+			giveOptions: []ResponseInjectorOption{
+				WithReporter(NewNoopReporter()),
+				WithName("custom"),
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewResponseInjector(tt.giveInjector, tt.giveMatch, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.giveInjector, ri.injector)
+			} else {
+				assert.Nil(t, ri)
+			}
+		})
+	}
+}
+
+// TestResponseInjectorHandler tests ResponseInjector.Handler.
+func TestResponseInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveMatch    func(int, http.Header) bool
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:         "no match",
+			giveInjector: newTestInjector500s(),
+			giveMatch:    testMatchCode(http.StatusOK),
+			wantCode:     testHandlerCode,
+			wantBody:     testHandlerBody,
+		},
+		{
+			name:         "match code 500s",
+			giveInjector: newTestInjector500s(),
+			giveMatch:    testMatchCode(testHandlerCode),
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "match header 500s",
+			giveInjector: newTestInjector500s(),
+			giveMatch: func(code int, header http.Header) bool {
+				return header.Get("X-Content-Type-Options") == "nosniff"
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:         "match inject nothing",
+			giveInjector: newTestInjectorNoop(),
+			giveMatch:    testMatchCode(testHandlerCode),
+			wantCode:     testHandlerCode,
+			wantBody:     testHandlerBody,
+		},
+		{
+			name:         "match one",
+			giveInjector: newTestInjectorOneOK(),
+			giveMatch:    testMatchCode(testHandlerCode),
+			wantCode:     http.StatusOK,
+			wantBody:     "one" + testHandlerBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := NewResponseInjector(tt.giveInjector, tt.giveMatch)
+			assert.NoError(t, err)
+
+			f, err := NewFault(ri,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			rr := testRequest(t, f)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rr.Body.String()))
+		})
+	}
+}
+
+// TestResponseInjectorHandlerWriteError tests that ResponseInjector.Handler aborts the request if
+// the buffered response cannot be written.
+func TestResponseInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewResponseInjector(newTestInjectorNoop(), testMatchCode(testHandlerCode))
+	assert.NoError(t, err)
+
+	h := ri.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, testHandlerBody, testHandlerCode)
+	}))
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+// TestResponseInjectorHandlerReport tests that ResponseInjector.Handler reports its states only
+// when the response matches.
+func TestResponseInjectorHandlerReport(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 4)}
+	ri, err := NewResponseInjector(newTestInjector500s(), func(code int, header http.Header) bool {
+		return header.Get(testHeaderKey) != ""
+	}, WithReporter(rep), WithName("custom"))
+	assert.NoError(t, err)
+
+	h := ri.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(testHeaderKey, r.Header.Get(testHeaderKey))
+		http.Error(w, testHandlerBody, testHandlerCode)
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, testHandlerCode, rr.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(testHeaderKey, testHeaderVal)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	assert.ElementsMatch(t, []string{"custom started", "custom finished"},
+		[]string{<-rep.states, <-rep.states})
+	assert.Empty(t, rep.states)
+}
+
+// TestResponseInjectorString tests ResponseInjector.String.
+func TestResponseInjectorString(t *testing.T) {
+	t.Parallel()
+
+	ri, err := NewResponseInjector(newTestInjectorNoop(), testMatchCode(http.StatusOK))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ResponseInjector(testInjectorNoop)", ri.String())
+
+	ri, err = NewResponseInjector(newTestInjectorNoop(), testMatchCode(http.StatusOK), WithName("custom"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "custom(testInjectorNoop)", ri.String())
+}
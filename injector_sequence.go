@@ -0,0 +1,105 @@
+package fault
+
+import (
+	"net/http"
+	"sync"
+)
+
+// SequenceInjector runs its Injectors in order across successive requests, one Injector per
+// request.
+type SequenceInjector struct {
+	injectors   []Injector
+	middlewares []func(next http.Handler) http.Handler
+	repeat      bool
+
+	// pos is the index of the Injector that will run on the next request.
+	pos int
+	// posMtx protects SequenceInjector.pos.
+	posMtx sync.Mutex
+}
+
+// SequenceInjectorOption configures a SequenceInjector.
+type SequenceInjectorOption interface {
+	applySequenceInjector(i *SequenceInjector) error
+}
+
+type repeatOption bool
+
+func (o repeatOption) applySequenceInjector(i *SequenceInjector) error {
+	i.repeat = bool(o)
+	return nil
+}
+
+// WithRepeat sets if the SequenceInjector starts over from the first Injector after running the
+// last. Default false, all requests continue without injection after the sequence ends.
+func WithRepeat(r bool) SequenceInjectorOption {
+	return repeatOption(r)
+}
+
+// NewSequenceInjector returns a SequenceInjector that runs the first Injector on the first request,
+// the second Injector on the second request, and so on. A nil Injector continues the request
+// without injecting.
+func NewSequenceInjector(is []Injector, opts ...SequenceInjectorOption) (*SequenceInjector, error) {
+	// set defaults
+	si := &SequenceInjector{
+		repeat: false,
+	}
+
+	// apply options
+	err := applyOptions(opts, SequenceInjectorOption.applySequenceInjector, si)
+	if err != nil {
+		return nil, err
+	}
+
+	// set middleware
+	si.injectors = is
+	for _, i := range is {
+		if i == nil {
+			si.middlewares = append(si.middlewares, nil)
+		} else {
+			si.middlewares = append(si.middlewares, i.Handler)
+		}
+	}
+
+	return si, nil
+}
+
+// Handler executes the next Injector in SequenceInjector.middlewares.
+func (i *SequenceInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mw := i.step(); mw != nil {
+			mw(next).ServeHTTP(w, r)
+		} else {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// step returns the middleware for the current request and advances the sequence. It returns nil
+// if the sequence has ended.
+func (i *SequenceInjector) step() func(next http.Handler) http.Handler {
+	i.posMtx.Lock()
+	defer i.posMtx.Unlock()
+
+	if i.repeat && i.pos >= len(i.middlewares) {
+		i.pos = 0
+	}
+	if i.pos >= len(i.middlewares) {
+		return nil
+	}
+
+	mw := i.middlewares[i.pos]
+	i.pos++
+
+	return mw
+}
+
+// String describes the SequenceInjector and the Injectors it runs, such as
+// "SequenceInjector(repeat)[nil, ErrorInjector(503)]". A nil Injector is "nil".
+func (i *SequenceInjector) String() string {
+	s := "SequenceInjector"
+	if i.repeat {
+		s += "(repeat)"
+	}
+	return s + "[" + injectorsString(i.injectors) + "]"
+}
@@ -0,0 +1,159 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewSequenceInjector tests NewSequenceInjector.
+func TestNewSequenceInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector []Injector
+		giveOptions  []SequenceInjectorOption
+		wantRepeat   bool
+		wantErr      error
+	}{
+		{
+			name:         "nil",
+			giveInjector: nil,
+			giveOptions:  nil,
+			wantRepeat:   false,
+			wantErr:      nil,
+		},
+		{
+			name: "two with nil",
+			giveInjector: []Injector{
+				nil,
+				newTestInjector500s(),
+			},
+			giveOptions: nil,
+			wantRepeat:  false,
+			wantErr:     nil,
+		},
+		{
+			name: "repeat",
+			giveInjector: []Injector{
+				newTestInjector500s(),
+			},
+			giveOptions: []SequenceInjectorOption{
+				WithRepeat(true),
+			},
+			wantRepeat: true,
+			wantErr:    nil,
+		},
+		{
+			name: "option error",
+			giveInjector: []Injector{
+				newTestInjector500s(),
+			},
+			giveOptions: []SequenceInjectorOption{
+				withError(),
+			},
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			si, err := NewSequenceInjector(tt.giveInjector, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantRepeat, si.repeat)
+				assert.Equal(t, len(tt.giveInjector), len(si.middlewares))
+			} else {
+				assert.Nil(t, si)
+			}
+		})
+	}
+}
+
+// TestSequenceInjectorHandler tests SequenceInjector.Handler over many requests.
+func TestSequenceInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector []Injector
+		giveOptions  []SequenceInjectorOption
+		wantCodes    []int
+	}{
+		{
+			name:         "empty",
+			giveInjector: []Injector{},
+			giveOptions:  nil,
+			wantCodes:    []int{testHandlerCode, testHandlerCode},
+		},
+		{
+			name: "once",
+			giveInjector: []Injector{
+				newTestInjector500s(),
+				nil,
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: nil,
+			wantCodes: []int{
+				http.StatusInternalServerError, testHandlerCode, http.StatusTeapot,
+				testHandlerCode, testHandlerCode,
+			},
+		},
+		{
+			name: "repeat",
+			giveInjector: []Injector{
+				newTestInjector500s(),
+				nil,
+				newTestInjectorTwoTeapot(),
+			},
+			giveOptions: []SequenceInjectorOption{
+				WithRepeat(true),
+			},
+			wantCodes: []int{
+				http.StatusInternalServerError, testHandlerCode, http.StatusTeapot,
+				http.StatusInternalServerError, testHandlerCode,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			si, err := NewSequenceInjector(tt.giveInjector, tt.giveOptions...)
+			assert.NoError(t, err)
+
+			f, err := NewFault(si,
+				WithEnabled(true),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			var codes []int
+			for range tt.wantCodes {
+				codes = append(codes, testRequest(t, f).Code)
+			}
+
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+// TestSequenceInjectorString tests SequenceInjector.String.
+func TestSequenceInjectorString(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewSequenceInjector([]Injector{nil, newTestInjectorNoop()})
+	assert.NoError(t, err)
+	assert.Equal(t, "SequenceInjector[nil, testInjectorNoop]", si.String())
+
+	si, err = NewSequenceInjector([]Injector{newTestInjectorNoop()}, WithRepeat(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "SequenceInjector(repeat)[testInjectorNoop]", si.String())
+}
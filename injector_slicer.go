@@ -0,0 +1,182 @@
+package fault
+
+import (
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlicerInjector continues the request and writes the response body in many small slices.
+type SlicerInjector struct {
+	size      int
+	variation int
+	delay     time.Duration
+	slowF     func(t time.Duration)
+	reporter  Reporter
+	name      string
+
+	randSeed int64
+	rand     *rand.Rand
+
+	// *rand.Rand is not thread safe. This mutex protects our random source
+	randMtx sync.Mutex
+}
+
+// SlicerInjectorOption configures a SlicerInjector.
+type SlicerInjectorOption interface {
+	applySlicerInjector(i *SlicerInjector) error
+}
+
+type sliceVariationOption int
+
+func (o sliceVariationOption) applySlicerInjector(i *SlicerInjector) error {
+	if o < 0 {
+		return &OptionError{Option: "WithSliceVariation", Value: int(o), Err: ErrInvalidCount}
+	}
+	i.variation = int(o)
+	return nil
+}
+
+// WithSliceVariation makes each slice up to n bytes larger or smaller than the average size, chosen
+// randomly. n must be less than the average size. Default 0.
+func WithSliceVariation(n int) SlicerInjectorOption {
+	return sliceVariationOption(n)
+}
+
+type sliceDelayOption time.Duration
+
+func (o sliceDelayOption) applySlicerInjector(i *SlicerInjector) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithSliceDelay", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	i.delay = time.Duration(o)
+	return nil
+}
+
+// WithSliceDelay waits d after writing each slice. Default no wait.
+func WithSliceDelay(d time.Duration) SlicerInjectorOption {
+	return sliceDelayOption(d)
+}
+
+func (o slowFunctionOption) applySlicerInjector(i *SlicerInjector) error {
+	i.slowF = o
+	return nil
+}
+
+func (o randSeedOption) applySlicerInjector(i *SlicerInjector) error {
+	i.randSeed = int64(o)
+	return nil
+}
+
+func (o reporterOption) applySlicerInjector(i *SlicerInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applySlicerInjector(i *SlicerInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewSlicerInjector returns a SlicerInjector that writes the response body in slices of size bytes
+// on average. size must be greater than 0.
+func NewSlicerInjector(size int, opts ...SlicerInjectorOption) (*SlicerInjector, error) {
+	if size <= 0 {
+		return nil, &OptionError{Option: "NewSlicerInjector", Value: size, Err: ErrInvalidCount}
+	}
+
+	// set defaults
+	si := &SlicerInjector{
+		size:     size,
+		slowF:    time.Sleep,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(SlicerInjector{}).Name(),
+		randSeed: defaultRandSeed,
+	}
+
+	// apply options
+	err := applyOptions(opts, SlicerInjectorOption.applySlicerInjector, si)
+	if err != nil {
+		return nil, err
+	}
+
+	if si.variation >= si.size {
+		return nil, &OptionError{Option: "WithSliceVariation", Value: si.variation, Err: ErrInvalidCount}
+	}
+
+	// set seeded rand source
+	si.rand = rand.New(rand.NewSource(si.randSeed))
+
+	return si, nil
+}
+
+// Handler continues the request and writes the response body in slices, flushing each slice so
+// that it is sent separately, and waiting WithSliceDelay after each one. Use the SlicerInjector to
+// test clients and parsers that assume a whole message arrives in one read.
+func (i *SlicerInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		next.ServeHTTP(&slicerWriter{ResponseRecorderWriter: &ResponseRecorderWriter{w: w}, injector: i}, r)
+
+		go report(i.reporter, i.name, StateFinished)
+	})
+}
+
+// String describes the SlicerInjector, such as "SlicerInjector(100, variation=10, delay=1ms)".
+func (i *SlicerInjector) String() string {
+	details := []string{strconv.Itoa(i.size)}
+	if i.variation > 0 {
+		details = append(details, "variation="+strconv.Itoa(i.variation))
+	}
+	if i.delay > 0 {
+		details = append(details, "delay="+i.delay.String())
+	}
+
+	return i.name + "(" + strings.Join(details, ", ") + ")"
+}
+
+// sliceSize returns the size of the next slice.
+func (i *SlicerInjector) sliceSize() int {
+	if i.variation == 0 {
+		return i.size
+	}
+
+	i.randMtx.Lock()
+	defer i.randMtx.Unlock()
+	return i.size - i.variation + i.rand.Intn(2*i.variation+1)
+}
+
+// slicerWriter is an http.ResponseWriter that writes the body in slices with a SlicerInjector.
+// Flushing, hijacking, server push, and Unwrap pass through it.
+type slicerWriter struct {
+	*ResponseRecorderWriter
+	injector *SlicerInjector
+}
+
+// Write writes b in slices, flushing and waiting after each slice.
+func (w *slicerWriter) Write(b []byte) (int, error) {
+	if len(b) == 0 {
+		return w.ResponseRecorderWriter.Write(b)
+	}
+
+	var written int
+	for len(b) > 0 {
+		slice := b[:min(len(b), w.injector.sliceSize())]
+		n, err := w.ResponseRecorderWriter.Write(slice)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		w.Flush()
+		if w.injector.delay > 0 {
+			w.injector.slowF(w.injector.delay)
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
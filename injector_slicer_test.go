@@ -0,0 +1,263 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSliceWriter is an http.ResponseWriter that records each write separately.
+type testSliceWriter struct {
+	*httptest.ResponseRecorder
+	writes []string
+}
+
+// Write records b and writes it to the wrapped ResponseRecorder.
+func (w *testSliceWriter) Write(b []byte) (int, error) {
+	w.writes = append(w.writes, string(b))
+	return w.ResponseRecorder.Write(b)
+}
+
+// TestNewSlicerInjector tests NewSlicerInjector.
+func TestNewSlicerInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveSize    int
+		giveOptions []SlicerInjectorOption
+		want        *SlicerInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveSize:    64,
+			giveOptions: nil,
+			want: &SlicerInjector{
+				size:     64,
+				reporter: NewNoopReporter(),
+				name:     "SlicerInjector",
+				randSeed: defaultRandSeed,
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "all options",
+			giveSize: 64,
+			giveOptions: []SlicerInjectorOption{
+				WithSliceVariation(32),
+				WithSliceDelay(time.Millisecond),
+				WithSlowFunc(func(time.Duration) {}),
+				WithRandSeed(100),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &SlicerInjector{
+				size:      64,
+				variation: 32,
+				delay:     time.Millisecond,
+				reporter:  newTestReporter(),
+				name:      "custom",
+				randSeed:  100,
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "invalid size",
+			giveSize:    0,
+			giveOptions: nil,
+			want:        nil,
+			wantErr:     &OptionError{Option: "NewSlicerInjector", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name:     "negative variation",
+			giveSize: 64,
+			giveOptions: []SlicerInjectorOption{
+				WithSliceVariation(-1),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithSliceVariation", Value: -1, Err: ErrInvalidCount},
+		},
+		{
+			name:     "variation not less than size",
+			giveSize: 64,
+			giveOptions: []SlicerInjectorOption{
+				WithSliceVariation(64),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithSliceVariation", Value: 64, Err: ErrInvalidCount},
+		},
+		{
+			name:     "invalid delay",
+			giveSize: 64,
+			giveOptions: []SlicerInjectorOption{
+				WithSliceDelay(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithSliceDelay", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name:     "option error",
+			giveSize: 64,
+			giveOptions: []SlicerInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			si, err := NewSlicerInjector(tt.giveSize, tt.giveOptions...)
+
+			// Function and rand source equality cannot be determined so set to nil before comparing
+			if si != nil {
+				si.slowF = nil
+				si.rand = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, si)
+		})
+	}
+}
+
+// TestSlicerInjectorHandler tests SlicerInjector.Handler.
+func TestSlicerInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveSize    int
+		giveOptions []SlicerInjectorOption
+		giveBody    string
+		wantWrites  []string
+		wantSlept   time.Duration
+	}{
+		{
+			name:        "slices",
+			giveSize:    4,
+			giveOptions: nil,
+			giveBody:    "abcdefghij",
+			wantWrites:  []string{"abcd", "efgh", "ij"},
+			wantSlept:   0,
+		},
+		{
+			name:        "delay",
+			giveSize:    4,
+			giveOptions: []SlicerInjectorOption{WithSliceDelay(time.Millisecond)},
+			giveBody:    "abcdefghij",
+			wantWrites:  []string{"abcd", "efgh", "ij"},
+			wantSlept:   3 * time.Millisecond,
+		},
+		{
+			name:        "one slice",
+			giveSize:    64,
+			giveOptions: []SlicerInjectorOption{WithSliceDelay(time.Millisecond)},
+			giveBody:    "abc",
+			wantWrites:  []string{"abc"},
+			wantSlept:   time.Millisecond,
+		},
+		{
+			name:        "empty",
+			giveSize:    4,
+			giveOptions: []SlicerInjectorOption{WithSliceDelay(time.Millisecond)},
+			giveBody:    "",
+			wantWrites:  []string{""},
+			wantSlept:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var slept time.Duration
+			si, err := NewSlicerInjector(tt.giveSize, append(tt.giveOptions,
+				WithSlowFunc(func(d time.Duration) { slept += d }),
+			)...)
+			assert.NoError(t, err)
+
+			h := si.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n, err := w.Write([]byte(tt.giveBody))
+				assert.NoError(t, err)
+				assert.Equal(t, len(tt.giveBody), n)
+			}))
+
+			sw := &testSliceWriter{ResponseRecorder: httptest.NewRecorder()}
+			h.ServeHTTP(sw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, sw.Code)
+			assert.Equal(t, tt.giveBody, sw.Body.String())
+			assert.Equal(t, tt.wantWrites, sw.writes)
+			assert.Equal(t, tt.wantSlept, slept)
+		})
+	}
+}
+
+// TestSlicerInjectorHandlerVariation tests that a SlicerInjector with WithSliceVariation writes
+// slices within the variation of the average size.
+func TestSlicerInjectorHandlerVariation(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewSlicerInjector(8, WithSliceVariation(4))
+	assert.NoError(t, err)
+
+	body := strings.Repeat("a", 1000)
+	h := si.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+
+	sw := &testSliceWriter{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(sw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, body, sw.Body.String())
+	sizes := make(map[int]bool)
+	for _, w := range sw.writes[:len(sw.writes)-1] {
+		assert.GreaterOrEqual(t, len(w), 4)
+		assert.LessOrEqual(t, len(w), 12)
+		sizes[len(w)] = true
+	}
+	assert.Greater(t, len(sizes), 1)
+}
+
+// TestSlicerInjectorHandlerWriteError tests that the http.ResponseWriter that a SlicerInjector
+// passes to the next handler stops at the first failed write.
+func TestSlicerInjectorHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewSlicerInjector(4)
+	assert.NoError(t, err)
+
+	h := si.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := w.Write([]byte("abcdefghij"))
+		assert.Equal(t, 2, n)
+		assert.Equal(t, errTestWrite, err)
+	}))
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.code)
+}
+
+// TestSlicerInjectorString tests SlicerInjector.String.
+func TestSlicerInjectorString(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewSlicerInjector(100)
+	assert.NoError(t, err)
+	assert.Equal(t, "SlicerInjector(100)", si.String())
+
+	si, err = NewSlicerInjector(100, WithSliceVariation(10), WithSliceDelay(time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, "SlicerInjector(100, variation=10, delay=1ms)", si.String())
+}
@@ -1,6 +1,7 @@
 package fault
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
 	"time"
@@ -9,8 +10,10 @@ import (
 // SlowInjector waits and then continues the request.
 type SlowInjector struct {
 	duration time.Duration
+	after    bool
 	slowF    func(t time.Duration)
 	reporter Reporter
+	name     string
 }
 
 // SlowInjectorOption configures a SlowInjector.
@@ -18,6 +21,16 @@ type SlowInjectorOption interface {
 	applySlowInjector(i *SlowInjector) error
 }
 
+// SlowFuncOption configures things that can set a function to wait.
+type SlowFuncOption interface {
+	SlowInjectorOption
+	LoadLatencyInjectorOption
+	StreamOption
+	PushInjectorOption
+	BandwidthInjectorOption
+	SlicerInjectorOption
+}
+
 type slowFunctionOption func(t time.Duration)
 
 func (o slowFunctionOption) applySlowInjector(i *SlowInjector) error {
@@ -26,15 +39,35 @@ func (o slowFunctionOption) applySlowInjector(i *SlowInjector) error {
 }
 
 // WithSlowFunc sets the function that will be used to wait the time.Duration.
-func WithSlowFunc(f func(t time.Duration)) SlowInjectorOption {
+func WithSlowFunc(f func(t time.Duration)) SlowFuncOption {
 	return slowFunctionOption(f)
 }
 
+type slowAfterHandlerOption struct{}
+
+func (o slowAfterHandlerOption) applySlowInjector(i *SlowInjector) error {
+	i.after = true
+	return nil
+}
+
+// WithSlowAfterHandler waits after the next handler returns instead of before it runs, delaying the
+// completion of the response rather than the start of the handler. Handler side timeouts do not
+// include the wait, and whatever the handler wrote without flushing is held in the server's buffer
+// until the wait ends, like a slow network on the way back to the client.
+func WithSlowAfterHandler() SlowInjectorOption {
+	return slowAfterHandlerOption{}
+}
+
 func (o reporterOption) applySlowInjector(i *SlowInjector) error {
 	i.reporter = o.reporter
 	return nil
 }
 
+func (o nameOption) applySlowInjector(i *SlowInjector) error {
+	i.name = string(o)
+	return nil
+}
+
 // NewSlowInjector returns a SlowInjector.
 func NewSlowInjector(d time.Duration, opts ...SlowInjectorOption) (*SlowInjector, error) {
 	// set defaults
@@ -42,26 +75,49 @@ func NewSlowInjector(d time.Duration, opts ...SlowInjectorOption) (*SlowInjector
 		duration: d,
 		slowF:    time.Sleep,
 		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(SlowInjector{}).Name(),
 	}
 
 	// apply options
-	for _, opt := range opts {
-		err := opt.applySlowInjector(si)
-		if err != nil {
-			return nil, err
-		}
+	err := applyOptions(opts, SlowInjectorOption.applySlowInjector, si)
+	if err != nil {
+		return nil, err
 	}
 
 	return si, nil
 }
 
-// Handler runs i.slowF to wait the set duration and then continues.
+// Handler runs i.slowF to wait the set duration and then continues. With WithSlowAfterHandler it
+// continues first and waits after next returns. The wait is reported to a DelayReporter and, unless
+// it is after the handler, added to the InjectedDelay of the request.
 func (i *SlowInjector) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		go i.reporter.Report(reflect.ValueOf(*i).Type().Name(), StateStarted)
+		if i.after {
+			next.ServeHTTP(w, r)
+
+			go report(i.reporter, i.name, StateStarted)
+			i.slowF(i.duration)
+			recordDelay(r, i.duration)
+			reportDelay(i.reporter, i.name, i.duration)
+			go report(i.reporter, i.name, StateFinished)
+			return
+		}
+
+		go report(i.reporter, i.name, StateStarted)
 		i.slowF(i.duration)
-		go i.reporter.Report(reflect.ValueOf(*i).Type().Name(), StateFinished)
+		recordDelay(r, i.duration)
+		reportDelay(i.reporter, i.name, i.duration)
+		go report(i.reporter, i.name, StateFinished)
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, withInjectedDelay(r, i.duration))
 	})
 }
+
+// String describes the SlowInjector, such as "SlowInjector(1s)" or, with WithSlowAfterHandler,
+// "SlowInjector(1s, after handler)".
+func (i *SlowInjector) String() string {
+	if i.after {
+		return fmt.Sprintf("%s(%s, after handler)", i.name, i.duration)
+	}
+	return fmt.Sprintf("%s(%s)", i.name, i.duration)
+}
@@ -1,6 +1,8 @@
 package fault
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -27,6 +29,7 @@ func TestNewSlowInjector(t *testing.T) {
 				duration: 0,
 				slowF:    time.Sleep,
 				reporter: NewNoopReporter(),
+				name:     "SlowInjector",
 			},
 			wantErr: nil,
 		},
@@ -38,6 +41,7 @@ func TestNewSlowInjector(t *testing.T) {
 				duration: 0,
 				slowF:    time.Sleep,
 				reporter: NewNoopReporter(),
+				name:     "SlowInjector",
 			},
 			wantErr: nil,
 		},
@@ -49,6 +53,7 @@ func TestNewSlowInjector(t *testing.T) {
 				duration: time.Minute,
 				slowF:    time.Sleep,
 				reporter: NewNoopReporter(),
+				name:     "SlowInjector",
 			},
 			wantErr: nil,
 		},
@@ -62,6 +67,22 @@ func TestNewSlowInjector(t *testing.T) {
 				duration: time.Minute,
 				slowF:    func(time.Duration) {},
 				reporter: NewNoopReporter(),
+				name:     "SlowInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "after handler",
+			giveDuration: time.Minute,
+			giveOptions: []SlowInjectorOption{
+				WithSlowAfterHandler(),
+			},
+			want: &SlowInjector{
+				duration: time.Minute,
+				after:    true,
+				slowF:    time.Sleep,
+				reporter: NewNoopReporter(),
+				name:     "SlowInjector",
 			},
 			wantErr: nil,
 		},
@@ -75,6 +96,21 @@ func TestNewSlowInjector(t *testing.T) {
 				duration: time.Minute,
 				slowF:    time.Sleep,
 				reporter: newTestReporter(),
+				name:     "SlowInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "custom name",
+			giveDuration: time.Minute,
+			giveOptions: []SlowInjectorOption{
+				WithName("custom"),
+			},
+			want: &SlowInjector{
+				duration: time.Minute,
+				slowF:    time.Sleep,
+				reporter: NewNoopReporter(),
+				name:     "custom",
 			},
 			wantErr: nil,
 		},
@@ -170,3 +206,68 @@ func TestSlowInjectorHandler(t *testing.T) {
 		})
 	}
 }
+
+// TestSlowInjectorHandlerAfter tests that SlowInjector.Handler waits before or after the next
+// handler.
+func TestSlowInjectorHandlerAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []SlowInjectorOption
+		want        []string
+		wantDelay   time.Duration
+	}{
+		{
+			name:        "before",
+			giveOptions: nil,
+			want:        []string{"slow", "handler"},
+			wantDelay:   time.Second,
+		},
+		{
+			name:        "after",
+			giveOptions: []SlowInjectorOption{WithSlowAfterHandler()},
+			want:        []string{"handler", "slow"},
+			wantDelay:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			reporter := newTestDelayReporter()
+			opts := append([]SlowInjectorOption{WithSlowFunc(func(time.Duration) {
+				got = append(got, "slow")
+			}), WithReporter(reporter)}, tt.giveOptions...)
+
+			si, err := NewSlowInjector(time.Second, opts...)
+			assert.NoError(t, err)
+
+			h := si.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, "handler")
+				assert.Equal(t, tt.wantDelay, InjectedDelay(r))
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, time.Second, <-reporter.delays)
+		})
+	}
+}
+
+// TestSlowInjectorString tests SlowInjector.String.
+func TestSlowInjectorString(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewSlowInjector(time.Second, WithName("custom"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "custom(1s)", si.String())
+
+	si, err = NewSlowInjector(time.Second, WithSlowAfterHandler())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "SlowInjector(1s, after handler)", si.String())
+}
@@ -0,0 +1,151 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultStaleAge is how old a StaleCacheInjector makes responses look by default.
+	defaultStaleAge = 24 * time.Hour
+	// defaultStaleETag is the ETag a StaleCacheInjector sets by default.
+	defaultStaleETag = `"stale"`
+)
+
+// StaleCacheInjector rewrites the caching headers of the response so that it looks stale and can
+// answer conditional requests with an incorrect 304 Not Modified.
+type StaleCacheInjector struct {
+	age         time.Duration
+	etag        string
+	notModified bool
+	nowF        func() time.Time
+	reporter    Reporter
+	name        string
+}
+
+// StaleCacheInjectorOption configures a StaleCacheInjector.
+type StaleCacheInjectorOption interface {
+	applyStaleCacheInjector(i *StaleCacheInjector) error
+}
+
+type staleAgeOption time.Duration
+
+func (o staleAgeOption) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithStaleAge", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	i.age = time.Duration(o)
+	return nil
+}
+
+// WithStaleAge sets how old the response looks. The Date header is set to age ago, the Age header to
+// age, and the Last-Modified header to age before the Date. Default 24 hours.
+func WithStaleAge(age time.Duration) StaleCacheInjectorOption {
+	return staleAgeOption(age)
+}
+
+type staleETagOption string
+
+func (o staleETagOption) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	i.etag = string(o)
+	return nil
+}
+
+// WithStaleETag sets the ETag header of the response. Default `"stale"`.
+func WithStaleETag(etag string) StaleCacheInjectorOption {
+	return staleETagOption(etag)
+}
+
+type staleNotModifiedOption struct{}
+
+func (o staleNotModifiedOption) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	i.notModified = true
+	return nil
+}
+
+// WithStaleNotModified answers every conditional request, one with an If-None-Match or
+// If-Modified-Since header, with 304 Not Modified without continuing the request, whether or not
+// the client's cached copy is current.
+func WithStaleNotModified() StaleCacheInjectorOption {
+	return staleNotModifiedOption{}
+}
+
+func (o nowFuncOption) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	i.nowF = o
+	return nil
+}
+
+func (o reporterOption) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	i.reporter = o.reporter
+	return nil
+}
+
+func (o nameOption) applyStaleCacheInjector(i *StaleCacheInjector) error {
+	i.name = string(o)
+	return nil
+}
+
+// NewStaleCacheInjector returns a StaleCacheInjector.
+func NewStaleCacheInjector(opts ...StaleCacheInjectorOption) (*StaleCacheInjector, error) {
+	// set defaults
+	si := &StaleCacheInjector{
+		age:      defaultStaleAge,
+		etag:     defaultStaleETag,
+		nowF:     time.Now,
+		reporter: NewNoopReporter(),
+		name:     reflect.TypeOf(StaleCacheInjector{}).Name(),
+	}
+
+	// apply options
+	err := applyOptions(opts, StaleCacheInjectorOption.applyStaleCacheInjector, si)
+	if err != nil {
+		return nil, err
+	}
+
+	return si, nil
+}
+
+// Handler continues the request and rewrites the Date, Age, ETag, and Last-Modified headers of the
+// response just before they are written, replacing any set by next. With WithStaleNotModified
+// conditional requests are answered with 304 Not Modified and the rewritten headers instead. Use
+// the StaleCacheInjector to test client cache validation against a misbehaving origin.
+func (i *StaleCacheInjector) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		go report(i.reporter, i.name, StateStarted)
+
+		if i.notModified && (r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "") {
+			i.setHeaders(w.Header())
+			w.WriteHeader(http.StatusNotModified)
+			go report(i.reporter, i.name, StateFinished)
+			return
+		}
+
+		go report(i.reporter, i.name, StateFinished)
+
+		rw := &ResponseRecorderWriter{w: w, onWriteHeader: i.setHeaders}
+		next.ServeHTTP(rw, r)
+
+		// write the header for next if it wrote nothing, so that it is rewritten
+		if !rw.WroteHeader() && !rw.Hijacked() {
+			rw.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+// setHeaders sets the stale caching headers in h.
+func (i *StaleCacheInjector) setHeaders(h http.Header) {
+	date := i.nowF().Add(-i.age).UTC()
+
+	h.Set("Date", date.Format(http.TimeFormat))
+	h.Set("Age", strconv.Itoa(int(i.age/time.Second)))
+	h.Set("Last-Modified", date.Add(-i.age).Format(http.TimeFormat))
+	h.Set("ETag", i.etag)
+}
+
+// String describes the StaleCacheInjector, such as "StaleCacheInjector(24h0m0s)".
+func (i *StaleCacheInjector) String() string {
+	return fmt.Sprintf("%s(%s)", i.name, i.age)
+}
@@ -0,0 +1,189 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewStaleCacheInjector tests NewStaleCacheInjector.
+func TestNewStaleCacheInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []StaleCacheInjectorOption
+		want        *StaleCacheInjector
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &StaleCacheInjector{
+				age:      24 * time.Hour,
+				etag:     `"stale"`,
+				reporter: NewNoopReporter(),
+				name:     "StaleCacheInjector",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []StaleCacheInjectorOption{
+				WithStaleAge(time.Hour),
+				WithStaleETag(`"bogus"`),
+				WithStaleNotModified(),
+				WithNowFunc(time.Now),
+				WithReporter(newTestReporter()),
+				WithName("custom"),
+			},
+			want: &StaleCacheInjector{
+				age:         time.Hour,
+				etag:        `"bogus"`,
+				notModified: true,
+				reporter:    newTestReporter(),
+				name:        "custom",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid age",
+			giveOptions: []StaleCacheInjectorOption{
+				WithStaleAge(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithStaleAge", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name: "option error",
+			giveOptions: []StaleCacheInjectorOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			si, err := NewStaleCacheInjector(tt.giveOptions...)
+
+			// Function equality cannot be determined so set to nil before comparing.
+			if si != nil {
+				si.nowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, si)
+		})
+	}
+}
+
+// TestStaleCacheInjectorHandler tests StaleCacheInjector.Handler.
+func TestStaleCacheInjectorHandler(t *testing.T) {
+	t.Parallel()
+
+	now := func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	writeBody := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"fresh"`)
+		w.Header().Set("Date", "fresh")
+		http.Error(w, testHandlerBody, testHandlerCode)
+	})
+	writeNothing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name        string
+		giveOptions []StaleCacheInjectorOption
+		giveHandler http.Handler
+		giveHeader  http.Header
+		wantCode    int
+		wantHeader  http.Header
+	}{
+		{
+			name:        "rewrite",
+			giveOptions: nil,
+			giveHandler: writeBody,
+			wantCode:    testHandlerCode,
+			wantHeader: http.Header{
+				"Date":          {"Mon, 01 Jan 2024 03:04:05 GMT"},
+				"Age":           {"86400"},
+				"Last-Modified": {"Sun, 31 Dec 2023 03:04:05 GMT"},
+				"Etag":          {`"stale"`},
+			},
+		},
+		{
+			name:        "rewrite when next writes nothing",
+			giveOptions: []StaleCacheInjectorOption{WithStaleAge(time.Hour), WithStaleETag(`"bogus"`)},
+			giveHandler: writeNothing,
+			wantCode:    http.StatusOK,
+			wantHeader: http.Header{
+				"Date":          {"Tue, 02 Jan 2024 02:04:05 GMT"},
+				"Age":           {"3600"},
+				"Last-Modified": {"Tue, 02 Jan 2024 01:04:05 GMT"},
+				"Etag":          {`"bogus"`},
+			},
+		},
+		{
+			name:        "not modified",
+			giveOptions: []StaleCacheInjectorOption{WithStaleNotModified()},
+			giveHandler: writeBody,
+			giveHeader:  http.Header{"If-None-Match": {`"fresh"`}},
+			wantCode:    http.StatusNotModified,
+			wantHeader: http.Header{
+				"Date":          {"Mon, 01 Jan 2024 03:04:05 GMT"},
+				"Age":           {"86400"},
+				"Last-Modified": {"Sun, 31 Dec 2023 03:04:05 GMT"},
+				"Etag":          {`"stale"`},
+			},
+		},
+		{
+			name:        "not modified unconditional",
+			giveOptions: []StaleCacheInjectorOption{WithStaleNotModified()},
+			giveHandler: writeBody,
+			wantCode:    testHandlerCode,
+			wantHeader: http.Header{
+				"Date":          {"Mon, 01 Jan 2024 03:04:05 GMT"},
+				"Age":           {"86400"},
+				"Last-Modified": {"Sun, 31 Dec 2023 03:04:05 GMT"},
+				"Etag":          {`"stale"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			si, err := NewStaleCacheInjector(append(tt.giveOptions, WithNowFunc(now))...)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, vals := range tt.giveHeader {
+				req.Header[key] = vals
+			}
+
+			rr := httptest.NewRecorder()
+			si.Handler(tt.giveHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			for key := range tt.wantHeader {
+				assert.Equal(t, tt.wantHeader.Get(key), rr.Header().Get(key), key)
+			}
+		})
+	}
+}
+
+// TestStaleCacheInjectorString tests StaleCacheInjector.String.
+func TestStaleCacheInjectorString(t *testing.T) {
+	t.Parallel()
+
+	si, err := NewStaleCacheInjector(WithStaleAge(time.Hour))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "StaleCacheInjector(1h0m0s)", si.String())
+}
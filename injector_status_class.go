@@ -0,0 +1,67 @@
+package fault
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// statusClassSize is the number of status codes in a class, such as the 5xx server errors.
+const statusClassSize = 100
+
+type errorInjectorOptionsOption []ErrorInjectorOption
+
+func (o errorInjectorOptionsOption) applyRandomInjector(i *RandomInjector) error {
+	return &OptionError{Option: "WithErrorInjectorOptions", Value: []ErrorInjectorOption(o), Err: ErrUnsupported}
+}
+
+// WithErrorInjectorOptions passes opts to NewErrorInjector for every ErrorInjector created by
+// NewClientErrorInjector and NewServerErrorInjector, such as WithReporter to report which status
+// code was returned. It is not supported by NewRandomInjector.
+func WithErrorInjectorOptions(opts ...ErrorInjectorOption) RandomInjectorOption {
+	return errorInjectorOptionsOption(opts)
+}
+
+// NewClientErrorInjector returns a RandomInjector that responds with a 4xx status code chosen from
+// every code that has status text, such as 400, 404, or 429. Use it for quick smoke tests of how
+// clients handle client errors without listing codes. opts configure the RandomInjector, such as
+// WithSelectionMode(SelectionShuffle) to respond with every code once before repeating, and
+// WithErrorInjectorOptions configures each ErrorInjector.
+func NewClientErrorInjector(opts ...RandomInjectorOption) (*RandomInjector, error) {
+	return newStatusClassInjector(http.StatusBadRequest, opts)
+}
+
+// NewServerErrorInjector returns a RandomInjector that responds with a 5xx status code chosen from
+// every code that has status text, such as 500, 502, or 503. Use it for quick smoke tests of how
+// clients handle server errors without listing codes. opts configure the RandomInjector like
+// NewClientErrorInjector.
+func NewServerErrorInjector(opts ...RandomInjectorOption) (*RandomInjector, error) {
+	return newStatusClassInjector(http.StatusInternalServerError, opts)
+}
+
+// newStatusClassInjector returns a RandomInjector of an ErrorInjector for every status code with
+// status text in the class that starts at first. The status code is appended to the name of each
+// ErrorInjector, such as "ErrorInjector-503", so that reports show which code was returned.
+func newStatusClassInjector(first int, opts []RandomInjectorOption) (*RandomInjector, error) {
+	var errorOpts []ErrorInjectorOption
+	opts = slices.DeleteFunc(slices.Clone(opts), func(opt RandomInjectorOption) bool {
+		eo, ok := opt.(errorInjectorOptionsOption)
+		errorOpts = append(errorOpts, eo...)
+		return ok
+	})
+
+	var is []Injector
+	for code := first; code < first+statusClassSize; code++ {
+		if http.StatusText(code) == "" {
+			continue
+		}
+		ei, err := NewErrorInjector(code, errorOpts...)
+		if err != nil {
+			return nil, err
+		}
+		ei.name += "-" + strconv.Itoa(code)
+		is = append(is, ei)
+	}
+
+	return NewRandomInjector(is, opts...)
+}
@@ -0,0 +1,97 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewStatusClassInjector tests NewClientErrorInjector and NewServerErrorInjector.
+func TestNewStatusClassInjector(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveNew     func(opts ...RandomInjectorOption) (*RandomInjector, error)
+		giveOptions []RandomInjectorOption
+		wantCodes   []int
+		wantErr     error
+	}{
+		{
+			name:        "client errors",
+			giveNew:     NewClientErrorInjector,
+			giveOptions: []RandomInjectorOption{WithSelectionMode(SelectionRoundRobin)},
+			wantCodes: []int{
+				400, 401, 402, 403, 404, 405, 406, 407, 408, 409, 410, 411, 412, 413, 414, 415,
+				416, 417, 418, 421, 422, 423, 424, 425, 426, 428, 429, 431, 451,
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "server errors",
+			giveNew:     NewServerErrorInjector,
+			giveOptions: []RandomInjectorOption{WithSelectionMode(SelectionRoundRobin)},
+			wantCodes:   []int{500, 501, 502, 503, 504, 505, 506, 507, 508, 510, 511},
+			wantErr:     nil,
+		},
+		{
+			name:        "option error",
+			giveNew:     NewServerErrorInjector,
+			giveOptions: []RandomInjectorOption{withError()},
+			wantCodes:   nil,
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ri, err := tt.giveNew(tt.giveOptions...)
+			assert.Equal(t, tt.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			f, err := NewFault(ri, WithEnabled(true), WithParticipation(1.0))
+			assert.NoError(t, err)
+
+			codes := make([]int, 0, len(tt.wantCodes))
+			for range tt.wantCodes {
+				rr := testRequest(t, f)
+				assert.Equal(t, http.StatusText(rr.Code)+"\n", rr.Body.String())
+				codes = append(codes, rr.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+// TestNewStatusClassInjectorErrorInjectorOptions tests NewServerErrorInjector with
+// WithErrorInjectorOptions.
+func TestNewStatusClassInjectorErrorInjectorOptions(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 10)}
+	ri, err := NewServerErrorInjector(
+		WithSelectionMode(SelectionRoundRobin),
+		WithErrorInjectorOptions(WithReporter(rep), WithName("custom")),
+	)
+	assert.NoError(t, err)
+
+	f, err := NewFault(ri, WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.ElementsMatch(t, []string{"custom-500 started", "custom-500 finished"}, []string{<-rep.states, <-rep.states})
+
+	ri, err = NewClientErrorInjector(WithErrorInjectorOptions(withError()))
+	assert.Nil(t, ri)
+	assert.Equal(t, errErrorOption, err)
+
+	ri, err = NewRandomInjector([]Injector{newTestInjector500s()}, WithErrorInjectorOptions())
+	assert.Nil(t, ri)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
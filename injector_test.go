@@ -0,0 +1,187 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInjectorsPassthrough tests that every Injector that continues a request passes a
+// ResponseWriter that supports http.Flusher, http.Hijacker, and http.Pusher to the next handler
+// when the original ResponseWriter does.
+func TestInjectorsPassthrough(t *testing.T) {
+	t.Parallel()
+
+	noSleep := WithSlowFunc(func(time.Duration) {})
+	slow, err := NewSlowInjector(time.Second, noSleep)
+	assert.NoError(t, err)
+
+	newInjector := func(i Injector, err error) Injector {
+		assert.NoError(t, err)
+		return i
+	}
+
+	tests := []struct {
+		name        string
+		give        Injector
+		wantPusher  bool
+		wantFlushed bool
+	}{
+		{
+			name:        "BandwidthInjector",
+			give:        newInjector(NewBandwidthInjector(1024, noSleep)),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "ChainInjector",
+			give:        newInjector(NewChainInjector([]Injector{slow})),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "CharsetInjector",
+			give:        newInjector(NewCharsetInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "ConditionalInjector",
+			give:        newInjector(NewConditionalInjector(func(r *http.Request) bool { return true }, slow, nil)),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "ConnectionCloseInjector",
+			give:        newInjector(NewConnectionCloseInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "CPUInjector",
+			give:        newInjector(NewCPUInjector(time.Millisecond)),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "DoubleWriteHeaderInjector",
+			give:        newInjector(NewDoubleWriteHeaderInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "DowngradeInjector",
+			give:        newInjector(NewDowngradeInjector()),
+			wantPusher:  false,
+			wantFlushed: true,
+		},
+		{
+			name:        "FlappingInjector",
+			give:        newInjector(NewFlappingInjector(slow, time.Hour, time.Hour)),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "LoadLatencyInjector",
+			give:        newInjector(NewLoadLatencyInjector(func(int) time.Duration { return 0 })),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "MalformedHeaderInjector",
+			give:        newInjector(NewMalformedHeaderInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "OutageInjector",
+			give:        newInjector(NewOutageInjector(time.Hour, WithOutageInjector(slow))),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "PushInjector",
+			give:        newInjector(NewPushInjector(WithPushDelay(time.Second), noSleep)),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "RandomInjector",
+			give:        newInjector(NewRandomInjector([]Injector{slow})),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "RequestBodyInjector",
+			give:        newInjector(NewRequestBodyInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "RequestHeaderInjector",
+			give:        newInjector(NewRequestHeaderInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			// flushes are ignored because the response is buffered
+			name:        "ResponseInjector",
+			give:        newInjector(NewResponseInjector(slow, func(int, http.Header) bool { return true })),
+			wantPusher:  true,
+			wantFlushed: false,
+		},
+		{
+			name:        "SequenceInjector",
+			give:        newInjector(NewSequenceInjector([]Injector{slow})),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "SlicerInjector",
+			give:        newInjector(NewSlicerInjector(8, WithSliceDelay(time.Second), noSleep)),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "SlowInjector",
+			give:        slow,
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+		{
+			name:        "StaleCacheInjector",
+			give:        newInjector(NewStaleCacheInjector()),
+			wantPusher:  true,
+			wantFlushed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := tt.give.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p, ok := w.(http.Pusher)
+				assert.Equal(t, tt.wantPusher, ok)
+				if ok {
+					assert.ErrorIs(t, p.Push("/style.css", nil), errTestPush)
+				}
+
+				w.(http.Flusher).Flush()
+
+				_, _, err := w.(http.Hijacker).Hijack()
+				assert.NoError(t, err)
+			}))
+
+			fw := &testFullWriter{ResponseRecorder: httptest.NewRecorder()}
+			h.ServeHTTP(fw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.True(t, fw.hijacked)
+			assert.Equal(t, tt.wantPusher, fw.pushed == "/style.css")
+			assert.Equal(t, tt.wantFlushed, fw.Flushed)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package fault
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// InjectionContext describes the decision that led a Fault to run an InjectorV2, along with the
+// plumbing an InjectorV2 needs to report and continue the request.
+type InjectionContext struct {
+	// Fault is the name of the Fault running the InjectorV2.
+	Fault string
+	// Trace records how the Fault decided to inject. Its Reason is always ReasonInjected.
+	Trace Trace
+	// InjectionID, if the Fault has WithInjectionID, is the correlation ID of the injection.
+	InjectionID string
+	// Reporter is the Reporter of the Fault, set with WithReporter.
+	Reporter Reporter
+	// Next is the handler that continues the request.
+	Next http.Handler
+}
+
+// Report sends state to the Reporter under the name of the Fault without blocking.
+func (ic InjectionContext) Report(state InjectorState) {
+	go report(ic.Reporter, ic.Fault, state)
+}
+
+// InjectorV2 is an optional interface for Injectors that need to know how a Fault decided to run
+// them. When the Injector passed to NewFault also implements InjectorV2, the Fault calls
+// ServeInjection instead of Handler. Use AdaptInjectorV2 to pass an InjectorV2 to NewFault.
+type InjectorV2 interface {
+	ServeInjection(ic InjectionContext, w http.ResponseWriter, r *http.Request)
+}
+
+// InjectorV2Func is a function that implements InjectorV2.
+type InjectorV2Func func(ic InjectionContext, w http.ResponseWriter, r *http.Request)
+
+// ServeInjection calls fn(ic, w, r).
+func (fn InjectorV2Func) ServeInjection(ic InjectionContext, w http.ResponseWriter, r *http.Request) {
+	fn(ic, w, r)
+}
+
+// AdaptInjector returns an InjectorV2 that runs i, ignoring everything in the InjectionContext
+// except Next.
+func AdaptInjector(i Injector) InjectorV2 {
+	return InjectorV2Func(func(ic InjectionContext, w http.ResponseWriter, r *http.Request) {
+		i.Handler(ic.Next).ServeHTTP(w, r)
+	})
+}
+
+// adaptedInjectorV2 is an Injector that runs an InjectorV2.
+type adaptedInjectorV2 struct {
+	InjectorV2
+}
+
+// AdaptInjectorV2 returns an Injector that runs i. A Fault runs i with a full InjectionContext.
+// Anywhere else, such as within a ChainInjector, i receives an InjectionContext with only a name,
+// a Trace without a participation roll, a NoopReporter, and Next.
+func AdaptInjectorV2(i InjectorV2) Injector {
+	return adaptedInjectorV2{i}
+}
+
+// Handler runs the InjectorV2 with a minimal InjectionContext.
+func (i adaptedInjectorV2) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := typeName(i.InjectorV2)
+		i.ServeInjection(InjectionContext{
+			Fault:    name,
+			Trace:    Trace{Fault: name, Reason: ReasonInjected},
+			Reporter: NewNoopReporter(),
+			Next:     next,
+		}, w, r)
+	})
+}
+
+// String describes the InjectorV2, see injectorString.
+func (i adaptedInjectorV2) String() string {
+	if s, ok := i.InjectorV2.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return typeName(i.InjectorV2)
+}
+
+// inject runs injected, the composed handler of the Injector of the Fault, or the InjectorV2 of the
+// Fault if it has one.
+func (f *Fault) inject(w http.ResponseWriter, r *http.Request, next, injected http.Handler, t Trace) {
+	if f.injectorV2 == nil {
+		injected.ServeHTTP(w, r)
+		return
+	}
+
+	f.injectorV2.ServeInjection(InjectionContext{
+		Fault:       f.name,
+		Trace:       t,
+		InjectionID: f.InjectionID(r.Context()),
+		Reporter:    f.reporter,
+		Next:        next,
+	}, w, r)
+}
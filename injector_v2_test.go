@@ -0,0 +1,89 @@
+package fault
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testChanReporter sends every state it receives to a channel.
+type testChanReporter struct {
+	states chan string
+}
+
+// Report sends name and state to r.states.
+func (r *testChanReporter) Report(name string, state InjectorState) {
+	r.states <- name + " " + state.String()
+}
+
+// testInjectorV2 sets a header with the name of the Fault and its Trace and continues.
+type testInjectorV2 struct{}
+
+// ServeInjection sets a header from ic and continues the request.
+func (i *testInjectorV2) ServeInjection(ic InjectionContext, w http.ResponseWriter, r *http.Request) {
+	ic.Report(StateStarted)
+	w.Header().Set("X-Fault", ic.Fault+" "+ic.Trace.String())
+	ic.Next.ServeHTTP(w, r)
+}
+
+// String describes the testInjectorV2.
+func (i *testInjectorV2) String() string {
+	return "testInjectorV2(v2)"
+}
+
+// TestInjectorV2 tests that a Fault runs an InjectorV2 with an InjectionContext.
+func TestInjectorV2(t *testing.T) {
+	t.Parallel()
+
+	rep := &testChanReporter{states: make(chan string, 1)}
+	f, err := NewFault(AdaptInjectorV2(&testInjectorV2{}),
+		WithEnabled(true),
+		WithParticipation(0.5),
+		WithRandFloat32Func(func() float32 { return 0.25 }),
+		WithReporter(rep),
+	)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+
+	assert.Equal(t, testHandlerCode, rr.Code)
+	assert.Equal(t, "testInjectorV2 testInjectorV2: injected (roll 0.25 < 0.50)", rr.Header().Get("X-Fault"))
+	assert.Equal(t, "testInjectorV2 started", <-rep.states)
+	assert.Equal(t, "testInjectorV2(v2) @ 50%", f.String())
+}
+
+// TestAdaptInjectorV2 tests an InjectorV2 adapted with AdaptInjectorV2 outside of a Fault.
+func TestAdaptInjectorV2(t *testing.T) {
+	t.Parallel()
+
+	ci, err := NewChainInjector([]Injector{AdaptInjectorV2(&testInjectorV2{})})
+	assert.NoError(t, err)
+
+	f, err := NewFault(ci, WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+
+	assert.Equal(t, "testInjectorV2 testInjectorV2: injected", rr.Header().Get("X-Fault"))
+	assert.Equal(t, "ChainInjector[testInjectorV2(v2)]", ci.String())
+
+}
+
+// TestAdaptInjector tests that AdaptInjector runs an Injector as an InjectorV2.
+func TestAdaptInjector(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(AdaptInjectorV2(AdaptInjector(newTestInjectorOneOK())),
+		WithEnabled(true),
+		WithParticipation(1.0),
+	)
+	assert.NoError(t, err)
+
+	rr := testRequest(t, f)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "one"+testHandlerBody, strings.TrimSpace(rr.Body.String()))
+	assert.Equal(t, "InjectorV2Func @ 100%", f.String())
+}
@@ -0,0 +1,96 @@
+package fault
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidInstances when the number of instances is less than 1, or bad instances are empty or
+	// not between 0 and the number of instances.
+	ErrInvalidInstances = errors.New("bad instances must be between 0 and the number of instances")
+)
+
+type badInstancesOption struct {
+	n   int
+	bad []int
+}
+
+func (o badInstancesOption) applyFault(f *Fault) error {
+	if o.n < 1 || len(o.bad) == 0 {
+		return &OptionError{Option: "WithBadInstances", Value: o.n, Err: ErrInvalidInstances}
+	}
+
+	bad := make([]bool, o.n)
+	for _, i := range o.bad {
+		if i < 0 || i >= o.n {
+			return &OptionError{Option: "WithBadInstances", Value: i, Err: ErrInvalidInstances}
+		}
+		bad[i] = true
+	}
+
+	f.badInstances = bad
+	return nil
+}
+
+// WithBadInstances simulates n virtual backend instances of which the instances numbered bad, from 0
+// to n-1, are bad, such as WithBadInstances(10, 3) for "1 of 10 replicas is bad". Each request is
+// consistently mapped to one instance by hashing the key from WithInstanceKeyFunc, and the Fault
+// participates in every request mapped to a bad instance and in no others, instead of using its own
+// participation or deterministic mode. Unlike random participation, the same clients keep failing
+// while others are unaffected, as they are when a load balancer routes them to a bad replica. Every
+// other check, such as allowlists and Guards, still applies.
+func WithBadInstances(n int, bad ...int) Option {
+	return badInstancesOption{n: n, bad: bad}
+}
+
+type instanceKeyFuncOption func(r *http.Request) string
+
+func (o instanceKeyFuncOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithInstanceKeyFunc", Value: nil, Err: ErrNilFunc}
+	}
+	f.instanceKeyF = o
+	return nil
+}
+
+// WithInstanceKeyFunc sets the function that returns the key that WithBadInstances hashes to map a
+// request to an instance. Default RemoteAddrKey, which maps each client connection to one instance
+// like a load balancer with keep-alive connections. Return a client or session ID instead to keep a
+// client on one instance across connections.
+func WithInstanceKeyFunc(f func(r *http.Request) string) Option {
+	return instanceKeyFuncOption(f)
+}
+
+// RemoteAddrKey returns the network address of the client of r, see http.Request.RemoteAddr.
+func RemoteAddrKey(r *http.Request) string {
+	return r.RemoteAddr
+}
+
+// instance returns the number of the virtual instance that r is mapped to.
+func (f *Fault) instance(r *http.Request) int {
+	return int(hashString(f.instanceKeyF(r)) % uint64(len(f.badInstances)))
+}
+
+// instancesString describes the bad instances of f, such as "instances 3,7 of 10".
+func (f *Fault) instancesString() string {
+	var bad []string
+	for i, b := range f.badInstances {
+		if b {
+			bad = append(bad, strconv.Itoa(i))
+		}
+	}
+
+	s := "instance "
+	if len(bad) > 1 {
+		s = "instances "
+	}
+	return s + strings.Join(bad, ",") + " of " + strconv.Itoa(len(f.badInstances))
+}
+
+// hashString returns a hash of s that spreads every bit of s over the result.
+func hashString(s string) uint64 {
+	return mix64(fnv64a(s))
+}
@@ -0,0 +1,102 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithBadInstances tests WithBadInstances and WithInstanceKeyFunc.
+func TestWithBadInstances(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		wantString  string
+		wantErr     error
+	}{
+		{
+			name:        "one bad instance",
+			giveOptions: []Option{WithBadInstances(10, 3)},
+			wantString:  "instance 3 of 10",
+			wantErr:     nil,
+		},
+		{
+			name:        "many bad instances",
+			giveOptions: []Option{WithBadInstances(10, 7, 3, 3), WithInstanceKeyFunc(testBucketingKey)},
+			wantString:  "instances 3,7 of 10",
+			wantErr:     nil,
+		},
+		{
+			name:        "no instances",
+			giveOptions: []Option{WithBadInstances(0, 0)},
+			wantErr:     &OptionError{Option: "WithBadInstances", Value: 0, Err: ErrInvalidInstances},
+		},
+		{
+			name:        "no bad instances",
+			giveOptions: []Option{WithBadInstances(10)},
+			wantErr:     &OptionError{Option: "WithBadInstances", Value: 10, Err: ErrInvalidInstances},
+		},
+		{
+			name:        "negative bad instance",
+			giveOptions: []Option{WithBadInstances(10, -1)},
+			wantErr:     &OptionError{Option: "WithBadInstances", Value: -1, Err: ErrInvalidInstances},
+		},
+		{
+			name:        "bad instance out of range",
+			giveOptions: []Option{WithBadInstances(10, 10)},
+			wantErr:     &OptionError{Option: "WithBadInstances", Value: 10, Err: ErrInvalidInstances},
+		},
+		{
+			name:        "nil key function",
+			giveOptions: []Option{WithInstanceKeyFunc(nil)},
+			wantErr:     &OptionError{Option: "WithInstanceKeyFunc", Value: nil, Err: ErrNilFunc},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(newTestInjectorNoop(), tt.giveOptions...)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Contains(t, f.String(), " @ "+tt.wantString)
+			}
+		})
+	}
+}
+
+// TestBadInstancesHandler tests that a Fault with WithBadInstances injects into every request from
+// the clients mapped to a bad instance and no others.
+func TestBadInstancesHandler(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjector500s(), WithEnabled(true), WithBadInstances(10, 3))
+	assert.NoError(t, err)
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(addr string) bool {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = addr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		return rr.Code == http.StatusInternalServerError
+	}
+
+	var bad int
+	for i := range 2000 {
+		addr := "10.0.0.1:" + strconv.Itoa(10000+i)
+		injected := serve(addr)
+		assert.Equal(t, injected, serve(addr), "clients stay on one instance")
+		if injected {
+			bad++
+		}
+	}
+
+	assert.InDelta(t, 200, bad, 50)
+}
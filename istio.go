@@ -0,0 +1,138 @@
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// istioFault is an Istio HTTPFaultInjection.
+type istioFault struct {
+	Delay *istioDelay `json:"delay"`
+	Abort *istioAbort `json:"abort"`
+}
+
+// istioDelay is the delay of an Istio HTTPFaultInjection.
+type istioDelay struct {
+	FixedDelay       string           `json:"fixedDelay"`
+	ExponentialDelay string           `json:"exponentialDelay"`
+	Percentage       *istioPercentage `json:"percentage"`
+	Percent          *int             `json:"percent"`
+}
+
+// istioAbort is the abort of an Istio HTTPFaultInjection.
+type istioAbort struct {
+	HTTPStatus int              `json:"httpStatus"`
+	GRPCStatus string           `json:"grpcStatus"`
+	HTTP2Error string           `json:"http2Error"`
+	Percentage *istioPercentage `json:"percentage"`
+}
+
+// istioPercentage is an Istio Percent, from 0 to 100.
+type istioPercentage struct {
+	Value float64 `json:"value"`
+}
+
+// istioFullPercent is the Istio percentage of all requests.
+const istioFullPercent = 100
+
+// ParseIstioFault returns a Config for the delay and a Config for the abort of an Istio
+// HTTPFaultInjection encoded as YAML or JSON, such as the fault of a VirtualService route. Like the
+// Envoy fault filter, the delay is injected before the abort, so create a Fault from each Config and
+// combine them with Chain in the order they are returned.
+//
+// The delay becomes a SlowInjector and the abort becomes an ErrorInjector. Each Config is enabled
+// and its participation is the Istio percentage divided by 100, or 1.0 if no percentage is set.
+// Exponential delays, gRPC statuses, and HTTP/2 errors return an error that wraps ErrUnsupported.
+func ParseIstioFault(data []byte) ([]Config, error) {
+	// JSON is valid YAML, so decode YAML and then decode the result as JSON with the field names
+	// that the Istio API uses for both
+	var v any
+	err := yaml.Unmarshal(data, &v)
+	if err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fi istioFault
+	err = json.Unmarshal(data, &fi)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfgs []Config
+
+	if fi.Delay != nil {
+		cfg, err := fi.Delay.config()
+		if err != nil {
+			return nil, err
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	if fi.Abort != nil {
+		cfg, err := fi.Abort.config()
+		if err != nil {
+			return nil, err
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("delay or abort: %w", ErrNilInjector)
+	}
+
+	return cfgs, nil
+}
+
+// config returns the Config for the delay.
+func (d *istioDelay) config() (Config, error) {
+	if d.ExponentialDelay != "" {
+		return Config{}, fmt.Errorf("exponentialDelay: %w", ErrUnsupported)
+	}
+
+	dur, err := time.ParseDuration(d.FixedDelay)
+	if err != nil || dur <= 0 {
+		return Config{}, fmt.Errorf("fixedDelay %s: %w", d.FixedDelay, ErrInvalidDuration)
+	}
+
+	percent := float64(istioFullPercent)
+	switch {
+	case d.Percentage != nil:
+		percent = d.Percentage.Value
+	case d.Percent != nil:
+		percent = float64(*d.Percent)
+	}
+
+	return Config{
+		Enabled:       true,
+		Participation: float32(percent / istioFullPercent),
+		Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(dur)},
+	}, nil
+}
+
+// config returns the Config for the abort.
+func (a *istioAbort) config() (Config, error) {
+	switch {
+	case a.GRPCStatus != "":
+		return Config{}, fmt.Errorf("grpcStatus: %w", ErrUnsupported)
+	case a.HTTP2Error != "":
+		return Config{}, fmt.Errorf("http2Error: %w", ErrUnsupported)
+	}
+
+	percent := float64(istioFullPercent)
+	if a.Percentage != nil {
+		percent = a.Percentage.Value
+	}
+
+	return Config{
+		Enabled:       true,
+		Participation: float32(percent / istioFullPercent),
+		Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: a.HTTPStatus},
+	}, nil
+}
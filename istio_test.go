@@ -0,0 +1,160 @@
+package fault
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseIstioFault tests ParseIstioFault.
+func TestParseIstioFault(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		give     string
+		wantCfgs []Config
+		wantErr  error
+	}{
+		{
+			name: "delay and abort",
+			give: `{"delay":{"percentage":{"value":0.1},"fixedDelay":"5s"},
+				"abort":{"percentage":{"value":50},"httpStatus":503}}`,
+			wantCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 0.001,
+					Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(5 * time.Second)},
+				},
+				{
+					Enabled:       true,
+					Participation: 0.5,
+					Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: http.StatusServiceUnavailable},
+				},
+			},
+		},
+		{
+			name: "yaml",
+			give: `
+delay:
+  percentage:
+    value: 0.1
+  fixedDelay: 5s
+abort:
+  percentage:
+    value: 50
+  httpStatus: 503
+`,
+			wantCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 0.001,
+					Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(5 * time.Second)},
+				},
+				{
+					Enabled:       true,
+					Participation: 0.5,
+					Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: http.StatusServiceUnavailable},
+				},
+			},
+		},
+		{
+			name: "deprecated percent",
+			give: `{"delay":{"percent":20,"fixedDelay":"100ms"}}`,
+			wantCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 0.2,
+					Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(100 * time.Millisecond)},
+				},
+			},
+		},
+		{
+			name: "no percentage",
+			give: `{"abort":{"httpStatus":400}}`,
+			wantCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 1.0,
+					Injector:      &InjectorConfig{Type: InjectorTypeError, StatusCode: http.StatusBadRequest},
+				},
+			},
+		},
+		{
+			name:    "empty",
+			give:    `{}`,
+			wantErr: ErrNilInjector,
+		},
+		{
+			name:    "exponential delay",
+			give:    `{"delay":{"exponentialDelay":"1s"}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "invalid delay",
+			give:    `{"delay":{"fixedDelay":"0s"}}`,
+			wantErr: ErrInvalidDuration,
+		},
+		{
+			name:    "grpc status",
+			give:    `{"abort":{"grpcStatus":"UNAVAILABLE"}}`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "http2 error",
+			give:    `{"abort":{"http2Error":"REFUSED_STREAM"}}`,
+			wantErr: ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfgs, err := ParseIstioFault([]byte(tt.give))
+			if tt.wantErr != nil {
+				assert.Nil(t, cfgs)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCfgs, cfgs)
+			for _, cfg := range cfgs {
+				assert.Empty(t, Validate(cfg))
+			}
+		})
+	}
+}
+
+// TestParseIstioFaultInvalid tests that ParseIstioFault returns YAML and JSON errors and leaves
+// checking the values to Validate.
+func TestParseIstioFaultInvalid(t *testing.T) {
+	t.Parallel()
+
+	cfgs, err := ParseIstioFault([]byte(`[]`))
+	var typeErr *json.UnmarshalTypeError
+	assert.Nil(t, cfgs)
+	assert.ErrorAs(t, err, &typeErr)
+
+	cfgs, err = ParseIstioFault([]byte(`delay: [`))
+	assert.Nil(t, cfgs)
+	assert.ErrorContains(t, err, "yaml:")
+
+	// YAML values that JSON cannot represent
+	cfgs, err = ParseIstioFault([]byte(`{abort: {percentage: {value: .inf}}}`))
+	var unsupportedErr *json.UnsupportedValueError
+	assert.Nil(t, cfgs)
+	assert.ErrorAs(t, err, &unsupportedErr)
+
+	cfgs, err = ParseIstioFault([]byte(`{"abort":{"percentage":{"value":150},"httpStatus":1}}`))
+	assert.NoError(t, err)
+	assert.Len(t, cfgs, 1)
+	errs := Validate(cfgs[0])
+	assert.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[0], ErrInvalidPercent)
+	assert.ErrorIs(t, errs[1], ErrInvalidHTTPCode)
+}
@@ -0,0 +1,59 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// MessageHandlerFunc processes a single message, such as a record from a Kafka topic or an SQS
+// event. Most consumer libraries accept or can be adapted to this signature.
+type MessageHandlerFunc[M any] func(ctx context.Context, msg M) error
+
+// MessageResultHandlerFunc processes a single message and returns a result, such as the batch item
+// failures returned by an AWS Lambda SQS handler.
+type MessageResultHandlerFunc[M, R any] func(ctx context.Context, msg M) (R, error)
+
+// MessageMiddleware returns message processing middleware that evaluates the Fault before each
+// message. Injectors that continue the request (such as the SlowInjector) delay the message,
+// Injectors that respond (such as the ErrorInjector) return an error wrapping ErrInjected, and
+// Injectors that abort (such as the RejectInjector) drop the message by returning nil without
+// running next.
+func MessageMiddleware[M any](f *Fault) func(next MessageHandlerFunc[M]) MessageHandlerFunc[M] {
+	return func(next MessageHandlerFunc[M]) MessageHandlerFunc[M] {
+		return func(ctx context.Context, msg M) error {
+			var called bool
+
+			err := Do(ctx, f, func(ctx context.Context) error {
+				called = true
+				return next(ctx, msg)
+			})
+
+			if !called && errors.Is(err, http.ErrAbortHandler) {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// MessageResultMiddleware is the same as MessageMiddleware for handlers that return a result. When
+// the message is dropped or an error is injected the zero value of R is returned.
+func MessageResultMiddleware[M, R any](
+	f *Fault,
+) func(next MessageResultHandlerFunc[M, R]) MessageResultHandlerFunc[M, R] {
+	return func(next MessageResultHandlerFunc[M, R]) MessageResultHandlerFunc[M, R] {
+		return func(ctx context.Context, msg M) (R, error) {
+			var result R
+
+			err := MessageMiddleware[M](f)(func(ctx context.Context, msg M) error {
+				var err error
+				result, err = next(ctx, msg)
+				return err
+			})(ctx, msg)
+
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestMessage = errors.New("error from message handler")
+)
+
+// TestMessageMiddleware tests MessageMiddleware.
+func TestMessageMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveEnabled  bool
+		giveErr      error
+		wantCalled   bool
+		wantErr      error
+	}{
+		{
+			name:         "not enabled",
+			giveInjector: newTestInjector500s(),
+			giveEnabled:  false,
+			giveErr:      nil,
+			wantCalled:   true,
+			wantErr:      nil,
+		},
+		{
+			name:         "handler error",
+			giveInjector: newTestInjectorNoop(),
+			giveEnabled:  true,
+			giveErr:      errTestMessage,
+			wantCalled:   true,
+			wantErr:      errTestMessage,
+		},
+		{
+			name:         "error",
+			giveInjector: newTestInjector500s(),
+			giveEnabled:  true,
+			giveErr:      nil,
+			wantCalled:   false,
+			wantErr:      ErrInjected,
+		},
+		{
+			name:         "drop",
+			giveInjector: &testInjectorPanic{value: http.ErrAbortHandler},
+			giveEnabled:  true,
+			giveErr:      nil,
+			wantCalled:   false,
+			wantErr:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(tt.giveInjector,
+				WithEnabled(tt.giveEnabled),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			var called bool
+			handler := MessageMiddleware[string](f)(func(ctx context.Context, msg string) error {
+				called = true
+				assert.Equal(t, tt.name, msg)
+				return tt.giveErr
+			})
+
+			err = handler(context.Background(), tt.name)
+
+			assert.Equal(t, tt.wantCalled, called)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestMessageResultMiddleware tests MessageResultMiddleware.
+func TestMessageResultMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveEnabled  bool
+		wantResult   int
+		wantErr      error
+	}{
+		{
+			name:         "not enabled",
+			giveInjector: newTestInjector500s(),
+			giveEnabled:  false,
+			wantResult:   1,
+			wantErr:      nil,
+		},
+		{
+			name:         "error",
+			giveInjector: newTestInjector500s(),
+			giveEnabled:  true,
+			wantResult:   0,
+			wantErr:      ErrInjected,
+		},
+		{
+			name:         "drop",
+			giveInjector: &testInjectorPanic{value: http.ErrAbortHandler},
+			giveEnabled:  true,
+			wantResult:   0,
+			wantErr:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(tt.giveInjector,
+				WithEnabled(tt.giveEnabled),
+				WithParticipation(1.0),
+			)
+			assert.NoError(t, err)
+
+			handler := MessageResultMiddleware[string, int](f)(func(ctx context.Context, msg string) (int, error) {
+				return 1, nil
+			})
+
+			result, err := handler(context.Background(), tt.name)
+
+			assert.Equal(t, tt.wantResult, result)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package fault
+
+import "net/http"
+
+type patternBlocklistOption []string
+
+func (o patternBlocklistOption) applyFault(f *Fault) error {
+	blocklist := make(map[string]bool, len(o))
+	for _, pattern := range o {
+		blocklist[pattern] = true
+	}
+	f.patternBlocklist = blocklist
+	return nil
+}
+
+// WithPatternBlocklist is a list of route patterns, such as "GET /users/{id}", that the Injector
+// will not run against.
+func WithPatternBlocklist(blocklist []string) Option {
+	return patternBlocklistOption(blocklist)
+}
+
+type patternAllowlistOption []string
+
+func (o patternAllowlistOption) applyFault(f *Fault) error {
+	allowlist := make(map[string]bool, len(o))
+	for _, pattern := range o {
+		allowlist[pattern] = true
+	}
+	f.patternAllowlist = allowlist
+	return nil
+}
+
+// WithPatternAllowlist is, if set, a list of the only route patterns, such as "GET /users/{id}",
+// that the Injector will run against.
+func WithPatternAllowlist(allowlist []string) Option {
+	return patternAllowlistOption(allowlist)
+}
+
+type patternFuncOption func(r *http.Request) string
+
+func (o patternFuncOption) applyFault(f *Fault) error {
+	if o == nil {
+		return &OptionError{Option: "WithPatternFunc", Value: nil, Err: ErrNilFunc}
+	}
+	f.patternF = o
+	return nil
+}
+
+// WithPatternFunc sets the function that returns the route pattern that matched a request. Default
+// ServeMuxPattern. Use this option to integrate with other routers, for example with chi:
+//
+//	fault.WithPatternFunc(func(r *http.Request) string {
+//		return chi.RouteContext(r.Context()).RoutePattern()
+//	})
+func WithPatternFunc(f func(r *http.Request) string) Option {
+	return patternFuncOption(f)
+}
+
+// ServeMuxPattern returns the http.ServeMux pattern that matched the request, exactly as it was
+// registered.
+func ServeMuxPattern(r *http.Request) string {
+	return r.Pattern
+}
+
+// checkPatternLists checks the route pattern of the request against the provided pattern allowlist
+// and blocklist, returning the Reason the request may not proceed or reasonNone if it may.
+func (f *Fault) checkPatternLists(r *http.Request) Reason {
+	if len(f.patternBlocklist) == 0 && len(f.patternAllowlist) == 0 {
+		return reasonNone
+	}
+
+	pattern := f.patternF(r)
+
+	if f.patternBloom.mayContain(pattern) && f.patternBlocklist[pattern] {
+		return ReasonPatternBlocklist
+	}
+
+	if len(f.patternAllowlist) > 0 && !f.patternAllowlist[pattern] {
+		return ReasonPatternAllowlist
+	}
+
+	return reasonNone
+}
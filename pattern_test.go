@@ -0,0 +1,116 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithPatternFunc tests WithPatternFunc.
+func TestWithPatternFunc(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFault(newTestInjectorNoop(),
+		WithPatternFunc(nil),
+	)
+
+	assert.Equal(t, &OptionError{Option: "WithPatternFunc", Value: nil, Err: ErrNilFunc}, err)
+}
+
+// TestFaultPatternLists tests the pattern allowlist and blocklist using an http.ServeMux.
+func TestFaultPatternLists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		givePath    string
+		wantCode    int
+	}{
+		{
+			name:        "no lists",
+			giveOptions: nil,
+			givePath:    "/users/1",
+			wantCode:    http.StatusInternalServerError,
+		},
+		{
+			name: "blocklist",
+			giveOptions: []Option{
+				WithPatternBlocklist([]string{"/users/{id}"}),
+			},
+			givePath: "/users/1",
+			wantCode: testHandlerCode,
+		},
+		{
+			name: "blocklist other",
+			giveOptions: []Option{
+				WithPatternBlocklist([]string{"/users/{id}"}),
+			},
+			givePath: "/other",
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "allowlist",
+			giveOptions: []Option{
+				WithPatternAllowlist([]string{"/users/{id}"}),
+			},
+			givePath: "/users/2",
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "allowlist other",
+			giveOptions: []Option{
+				WithPatternAllowlist([]string{"/users/{id}"}),
+			},
+			givePath: "/other",
+			wantCode: testHandlerCode,
+		},
+		{
+			name: "allowlist and blocklist",
+			giveOptions: []Option{
+				WithPatternBlocklist([]string{"/users/{id}"}),
+				WithPatternAllowlist([]string{"/users/{id}"}),
+			},
+			givePath: "/users/3",
+			wantCode: testHandlerCode,
+		},
+		{
+			name: "custom function",
+			giveOptions: []Option{
+				WithPatternAllowlist([]string{"custom"}),
+				WithPatternFunc(func(*http.Request) string { return "custom" }),
+			},
+			givePath: "/other",
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{
+				WithEnabled(true),
+				WithParticipation(1.0),
+			}, tt.giveOptions...)
+
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			handler := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, testHandlerBody, testHandlerCode)
+			}))
+
+			mux := http.NewServeMux()
+			mux.Handle("/users/{id}", handler)
+			mux.Handle("/other", handler)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.givePath, nil))
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package fault
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	// ErrInvalidRate when a rate is not greater than 0.
+	ErrInvalidRate = errors.New("rate must be greater than 0")
+)
+
+type targetRateOption float64
+
+func (o targetRateOption) applyFault(f *Fault) error {
+	if !(o > 0) || math.IsInf(float64(o), 1) {
+		return &OptionError{Option: "WithTargetRate", Value: float64(o), Err: ErrInvalidRate}
+	}
+	f.targetRate = float64(o)
+	return nil
+}
+
+// WithTargetRate runs the Injector on perSecond requests per second, such as 2 injected faults per
+// second, instead of a percentage of requests. An injection becomes due every 1/perSecond seconds
+// and runs on the next evaluated request, so the effective participation adapts as traffic varies
+// over the day. Injections that became due while there was no traffic are not made up later. Other
+// deterministic modes such as WithEveryNth take priority over WithTargetRate, which takes priority
+// over WithParticipation. perSecond must be greater than 0.
+func WithTargetRate(perSecond float64) Option {
+	return targetRateOption(perSecond)
+}
+
+// participateRate returns true if an injection is due according to f.targetRate.
+func (f *Fault) participateRate() bool {
+	now := f.nowF()
+	interval := time.Duration(float64(time.Second) / f.targetRate)
+
+	f.rateMtx.Lock()
+	defer f.rateMtx.Unlock()
+
+	if now.Before(f.nextInjection) {
+		return false
+	}
+
+	// injections missed while idle are not made up
+	if now.Sub(f.nextInjection) >= interval {
+		f.nextInjection = now
+	}
+	f.nextInjection = f.nextInjection.Add(interval)
+
+	return true
+}
@@ -0,0 +1,60 @@
+package fault
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithTargetRateInvalid tests WithTargetRate with invalid rates.
+func TestWithTargetRateInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, rate := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		f, err := NewFault(newTestInjectorNoop(), WithTargetRate(rate))
+		assert.Nil(t, f)
+		assert.ErrorIs(t, err, ErrInvalidRate)
+
+		var oe *OptionError
+		assert.ErrorAs(t, err, &oe)
+		assert.Equal(t, "WithTargetRate", oe.Option)
+	}
+}
+
+// TestFaultTargetRate tests that a Fault with WithTargetRate injects at the target rate regardless
+// of traffic.
+func TestFaultTargetRate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	f, err := NewFault(newTestInjector500s(),
+		WithEnabled(true),
+		WithTargetRate(2),
+		WithNowFunc(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+
+	// count injections for requests spread evenly over d
+	inject := func(requests int, d time.Duration) int {
+		var injected int
+		for range requests {
+			if testRequest(t, f).Code == http.StatusInternalServerError {
+				injected++
+			}
+			now = now.Add(d / time.Duration(requests))
+		}
+		return injected
+	}
+
+	// low traffic
+	assert.Equal(t, 10, inject(10, 5*time.Second))
+	// high traffic
+	assert.Equal(t, 20, inject(1000, 10*time.Second))
+
+	// injections are not made up after idle periods
+	now = now.Add(time.Hour)
+	assert.Equal(t, 1, inject(10, 10*time.Millisecond))
+}
@@ -0,0 +1,46 @@
+package fault
+
+import (
+	"strconv"
+)
+
+type participationRatioOption struct {
+	k int64
+	n int64
+}
+
+func (o participationRatioOption) applyFault(f *Fault) error {
+	if o.n < 1 || o.k < 0 || o.k > o.n {
+		return &OptionError{Option: "WithParticipationRatio", Value: []int64{o.k, o.n}, Err: ErrInvalidCount}
+	}
+	f.rate.Store(&participationRate{p: float32(o.k) / float32(o.n), k: o.k, n: o.n})
+	return nil
+}
+
+// WithParticipationRatio runs the Injector on k in every n requests on average, such as
+// WithParticipationRatio(1, 100000) for one in one hundred thousand. The ratio is rolled with
+// integers from the random source seeded by WithRandSeed, so very low rates are exact instead of
+// limited by the float32 precision of WithParticipation. WithRandFloat32Func does not apply. It
+// replaces WithParticipation, and WithParticipationFunc and deterministic modes such as WithEveryNth
+// take priority over it. 0 <= k <= n and n must be at least 1.
+func WithParticipationRatio(k, n int64) Option {
+	return participationRatioOption{k: k, n: n}
+}
+
+// participateRatio randomly decides if the Injector should run based on rate.k in rate.n, scaled by
+// the requests in flight if WithBrownout is set, and records the roll in t.
+func (f *Fault) participateRatio(rate participationRate, t *Trace) bool {
+	rn := f.rollN(rate.n)
+
+	k := float64(rate.k) * float64(f.brownoutFactor())
+	t.Rolled = true
+	t.Roll = float32(float64(rn) / float64(rate.n))
+	t.Participation = float32(k / float64(rate.n))
+
+	return float64(rn) < k
+}
+
+// ratioString describes rate.k and rate.n, such as "1 in 100000".
+func (rate participationRate) ratioString() string {
+	return strconv.FormatInt(rate.k, 10) + " in " + strconv.FormatInt(rate.n, 10)
+}
@@ -0,0 +1,164 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithParticipationRatio tests WithParticipationRatio.
+func TestWithParticipationRatio(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		giveK   int64
+		giveN   int64
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			giveK:   1,
+			giveN:   100000,
+			wantErr: nil,
+		},
+		{
+			name:    "never",
+			giveK:   0,
+			giveN:   1,
+			wantErr: nil,
+		},
+		{
+			name:    "zero n",
+			giveK:   0,
+			giveN:   0,
+			wantErr: &OptionError{Option: "WithParticipationRatio", Value: []int64{0, 0}, Err: ErrInvalidCount},
+		},
+		{
+			name:    "negative k",
+			giveK:   -1,
+			giveN:   10,
+			wantErr: &OptionError{Option: "WithParticipationRatio", Value: []int64{-1, 10}, Err: ErrInvalidCount},
+		},
+		{
+			name:    "k greater than n",
+			giveK:   11,
+			giveN:   10,
+			wantErr: &OptionError{Option: "WithParticipationRatio", Value: []int64{11, 10}, Err: ErrInvalidCount},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(newTestInjectorNoop(), WithParticipationRatio(tt.giveK, tt.giveN))
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, participationRate{p: float32(tt.giveK) / float32(tt.giveN), k: tt.giveK, n: tt.giveN},
+					f.participation())
+			}
+		})
+	}
+}
+
+// TestFaultParticipationRatio tests that a Fault with WithParticipationRatio injects k in every n
+// requests on average.
+func TestFaultParticipationRatio(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		wantMin     int
+		wantMax     int
+	}{
+		{
+			name:        "always",
+			giveOptions: []Option{WithParticipationRatio(1, 1)},
+			wantMin:     1000,
+			wantMax:     1000,
+		},
+		{
+			name:        "never",
+			giveOptions: []Option{WithParticipationRatio(0, 1000000)},
+			wantMin:     0,
+			wantMax:     0,
+		},
+		{
+			name:        "quarter",
+			giveOptions: []Option{WithParticipationRatio(1, 4)},
+			wantMin:     200,
+			wantMax:     300,
+		},
+		{
+			name:        "replaced by participation",
+			giveOptions: []Option{WithParticipationRatio(0, 1), WithParticipation(1.0)},
+			wantMin:     1000,
+			wantMax:     1000,
+		},
+		{
+			name: "participation func priority",
+			giveOptions: []Option{
+				WithParticipationRatio(0, 1),
+				WithParticipationFunc(func(r *http.Request) float32 { return 1.0 }),
+			},
+			wantMin: 1000,
+			wantMax: 1000,
+		},
+		{
+			name:        "brownout",
+			giveOptions: []Option{WithParticipationRatio(1, 1), WithBrownout(1, 2)},
+			wantMin:     0,
+			wantMax:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(newTestInjector500s(), append([]Option{WithEnabled(true)}, tt.giveOptions...)...)
+			assert.NoError(t, err)
+
+			var injected int
+			for range 1000 {
+				if testRequest(t, f).Code == http.StatusInternalServerError {
+					injected++
+				}
+			}
+
+			assert.GreaterOrEqual(t, injected, tt.wantMin)
+			assert.LessOrEqual(t, injected, tt.wantMax)
+		})
+	}
+}
+
+// TestFaultParticipationRatioTrace tests that WithParticipationRatio records its roll in the Trace.
+func TestFaultParticipationRatioTrace(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithParticipationRatio(1, 1),
+		WithDebugTrace(true),
+	)
+	assert.NoError(t, err)
+
+	var got any
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context().Value(ContextKeyTrace)
+	}))
+	h.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []Trace{{
+		Fault:         "testInjectorNoop",
+		Reason:        ReasonInjected,
+		Rolled:        true,
+		Roll:          0.0,
+		Participation: 1.0,
+	}}, got)
+}
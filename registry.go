@@ -0,0 +1,178 @@
+package fault
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrNilFault when a nil Fault is provided.
+	ErrNilFault = errors.New("fault cannot be nil")
+	// ErrNilRegistry when a nil Registry is provided.
+	ErrNilRegistry = errors.New("registry cannot be nil")
+	// ErrDuplicateName when a name is already in use.
+	ErrDuplicateName = errors.New("name already in use")
+)
+
+// Registry holds named Faults so that they can be inspected and controlled together, for example
+// from an ops dashboard.
+type Registry struct {
+	// faults are the registered Faults in the order they were registered.
+	faults []*Fault
+	// loadedAt is when Load last replaced the Faults, or zero if it has not.
+	loadedAt time.Time
+
+	// name is the name of the Registry in its parent if it is a scope.
+	name string
+	// parent is the Registry that the Registry is a scope of, if any.
+	parent *Registry
+	// scopes are the scopes of the Registry in the order they were created.
+	scopes []*Registry
+
+	// enabled determines if the Faults in the Registry and its scopes may inject.
+	enabled atomic.Bool
+	// coordinator, if set, must allow every injection of the Faults in the Registry and its scopes.
+	coordinator Coordinator
+
+	nowF func() time.Time
+	// drainTickF returns a channel that receives each time Drain checks for injections in
+	// progress, and a function that stops it.
+	drainTickF func() (<-chan time.Time, func())
+
+	// mtx protects faults, loadedAt, and scopes.
+	mtx sync.RWMutex
+
+	// subscribers are called with a Snapshot when the Faults change.
+	subscribers []*subscriber
+	// subMtx protects subscribers.
+	subMtx sync.Mutex
+	// notifyMtx makes sure subscribers are called one at a time.
+	notifyMtx sync.Mutex
+}
+
+// RegistryOption configures a Registry.
+type RegistryOption interface {
+	applyRegistry(r *Registry) error
+}
+
+func (o nowFuncOption) applyRegistry(r *Registry) error {
+	r.nowF = o
+	return nil
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry(opts ...RegistryOption) (*Registry, error) {
+	// set defaults
+	reg := &Registry{
+		nowF:       time.Now,
+		drainTickF: drainTicker,
+	}
+	reg.enabled.Store(true)
+
+	// apply options
+	err := applyOptions(opts, RegistryOption.applyRegistry, reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return reg, nil
+}
+
+// Register adds faults to the Registry. Faults are identified by their name, which must be unique
+// in the Registry. If any Fault cannot be registered none are. Register a Fault in only one
+// Registry or scope, which decides the scope the Fault is in.
+func (r *Registry) Register(faults ...*Fault) error {
+	r.mtx.Lock()
+	err := checkFaults("Register", append(r.faults[:len(r.faults):len(r.faults)], faults...))
+	if err == nil {
+		r.faults = append(r.faults, faults...)
+	}
+	r.mtx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	for _, f := range faults {
+		f.watch(r, true)
+	}
+	r.notify()
+	return nil
+}
+
+// Load replaces every Fault in the Registry with Faults built from cfgs, such as after reloading a
+// configuration file, and records when it did so. If any Config is not valid the Registry is not
+// changed. The Faults previously returned by the Registry keep their handlers, so Load only takes
+// effect for handlers built from Faults looked up after it returns.
+func (r *Registry) Load(cfgs ...Config) error {
+	faults := make([]*Fault, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		f, err := NewFaultFromConfig(cfg)
+		if err != nil {
+			return err
+		}
+		faults = append(faults, f)
+	}
+
+	err := checkFaults("Load", faults)
+	if err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	old := r.faults
+	r.faults = faults
+	r.loadedAt = r.nowF()
+	r.mtx.Unlock()
+
+	for _, f := range old {
+		f.watch(r, false)
+	}
+	for _, f := range faults {
+		f.watch(r, true)
+	}
+	r.notify()
+	return nil
+}
+
+// Fault returns the registered Fault named name, or nil if there is none. Faults in scopes are
+// named with the scope, such as "payments/a".
+func (r *Registry) Fault(name string) *Fault {
+	for _, e := range r.entries() {
+		if e.name == name {
+			return e.fault
+		}
+	}
+
+	return nil
+}
+
+// Faults returns the registered Faults in the order they were registered, followed by the Faults
+// in each scope in the order the scopes were created.
+func (r *Registry) Faults() []*Fault {
+	entries := r.entries()
+
+	faults := make([]*Fault, 0, len(entries))
+	for _, e := range entries {
+		faults = append(faults, e.fault)
+	}
+	return faults
+}
+
+// checkFaults returns an error for op if any of faults is nil or shares a name with another.
+func checkFaults(op string, faults []*Fault) error {
+	names := make(map[string]bool, len(faults))
+	for _, f := range faults {
+		if f == nil {
+			return &OptionError{Option: op, Value: nil, Err: ErrNilFault}
+		}
+		if names[f.name] {
+			return &OptionError{Option: op, Value: f.name, Err: ErrDuplicateName}
+		}
+		names[f.name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package fault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRegistryFault returns an enabled Fault named name.
+func testRegistryFault(t *testing.T, name string) *Fault {
+	t.Helper()
+
+	f, err := NewFault(newTestInjectorNoop(), WithEnabled(true), WithName(name))
+	assert.NoError(t, err)
+
+	return f
+}
+
+// TestNewRegistry tests NewRegistry.
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry(WithNowFunc(time.Now))
+	assert.NoError(t, err)
+	assert.NotNil(t, reg.nowF)
+	assert.Empty(t, reg.Faults())
+
+	reg, err = NewRegistry(withError())
+	assert.Nil(t, reg)
+	assert.Equal(t, errErrorOption, err)
+}
+
+// TestRegistryRegister tests Registry.Register.
+func TestRegistryRegister(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	a := testRegistryFault(t, "a")
+	b := testRegistryFault(t, "b")
+
+	assert.NoError(t, reg.Register(a, b))
+	assert.Equal(t, []*Fault{a, b}, reg.Faults())
+	assert.Same(t, b, reg.Fault("b"))
+	assert.Nil(t, reg.Fault("c"))
+
+	err = reg.Register(testRegistryFault(t, "c"), testRegistryFault(t, "a"))
+	assert.Equal(t, &OptionError{Option: "Register", Value: "a", Err: ErrDuplicateName}, err)
+
+	err = reg.Register(nil)
+	assert.Equal(t, &OptionError{Option: "Register", Value: nil, Err: ErrNilFault}, err)
+
+	// failed registrations change nothing
+	assert.Equal(t, []*Fault{a, b}, reg.Faults())
+}
+
+// TestRegistryLoad tests Registry.Load.
+func TestRegistryLoad(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	reg, err := NewRegistry(WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(testRegistryFault(t, "old")))
+
+	reject := &InjectorConfig{Type: InjectorTypeReject}
+
+	err = reg.Load(Config{Name: "a", Injector: reject}, Config{Name: "a", Injector: reject})
+	assert.Equal(t, &OptionError{Option: "Load", Value: "a", Err: ErrDuplicateName}, err)
+
+	err = reg.Load(Config{Name: "a"})
+	assert.Error(t, err)
+
+	assert.Len(t, reg.Faults(), 1)
+	assert.NotNil(t, reg.Fault("old"))
+	assert.Nil(t, reg.Status().LoadedAt)
+
+	assert.NoError(t, reg.Load(Config{Name: "a", Injector: reject}, Config{Name: "b", Injector: reject}))
+	assert.Len(t, reg.Faults(), 2)
+	assert.Nil(t, reg.Fault("old"))
+	assert.NotNil(t, reg.Fault("a"))
+	assert.Equal(t, &now, reg.Status().LoadedAt)
+}
@@ -1,10 +1,96 @@
 package fault
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrReporterPanic when a Reporter panics.
+	ErrReporterPanic = errors.New("reporter panicked")
+)
+
 // Reporter receives events from faults to use for logging, stats, and other custom reporting.
 type Reporter interface {
 	Report(name string, state InjectorState)
 }
 
+// ReporterV2 is an optional interface for Reporters that can fail to report an event, such as
+// Reporters that send events over the network. When a Reporter also implements ReporterV2, the
+// package calls ReportV2 instead of Report, and calls ReportFailed as a fallback with the error if
+// ReportV2 returns one or panics, so that failures can be counted or logged elsewhere.
+//
+// The package recovers panics from every Reporter, so that a buggy Reporter cannot take down request
+// handling. Panics from a Reporter that does not implement ReporterV2, and from ReportFailed, are
+// discarded.
+type ReporterV2 interface {
+	Reporter
+	// ReportV2 reports the event like Report, returning an error if it could not be reported.
+	ReportV2(name string, state InjectorState) error
+	// ReportFailed is called with the error when ReportV2 fails. Errors from panics wrap
+	// ErrReporterPanic.
+	ReportFailed(name string, state InjectorState, err error)
+}
+
+// report sends state to r under name following the contract of ReporterV2, recovering panics.
+func report(r Reporter, name string, state InjectorState) {
+	// panics are discarded, or passed to ReportFailed by reportV2
+	defer func() { recover() }()
+
+	r2, ok := r.(ReporterV2)
+	if !ok {
+		r.Report(name, state)
+		return
+	}
+
+	err := reportV2(r2, name, state)
+	if err != nil {
+		r2.ReportFailed(name, state, err)
+	}
+}
+
+// abort reports StateAborted to r under name and aborts the request by panicking with
+// http.ErrAbortHandler, which the http.Server handles without logging a stack trace.
+func abort(r Reporter, name string) {
+	go report(r, name, StateAborted)
+
+	// This is a specialized and documented way of sending an interrupted response to the client
+	// without printing the panic stack trace or erroring.
+	// https://golang.org/pkg/net/http/#Handler
+	panic(http.ErrAbortHandler)
+}
+
+// reportV2 calls r.ReportV2, returning an error that wraps ErrReporterPanic if it panics.
+func reportV2(r ReporterV2, name string, state InjectorState) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: %v", ErrReporterPanic, p)
+		}
+	}()
+
+	return r.ReportV2(name, state)
+}
+
+// DelayReporter is a Reporter that also receives the delay each time an Injector waits, such as the
+// SlowInjector and the LoadLatencyInjector. Use it to correlate the size of injected delays with how
+// clients behave. ReportDelay is called after the Injector finishes waiting.
+type DelayReporter interface {
+	Reporter
+	ReportDelay(name string, d time.Duration)
+}
+
+// reportDelay reports that the Injector named name waited d if r is a DelayReporter.
+func reportDelay(r Reporter, name string, d time.Duration) {
+	if dr, ok := r.(DelayReporter); ok {
+		go func() {
+			defer func() { recover() }()
+			dr.ReportDelay(name, d)
+		}()
+	}
+}
+
 // NoopReporter is a reporter that does nothing.
 type NoopReporter struct{}
 
@@ -18,9 +104,34 @@ func (r *NoopReporter) Report(name string, state InjectorState) {}
 
 // ReporterOption configures structs that accept a Reporter.
 type ReporterOption interface {
+	Option
+	ChainInjectorOption
 	RejectInjectorOption
 	ErrorInjectorOption
 	SlowInjectorOption
+	CPUInjectorOption
+	PanicInjectorOption
+	RequestHeaderInjectorOption
+	RequestBodyInjectorOption
+	ConnectionCloseInjectorOption
+	StaleCacheInjectorOption
+	MalformedHeaderInjectorOption
+	OutageInjectorOption
+	FlappingInjectorOption
+	LoadLatencyInjectorOption
+	DuplicateRequestInjectorOption
+	PayloadInjectorOption
+	IdleInjectorOption
+	DoubleWriteHeaderInjectorOption
+	DowngradeInjectorOption
+	GzipBombInjectorOption
+	CharsetInjectorOption
+	PushInjectorOption
+	ResponseInjectorOption
+	ConditionalInjectorOption
+	BucketingOption
+	BandwidthInjectorOption
+	SlicerInjectorOption
 }
 
 // reporterOption holds our passed in Reporter.
@@ -28,7 +139,61 @@ type reporterOption struct {
 	reporter Reporter
 }
 
-// WithReporter sets the Reporter.
+func (o reporterOption) applyFault(f *Fault) error {
+	f.reporter = o.reporter
+	return nil
+}
+
+// WithReporter sets the Reporter. A Fault passes its Reporter to an InjectorV2 in the
+// InjectionContext.
 func WithReporter(r Reporter) ReporterOption {
 	return reporterOption{r}
 }
+
+// NameOption configures structs that report or annotate requests with a name.
+type NameOption interface {
+	Option
+	FaultGroupOption
+	ChainInjectorOption
+	RejectInjectorOption
+	ErrorInjectorOption
+	SlowInjectorOption
+	CPUInjectorOption
+	PanicInjectorOption
+	RequestHeaderInjectorOption
+	RequestBodyInjectorOption
+	ConnectionCloseInjectorOption
+	StaleCacheInjectorOption
+	MalformedHeaderInjectorOption
+	OutageInjectorOption
+	FlappingInjectorOption
+	LoadLatencyInjectorOption
+	DuplicateRequestInjectorOption
+	PayloadInjectorOption
+	IdleInjectorOption
+	DoubleWriteHeaderInjectorOption
+	DowngradeInjectorOption
+	GzipBombInjectorOption
+	CharsetInjectorOption
+	PushInjectorOption
+	ResponseInjectorOption
+	ConditionalInjectorOption
+	BucketingOption
+	BandwidthInjectorOption
+	SlicerInjectorOption
+}
+
+// nameOption holds the name passed to the Reporter.
+type nameOption string
+
+// WithName sets the name passed to the Reporter, for example to label metrics. Default is the name
+// of the type, such as "ErrorInjector". The default name of a Fault is the name of the type of its
+// Injector.
+func WithName(n string) NameOption {
+	return nameOption(n)
+}
+
+func (o nameOption) applyFault(f *Fault) error {
+	f.name = string(o)
+	return nil
+}
@@ -0,0 +1,97 @@
+package fault
+
+// PrintfLogger is satisfied by loggers with a Printf method, such as *log.Logger, logrus, and
+// zerolog.
+type PrintfLogger interface {
+	Printf(format string, v ...any)
+}
+
+// PrintfReporter is a Reporter that logs each event to a PrintfLogger.
+type PrintfReporter struct {
+	logger PrintfLogger
+}
+
+// NewPrintfReporter returns a PrintfReporter that logs to l.
+func NewPrintfReporter(l PrintfLogger) *PrintfReporter {
+	return &PrintfReporter{logger: l}
+}
+
+// Report logs the name and state, such as "fault: ErrorInjector started".
+func (r *PrintfReporter) Report(name string, state InjectorState) {
+	r.logger.Printf("fault: %s %s", name, state)
+}
+
+// KeyValueLogger is satisfied by leveled structured loggers that accept alternating keys and
+// values, such as *zap.SugaredLogger.
+type KeyValueLogger interface {
+	Infow(msg string, keysAndValues ...any)
+	Warnw(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+}
+
+// KeyValueReporter is a Reporter that logs each event to a KeyValueLogger.
+type KeyValueReporter struct {
+	logger KeyValueLogger
+}
+
+// NewKeyValueReporter returns a KeyValueReporter that logs to l.
+func NewKeyValueReporter(l KeyValueLogger) *KeyValueReporter {
+	return &KeyValueReporter{logger: l}
+}
+
+// Report logs the message "fault" with the keys "name" and "state", at the error level for
+// StateErrored, the warn level for StateAborted, and the info level otherwise.
+func (r *KeyValueReporter) Report(name string, state InjectorState) {
+	log := r.logger.Infow
+	switch state {
+	case StateErrored:
+		log = r.logger.Errorw
+	case StateAborted:
+		log = r.logger.Warnw
+	}
+	log("fault", "name", name, "state", state.String())
+}
+
+// NewZapReporter returns a KeyValueReporter that logs to a *zap.SugaredLogger. Use
+// logger.Sugar() to get a *zap.SugaredLogger from a *zap.Logger.
+func NewZapReporter(l KeyValueLogger) *KeyValueReporter {
+	return NewKeyValueReporter(l)
+}
+
+// ZerologEvent is satisfied by *zerolog.Event.
+type ZerologEvent[E any] interface {
+	Str(key, val string) E
+	Msg(msg string)
+}
+
+// ZerologLogger is satisfied by *zerolog.Logger.
+type ZerologLogger[E ZerologEvent[E]] interface {
+	Info() E
+	Warn() E
+	Error() E
+}
+
+// ZerologReporter is a Reporter that logs each event to a *zerolog.Logger.
+type ZerologReporter[E ZerologEvent[E]] struct {
+	logger ZerologLogger[E]
+}
+
+// NewZerologReporter returns a ZerologReporter that logs to l, a *zerolog.Logger.
+func NewZerologReporter[E ZerologEvent[E]](l ZerologLogger[E]) *ZerologReporter[E] {
+	return &ZerologReporter[E]{logger: l}
+}
+
+// Report logs the message "fault" with the fields "name" and "state", at the error level for
+// StateErrored, the warn level for StateAborted, and the info level otherwise.
+func (r *ZerologReporter[E]) Report(name string, state InjectorState) {
+	var e E
+	switch state {
+	case StateErrored:
+		e = r.logger.Error()
+	case StateAborted:
+		e = r.logger.Warn()
+	default:
+		e = r.logger.Info()
+	}
+	e.Str("name", name).Str("state", state.String()).Msg("fault")
+}
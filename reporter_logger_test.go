@@ -0,0 +1,154 @@
+package fault
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testKeyValueLogger records the level and arguments of the last call.
+type testKeyValueLogger struct {
+	level         string
+	msg           string
+	keysAndValues []any
+}
+
+// Infow records the info level, msg, and keysAndValues.
+func (l *testKeyValueLogger) Infow(msg string, keysAndValues ...any) {
+	l.level, l.msg, l.keysAndValues = "info", msg, keysAndValues
+}
+
+// Warnw records the warn level, msg, and keysAndValues.
+func (l *testKeyValueLogger) Warnw(msg string, keysAndValues ...any) {
+	l.level, l.msg, l.keysAndValues = "warn", msg, keysAndValues
+}
+
+// Errorw records the error level, msg, and keysAndValues.
+func (l *testKeyValueLogger) Errorw(msg string, keysAndValues ...any) {
+	l.level, l.msg, l.keysAndValues = "error", msg, keysAndValues
+}
+
+// testZerologLogger mimics *zerolog.Logger.
+type testZerologLogger struct {
+	out string
+}
+
+// testZerologEvent mimics *zerolog.Event.
+type testZerologEvent struct {
+	logger *testZerologLogger
+	fields string
+}
+
+// Info returns a new event at the info level.
+func (l *testZerologLogger) Info() *testZerologEvent {
+	return &testZerologEvent{logger: l, fields: "level=info"}
+}
+
+// Warn returns a new event at the warn level.
+func (l *testZerologLogger) Warn() *testZerologEvent {
+	return &testZerologEvent{logger: l, fields: "level=warn"}
+}
+
+// Error returns a new event at the error level.
+func (l *testZerologLogger) Error() *testZerologEvent {
+	return &testZerologEvent{logger: l, fields: "level=error"}
+}
+
+// Str adds a field to the event.
+func (e *testZerologEvent) Str(key, val string) *testZerologEvent {
+	e.fields += fmt.Sprintf(" %s=%s", key, val)
+	return e
+}
+
+// Msg writes the event to its logger.
+func (e *testZerologEvent) Msg(msg string) {
+	e.logger.out = e.fields + " msg=" + msg
+}
+
+// TestInjectorStateString tests InjectorState.String.
+func TestInjectorStateString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "started", StateStarted.String())
+	assert.Equal(t, "finished", StateFinished.String())
+	assert.Equal(t, "skipped", StateSkipped.String())
+	assert.Equal(t, "errored", StateErrored.String())
+	assert.Equal(t, "aborted", StateAborted.String())
+	assert.Equal(t, "InjectorState(0)", InjectorState(0).String())
+}
+
+// TestPrintfReporter tests PrintfReporter.
+func TestPrintfReporter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	r := NewPrintfReporter(log.New(&buf, "", 0))
+
+	r.Report("ErrorInjector", StateStarted)
+
+	assert.Equal(t, "fault: ErrorInjector started\n", buf.String())
+}
+
+// TestKeyValueReporter tests KeyValueReporter and NewZapReporter.
+func TestKeyValueReporter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		giveState InjectorState
+		wantLevel string
+	}{
+		{giveState: StateStarted, wantLevel: "info"},
+		{giveState: StateFinished, wantLevel: "info"},
+		{giveState: StateSkipped, wantLevel: "info"},
+		{giveState: StateErrored, wantLevel: "error"},
+		{giveState: StateAborted, wantLevel: "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.giveState.String(), func(t *testing.T) {
+			t.Parallel()
+
+			l := &testKeyValueLogger{}
+			NewKeyValueReporter(l).Report("ErrorInjector", tt.giveState)
+
+			assert.Equal(t, tt.wantLevel, l.level)
+			assert.Equal(t, "fault", l.msg)
+			assert.Equal(t, []any{"name", "ErrorInjector", "state", tt.giveState.String()}, l.keysAndValues)
+
+			NewZapReporter(l).Report("SlowInjector", tt.giveState)
+
+			assert.Equal(t, tt.wantLevel, l.level)
+			assert.Equal(t, []any{"name", "SlowInjector", "state", tt.giveState.String()}, l.keysAndValues)
+		})
+	}
+}
+
+// TestZerologReporter tests ZerologReporter.
+func TestZerologReporter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		giveState InjectorState
+		want      string
+	}{
+		{giveState: StateStarted, want: "level=info name=ErrorInjector state=started msg=fault"},
+		{giveState: StateFinished, want: "level=info name=ErrorInjector state=finished msg=fault"},
+		{giveState: StateSkipped, want: "level=info name=ErrorInjector state=skipped msg=fault"},
+		{giveState: StateErrored, want: "level=error name=ErrorInjector state=errored msg=fault"},
+		{giveState: StateAborted, want: "level=warn name=ErrorInjector state=aborted msg=fault"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.giveState.String(), func(t *testing.T) {
+			t.Parallel()
+
+			l := &testZerologLogger{}
+			NewZerologReporter(l).Report("ErrorInjector", tt.giveState)
+
+			assert.Equal(t, tt.want, l.out)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package fault
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DroppedReporter is a Reporter that also receives the number of events that a RateLimitedReporter
+// dropped for a name and state in one second. The name is the name of the dropped events, so that
+// Reporters keyed by name, such as metrics Reporters, can count the drops in the same series.
+type DroppedReporter interface {
+	Reporter
+	ReportDropped(name string, state InjectorState, dropped int)
+}
+
+// RateLimitedReporter is a Reporter that passes at most a limited number of events per second for
+// each name and state pair to another Reporter, and drops the rest.
+type RateLimitedReporter struct {
+	reporter  Reporter
+	perSecond int
+	droppedF  func(name string, state InjectorState, dropped int)
+	nowF      func() time.Time
+
+	// windows holds the current one second window for each name and state pair.
+	windows map[reportKey]*reportWindow
+	// swept is when windows were last checked for seconds that ended.
+	swept time.Time
+	// mtx protects windows and swept.
+	mtx sync.Mutex
+}
+
+// reportKey identifies the events that a RateLimitedReporter limits together.
+type reportKey struct {
+	name  string
+	state InjectorState
+}
+
+// reportWindow counts the events for a reportKey in one second.
+type reportWindow struct {
+	start   time.Time
+	passed  int
+	dropped int
+}
+
+// RateLimitedReporterOption configures a RateLimitedReporter.
+type RateLimitedReporterOption interface {
+	applyRateLimitedReporter(r *RateLimitedReporter) error
+}
+
+type droppedFuncOption func(name string, state InjectorState, dropped int)
+
+func (o droppedFuncOption) applyRateLimitedReporter(r *RateLimitedReporter) error {
+	if o == nil {
+		return &OptionError{Option: "WithDroppedFunc", Value: nil, Err: ErrNilFunc}
+	}
+	r.droppedF = o
+	return nil
+}
+
+// WithDroppedFunc sets the function that summarizes the events dropped for a name and state in a
+// second. By default the summary is passed to ReportDropped if the wrapped Reporter is a
+// DroppedReporter, and discarded otherwise.
+func WithDroppedFunc(f func(name string, state InjectorState, dropped int)) RateLimitedReporterOption {
+	return droppedFuncOption(f)
+}
+
+func (o nowFuncOption) applyRateLimitedReporter(r *RateLimitedReporter) error {
+	r.nowF = o
+	return nil
+}
+
+// NewRateLimitedReporter returns a RateLimitedReporter that passes at most perSecond events per
+// second for each name and state pair to r. Use it to protect logging backends when a Fault with
+// high participation is enabled on a hot endpoint. perSecond must be at least 1.
+func NewRateLimitedReporter(
+	r Reporter,
+	perSecond int,
+	opts ...RateLimitedReporterOption,
+) (*RateLimitedReporter, error) {
+	if r == nil {
+		return nil, &OptionError{Option: "NewRateLimitedReporter", Value: nil, Err: ErrNilReporter}
+	}
+	if perSecond < 1 {
+		return nil, &OptionError{Option: "NewRateLimitedReporter", Value: perSecond, Err: ErrInvalidCount}
+	}
+
+	// set defaults
+	rr := &RateLimitedReporter{
+		reporter:  r,
+		perSecond: perSecond,
+		nowF:      time.Now,
+		windows:   make(map[reportKey]*reportWindow),
+	}
+	rr.droppedF = rr.reportDropped
+
+	// apply options
+	err := applyOptions(opts, RateLimitedReporterOption.applyRateLimitedReporter, rr)
+	if err != nil {
+		return nil, err
+	}
+
+	return rr, nil
+}
+
+// Report passes the event to the wrapped Reporter unless perSecond events with the same name and
+// state were already passed in the current second. At most once a second Report also summarizes the
+// events dropped in every second that has ended, for any name and state, before the event is
+// passed, so that the drops of a burst are summarized once any event follows it.
+func (r *RateLimitedReporter) Report(name string, state InjectorState) {
+	now := r.nowF()
+	key := reportKey{name: name, state: state}
+
+	r.mtx.Lock()
+	var summaries []droppedEvents
+	if now.Sub(r.swept) >= time.Second {
+		summaries = r.sweep(now)
+		r.swept = now
+	}
+
+	w, ok := r.windows[key]
+	if ok && now.Sub(w.start) >= time.Second {
+		if w.dropped > 0 {
+			summaries = append(summaries, droppedEvents{key: key, dropped: w.dropped})
+		}
+		ok = false
+	}
+	if !ok {
+		w = &reportWindow{start: now}
+		r.windows[key] = w
+	}
+
+	pass := w.passed < r.perSecond
+	if pass {
+		w.passed++
+	} else {
+		w.dropped++
+	}
+	r.mtx.Unlock()
+
+	r.summarize(summaries)
+	if pass {
+		report(r.reporter, name, state)
+	}
+}
+
+// Flush summarizes the events dropped so far in the current second for every name and state, such
+// as before the program exits.
+func (r *RateLimitedReporter) Flush() {
+	r.mtx.Lock()
+	var summaries []droppedEvents
+	for key, w := range r.windows {
+		if w.dropped > 0 {
+			summaries = append(summaries, droppedEvents{key: key, dropped: w.dropped})
+			w.dropped = 0
+		}
+	}
+	r.mtx.Unlock()
+
+	r.summarize(summaries)
+}
+
+// droppedEvents is the number of events dropped for a reportKey in one second.
+type droppedEvents struct {
+	key     reportKey
+	dropped int
+}
+
+// sweep removes the windows that ended before now and returns their dropped events. It must be
+// called with r.mtx held.
+func (r *RateLimitedReporter) sweep(now time.Time) []droppedEvents {
+	var summaries []droppedEvents
+	for key, w := range r.windows {
+		if now.Sub(w.start) < time.Second {
+			continue
+		}
+		if w.dropped > 0 {
+			summaries = append(summaries, droppedEvents{key: key, dropped: w.dropped})
+		}
+		delete(r.windows, key)
+	}
+	return summaries
+}
+
+// summarize passes each of summaries to r.droppedF, ordered by name and state.
+func (r *RateLimitedReporter) summarize(summaries []droppedEvents) {
+	slices.SortFunc(summaries, func(a, b droppedEvents) int {
+		return cmp.Or(strings.Compare(a.key.name, b.key.name), cmp.Compare(a.key.state, b.key.state))
+	})
+	for _, s := range summaries {
+		r.droppedF(s.key.name, s.key.state, s.dropped)
+	}
+}
+
+// reportDropped reports dropped events to the wrapped Reporter if it is a DroppedReporter.
+func (r *RateLimitedReporter) reportDropped(name string, state InjectorState, dropped int) {
+	if dr, ok := r.reporter.(DroppedReporter); ok {
+		func() {
+			defer func() { recover() }()
+			dr.ReportDropped(name, state, dropped)
+		}()
+	}
+}
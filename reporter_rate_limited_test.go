@@ -0,0 +1,243 @@
+package fault
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewRateLimitedReporter tests NewRateLimitedReporter.
+func TestNewRateLimitedReporter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		giveReporter  Reporter
+		givePerSecond int
+		giveOptions   []RateLimitedReporterOption
+		wantErr       error
+	}{
+		{
+			name:          "valid",
+			giveReporter:  newTestReporter(),
+			givePerSecond: 1,
+			giveOptions: []RateLimitedReporterOption{
+				WithDroppedFunc(func(string, InjectorState, int) {}),
+				WithNowFunc(time.Now),
+			},
+			wantErr: nil,
+		},
+		{
+			name:          "nil reporter",
+			giveReporter:  nil,
+			givePerSecond: 1,
+			giveOptions:   nil,
+			wantErr:       &OptionError{Option: "NewRateLimitedReporter", Value: nil, Err: ErrNilReporter},
+		},
+		{
+			name:          "invalid per second",
+			giveReporter:  newTestReporter(),
+			givePerSecond: 0,
+			giveOptions:   nil,
+			wantErr:       &OptionError{Option: "NewRateLimitedReporter", Value: 0, Err: ErrInvalidCount},
+		},
+		{
+			name:          "nil dropped func",
+			giveReporter:  newTestReporter(),
+			givePerSecond: 1,
+			giveOptions:   []RateLimitedReporterOption{WithDroppedFunc(nil)},
+			wantErr:       &OptionError{Option: "WithDroppedFunc", Value: nil, Err: ErrNilFunc},
+		},
+		{
+			name:          "option error",
+			giveReporter:  newTestReporter(),
+			givePerSecond: 1,
+			giveOptions:   []RateLimitedReporterOption{withError()},
+			wantErr:       errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, err := NewRateLimitedReporter(tt.giveReporter, tt.givePerSecond, tt.giveOptions...)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Nil(t, rr)
+			}
+		})
+	}
+}
+
+// testDroppedReporter is a DroppedReporter that sends its events and summaries to states.
+type testDroppedReporter struct {
+	testChanReporter
+}
+
+// ReportDropped sends name, state, and dropped to r.states.
+func (r *testDroppedReporter) ReportDropped(name string, state InjectorState, dropped int) {
+	r.states <- name + " " + state.String() + " dropped " + strconv.Itoa(dropped)
+}
+
+// TestRateLimitedReporterReport tests RateLimitedReporter.Report.
+func TestRateLimitedReporterReport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	rep := &testDroppedReporter{testChanReporter{states: make(chan string, 100)}}
+	rr, err := NewRateLimitedReporter(rep, 2, WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	for range 5 {
+		rr.Report("a", StateStarted)
+	}
+	rr.Report("a", StateFinished)
+	for range 3 {
+		rr.Report("b", StateStarted)
+	}
+
+	// the drops of b are summarized by the next event for a
+	now = now.Add(time.Second)
+	rr.Report("a", StateStarted)
+	rr.Report("b", StateStarted)
+
+	close(rep.states)
+	var got []string
+	for s := range rep.states {
+		got = append(got, s)
+	}
+
+	assert.Equal(t, []string{
+		"a started",
+		"a started",
+		"a finished",
+		"b started",
+		"b started",
+		"a started dropped 3",
+		"b started dropped 1",
+		"a started",
+		"b started",
+	}, got)
+}
+
+// TestRateLimitedReporterReportStaleWindow tests that RateLimitedReporter.Report summarizes the
+// drops of a window that ended since the last sweep, and that a sweep keeps the windows that have
+// not ended.
+func TestRateLimitedReporterReportStaleWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	rep := &testDroppedReporter{testChanReporter{states: make(chan string, 100)}}
+	rr, err := NewRateLimitedReporter(rep, 1, WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	rr.Report("a", StateStarted)
+	rr.Report("a", StateStarted)
+	now = now.Add(500 * time.Millisecond)
+	for range 3 {
+		rr.Report("b", StateStarted)
+	}
+
+	// the sweep summarizes a and keeps the window of b, which has not ended
+	now = now.Add(500 * time.Millisecond)
+	rr.Report("a", StateStarted)
+
+	// the window of b ends less than a second after the sweep
+	now = now.Add(600 * time.Millisecond)
+	rr.Report("b", StateStarted)
+
+	close(rep.states)
+	var got []string
+	for s := range rep.states {
+		got = append(got, s)
+	}
+
+	assert.Equal(t, []string{
+		"a started",
+		"b started",
+		"a started dropped 1",
+		"a started",
+		"b started dropped 2",
+		"b started",
+	}, got)
+}
+
+// TestRateLimitedReporterReportNotDroppedReporter tests that RateLimitedReporter.Report discards
+// summaries when the wrapped Reporter is not a DroppedReporter.
+func TestRateLimitedReporterReportNotDroppedReporter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	rep := &testChanReporter{states: make(chan string, 100)}
+	rr, err := NewRateLimitedReporter(rep, 1, WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	rr.Report("a", StateStarted)
+	rr.Report("a", StateStarted)
+	now = now.Add(time.Second)
+	rr.Report("a", StateStarted)
+
+	close(rep.states)
+	var got []string
+	for s := range rep.states {
+		got = append(got, s)
+	}
+
+	assert.Equal(t, []string{"a started", "a started"}, got)
+}
+
+// TestRateLimitedReporterFlush tests RateLimitedReporter.Flush.
+func TestRateLimitedReporterFlush(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	var dropped []string
+	rr, err := NewRateLimitedReporter(newTestReporter(), 1,
+		WithNowFunc(func() time.Time { return now }),
+		WithDroppedFunc(func(name string, state InjectorState, n int) {
+			dropped = append(dropped, name+" "+state.String()+" "+strconv.Itoa(n))
+		}),
+	)
+	assert.NoError(t, err)
+
+	for range 3 {
+		rr.Report("b", StateSkipped)
+		rr.Report("a", StateSkipped)
+	}
+	rr.Flush()
+	assert.Equal(t, []string{"a skipped 2", "b skipped 2"}, dropped)
+
+	// drops are only summarized once and the window keeps limiting
+	rr.Report("a", StateSkipped)
+	rr.Flush()
+	assert.Equal(t, []string{"a skipped 2", "b skipped 2", "a skipped 1"}, dropped)
+}
+
+// TestRateLimitedReporterDroppedFunc tests RateLimitedReporter with WithDroppedFunc.
+func TestRateLimitedReporterDroppedFunc(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	var dropped []int
+	rr, err := NewRateLimitedReporter(newTestReporter(), 1,
+		WithNowFunc(func() time.Time { return now }),
+		WithDroppedFunc(func(name string, state InjectorState, n int) {
+			assert.Equal(t, "a", name)
+			assert.Equal(t, StateSkipped, state)
+			dropped = append(dropped, n)
+		}),
+	)
+	assert.NoError(t, err)
+
+	for range 3 {
+		rr.Report("a", StateSkipped)
+	}
+	now = now.Add(2 * time.Second)
+	rr.Report("a", StateSkipped)
+
+	assert.Equal(t, []int{2}, dropped)
+}
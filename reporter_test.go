@@ -0,0 +1,127 @@
+package fault
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestReport = errors.New("error from test reporter")
+)
+
+// testReporterV2 is a ReporterV2 that returns err from ReportV2, or panics if panics is true, and
+// sends the error passed to ReportFailed on a channel.
+type testReporterV2 struct {
+	err          error
+	panics       bool
+	failedPanics bool
+	reported     chan string
+	failed       chan error
+}
+
+// newTestReporterV2 returns a new testReporterV2.
+func newTestReporterV2() *testReporterV2 {
+	return &testReporterV2{reported: make(chan string, 10), failed: make(chan error, 10)}
+}
+
+// Report sends the event on the channel.
+func (r *testReporterV2) Report(name string, state InjectorState) {
+	r.reported <- "v1 " + name + " " + state.String()
+}
+
+// ReportV2 sends the event on the channel and fails as configured.
+func (r *testReporterV2) ReportV2(name string, state InjectorState) error {
+	r.reported <- name + " " + state.String()
+	if r.panics {
+		panic("reporter bug")
+	}
+	return r.err
+}
+
+// ReportFailed sends err on the channel and panics if failedPanics is true.
+func (r *testReporterV2) ReportFailed(name string, state InjectorState, err error) {
+	r.failed <- fmt.Errorf("%s %s: %w", name, state, err)
+	if r.failedPanics {
+		panic("fallback bug")
+	}
+}
+
+// testPanicReporter is a Reporter that panics, first signaling on panicked if it is set.
+type testPanicReporter struct {
+	panicked chan struct{}
+}
+
+// Report signals on panicked and panics.
+func (r testPanicReporter) Report(name string, state InjectorState) {
+	if r.panicked != nil {
+		r.panicked <- struct{}{}
+	}
+	panic("reporter bug")
+}
+
+// ReportDelay panics.
+func (r testPanicReporter) ReportDelay(name string, d time.Duration) {
+	panic("reporter bug")
+}
+
+// TestReport tests that report follows the contract of ReporterV2.
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	r := newTestReporterV2()
+	report(r, "a", StateStarted)
+	assert.Equal(t, "a started", <-r.reported)
+	assert.Empty(t, r.failed)
+
+	r = newTestReporterV2()
+	r.err = errTestReport
+	report(r, "a", StateFinished)
+	assert.Equal(t, "a finished", <-r.reported)
+	assert.ErrorIs(t, <-r.failed, errTestReport)
+
+	r = newTestReporterV2()
+	r.panics = true
+	report(r, "a", StateAborted)
+	assert.Equal(t, "a aborted", <-r.reported)
+	err := <-r.failed
+	assert.ErrorIs(t, err, ErrReporterPanic)
+	assert.EqualError(t, err, "a aborted: reporter panicked: reporter bug")
+
+	r.failedPanics = true
+	assert.NotPanics(t, func() { report(r, "a", StateAborted) })
+	assert.Equal(t, "a aborted", <-r.reported)
+	assert.ErrorIs(t, <-r.failed, ErrReporterPanic)
+
+	assert.NotPanics(t, func() { report(testPanicReporter{}, "a", StateStarted) })
+}
+
+// TestReporterPanics tests that a Reporter that panics does not take down request handling.
+func TestReporterPanics(t *testing.T) {
+	t.Parallel()
+
+	r := testPanicReporter{panicked: make(chan struct{}, 20)}
+	si, err := NewSlowInjector(time.Millisecond, WithReporter(r))
+	assert.NoError(t, err)
+
+	f, err := NewFault(si, WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+
+	for range 10 {
+		assert.Equal(t, testHandlerCode, testRequest(t, f).Code)
+	}
+
+	// the process is still serving once the started and finished reports have panicked
+	timeout := time.After(time.Second)
+	for range 20 {
+		select {
+		case <-r.panicked:
+		case <-timeout:
+			t.Fatal("timed out waiting for the reports")
+		}
+	}
+	assert.Equal(t, testHandlerCode, testRequest(t, f).Code)
+}
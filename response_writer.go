@@ -0,0 +1,246 @@
+package fault
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+)
+
+// ResponseRecorderWriter wraps an http.ResponseWriter and records the status code, the number of
+// bytes written, and optionally the body, for use by Injectors that inspect or modify responses.
+// By default writes pass through to the wrapped ResponseWriter. With WithIntercept the response is
+// held until Commit so that it can be replaced or modified first.
+//
+// ResponseRecorderWriter implements http.Flusher, http.Hijacker, and http.Pusher by passing calls
+// through to the wrapped ResponseWriter. Hijack and Push return http.ErrNotSupported if the wrapped
+// ResponseWriter does not support them, and Unwrap returns the wrapped ResponseWriter for use with
+// http.ResponseController.
+type ResponseRecorderWriter struct {
+	w http.ResponseWriter
+
+	// intercept determines if the response is held until Commit instead of passing through.
+	intercept bool
+	// recordBody determines if a copy of the body is recorded while passing through.
+	recordBody bool
+
+	// header is the header of an intercepted response.
+	header http.Header
+
+	// hijacked is true once the connection is hijacked.
+	hijacked bool
+
+	// onWriteHeader, if set, can modify the header of a response that is passing through just
+	// before it is written.
+	onWriteHeader func(h http.Header)
+
+	wroteHeader bool
+	code        int
+	written     int64
+	body        bytes.Buffer
+}
+
+// ResponseRecorderWriterOption configures a ResponseRecorderWriter.
+type ResponseRecorderWriterOption interface {
+	applyResponseRecorderWriter(rw *ResponseRecorderWriter) error
+}
+
+type recordBodyOption struct{}
+
+func (o recordBodyOption) applyResponseRecorderWriter(rw *ResponseRecorderWriter) error {
+	rw.recordBody = true
+	return nil
+}
+
+// WithRecordBody records a copy of the body as it passes through, available from Body.
+func WithRecordBody() ResponseRecorderWriterOption {
+	return recordBodyOption{}
+}
+
+type interceptOption struct{}
+
+func (o interceptOption) applyResponseRecorderWriter(rw *ResponseRecorderWriter) error {
+	rw.intercept = true
+	return nil
+}
+
+// WithIntercept holds the status code, headers, and body instead of writing them to the wrapped
+// ResponseWriter. Call Commit to write the held response. Flush does nothing while intercepting.
+func WithIntercept() ResponseRecorderWriterOption {
+	return interceptOption{}
+}
+
+// NewResponseRecorderWriter returns a ResponseRecorderWriter that wraps w.
+func NewResponseRecorderWriter(
+	w http.ResponseWriter,
+	opts ...ResponseRecorderWriterOption,
+) (*ResponseRecorderWriter, error) {
+	rw := &ResponseRecorderWriter{
+		w: w,
+	}
+
+	// apply options
+	err := applyOptions(opts, ResponseRecorderWriterOption.applyResponseRecorderWriter, rw)
+	if err != nil {
+		return nil, err
+	}
+
+	if rw.intercept {
+		rw.header = make(http.Header)
+	}
+
+	return rw, nil
+}
+
+// newInterceptor returns a ResponseRecorderWriter that wraps w with WithIntercept.
+func newInterceptor(w http.ResponseWriter) *ResponseRecorderWriter {
+	return &ResponseRecorderWriter{
+		w:         w,
+		intercept: true,
+		header:    make(http.Header),
+	}
+}
+
+// Header returns the header of the response. While intercepting this is a separate header that
+// Commit copies to the wrapped ResponseWriter.
+func (rw *ResponseRecorderWriter) Header() http.Header {
+	if rw.intercept {
+		return rw.header
+	}
+	return rw.w.Header()
+}
+
+// WriteHeader records code and passes it through unless intercepting. Only the first call has any
+// effect.
+func (rw *ResponseRecorderWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.code = code
+
+	if !rw.intercept {
+		if rw.onWriteHeader != nil {
+			rw.onWriteHeader(rw.w.Header())
+		}
+		rw.w.WriteHeader(code)
+	}
+}
+
+// Write records b and passes it through unless intercepting, writing an http.StatusOK header
+// first if no header was written.
+func (rw *ResponseRecorderWriter) Write(b []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+
+	if rw.intercept {
+		rw.body.Write(b)
+		rw.written += int64(len(b))
+		return len(b), nil
+	}
+
+	n, err := rw.w.Write(b)
+	rw.written += int64(n)
+	if rw.recordBody {
+		rw.body.Write(b[:n])
+	}
+
+	return n, err
+}
+
+// StatusCode returns the status code written, or http.StatusOK if none has been written, which is
+// what net/http sends for a handler that does not write a header.
+func (rw *ResponseRecorderWriter) StatusCode() int {
+	if !rw.wroteHeader {
+		return http.StatusOK
+	}
+	return rw.code
+}
+
+// WroteHeader returns true if a status code has been written.
+func (rw *ResponseRecorderWriter) WroteHeader() bool {
+	return rw.wroteHeader
+}
+
+// BytesWritten returns the number of bytes of the body written.
+func (rw *ResponseRecorderWriter) BytesWritten() int64 {
+	return rw.written
+}
+
+// Body returns the recorded body. The body is only recorded while intercepting or with
+// WithRecordBody.
+func (rw *ResponseRecorderWriter) Body() []byte {
+	return rw.body.Bytes()
+}
+
+// SetBody replaces the intercepted body, for example to corrupt it before Commit.
+func (rw *ResponseRecorderWriter) SetBody(b []byte) {
+	rw.body.Reset()
+	rw.body.Write(b)
+}
+
+// Hijacked returns true if the connection was hijacked with Hijack.
+func (rw *ResponseRecorderWriter) Hijacked() bool {
+	return rw.hijacked
+}
+
+// Commit writes the intercepted header, status code, and body to the wrapped ResponseWriter. Commit
+// does nothing unless intercepting or if the connection was hijacked.
+func (rw *ResponseRecorderWriter) Commit() error {
+	if !rw.intercept || rw.hijacked {
+		return nil
+	}
+
+	return rw.commitTo(rw.w)
+}
+
+// commitTo writes the intercepted header, status code, and body to w.
+func (rw *ResponseRecorderWriter) commitTo(w http.ResponseWriter) error {
+	for key, vals := range rw.header {
+		w.Header()[key] = vals
+	}
+	w.WriteHeader(rw.StatusCode())
+
+	_, err := w.Write(rw.body.Bytes())
+	return err
+}
+
+// Flush flushes the wrapped ResponseWriter if it is an http.Flusher, writing an http.StatusOK
+// header first if no header was written. Flush does nothing while intercepting.
+func (rw *ResponseRecorderWriter) Flush() {
+	f, ok := rw.w.(http.Flusher)
+	if rw.intercept || !ok {
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	f.Flush()
+}
+
+// Hijack hijacks the connection of the wrapped ResponseWriter if it is an http.Hijacker.
+func (rw *ResponseRecorderWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, brw, err := h.Hijack()
+	if err == nil {
+		rw.hijacked = true
+	}
+
+	return conn, brw, err
+}
+
+// Push initiates an HTTP/2 server push with the wrapped ResponseWriter if it is an http.Pusher.
+func (rw *ResponseRecorderWriter) Push(target string, opts *http.PushOptions) error {
+	p, ok := rw.w.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return p.Push(target, opts)
+}
+
+// Unwrap returns the wrapped ResponseWriter.
+func (rw *ResponseRecorderWriter) Unwrap() http.ResponseWriter {
+	return rw.w
+}
@@ -0,0 +1,339 @@
+package fault
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestHijack = errors.New("error from test hijacker")
+	errTestPush   = errors.New("error from test pusher")
+)
+
+// testFullWriter is an http.ResponseWriter that also implements http.Flusher, http.Hijacker, and
+// http.Pusher.
+type testFullWriter struct {
+	*httptest.ResponseRecorder
+	hijackErr error
+	hijacked  bool
+	pushed    string
+}
+
+// Hijack records the hijack if w.hijackErr is nil and returns w.hijackErr.
+func (w *testFullWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = w.hijackErr == nil
+	return nil, nil, w.hijackErr
+}
+
+// Push records target and returns errTestPush.
+func (w *testFullWriter) Push(target string, opts *http.PushOptions) error {
+	w.pushed = target
+	return errTestPush
+}
+
+// testMinimalWriter is an http.ResponseWriter that implements no other interfaces.
+type testMinimalWriter struct {
+	header http.Header
+	code   int
+	err    error
+}
+
+// Header returns the header.
+func (w *testMinimalWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records code.
+func (w *testMinimalWriter) WriteHeader(code int) {
+	w.code = code
+}
+
+// Write writes half of b and returns w.err.
+func (w *testMinimalWriter) Write(b []byte) (int, error) {
+	return len(b) / 2, w.err
+}
+
+// TestNewResponseRecorderWriter tests NewResponseRecorderWriter.
+func TestNewResponseRecorderWriter(t *testing.T) {
+	t.Parallel()
+
+	rw, err := NewResponseRecorderWriter(httptest.NewRecorder(), withError())
+	assert.Nil(t, rw)
+	assert.Equal(t, errErrorOption, err)
+}
+
+// TestResponseRecorderWriter tests a ResponseRecorderWriter that passes writes through.
+func TestResponseRecorderWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []ResponseRecorderWriterOption
+		wantBody    string
+	}{
+		{
+			name:        "no body",
+			giveOptions: nil,
+			wantBody:    "",
+		},
+		{
+			name:        "record body",
+			giveOptions: []ResponseRecorderWriterOption{WithRecordBody()},
+			wantBody:    "hello world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			rw, err := NewResponseRecorderWriter(rec, tt.giveOptions...)
+			assert.NoError(t, err)
+
+			assert.False(t, rw.WroteHeader())
+			assert.Equal(t, http.StatusOK, rw.StatusCode())
+
+			rw.Header().Set("X-Test", "yes")
+			rw.WriteHeader(http.StatusTeapot)
+			rw.WriteHeader(http.StatusInternalServerError)
+			_, err = rw.Write([]byte("hello "))
+			assert.NoError(t, err)
+			_, err = rw.Write([]byte("world"))
+			assert.NoError(t, err)
+			assert.NoError(t, rw.Commit())
+
+			assert.True(t, rw.WroteHeader())
+			assert.Equal(t, http.StatusTeapot, rw.StatusCode())
+			assert.Equal(t, int64(11), rw.BytesWritten())
+			assert.Equal(t, tt.wantBody, string(rw.Body()))
+			assert.Equal(t, rec, rw.Unwrap())
+
+			assert.Equal(t, http.StatusTeapot, rec.Code)
+			assert.Equal(t, "yes", rec.Header().Get("X-Test"))
+			assert.Equal(t, "hello world", rec.Body.String())
+		})
+	}
+}
+
+// TestResponseRecorderWriterPartialWrite tests that a ResponseRecorderWriter records only the bytes
+// that the wrapped ResponseWriter wrote.
+func TestResponseRecorderWriterPartialWrite(t *testing.T) {
+	t.Parallel()
+
+	mw := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	rw, err := NewResponseRecorderWriter(mw, WithRecordBody())
+	assert.NoError(t, err)
+
+	n, err := rw.Write([]byte("abcd"))
+
+	assert.Equal(t, 2, n)
+	assert.Equal(t, errTestWrite, err)
+	assert.Equal(t, int64(2), rw.BytesWritten())
+	assert.Equal(t, "ab", string(rw.Body()))
+	assert.Equal(t, http.StatusOK, mw.code)
+}
+
+// TestResponseRecorderWriterIntercept tests a ResponseRecorderWriter with WithIntercept.
+func TestResponseRecorderWriterIntercept(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	rw, err := NewResponseRecorderWriter(rec, WithIntercept())
+	assert.NoError(t, err)
+
+	rw.Header().Set("X-Test", "yes")
+	rw.WriteHeader(http.StatusCreated)
+	_, err = rw.Write([]byte("original"))
+	assert.NoError(t, err)
+	rw.Flush()
+
+	assert.False(t, rec.Flushed)
+	assert.Equal(t, "", rec.Header().Get("X-Test"))
+	assert.Equal(t, 0, rec.Body.Len())
+	assert.Equal(t, http.StatusCreated, rw.StatusCode())
+	assert.Equal(t, int64(8), rw.BytesWritten())
+	assert.Equal(t, "original", string(rw.Body()))
+
+	rw.SetBody([]byte("modified"))
+	assert.NoError(t, rw.Commit())
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Test"))
+	assert.Equal(t, "modified", rec.Body.String())
+}
+
+// TestResponseRecorderWriterPassthrough tests that a ResponseRecorderWriter passes Flush, Hijack, and
+// Push through to the wrapped ResponseWriter.
+func TestResponseRecorderWriterPassthrough(t *testing.T) {
+	t.Parallel()
+
+	fw := &testFullWriter{ResponseRecorder: httptest.NewRecorder(), hijackErr: errTestHijack}
+	rw, err := NewResponseRecorderWriter(fw)
+	assert.NoError(t, err)
+
+	var _ http.Flusher = rw
+	var _ http.Hijacker = rw
+	var _ http.Pusher = rw
+
+	rw.Flush()
+	assert.True(t, fw.Flushed)
+	assert.True(t, rw.WroteHeader())
+	assert.Equal(t, http.StatusOK, fw.Code)
+
+	_, _, err = rw.Hijack()
+	assert.Equal(t, errTestHijack, err)
+	assert.False(t, rw.Hijacked())
+
+	err = rw.Push("/style.css", nil)
+	assert.Equal(t, errTestPush, err)
+	assert.Equal(t, "/style.css", fw.pushed)
+
+	assert.NoError(t, http.NewResponseController(rw).Flush())
+
+	mw := &testMinimalWriter{header: make(http.Header)}
+	rw, err = NewResponseRecorderWriter(mw)
+	assert.NoError(t, err)
+
+	rw.Flush()
+	assert.False(t, rw.WroteHeader())
+
+	_, _, err = rw.Hijack()
+	assert.Equal(t, http.ErrNotSupported, err)
+
+	err = rw.Push("/style.css", nil)
+	assert.Equal(t, http.ErrNotSupported, err)
+}
+
+// TestResponseRecorderWriterHijacked tests that an intercepting ResponseRecorderWriter does not
+// commit after the connection is hijacked.
+func TestResponseRecorderWriterHijacked(t *testing.T) {
+	t.Parallel()
+
+	fw := &testFullWriter{ResponseRecorder: httptest.NewRecorder()}
+	rw, err := NewResponseRecorderWriter(fw, WithIntercept())
+	assert.NoError(t, err)
+
+	_, err = rw.Write([]byte("body"))
+	assert.NoError(t, err)
+	_, _, err = rw.Hijack()
+	assert.NoError(t, err)
+	assert.True(t, rw.Hijacked())
+
+	assert.NoError(t, rw.Commit())
+	assert.False(t, fw.Flushed)
+	assert.Equal(t, 0, fw.Body.Len())
+}
+
+// TestInjectorsPreserveWriterInterfaces tests that every Injector that continues the request passes
+// a ResponseWriter that supports http.Flusher, http.Hijacker, and http.Pusher to the next handler.
+func TestInjectorsPreserveWriterInterfaces(t *testing.T) {
+	t.Parallel()
+
+	noSlow := WithSlowFunc(func(time.Duration) {})
+	always := func(r *http.Request) bool { return true }
+
+	tests := []struct {
+		name         string
+		giveInjector func() (Injector, error)
+		wantFlushed  bool
+	}{
+		{
+			name:         "slow",
+			giveInjector: func() (Injector, error) { return NewSlowInjector(time.Second, noSlow) },
+			wantFlushed:  true,
+		},
+		{
+			name:         "cpu",
+			giveInjector: func() (Injector, error) { return NewCPUInjector(time.Nanosecond) },
+			wantFlushed:  true,
+		},
+		{
+			name:         "request header",
+			giveInjector: func() (Injector, error) { return NewRequestHeaderInjector() },
+			wantFlushed:  true,
+		},
+		{
+			name:         "connection close",
+			giveInjector: func() (Injector, error) { return NewConnectionCloseInjector() },
+			wantFlushed:  true,
+		},
+		{
+			name:         "stale cache",
+			giveInjector: func() (Injector, error) { return NewStaleCacheInjector() },
+			wantFlushed:  true,
+		},
+		{
+			name:         "request body",
+			giveInjector: func() (Injector, error) { return NewRequestBodyInjector() },
+			wantFlushed:  true,
+		},
+		{
+			name: "chain",
+			giveInjector: func() (Injector, error) {
+				return NewChainInjector([]Injector{newTestInjectorNoop(), newTestInjectorNoop()})
+			},
+			wantFlushed: true,
+		},
+		{
+			name: "random",
+			giveInjector: func() (Injector, error) {
+				return NewRandomInjector([]Injector{newTestInjectorNoop()})
+			},
+			wantFlushed: true,
+		},
+		{
+			name: "sequence",
+			giveInjector: func() (Injector, error) {
+				return NewSequenceInjector([]Injector{newTestInjectorNoop()})
+			},
+			wantFlushed: true,
+		},
+		{
+			name: "conditional",
+			giveInjector: func() (Injector, error) {
+				return NewConditionalInjector(always, newTestInjectorNoop(), nil)
+			},
+			wantFlushed: true,
+		},
+		{
+			name: "response",
+			giveInjector: func() (Injector, error) {
+				return NewResponseInjector(newTestInjectorNoop(), func(int, http.Header) bool { return true })
+			},
+			wantFlushed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			i, err := tt.giveInjector()
+			assert.NoError(t, err)
+			f, err := NewFault(i, WithEnabled(true), WithParticipation(1.0))
+			assert.NoError(t, err)
+
+			h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.(http.Flusher).Flush()
+				assert.Equal(t, errTestPush, w.(http.Pusher).Push("/style.css", nil))
+				_, _, err := w.(http.Hijacker).Hijack()
+				assert.NoError(t, err)
+			}))
+
+			fw := &testFullWriter{ResponseRecorder: httptest.NewRecorder()}
+			h.ServeHTTP(fw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantFlushed, fw.Flushed)
+			assert.Equal(t, "/style.css", fw.pushed)
+			assert.True(t, fw.hijacked)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package fault
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// rotatingFileTimeFormat is appended to the names of rotated files.
+const rotatingFileTimeFormat = "20060102T150405.000000000"
+
+// RotatingFile is an io.WriteCloser that appends to a file and rotates it once it reaches a
+// maximum size, for use with an AuditReporter. Rotated files are renamed with the time of rotation
+// appended, such as "audit.log.20240102T150405.000000000", and are never removed.
+type RotatingFile struct {
+	path     string
+	maxBytes int64
+	nowF     func() time.Time
+
+	file *os.File
+	size int64
+
+	// fileMtx protects RotatingFile.file and RotatingFile.size.
+	fileMtx sync.Mutex
+}
+
+// NewRotatingFile opens or creates the file at path for appending and returns a RotatingFile that
+// rotates it before a write would grow it past maxBytes.
+func NewRotatingFile(path string, maxBytes int64) (*RotatingFile, error) {
+	if maxBytes < 1 {
+		return nil, &OptionError{Option: "NewRotatingFile", Value: maxBytes, Err: ErrInvalidCount}
+	}
+
+	rf := &RotatingFile{
+		path:     path,
+		maxBytes: maxBytes,
+		nowF:     time.Now,
+	}
+
+	err := rf.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+// Write appends p to the file, first rotating the file if p would grow it past the maximum size.
+// A single write larger than the maximum size is written to a new file on its own.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.fileMtx.Lock()
+	defer f.fileMtx.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxBytes {
+		err := f.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.fileMtx.Lock()
+	defer f.fileMtx.Unlock()
+
+	return f.file.Close()
+}
+
+// open opens the file at f.path for appending.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+
+	f.file = file
+	f.size = 0
+	if info, err := file.Stat(); err == nil {
+		f.size = info.Size()
+	}
+
+	return nil
+}
+
+// rotate closes the file, renames it, and opens a new file at f.path. If the file cannot be renamed
+// it is reopened so that later writes can try again.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	err = errors.Join(err, os.Rename(f.path, f.path+"."+f.nowF().UTC().Format(rotatingFileTimeFormat)))
+
+	return errors.Join(err, f.open())
+}
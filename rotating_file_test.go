@@ -0,0 +1,93 @@
+package fault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewRotatingFile tests NewRotatingFile.
+func TestNewRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	rf, err := NewRotatingFile(filepath.Join(dir, "audit.log"), 0)
+	assert.Nil(t, rf)
+	assert.Equal(t, &OptionError{Option: "NewRotatingFile", Value: int64(0), Err: ErrInvalidCount}, err)
+
+	rf, err = NewRotatingFile(filepath.Join(dir, "missing", "audit.log"), 10)
+	assert.Nil(t, rf)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// existing files are appended to
+	path := filepath.Join(dir, "existing.log")
+	assert.NoError(t, os.WriteFile(path, []byte("12345"), 0o600))
+	rf, err = NewRotatingFile(path, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), rf.size)
+	assert.NoError(t, rf.Close())
+}
+
+// TestRotatingFileWrite tests RotatingFile.Write.
+func TestRotatingFileWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	rf, err := NewRotatingFile(path, 10)
+	assert.NoError(t, err)
+	rf.nowF = func() time.Time { return now }
+
+	for _, line := range []string{"1234\n", "5678\n", "abcd\n", "this is too long\n"} {
+		n, err := rf.Write([]byte(line))
+		assert.NoError(t, err)
+		assert.Equal(t, len(line), n)
+		now = now.Add(time.Second)
+	}
+	assert.NoError(t, rf.Close())
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, "1234\n5678\n", read("audit.log.20240102T030407.000000000"))
+	assert.Equal(t, "abcd\n", read("audit.log.20240102T030408.000000000"))
+	assert.Equal(t, "this is too long\n", read("audit.log"))
+}
+
+// TestRotatingFileRotateError tests RotatingFile.Write when the file cannot be rotated.
+func TestRotatingFileRotateError(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	assert.NoError(t, os.Mkdir(dir, 0o700))
+	path := filepath.Join(dir, "audit.log")
+
+	rf, err := NewRotatingFile(path, 5)
+	assert.NoError(t, err)
+	_, err = rf.Write([]byte("1234\n"))
+	assert.NoError(t, err)
+
+	// the file cannot be renamed after it is removed, but is reopened
+	assert.NoError(t, os.Remove(path))
+	_, err = rf.Write([]byte("5678\n"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = rf.Write([]byte("5678\n"))
+	assert.NoError(t, err)
+
+	// the file cannot be reopened after the directory is removed
+	assert.NoError(t, os.RemoveAll(dir))
+	_, err = rf.Write([]byte("abcd\n"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
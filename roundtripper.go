@@ -0,0 +1,261 @@
+package fault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrInvalidTracePhase when an invalid TracePhase is provided.
+	ErrInvalidTracePhase = errors.New("not a valid trace phase")
+)
+
+// TracePhase determines the httptrace events that a RoundTripper emits around the time its Fault
+// spends injecting, so that client instrumentation built on httptrace shows injected latency in
+// that phase of the request.
+type TracePhase int
+
+const (
+	// TraceNone emits no events around the injection.
+	TraceNone TracePhase = iota
+	// TraceDNS emits DNSStart before the Fault runs and DNSDone once it continues or responds.
+	TraceDNS
+	// TraceConnect emits ConnectStart before the Fault runs and ConnectDone once it continues or
+	// responds.
+	TraceConnect
+	// TraceFirstByte sends the request first and holds back GotFirstResponseByte until the Fault has
+	// run on the response, so that injected latency shows up as time to first byte.
+	TraceFirstByte
+)
+
+// String returns the name of the TracePhase, such as "dns".
+func (p TracePhase) String() string {
+	switch p {
+	case TraceNone:
+		return "none"
+	case TraceDNS:
+		return "dns"
+	case TraceConnect:
+		return "connect"
+	case TraceFirstByte:
+		return "firstByte"
+	default:
+		return "TracePhase(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// RoundTripper is an http.RoundTripper that evaluates a Fault around each request sent by a client.
+type RoundTripper struct {
+	fault *Fault
+	next  http.RoundTripper
+	phase TracePhase
+}
+
+// RoundTripperOption configures a RoundTripper.
+type RoundTripperOption interface {
+	applyRoundTripper(rt *RoundTripper) error
+}
+
+type tracePhaseOption TracePhase
+
+func (o tracePhaseOption) applyRoundTripper(rt *RoundTripper) error {
+	if TracePhase(o) < TraceNone || TracePhase(o) > TraceFirstByte {
+		return &OptionError{Option: "WithTracePhase", Value: TracePhase(o), Err: ErrInvalidTracePhase}
+	}
+	rt.phase = TracePhase(o)
+	return nil
+}
+
+// WithTracePhase sets the phase of the request that the RoundTripper reports the time spent
+// injecting in, using the httptrace.ClientTrace of the request context. Default TraceNone.
+func WithTracePhase(p TracePhase) RoundTripperOption {
+	return tracePhaseOption(p)
+}
+
+// NewRoundTripper returns a RoundTripper that evaluates f around each request sent with next, or
+// with http.DefaultTransport if next is nil.
+func NewRoundTripper(f *Fault, next http.RoundTripper, opts ...RoundTripperOption) (*RoundTripper, error) {
+	if f == nil {
+		return nil, &OptionError{Option: "NewRoundTripper", Value: nil, Err: ErrNilFault}
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	// set defaults
+	rt := &RoundTripper{
+		fault: f,
+		next:  next,
+		phase: TraceNone,
+	}
+
+	// apply options
+	err := applyOptions(opts, RoundTripperOption.applyRoundTripper, rt)
+	if err != nil {
+		return nil, err
+	}
+
+	return rt, nil
+}
+
+// RoundTrip evaluates the Fault against req as if req were served by an http.Handler, and sends req
+// with the wrapped RoundTripper if the Injector continues the request. Injectors that change the
+// request, such as the RequestHeaderInjector, change the request that is sent. If the Injector
+// responds instead, such as the ErrorInjector, RoundTrip returns its response without sending req,
+// and if it aborts, such as the RejectInjector, RoundTrip returns an error wrapping ErrInjected.
+// Injectors that change the response written by a handler, such as the CharsetInjector, have no
+// effect. Responses from the Injector emit GotFirstResponseByte.
+//
+// With TraceFirstByte req is sent before the Fault runs, so Injectors that change the request have
+// no effect and the response is dropped if the Injector responds or aborts.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.phase == TraceFirstByte {
+		return rt.roundTripFirstByte(req)
+	}
+
+	trace := httptrace.ContextClientTrace(req.Context())
+	done := sync.OnceFunc(func() { rt.traceDone(trace, req) })
+	rt.traceStart(trace, req)
+
+	var called bool
+	var resp *http.Response
+	var err error
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		done()
+		resp, err = rt.next.RoundTrip(r)
+	})
+
+	rw, abortErr := rt.fault.serve(req, next)
+	done()
+
+	// req was not sent, so close its body as the wrapped RoundTripper would have
+	var closeErr error
+	if !called && req.Body != nil {
+		closeErr = req.Body.Close()
+	}
+
+	if abortErr != nil || closeErr != nil {
+		return nil, errors.Join(abortErr, closeErr)
+	}
+	if called {
+		if err != nil {
+			return nil, err
+		}
+		resp.Request = req
+		return resp, nil
+	}
+
+	return injectedResponse(trace, req, rw), nil
+}
+
+// roundTripFirstByte sends req and then evaluates the Fault, emitting GotFirstResponseByte once the
+// Fault continues or responds.
+func (rt *RoundTripper) roundTripFirstByte(req *http.Request) (*http.Response, error) {
+	trace := httptrace.ContextClientTrace(req.Context())
+
+	sent := req
+	if trace != nil {
+		held := *trace
+		held.GotFirstResponseByte = nil
+		sent = req.WithContext(traceContext{Context: req.Context(), trace: &held})
+	}
+
+	// the wrapped RoundTripper closes the body of req, including on errors
+	resp, err := rt.next.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	resp.Request = req
+
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	rw, abortErr := rt.fault.serve(req, next)
+	if abortErr == nil && called {
+		gotFirstResponseByte(trace)
+		return resp, nil
+	}
+
+	closeErr := resp.Body.Close()
+	if abortErr != nil || closeErr != nil {
+		return nil, errors.Join(abortErr, closeErr)
+	}
+
+	return injectedResponse(trace, req, rw), nil
+}
+
+// traceStart emits the event that starts the phase of the RoundTripper to trace, if it is not nil.
+func (rt *RoundTripper) traceStart(trace *httptrace.ClientTrace, req *http.Request) {
+	switch {
+	case trace == nil:
+	case rt.phase == TraceDNS && trace.DNSStart != nil:
+		trace.DNSStart(httptrace.DNSStartInfo{Host: req.URL.Hostname()})
+	case rt.phase == TraceConnect && trace.ConnectStart != nil:
+		trace.ConnectStart("tcp", req.URL.Host)
+	}
+}
+
+// traceDone emits the event that ends the phase of the RoundTripper to trace, if it is not nil.
+func (rt *RoundTripper) traceDone(trace *httptrace.ClientTrace, req *http.Request) {
+	switch {
+	case trace == nil:
+	case rt.phase == TraceDNS && trace.DNSDone != nil:
+		trace.DNSDone(httptrace.DNSDoneInfo{})
+	case rt.phase == TraceConnect && trace.ConnectDone != nil:
+		trace.ConnectDone("tcp", req.URL.Host, nil)
+	}
+}
+
+// gotFirstResponseByte emits GotFirstResponseByte to trace, if it is not nil.
+func gotFirstResponseByte(trace *httptrace.ClientTrace) {
+	if trace != nil && trace.GotFirstResponseByte != nil {
+		trace.GotFirstResponseByte()
+	}
+}
+
+// injectedResponse returns the response that an Injector wrote to rw for req, emitting
+// GotFirstResponseByte to trace.
+func injectedResponse(trace *httptrace.ClientTrace, req *http.Request, rw *ResponseRecorderWriter) *http.Response {
+	gotFirstResponseByte(trace)
+
+	code := rw.StatusCode()
+	body := rw.Body()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rw.Header(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// traceContext is a context whose httptrace.ClientTrace is replaced with trace, so that the hooks
+// of the trace of its parent can be held back.
+type traceContext struct {
+	context.Context
+	trace *httptrace.ClientTrace
+}
+
+// Value returns c.trace in place of the httptrace.ClientTrace of the parent, and otherwise the
+// value of the parent for key.
+func (c traceContext) Value(key any) any {
+	v := c.Context.Value(key)
+	if _, ok := v.(*httptrace.ClientTrace); ok {
+		return c.trace
+	}
+	return v
+}
@@ -0,0 +1,512 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestRoundTrip = errors.New("error from test round tripper")
+	errTestClose     = errors.New("error closing test body")
+)
+
+// testRoundTripperContextKey is used to verify that the sent request keeps its context values.
+type testRoundTripperContextKey struct{}
+
+// testRoundTripper is an http.RoundTripper that responds with testHandlerCode and testHandlerBody
+// without using the network. It records the events of the request in events and emits
+// GotFirstResponseByte to the trace of the request like a real transport.
+type testRoundTripper struct {
+	events   *[]string
+	err      error
+	closeErr error
+}
+
+// RoundTrip records that req was sent and responds with testHandlerCode or t.err.
+func (t *testRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.events = append(*t.events, "sent "+req.Header.Get(testHeaderKey))
+	if req.Context().Value(testRoundTripperContextKey{}) == nil {
+		*t.events = append(*t.events, "lost context")
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	if trace := httptrace.ContextClientTrace(req.Context()); trace != nil && trace.GotFirstResponseByte != nil {
+		trace.GotFirstResponseByte()
+	}
+
+	return &http.Response{
+		StatusCode: testHandlerCode,
+		Body:       &testBody{Reader: io.NopCloser(nil), err: t.closeErr},
+		Request:    req,
+	}, nil
+}
+
+// testBody is a response body that returns err when closed.
+type testBody struct {
+	io.Reader
+	err error
+}
+
+// Close returns b.err.
+func (b *testBody) Close() error {
+	return b.err
+}
+
+// testClientTrace returns an httptrace.ClientTrace that records its events in events.
+func testClientTrace(events *[]string) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { *events = append(*events, "dnsStart") },
+		DNSDone:              func(httptrace.DNSDoneInfo) { *events = append(*events, "dnsDone") },
+		ConnectStart:         func(string, string) { *events = append(*events, "connectStart") },
+		ConnectDone:          func(string, string, error) { *events = append(*events, "connectDone") },
+		GotFirstResponseByte: func() { *events = append(*events, "firstByte") },
+	}
+}
+
+// TestNewRoundTripper tests NewRoundTripper.
+func TestNewRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	fault, err := NewFault(newTestInjectorNoop())
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		giveFault   *Fault
+		giveNext    http.RoundTripper
+		giveOptions []RoundTripperOption
+		wantNext    http.RoundTripper
+		wantPhase   TracePhase
+		wantErr     error
+	}{
+		{
+			name:      "defaults",
+			giveFault: fault,
+			giveNext:  nil,
+			wantNext:  http.DefaultTransport,
+			wantPhase: TraceNone,
+			wantErr:   nil,
+		},
+		{
+			name:      "custom next",
+			giveFault: fault,
+			giveNext:  &testRoundTripper{},
+			wantNext:  &testRoundTripper{},
+			wantPhase: TraceNone,
+			wantErr:   nil,
+		},
+		{
+			name:        "with trace phase",
+			giveFault:   fault,
+			giveOptions: []RoundTripperOption{WithTracePhase(TraceFirstByte)},
+			wantNext:    http.DefaultTransport,
+			wantPhase:   TraceFirstByte,
+			wantErr:     nil,
+		},
+		{
+			name:      "nil fault",
+			giveFault: nil,
+			wantErr:   ErrNilFault,
+		},
+		{
+			name:        "negative trace phase",
+			giveFault:   fault,
+			giveOptions: []RoundTripperOption{WithTracePhase(-1)},
+			wantErr:     ErrInvalidTracePhase,
+		},
+		{
+			name:        "unknown trace phase",
+			giveFault:   fault,
+			giveOptions: []RoundTripperOption{WithTracePhase(TraceFirstByte + 1)},
+			wantErr:     ErrInvalidTracePhase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt, err := NewRoundTripper(tt.giveFault, tt.giveNext, tt.giveOptions...)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				assert.Nil(t, rt)
+				return
+			}
+			assert.Equal(t, tt.wantNext, rt.next)
+			assert.Equal(t, tt.wantPhase, rt.phase)
+		})
+	}
+}
+
+// TestTracePhaseString tests TracePhase.String.
+func TestTracePhaseString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "none", TraceNone.String())
+	assert.Equal(t, "dns", TraceDNS.String())
+	assert.Equal(t, "connect", TraceConnect.String())
+	assert.Equal(t, "firstByte", TraceFirstByte.String())
+	assert.Equal(t, "TracePhase(7)", TracePhase(7).String())
+}
+
+// TestRoundTripperRoundTrip tests RoundTripper.RoundTrip.
+func TestRoundTripperRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	continueInjector := newTestInjectorNoop()
+	respondInjector := newTestInjector500s()
+	abortInjector, err := NewRejectInjector()
+	assert.NoError(t, err)
+	headerInjector, err := NewRequestHeaderInjector(WithSetHeaders(map[string]string{testHeaderKey: "injected"}))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		givePhase    TracePhase
+		giveInjector Injector
+		giveTrace    bool
+		giveErr      error
+		giveCloseErr error
+		wantEvents   []string
+		wantCode     int
+		wantFromNext bool
+		wantErr      []error
+	}{
+		{
+			name:         "none continue",
+			givePhase:    TraceNone,
+			giveInjector: continueInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"sent original", "firstByte"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "none continue no trace",
+			givePhase:    TraceNone,
+			giveInjector: continueInjector,
+			giveTrace:    false,
+			wantEvents:   []string{"sent original"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "none change request",
+			givePhase:    TraceNone,
+			giveInjector: headerInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"sent injected", "firstByte"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "none respond",
+			givePhase:    TraceNone,
+			giveInjector: respondInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"firstByte"},
+			wantCode:     http.StatusInternalServerError,
+		},
+		{
+			name:         "none abort",
+			givePhase:    TraceNone,
+			giveInjector: abortInjector,
+			giveTrace:    true,
+			wantEvents:   nil,
+			wantErr:      []error{ErrInjected, http.ErrAbortHandler},
+		},
+		{
+			name:         "none transport error",
+			givePhase:    TraceNone,
+			giveInjector: continueInjector,
+			giveTrace:    true,
+			giveErr:      errTestRoundTrip,
+			wantEvents:   []string{"sent original"},
+			wantErr:      []error{errTestRoundTrip},
+		},
+		{
+			name:         "dns continue",
+			givePhase:    TraceDNS,
+			giveInjector: continueInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"dnsStart", "dnsDone", "sent original", "firstByte"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "dns respond",
+			givePhase:    TraceDNS,
+			giveInjector: respondInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"dnsStart", "dnsDone", "firstByte"},
+			wantCode:     http.StatusInternalServerError,
+		},
+		{
+			name:         "dns abort",
+			givePhase:    TraceDNS,
+			giveInjector: abortInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"dnsStart", "dnsDone"},
+			wantErr:      []error{ErrInjected, http.ErrAbortHandler},
+		},
+		{
+			name:         "dns no trace",
+			givePhase:    TraceDNS,
+			giveInjector: respondInjector,
+			giveTrace:    false,
+			wantEvents:   nil,
+			wantCode:     http.StatusInternalServerError,
+		},
+		{
+			name:         "connect continue",
+			givePhase:    TraceConnect,
+			giveInjector: continueInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"connectStart", "connectDone", "sent original", "firstByte"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "connect respond",
+			givePhase:    TraceConnect,
+			giveInjector: respondInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"connectStart", "connectDone", "firstByte"},
+			wantCode:     http.StatusInternalServerError,
+		},
+		{
+			name:         "first byte continue",
+			givePhase:    TraceFirstByte,
+			giveInjector: continueInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"sent original", "firstByte"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "first byte continue no trace",
+			givePhase:    TraceFirstByte,
+			giveInjector: continueInjector,
+			giveTrace:    false,
+			wantEvents:   []string{"sent original"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "first byte change request",
+			givePhase:    TraceFirstByte,
+			giveInjector: headerInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"sent original", "firstByte"},
+			wantCode:     testHandlerCode,
+			wantFromNext: true,
+		},
+		{
+			name:         "first byte respond",
+			givePhase:    TraceFirstByte,
+			giveInjector: respondInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"sent original", "firstByte"},
+			wantCode:     http.StatusInternalServerError,
+		},
+		{
+			name:         "first byte respond close error",
+			givePhase:    TraceFirstByte,
+			giveInjector: respondInjector,
+			giveTrace:    true,
+			giveCloseErr: errTestClose,
+			wantEvents:   []string{"sent original"},
+			wantErr:      []error{errTestClose},
+		},
+		{
+			name:         "first byte abort",
+			givePhase:    TraceFirstByte,
+			giveInjector: abortInjector,
+			giveTrace:    true,
+			wantEvents:   []string{"sent original"},
+			wantErr:      []error{ErrInjected, http.ErrAbortHandler},
+		},
+		{
+			name:         "first byte abort close error",
+			givePhase:    TraceFirstByte,
+			giveInjector: abortInjector,
+			giveTrace:    true,
+			giveCloseErr: errTestClose,
+			wantEvents:   []string{"sent original"},
+			wantErr:      []error{ErrInjected, errTestClose},
+		},
+		{
+			name:         "first byte transport error",
+			givePhase:    TraceFirstByte,
+			giveInjector: continueInjector,
+			giveTrace:    true,
+			giveErr:      errTestRoundTrip,
+			wantEvents:   []string{"sent original"},
+			wantErr:      []error{errTestRoundTrip},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var events []string
+			next := &testRoundTripper{events: &events, err: tt.giveErr, closeErr: tt.giveCloseErr}
+
+			f, err := NewFault(tt.giveInjector, WithEnabled(true), WithParticipation(1.0))
+			assert.NoError(t, err)
+			rt, err := NewRoundTripper(f, next, WithTracePhase(tt.givePhase))
+			assert.NoError(t, err)
+
+			ctx := context.WithValue(context.Background(), testRoundTripperContextKey{}, true)
+			if tt.giveTrace {
+				ctx = httptrace.WithClientTrace(ctx, testClientTrace(&events))
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil).WithContext(ctx)
+			req.Header.Set(testHeaderKey, "original")
+
+			resp, err := rt.RoundTrip(req)
+
+			assert.Equal(t, tt.wantEvents, events)
+			if tt.wantErr != nil {
+				for _, wantErr := range tt.wantErr {
+					assert.ErrorIs(t, err, wantErr)
+				}
+				assert.Nil(t, resp)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			assert.Same(t, req, resp.Request)
+			_, fromNext := resp.Body.(*testBody)
+			assert.Equal(t, tt.wantFromNext, fromNext)
+		})
+	}
+}
+
+// TestRoundTripperRoundTripResponse tests the response RoundTripper.RoundTrip returns when the
+// Injector responds.
+func TestRoundTripperRoundTripResponse(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjector500s(), WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+	rt, err := NewRoundTripper(f, &testRoundTripper{})
+	assert.NoError(t, err)
+
+	resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	wantBody := http.StatusText(http.StatusInternalServerError) + "\n"
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "500 Internal Server Error", resp.Status)
+	assert.Equal(t, "HTTP/1.1", resp.Proto)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, wantBody, string(body))
+	assert.Equal(t, int64(len(wantBody)), resp.ContentLength)
+}
+
+// testRequestBody is a request body that records whether it was closed and returns err when closed.
+type testRequestBody struct {
+	io.Reader
+	closed bool
+	err    error
+}
+
+// Close records that b was closed and returns b.err.
+func (b *testRequestBody) Close() error {
+	b.closed = true
+	return b.err
+}
+
+// TestRoundTripperRoundTripClosesBody tests that RoundTripper.RoundTrip closes the request body
+// when the Injector does not send the request.
+func TestRoundTripperRoundTripClosesBody(t *testing.T) {
+	t.Parallel()
+
+	abortInjector, err := NewRejectInjector()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		giveInjector Injector
+		giveCloseErr error
+		wantClosed   bool
+		wantErr      []error
+	}{
+		{
+			name:         "continue",
+			giveInjector: newTestInjectorNoop(),
+			wantClosed:   false,
+		},
+		{
+			name:         "respond",
+			giveInjector: newTestInjector500s(),
+			wantClosed:   true,
+		},
+		{
+			name:         "respond close error",
+			giveInjector: newTestInjector500s(),
+			giveCloseErr: errTestClose,
+			wantClosed:   true,
+			wantErr:      []error{errTestClose},
+		},
+		{
+			name:         "abort",
+			giveInjector: abortInjector,
+			wantClosed:   true,
+			wantErr:      []error{ErrInjected},
+		},
+		{
+			name:         "abort close error",
+			giveInjector: abortInjector,
+			giveCloseErr: errTestClose,
+			wantClosed:   true,
+			wantErr:      []error{ErrInjected, errTestClose},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var events []string
+			f, err := NewFault(tt.giveInjector, WithEnabled(true), WithParticipation(1.0))
+			assert.NoError(t, err)
+			rt, err := NewRoundTripper(f, &testRoundTripper{events: &events})
+			assert.NoError(t, err)
+
+			body := &testRequestBody{Reader: strings.NewReader("body"), err: tt.giveCloseErr}
+			ctx := context.WithValue(context.Background(), testRoundTripperContextKey{}, true)
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/", body).WithContext(ctx)
+			req.Body = body
+
+			resp, err := rt.RoundTrip(req)
+
+			// the testRoundTripper does not close the body of requests it is sent
+			assert.Equal(t, tt.wantClosed, body.closed)
+			for _, wantErr := range tt.wantErr {
+				assert.ErrorIs(t, err, wantErr)
+			}
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				assert.NoError(t, resp.Body.Close())
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package fault
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrInvalidScope when a scope name is empty or contains a "/".
+	ErrInvalidScope = errors.New("scope name must not be empty or contain /")
+)
+
+// registryEntry is a Fault in a Registry or one of its scopes.
+type registryEntry struct {
+	// name is the name of the Fault qualified by the scopes it is in, such as "payments/a".
+	name  string
+	fault *Fault
+	// enabled is true if the Fault and every scope it is in are enabled.
+	enabled bool
+}
+
+// Scope returns a new Registry scoped under r with name, so that teams can each control their own
+// Faults while sharing one admin surface. The Faults registered in the scope are part of r under
+// their name prefixed with the scope, such as "payments/a", so the Status, Snapshot, Faults, Toggle,
+// Drain, and Experiments of r include them. Scopes can be nested. opts configure the scope like
+// NewRegistry, and the scope uses the time function of r by default.
+//
+// Disable a scope with SetEnabled to stop every Fault in it without changing their own enabled
+// state, and pass WithCoordinator to give the scope a budget that every injection of its Faults must
+// fit in. name must not be empty, contain "/", or already be used by another scope of r.
+func (r *Registry) Scope(name string, opts ...RegistryOption) (*Registry, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, &OptionError{Option: "Scope", Value: name, Err: ErrInvalidScope}
+	}
+
+	// set defaults
+	scope := &Registry{
+		name:       name,
+		parent:     r,
+		nowF:       r.nowF,
+		drainTickF: r.drainTickF,
+	}
+	scope.enabled.Store(true)
+
+	// apply options
+	err := applyOptions(opts, RegistryOption.applyRegistry, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for _, s := range r.scopes {
+		if s.name == name {
+			return nil, &OptionError{Option: "Scope", Value: name, Err: ErrDuplicateName}
+		}
+	}
+	r.scopes = append(r.scopes, scope)
+
+	return scope, nil
+}
+
+// SetEnabled enables or disables every Fault in the Registry and its scopes, without changing the
+// enabled state of the Faults themselves. Registries are enabled by default.
+func (r *Registry) SetEnabled(e bool) {
+	if r.enabled.Swap(e) != e {
+		r.notify()
+	}
+}
+
+// Enabled returns true unless the Registry was disabled with SetEnabled. A Registry that is enabled
+// in a disabled scope is still Enabled, but its Faults do not inject.
+func (r *Registry) Enabled() bool {
+	return r.enabled.Load()
+}
+
+// entries returns the Faults in r followed by the Faults in each of its scopes, in the order they
+// were registered and the scopes were created.
+func (r *Registry) entries() []registryEntry {
+	return r.appendEntries(nil, "", true)
+}
+
+// appendEntries appends the Faults in r and its scopes to entries, prefixing their names with
+// prefix. enabled is false if a scope that r is in is disabled.
+func (r *Registry) appendEntries(entries []registryEntry, prefix string, enabled bool) []registryEntry {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	enabled = enabled && r.enabled.Load()
+	for _, f := range r.faults {
+		entries = append(entries, registryEntry{name: prefix + f.name, fault: f, enabled: enabled && f.enabled.Load()})
+	}
+	for _, s := range r.scopes {
+		entries = s.appendEntries(entries, prefix+s.name+"/", enabled)
+	}
+
+	return entries
+}
+
+// scopeEnabled returns true if the Fault is not registered or if its Registry and every Registry
+// that it is scoped under are enabled.
+func (f *Fault) scopeEnabled() bool {
+	for reg := f.registry.Load(); reg != nil; reg = reg.parent {
+		if !reg.enabled.Load() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// scopeAllow returns true if the Coordinators of the Registry of the Fault and of every Registry
+// that it is scoped under allow injecting into r. Each Coordinator is passed the name of the Fault
+// qualified by the scopes below its Registry.
+func (f *Fault) scopeAllow(r *http.Request) bool {
+	name := f.name
+	for reg := f.registry.Load(); reg != nil; reg = reg.parent {
+		if reg.coordinator != nil && !reg.coordinator.Allow(r.Context(), name) {
+			return false
+		}
+		if reg.parent != nil {
+			name = reg.name + "/" + name
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistryScope tests Registry.Scope.
+func TestRegistryScope(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100, 0)
+	reg, err := NewRegistry(WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		giveName    string
+		giveOptions []RegistryOption
+		wantErr     error
+	}{
+		{
+			name:     "valid",
+			giveName: "payments",
+			wantErr:  nil,
+		},
+		{
+			name:        "with coordinator",
+			giveName:    "search",
+			giveOptions: []RegistryOption{WithCoordinator(&testCoordinator{})},
+			wantErr:     nil,
+		},
+		{
+			name:     "empty",
+			giveName: "",
+			wantErr:  &OptionError{Option: "Scope", Value: "", Err: ErrInvalidScope},
+		},
+		{
+			name:     "slash",
+			giveName: "a/b",
+			wantErr:  &OptionError{Option: "Scope", Value: "a/b", Err: ErrInvalidScope},
+		},
+		{
+			name:        "nil coordinator",
+			giveName:    "nil",
+			giveOptions: []RegistryOption{WithCoordinator(nil)},
+			wantErr:     &OptionError{Option: "WithCoordinator", Value: nil, Err: ErrNilCoordinator},
+		},
+		{
+			name:        "option error",
+			giveName:    "error",
+			giveOptions: []RegistryOption{withError()},
+			wantErr:     errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		scope, err := reg.Scope(tt.giveName, tt.giveOptions...)
+		assert.Equal(t, tt.wantErr, err, tt.name)
+		assert.Equal(t, tt.wantErr == nil, scope != nil, tt.name)
+	}
+
+	_, err = reg.Scope("payments")
+	assert.Equal(t, &OptionError{Option: "Scope", Value: "payments", Err: ErrDuplicateName}, err)
+
+	// scopes use the time function of their parent
+	scope, err := reg.Scope("orders")
+	assert.NoError(t, err)
+	assert.NoError(t, scope.Load())
+	assert.Equal(t, now, scope.Snapshot().LoadedAt)
+}
+
+// TestRegistryScopeNames tests that the Faults in scopes are part of their parents under names
+// qualified by the scope.
+func TestRegistryScopeNames(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	payments, err := reg.Scope("payments")
+	assert.NoError(t, err)
+	eu, err := payments.Scope("eu")
+	assert.NoError(t, err)
+
+	a := testRegistryFault(t, "a")
+	pa := testRegistryFault(t, "a")
+	eb := testRegistryFault(t, "b")
+	assert.NoError(t, eu.Register(eb))
+	assert.NoError(t, payments.Register(pa))
+	assert.NoError(t, reg.Register(a))
+
+	assert.Equal(t, []*Fault{a, pa, eb}, reg.Faults())
+	assert.Equal(t, []*Fault{pa, eb}, payments.Faults())
+	assert.Same(t, pa, reg.Fault("payments/a"))
+	assert.Same(t, eb, reg.Fault("payments/eu/b"))
+	assert.Same(t, eb, payments.Fault("eu/b"))
+
+	var names []string
+	for _, fs := range reg.Status().FaultStatuses {
+		names = append(names, fs.Name)
+	}
+	assert.Equal(t, []string{"a", "payments/a", "payments/eu/b"}, names)
+
+	// Toggle and Drain include the Faults in scopes
+	assert.False(t, reg.Toggle())
+	assert.False(t, eb.enabled.Load())
+}
+
+// TestRegistryScopeSetEnabled tests that disabling a Registry stops the Faults in it and its scopes
+// from injecting.
+func TestRegistryScopeSetEnabled(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	payments, err := reg.Scope("payments")
+	assert.NoError(t, err)
+	eu, err := payments.Scope("eu")
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjector500s(), WithEnabled(true), WithParticipation(1.0), WithName("a"))
+	assert.NoError(t, err)
+	assert.NoError(t, eu.Register(f))
+
+	var got []Snapshot
+	reg.Subscribe(func(s Snapshot) {
+		got = append(got, s)
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, testRequest(t, f).Code)
+
+	payments.SetEnabled(false)
+	payments.SetEnabled(false)
+	assert.False(t, payments.Enabled())
+	assert.True(t, eu.Enabled())
+	assert.Equal(t, testHandlerCode, testRequest(t, f).Code)
+	assert.True(t, f.enabled.Load())
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, []FaultSnapshot{{Name: "payments/eu/a", Enabled: false, Description: f.String()}}, got[0].Faults)
+	assert.Equal(t, 0, reg.Status().Active)
+
+	payments.SetEnabled(true)
+	assert.Len(t, got, 2)
+	assert.Equal(t, http.StatusInternalServerError, testRequest(t, f).Code)
+
+	// changes to Faults in scopes are reported to the parent
+	f.SetEnabled(false)
+	assert.Len(t, got, 3)
+}
+
+// TestRegistryScopeCoordinator tests that the Coordinators of a Registry and its parents must allow
+// every injection of the Faults in it.
+func TestRegistryScopeCoordinator(t *testing.T) {
+	t.Parallel()
+
+	rootCoordinator := &testCoordinator{allow: true}
+	reg, err := NewRegistry(WithCoordinator(rootCoordinator))
+	assert.NoError(t, err)
+	paymentsCoordinator := &testCoordinator{allow: true}
+	payments, err := reg.Scope("payments", WithCoordinator(paymentsCoordinator))
+	assert.NoError(t, err)
+	eu, err := payments.Scope("eu")
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjector500s(), WithEnabled(true), WithParticipation(1.0), WithName("a"))
+	assert.NoError(t, err)
+	assert.NoError(t, eu.Register(f))
+
+	assert.Equal(t, http.StatusInternalServerError, testRequest(t, f).Code)
+	assert.Equal(t, []string{"eu/a"}, paymentsCoordinator.names)
+	assert.Equal(t, []string{"payments/eu/a"}, rootCoordinator.names)
+
+	paymentsCoordinator.allow = false
+	assert.Equal(t, testHandlerCode, testRequest(t, f).Code)
+	assert.Equal(t, Stats{Injected: 1, Skipped: 1}, f.Stats())
+	assert.Len(t, rootCoordinator.names, 1)
+
+	// Faults removed from a Registry leave its scope
+	assert.NoError(t, eu.Load())
+	assert.Equal(t, http.StatusInternalServerError, testRequest(t, f).Code)
+}
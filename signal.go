@@ -0,0 +1,67 @@
+package fault
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Toggle disables every Fault in the Registry if any of them are enabled, and otherwise enables
+// every Fault. It returns true if the Faults are now enabled.
+func (r *Registry) Toggle() bool {
+	faults := r.Faults()
+
+	enable := true
+	for _, f := range faults {
+		if f.enabled.Load() {
+			enable = false
+			break
+		}
+	}
+
+	for _, f := range faults {
+		f.setEnabled(enable)
+	}
+
+	return enable
+}
+
+// ToggleOnSignal calls Toggle each time the process receives one of the signals, such as
+// syscall.SIGUSR2, giving operators a way to turn faults off and on with kill when there is no
+// admin port. Until stop is called the signals no longer have their default behavior, such as
+// terminating the process. stop waits for any Toggle in progress to finish.
+func (r *Registry) ToggleOnSignal(sig os.Signal, sigs ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, append([]os.Signal{sig}, sigs...)...)
+
+	return r.toggleOn(c, func() { signal.Stop(c) })
+}
+
+// toggleOn calls Toggle for each value received from c until stop is called. unsubscribe is called
+// once when stopping, before waiting for the last Toggle.
+func (r *Registry) toggleOn(c <-chan os.Signal, unsubscribe func()) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-c:
+				r.Toggle()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unsubscribe()
+			close(done)
+			wg.Wait()
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package fault
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistryToggle tests Registry.Toggle.
+func TestRegistryToggle(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	a := testRegistryFault(t, "a")
+	b := testRegistryFault(t, "b")
+	b.SetEnabled(false)
+	assert.NoError(t, reg.Register(a, b))
+
+	// any enabled Fault disables all of them
+	assert.False(t, reg.Toggle())
+	assert.False(t, a.enabled.Load())
+	assert.False(t, b.enabled.Load())
+
+	assert.True(t, reg.Toggle())
+	assert.True(t, a.enabled.Load())
+	assert.True(t, b.enabled.Load())
+}
+
+// TestRegistryToggleOn tests that Registry.toggleOn toggles for each signal until stopped.
+func TestRegistryToggleOn(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	f := testRegistryFault(t, "a")
+	assert.NoError(t, reg.Register(f))
+
+	c := make(chan os.Signal)
+	var unsubscribed int
+	stop := reg.toggleOn(c, func() { unsubscribed++ })
+
+	c <- os.Interrupt
+	c <- os.Interrupt
+	c <- os.Interrupt
+
+	stop()
+	stop()
+
+	assert.False(t, f.enabled.Load())
+	assert.Equal(t, 1, unsubscribed)
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package fault
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistryToggleOnSignal tests Registry.ToggleOnSignal with a real signal.
+func TestRegistryToggleOnSignal(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	f := testRegistryFault(t, "a")
+	assert.NoError(t, reg.Register(f))
+
+	stop := reg.ToggleOnSignal(syscall.SIGUSR2)
+	defer stop()
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	assert.Eventually(t, func() bool { return !f.enabled.Load() }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,57 @@
+package fault
+
+import (
+	"net/http"
+)
+
+type skipHealthEndpointsOption []string
+
+func (o skipHealthEndpointsOption) applyFault(f *Fault) error {
+	paths := []string{"/healthz", "/livez", "/readyz", "/health", "/ping", "/metrics"}
+
+	f.skipPaths = make(map[string]bool, len(paths)+len(o))
+	for _, path := range append(paths, o...) {
+		f.skipPaths[path] = true
+	}
+	return nil
+}
+
+// WithSkipHealthEndpoints prevents the Injector from running against common health check, readiness,
+// and metrics paths: /healthz, /livez, /readyz, /health, /ping, and /metrics, along with any extra
+// paths provided. Injecting into these paths can cause orchestrators to restart or remove healthy
+// instances. The paths are skipped in addition to any WithPathBlocklist.
+func WithSkipHealthEndpoints(extra ...string) Option {
+	return skipHealthEndpointsOption(extra)
+}
+
+type skipPreflightOption struct{}
+
+func (o skipPreflightOption) applyFault(f *Fault) error {
+	f.skipPreflight = true
+	return nil
+}
+
+// WithSkipPreflight prevents the Injector from running against CORS preflight requests, which are
+// OPTIONS requests with an Access-Control-Request-Method header. Browsers report failed preflights
+// as opaque CORS errors that do not represent the failure being tested.
+func WithSkipPreflight() Option {
+	return skipPreflightOption{}
+}
+
+// checkSkip returns the Reason the Fault always skips the request, or reasonNone if it does not.
+func (f *Fault) checkSkip(r *http.Request) Reason {
+	if f.skipPreflight && isPreflight(r) {
+		return ReasonPreflight
+	}
+
+	if f.skipPaths[r.URL.Path] {
+		return ReasonSkipPath
+	}
+
+	return reasonNone
+}
+
+// isPreflight returns true if r is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
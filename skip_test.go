@@ -0,0 +1,142 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithSkipHealthEndpoints tests that WithSkipHealthEndpoints skips health check paths.
+func TestWithSkipHealthEndpoints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		givePath    string
+		giveOptions []Option
+		wantCode    int
+	}{
+		{
+			name:        "healthz",
+			givePath:    "/healthz",
+			giveOptions: []Option{WithSkipHealthEndpoints()},
+			wantCode:    testHandlerCode,
+		},
+		{
+			name:        "metrics",
+			givePath:    "/metrics",
+			giveOptions: []Option{WithSkipHealthEndpoints()},
+			wantCode:    testHandlerCode,
+		},
+		{
+			name:        "extra",
+			givePath:    "/status",
+			giveOptions: []Option{WithSkipHealthEndpoints("/status")},
+			wantCode:    testHandlerCode,
+		},
+		{
+			name:        "other path",
+			givePath:    "/api",
+			giveOptions: []Option{WithSkipHealthEndpoints("/status")},
+			wantCode:    http.StatusInternalServerError,
+		},
+		{
+			name:     "with blocklist",
+			givePath: "/api",
+			giveOptions: []Option{
+				WithSkipHealthEndpoints(),
+				WithPathBlocklist([]string{"/api"}),
+			},
+			wantCode: testHandlerCode,
+		},
+		{
+			name:        "not set",
+			givePath:    "/healthz",
+			giveOptions: nil,
+			wantCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithParticipation(1.0)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, testHandlerBody, testHandlerCode)
+			})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.givePath, nil))
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
+// TestWithSkipPreflight tests that WithSkipPreflight skips CORS preflight requests.
+func TestWithSkipPreflight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveMethod  string
+		giveHeader  string
+		giveOptions []Option
+		wantCode    int
+	}{
+		{
+			name:        "preflight",
+			giveMethod:  http.MethodOptions,
+			giveHeader:  http.MethodPost,
+			giveOptions: []Option{WithSkipPreflight()},
+			wantCode:    testHandlerCode,
+		},
+		{
+			name:        "options without request method",
+			giveMethod:  http.MethodOptions,
+			giveHeader:  "",
+			giveOptions: []Option{WithSkipPreflight()},
+			wantCode:    http.StatusInternalServerError,
+		},
+		{
+			name:        "get with request method",
+			giveMethod:  http.MethodGet,
+			giveHeader:  http.MethodPost,
+			giveOptions: []Option{WithSkipPreflight()},
+			wantCode:    http.StatusInternalServerError,
+		},
+		{
+			name:        "not set",
+			giveMethod:  http.MethodOptions,
+			giveHeader:  http.MethodPost,
+			giveOptions: nil,
+			wantCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithParticipation(1.0)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(tt.giveMethod, "/", nil)
+			if tt.giveHeader != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.giveHeader)
+			}
+
+			rr := httptest.NewRecorder()
+			f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, testHandlerBody, testHandlerCode)
+			})).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package fault
+
+// The constants of the per-request random source, see WithSplitRand.
+const (
+	// splitRandGamma is the odd increment of SplitMix64 between the states of consecutive requests.
+	splitRandGamma = 0x9e3779b97f4a7c15
+	// float32Bits is the number of random bits in a float32 in [0.0,1.0).
+	float32Bits = 24
+)
+
+type splitRandOption bool
+
+func (o splitRandOption) applyFault(f *Fault) error {
+	f.splitRand = bool(o)
+	return nil
+}
+
+// WithSplitRand sets if the Fault derives the random number for each participation roll from the
+// seed set with WithRandSeed and a count of its rolls, in the style of SplitMix64, instead of drawing
+// it from one rand.Rand behind a mutex. Default false. Rolls are as reproducible as the default for
+// a given seed and order of requests, but concurrent requests never wait on each other for a random
+// number, which removes a point of contention from enabled Faults on busy servers. It applies to
+// WithParticipation, WithParticipationFunc, and WithParticipationRatio, and WithRandFloat32Func does
+// not apply.
+func WithSplitRand(s bool) Option {
+	return splitRandOption(s)
+}
+
+// roll returns the next random float32 in [0.0,1.0) for participation.
+func (f *Fault) roll() float32 {
+	if f.splitRand {
+		return float32(f.splitUint64()>>(64-float32Bits)) / (1 << float32Bits)
+	}
+
+	f.randMtx.Lock()
+	defer f.randMtx.Unlock()
+
+	return f.randF()
+}
+
+// rollN returns the next random int64 in [0,n) for participation.
+func (f *Fault) rollN(n int64) int64 {
+	if f.splitRand {
+		return int64(f.splitUint64() % uint64(n))
+	}
+
+	f.randMtx.Lock()
+	defer f.randMtx.Unlock()
+
+	return f.rand.Int63n(n)
+}
+
+// splitUint64 returns a random uint64 derived from the seed of the Fault and the number of times it
+// has been called, by mixing the SplitMix64 state of that call with mix64.
+func (f *Fault) splitUint64() uint64 {
+	n := f.splitCounter.Add(1)
+	return mix64(uint64(f.randSeed) + n*splitRandGamma)
+}
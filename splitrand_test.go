@@ -0,0 +1,83 @@
+package fault
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSplitRand tests that WithSplitRand rolls are uniform and reproducible for a seed.
+func TestSplitRand(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithSplitRand(true))
+	assert.NoError(t, err)
+	same, err := NewFault(newTestInjectorNoop(), WithSplitRand(true), WithRandFloat32Func(func() float32 { return 0 }))
+	assert.NoError(t, err)
+	other, err := NewFault(newTestInjectorNoop(), WithSplitRand(true), WithRandSeed(2))
+	assert.NoError(t, err)
+
+	var sum float64
+	var differ bool
+	for range 10000 {
+		rn := f.roll()
+		assert.GreaterOrEqual(t, rn, float32(0.0))
+		assert.Less(t, rn, float32(1.0))
+		assert.Equal(t, rn, same.roll())
+		differ = differ || rn != other.roll()
+		sum += float64(rn)
+
+		n := f.rollN(3)
+		assert.Less(t, n, int64(3))
+		assert.Equal(t, n, same.rollN(3))
+	}
+
+	assert.InDelta(t, 0.5, sum/10000, 0.01)
+	assert.True(t, differ, "different seeds roll differently")
+}
+
+// TestSplitRandHandler tests that Faults with WithSplitRand participate in proportion to their
+// participation from concurrent requests.
+func TestSplitRandHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+	}{
+		{
+			name:        "participation",
+			giveOptions: []Option{WithParticipation(0.25)},
+		},
+		{
+			name:        "ratio",
+			giveOptions: []Option{WithParticipationRatio(1, 4)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithSplitRand(true)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for range 4 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 1000 {
+						testRequest(t, f)
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.InDelta(t, 1000, f.Stats().Injected, 150)
+			assert.Equal(t, uint64(4000), f.splitCounter.Load())
+		})
+	}
+}
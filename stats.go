@@ -0,0 +1,229 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrInvalidBuckets when histogram buckets are empty, not greater than 0, or not increasing.
+	ErrInvalidBuckets = errors.New("buckets must be greater than 0 and increasing")
+)
+
+// OtherRoute is the route that WithRouteStats counts requests under once the limit of distinct
+// routes is reached.
+const OtherRoute = "(other)"
+
+// Stats counts the requests that a Fault evaluated since it was created.
+type Stats struct {
+	// Injected is the number of requests the Fault injected.
+	Injected uint64 `json:"injected"`
+	// Skipped is the number of requests the Fault evaluated without injecting, the same requests
+	// that are recorded under ContextKeySkipped.
+	Skipped uint64 `json:"skipped"`
+	// Routes breaks down the counts by route if WithRouteStats is set.
+	Routes map[string]RouteStats `json:"routes,omitempty"`
+	// Delays is a histogram of the delays that the Injector waited for injected requests if
+	// WithDelayStats is set.
+	Delays []DelayBucket `json:"delays,omitempty"`
+}
+
+// DelayBucket counts the injected requests with a delay in one bucket of a histogram.
+type DelayBucket struct {
+	// Max is the longest delay in the bucket, or 0 for the last bucket, which counts every delay
+	// longer than the configured buckets.
+	Max time.Duration `json:"max"`
+	// Count is the number of injected requests with a delay in the bucket, longer than the Max of
+	// the previous bucket.
+	Count uint64 `json:"count"`
+}
+
+// RouteStats counts the requests to one route that a Fault evaluated.
+type RouteStats struct {
+	// Injected is the number of requests to the route the Fault injected.
+	Injected uint64 `json:"injected"`
+	// Skipped is the number of requests to the route the Fault evaluated without injecting.
+	Skipped uint64 `json:"skipped"`
+}
+
+type routeStatsOption int
+
+func (o routeStatsOption) applyFault(f *Fault) error {
+	if o < 1 {
+		return &OptionError{Option: "WithRouteStats", Value: int(o), Err: ErrInvalidCount}
+	}
+	f.routeStatsLimit = int(o)
+	f.routeStats = make(map[string]RouteStats)
+	return nil
+}
+
+// WithRouteStats breaks down the Stats of the Fault by route, so that experiment reports can show
+// which endpoints absorbed the injected faults. The route is the pattern returned by the function
+// set with WithPatternFunc, or the request path if the pattern is empty, such as when the Fault
+// runs before the http.ServeMux. At most limit routes are counted separately to bound memory, and
+// requests to any further routes are counted under OtherRoute. limit must be at least 1.
+func WithRouteStats(limit int) Option {
+	return routeStatsOption(limit)
+}
+
+type delayStatsOption []time.Duration
+
+func (o delayStatsOption) applyFault(f *Fault) error {
+	if !validBuckets(o) {
+		return &OptionError{Option: "WithDelayStats", Value: []time.Duration(o), Err: ErrInvalidBuckets}
+	}
+	f.delayBuckets = slices.Clone(o)
+	f.delayCounts = make([]atomic.Uint64, len(o)+1)
+	return nil
+}
+
+// validBuckets returns true if buckets is not empty and every bucket is greater than 0 and than the
+// bucket before it.
+func validBuckets(buckets []time.Duration) bool {
+	var prev time.Duration
+	for _, b := range buckets {
+		if b <= prev {
+			return false
+		}
+		prev = b
+	}
+	return len(buckets) > 0
+}
+
+// WithDelayStats counts the delays that the Injector waits for each injected request, such as with
+// the SlowInjector or the LoadLatencyInjector, in a histogram in the Stats of the Fault, so that
+// operators can check that the injected latency matches what they configured. buckets are the
+// longest delay in each bucket and must be greater than 0 and increasing, such as
+// DefaultDelayBuckets(). Delays longer than the last bucket are counted in one more bucket.
+// Injected requests that were not delayed are not counted.
+func WithDelayStats(buckets []time.Duration) Option {
+	return delayStatsOption(buckets)
+}
+
+// DefaultDelayBuckets returns buckets for WithDelayStats from 1ms to 10s.
+func DefaultDelayBuckets() []time.Duration {
+	return []time.Duration{
+		time.Millisecond,
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		25 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2500 * time.Millisecond,
+		5 * time.Second,
+		10 * time.Second,
+	}
+}
+
+// Stats returns the Stats of the Fault. Requests that pass while the Fault is disabled or warming up
+// are not counted.
+func (f *Fault) Stats() Stats {
+	s := Stats{
+		Injected: f.injected.Load(),
+		Skipped:  f.skipped.Load(),
+	}
+
+	if f.routeStatsLimit > 0 {
+		f.routeMtx.Lock()
+		defer f.routeMtx.Unlock()
+
+		s.Routes = make(map[string]RouteStats, len(f.routeStats))
+		for route, rs := range f.routeStats {
+			s.Routes[route] = rs
+		}
+	}
+
+	if f.delayBuckets != nil {
+		s.Delays = make([]DelayBucket, len(f.delayCounts))
+		for i := range f.delayCounts {
+			s.Delays[i].Count = f.delayCounts[i].Load()
+			if i < len(f.delayBuckets) {
+				s.Delays[i].Max = f.delayBuckets[i]
+			}
+		}
+	}
+
+	return s
+}
+
+// count counts r as injected or skipped.
+func (f *Fault) count(r *http.Request, injected bool) {
+	if injected {
+		f.injected.Add(1)
+	} else {
+		f.skipped.Add(1)
+	}
+
+	if f.routeStatsLimit == 0 {
+		return
+	}
+
+	route := f.patternF(r)
+	if route == "" {
+		route = r.URL.Path
+	}
+
+	f.routeMtx.Lock()
+	defer f.routeMtx.Unlock()
+
+	rs, ok := f.routeStats[route]
+	if !ok && len(f.routeStats) >= f.routeStatsLimit {
+		route = OtherRoute
+		rs = f.routeStats[route]
+	}
+	if injected {
+		rs.Injected++
+	} else {
+		rs.Skipped++
+	}
+	f.routeStats[route] = rs
+}
+
+// delayRecorderKey is the request context key for the *delayRecorder of the Fault that is
+// injecting.
+type delayRecorderKey struct{}
+
+// delayRecorder sums the delays that an Injector waits for one request.
+type delayRecorder struct {
+	d atomic.Int64
+}
+
+// withDelayRecorder returns a shallow copy of r that records the delays waited by the Injector of
+// f, and a function that counts them once the Injector returns. Faults without WithDelayStats hide
+// the recorder of any outer Fault so that their delays are not counted by it, which only allocates
+// if there is an outer recorder.
+func (f *Fault) withDelayRecorder(r *http.Request) (*http.Request, func()) {
+	if f.delayBuckets == nil {
+		if r.Context().Value(delayRecorderKey{}) != nil {
+			r = r.WithContext(context.WithValue(r.Context(), delayRecorderKey{}, (*delayRecorder)(nil)))
+		}
+		return r, func() {}
+	}
+
+	rec := &delayRecorder{}
+	r = r.WithContext(context.WithValue(r.Context(), delayRecorderKey{}, rec))
+
+	return r, func() {
+		d := time.Duration(rec.d.Load())
+		if d <= 0 {
+			return
+		}
+		i, _ := slices.BinarySearch(f.delayBuckets, d)
+		f.delayCounts[i].Add(1)
+	}
+}
+
+// recordDelay adds d to the delays recorded for r by the Fault that is injecting into it, if that
+// Fault has WithDelayStats.
+func recordDelay(r *http.Request, d time.Duration) {
+	if rec, ok := r.Context().Value(delayRecorderKey{}).(*delayRecorder); ok && rec != nil {
+		rec.d.Add(int64(d))
+	}
+}
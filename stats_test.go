@@ -0,0 +1,183 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFaultStats tests Fault.Stats.
+func TestFaultStats(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjector500s(),
+		WithEnabled(true),
+		WithEveryNth(2),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{}, f.Stats())
+
+	for range 3 {
+		testRequest(t, f)
+	}
+	assert.Equal(t, Stats{Injected: 1, Skipped: 2}, f.Stats())
+
+	// requests passed while disabled are not counted
+	f.SetEnabled(false)
+	testRequest(t, f)
+	assert.Equal(t, Stats{Injected: 1, Skipped: 2}, f.Stats())
+}
+
+// TestFaultRouteStats tests that WithRouteStats breaks down Stats by route.
+func TestFaultRouteStats(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithParticipationFunc(func(r *http.Request) float32 {
+			if r.URL.Path == "/a" {
+				return 1.0
+			}
+			return 0.0
+		}),
+		WithPatternFunc(func(r *http.Request) string {
+			if strings.HasPrefix(r.URL.Path, "/users/") {
+				return "GET /users/{id}"
+			}
+			return ""
+		}),
+		WithRouteStats(2),
+	)
+	assert.NoError(t, err)
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, path := range []string{"/a", "/a", "/users/1", "/users/2", "/b", "/c", "/a"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, Stats{
+		Injected: 3,
+		Skipped:  4,
+		Routes: map[string]RouteStats{
+			"/a":              {Injected: 3},
+			"GET /users/{id}": {Skipped: 2},
+			OtherRoute:        {Skipped: 2},
+		},
+	}, f.Stats())
+}
+
+// TestWithRouteStatsInvalid tests WithRouteStats with an invalid limit.
+func TestWithRouteStatsInvalid(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFault(newTestInjectorNoop(), WithRouteStats(0))
+	assert.Nil(t, f)
+	assert.Equal(t, &OptionError{Option: "WithRouteStats", Value: 0, Err: ErrInvalidCount}, err)
+}
+
+// TestFaultDelayStats tests that WithDelayStats counts injected delays in a histogram.
+func TestFaultDelayStats(t *testing.T) {
+	t.Parallel()
+
+	delays := []time.Duration{0, time.Millisecond, 3 * time.Millisecond, time.Second, 20 * time.Second}
+	var n int
+	li, err := NewLoadLatencyInjector(func(int) time.Duration {
+		n++
+		return delays[n-1]
+	}, WithSlowFunc(func(time.Duration) {}))
+	assert.NoError(t, err)
+
+	f, err := NewFault(li,
+		WithEnabled(true),
+		WithParticipation(1.0),
+		WithDelayStats([]time.Duration{time.Millisecond, 10 * time.Millisecond, time.Second}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []DelayBucket{
+		{Max: time.Millisecond},
+		{Max: 10 * time.Millisecond},
+		{Max: time.Second},
+		{Max: 0},
+	}, f.Stats().Delays)
+
+	for range delays {
+		testRequest(t, f)
+	}
+
+	assert.Equal(t, Stats{
+		Injected: 5,
+		Delays: []DelayBucket{
+			{Max: time.Millisecond, Count: 1},
+			{Max: 10 * time.Millisecond, Count: 1},
+			{Max: time.Second, Count: 1},
+			{Max: 0, Count: 1},
+		},
+	}, f.Stats())
+}
+
+// TestFaultDelayStatsNested tests that a Fault with WithDelayStats does not count the delays of
+// the Faults it wraps.
+func TestFaultDelayStatsNested(t *testing.T) {
+	t.Parallel()
+
+	outer, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithParticipation(1.0),
+		WithDelayStats(DefaultDelayBuckets()),
+	)
+	assert.NoError(t, err)
+
+	si, err := NewSlowInjector(time.Second, WithSlowFunc(func(time.Duration) {}))
+	assert.NoError(t, err)
+	inner, err := NewFault(si, WithEnabled(true), WithParticipation(1.0))
+	assert.NoError(t, err)
+
+	h := outer.Handler(inner.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	for _, b := range outer.Stats().Delays {
+		assert.Zero(t, b.Count)
+	}
+	assert.Equal(t, Stats{Injected: 1}, inner.Stats())
+}
+
+// TestWithDelayStatsInvalid tests WithDelayStats with invalid buckets.
+func TestWithDelayStatsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveBuckets []time.Duration
+	}{
+		{
+			name:        "nil",
+			giveBuckets: nil,
+		},
+		{
+			name:        "zero",
+			giveBuckets: []time.Duration{0, time.Second},
+		},
+		{
+			name:        "decreasing",
+			giveBuckets: []time.Duration{time.Second, time.Millisecond},
+		},
+		{
+			name:        "duplicate",
+			giveBuckets: []time.Duration{time.Second, time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(newTestInjectorNoop(), WithDelayStats(tt.giveBuckets))
+			assert.Nil(t, f)
+			assert.Equal(t, &OptionError{Option: "WithDelayStats", Value: tt.giveBuckets, Err: ErrInvalidBuckets}, err)
+		})
+	}
+}
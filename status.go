@@ -0,0 +1,88 @@
+package fault
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// RegistryStatus describes a Registry and its Faults.
+type RegistryStatus struct {
+	// Faults is the number of registered Faults.
+	Faults int `json:"faults"`
+	// Active is the number of registered Faults that are enabled, in scopes that are enabled.
+	Active int `json:"active"`
+	// LoadedAt is when Registry.Load last replaced the Faults, if ever.
+	LoadedAt *time.Time `json:"loadedAt,omitempty"`
+	// FaultStatuses describes each registered Fault in the order they were registered.
+	FaultStatuses []FaultStatus `json:"faultStatuses"`
+}
+
+// FaultStatus describes a Fault in a RegistryStatus.
+type FaultStatus struct {
+	// Name is the name of the Fault, prefixed with its scope if it is in one, such as "payments/a".
+	Name string `json:"name"`
+	// Enabled is true if the Fault and every scope it is in are enabled.
+	Enabled bool `json:"enabled"`
+	// Description is the String of the Fault.
+	Description string `json:"description"`
+
+	Stats
+}
+
+// Status returns the status of the Registry and its Faults.
+func (r *Registry) Status() RegistryStatus {
+	entries := r.entries()
+
+	s := RegistryStatus{
+		Faults:        len(entries),
+		FaultStatuses: make([]FaultStatus, 0, len(entries)),
+	}
+
+	r.mtx.RLock()
+	if !r.loadedAt.IsZero() {
+		loadedAt := r.loadedAt
+		s.LoadedAt = &loadedAt
+	}
+	r.mtx.RUnlock()
+
+	for _, e := range entries {
+		if e.enabled {
+			s.Active++
+		}
+		s.FaultStatuses = append(s.FaultStatuses, FaultStatus{
+			Name:        e.name,
+			Enabled:     e.enabled,
+			Description: e.fault.String(),
+			Stats:       e.fault.Stats(),
+		})
+	}
+
+	return s
+}
+
+// StatusHandler returns a read-only http.Handler that responds to GET and HEAD requests with the
+// Status of the Registry as JSON, such as:
+//
+//	{"faults":1,"active":1,"faultStatuses":[{"name":"ErrorInjector","enabled":true,
+//	"description":"ErrorInjector(503) @ 5%","injected":12,"skipped":228}]}
+//
+// Mount it next to other ops endpoints, such as /debug/faults. It never changes the Registry.
+func (r *Registry) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		// the client is gone if the status cannot be written
+		err := json.NewEncoder(w).Encode(r.Status())
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
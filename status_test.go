@@ -0,0 +1,105 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistryStatus tests Registry.Status.
+func TestRegistryStatus(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+	assert.Equal(t, RegistryStatus{FaultStatuses: []FaultStatus{}}, reg.Status())
+
+	on, err := NewFault(newTestInjector500s(), WithEnabled(true), WithParticipation(1.0), WithName("on"))
+	assert.NoError(t, err)
+	off, err := NewFault(newTestInjectorNoop(), WithName("off"))
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Register(on, off))
+
+	testRequest(t, on)
+	testRequest(t, on)
+
+	assert.Equal(t, RegistryStatus{
+		Faults: 2,
+		Active: 1,
+		FaultStatuses: []FaultStatus{
+			{Name: "on", Enabled: true, Description: on.String(), Stats: Stats{Injected: 2}},
+			{Name: "off", Enabled: false, Description: off.String()},
+		},
+	}, reg.Status())
+}
+
+// TestRegistryStatusHandler tests Registry.StatusHandler.
+func TestRegistryStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	reg, err := NewRegistry(WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+	assert.NoError(t, reg.Load(Config{Name: "a", Enabled: true, Injector: &InjectorConfig{Type: InjectorTypeReject}}))
+
+	tests := []struct {
+		name       string
+		giveMethod string
+		wantCode   int
+		wantHeader http.Header
+		wantBody   string
+	}{
+		{
+			name:       "get",
+			giveMethod: http.MethodGet,
+			wantCode:   http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type":  {"application/json"},
+				"Cache-Control": {"no-store"},
+			},
+			wantBody: `{"faults":1,"active":1,"loadedAt":"2020-01-01T00:00:00Z","faultStatuses":[{"name":"a",` +
+				`"enabled":true,"description":"a: RejectInjector @ 0%","injected":0,"skipped":0}]}` + "\n",
+		},
+		{
+			name:       "post",
+			giveMethod: http.MethodPost,
+			wantCode:   http.StatusMethodNotAllowed,
+			wantHeader: http.Header{
+				"Allow":                  {"GET, HEAD"},
+				"Content-Type":           {"text/plain; charset=utf-8"},
+				"X-Content-Type-Options": {"nosniff"},
+			},
+			wantBody: http.StatusText(http.StatusMethodNotAllowed) + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			reg.StatusHandler().ServeHTTP(rr, httptest.NewRequest(tt.giveMethod, "/", nil))
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantHeader, rr.Header())
+			assert.Equal(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+// TestRegistryStatusHandlerWriteError tests that Registry.StatusHandler aborts the request if the
+// status cannot be written.
+func TestRegistryStatusHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	w := &testMinimalWriter{header: make(http.Header), err: errTestWrite}
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		reg.StatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,229 @@
+package fault
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNilError when a nil error is provided.
+	ErrNilError = errors.New("error cannot be nil")
+)
+
+// stream holds the faults that a Reader or Writer injects into each read or write.
+type stream struct {
+	participation float32
+
+	delay time.Duration
+	slowF func(t time.Duration)
+	short bool
+	err   error
+
+	randSeed int64
+	rand     *rand.Rand
+	randF    func() float32
+
+	// randMtx protects stream.rand, which is not thread safe.
+	randMtx sync.Mutex
+}
+
+// StreamOption configures a Reader or Writer.
+type StreamOption interface {
+	applyStream(s *stream) error
+}
+
+func (o participationOption) applyStream(s *stream) error {
+	if o < 0.0 || o > 1.0 {
+		return &OptionError{Option: "WithParticipation", Value: float32(o), Err: ErrInvalidPercent}
+	}
+	s.participation = float32(o)
+	return nil
+}
+
+func (o randSeedOption) applyStream(s *stream) error {
+	s.randSeed = int64(o)
+	return nil
+}
+
+func (o randFloat32FuncOption) applyStream(s *stream) error {
+	s.randF = o
+	return nil
+}
+
+func (o slowFunctionOption) applyStream(s *stream) error {
+	s.slowF = o
+	return nil
+}
+
+type streamDelayOption time.Duration
+
+func (o streamDelayOption) applyStream(s *stream) error {
+	if o <= 0 {
+		return &OptionError{Option: "WithStreamDelay", Value: time.Duration(o), Err: ErrInvalidDuration}
+	}
+	s.delay = time.Duration(o)
+	return nil
+}
+
+// WithStreamDelay waits d before each read or write that participates, simulating a slow disk or
+// network.
+func WithStreamDelay(d time.Duration) StreamOption {
+	return streamDelayOption(d)
+}
+
+type shortIOOption struct{}
+
+func (o shortIOOption) applyStream(s *stream) error {
+	s.short = true
+	return nil
+}
+
+// WithShortIO makes each read or write that participates process only half of the buffer, and at
+// least one byte. Short writes return io.ErrShortWrite, as io.Writer requires.
+func WithShortIO() StreamOption {
+	return shortIOOption{}
+}
+
+type streamErrorOption struct {
+	err error
+}
+
+func (o streamErrorOption) applyStream(s *stream) error {
+	if o.err == nil {
+		return &OptionError{Option: "WithStreamError", Value: nil, Err: ErrNilError}
+	}
+	s.err = o.err
+	return nil
+}
+
+// WithStreamError makes each read or write that participates fail with err without reading or
+// writing anything.
+func WithStreamError(err error) StreamOption {
+	return streamErrorOption{err: err}
+}
+
+// newStream returns a stream with opts applied.
+func newStream(opts []StreamOption) (*stream, error) {
+	// set defaults
+	s := &stream{
+		slowF:    time.Sleep,
+		randSeed: defaultRandSeed,
+	}
+
+	// apply options
+	err := applyOptions(opts, StreamOption.applyStream, s)
+	if err != nil {
+		return nil, err
+	}
+
+	// set seeded rand source and function
+	s.rand = rand.New(rand.NewSource(s.randSeed))
+	if s.randF == nil {
+		s.randF = s.rand.Float32
+	}
+
+	return s, nil
+}
+
+// inject decides if the read or write of p participates and, if so, waits and returns how much of
+// p to process and the error to return instead of processing p.
+func (s *stream) inject(p []byte) ([]byte, error) {
+	if !s.participate() {
+		return p, nil
+	}
+
+	s.wait()
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.shorten(p), nil
+}
+
+// participate decides if an operation participates.
+func (s *stream) participate() bool {
+	s.randMtx.Lock()
+	rn := s.randF()
+	s.randMtx.Unlock()
+
+	return rn < s.participation
+}
+
+// wait waits for the configured delay, if any.
+func (s *stream) wait() {
+	if s.delay > 0 {
+		s.slowF(s.delay)
+	}
+}
+
+// shorten returns the part of p to process, which is half of p if WithShortIO is set.
+func (s *stream) shorten(p []byte) []byte {
+	if s.short && len(p) > 1 {
+		return p[:len(p)/2]
+	}
+	return p
+}
+
+// streamReader is an io.Reader that injects faults into reads.
+type streamReader struct {
+	r io.Reader
+	s *stream
+}
+
+// Reader returns an io.Reader that reads from r and injects faults into a participation percent of
+// reads, such as WithStreamDelay, WithShortIO, and WithStreamError. It accepts the same
+// WithParticipation, WithRandSeed, WithRandFloat32Func, and WithSlowFunc options as Faults and
+// Injectors. Use Reader to chaos test file and stream processing code.
+func Reader(r io.Reader, opts ...StreamOption) (io.Reader, error) {
+	s, err := newStream(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamReader{r: r, s: s}, nil
+}
+
+// Read reads from the wrapped io.Reader, injecting faults.
+func (r *streamReader) Read(p []byte) (int, error) {
+	p, err := r.s.inject(p)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
+
+// streamWriter is an io.Writer that injects faults into writes.
+type streamWriter struct {
+	w io.Writer
+	s *stream
+}
+
+// Writer returns an io.Writer that writes to w and injects faults into a participation percent of
+// writes, with the same options as Reader.
+func Writer(w io.Writer, opts ...StreamOption) (io.Writer, error) {
+	s, err := newStream(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamWriter{w: w, s: s}, nil
+}
+
+// Write writes to the wrapped io.Writer, injecting faults.
+func (w *streamWriter) Write(p []byte) (int, error) {
+	short, err := w.s.inject(p)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.w.Write(short)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+
+	return n, err
+}
@@ -0,0 +1,272 @@
+package fault
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTestStream = errors.New("error in stream")
+)
+
+// TestNewStream tests newStream.
+func TestNewStream(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []StreamOption
+		want        *stream
+		wantErr     error
+	}{
+		{
+			name:        "no options",
+			giveOptions: nil,
+			want: &stream{
+				randSeed: defaultRandSeed,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "all options",
+			giveOptions: []StreamOption{
+				WithParticipation(0.5),
+				WithStreamDelay(time.Second),
+				WithShortIO(),
+				WithStreamError(errTestStream),
+				WithRandSeed(100),
+				WithRandFloat32Func(func() float32 { return 0.0 }),
+				WithSlowFunc(func(time.Duration) {}),
+			},
+			want: &stream{
+				participation: 0.5,
+				delay:         time.Second,
+				short:         true,
+				err:           errTestStream,
+				randSeed:      100,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "invalid participation",
+			giveOptions: []StreamOption{
+				WithParticipation(1.1),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithParticipation", Value: float32(1.1), Err: ErrInvalidPercent},
+		},
+		{
+			name: "invalid delay",
+			giveOptions: []StreamOption{
+				WithStreamDelay(0),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithStreamDelay", Value: time.Duration(0), Err: ErrInvalidDuration},
+		},
+		{
+			name: "nil error",
+			giveOptions: []StreamOption{
+				WithStreamError(nil),
+			},
+			want:    nil,
+			wantErr: &OptionError{Option: "WithStreamError", Value: nil, Err: ErrNilError},
+		},
+		{
+			name: "option error",
+			giveOptions: []StreamOption{
+				withError(),
+			},
+			want:    nil,
+			wantErr: errErrorOption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := newStream(tt.giveOptions)
+
+			// Function equality cannot be determined so set to nil before comparing
+			if s != nil {
+				assert.NotNil(t, s.rand)
+				assert.NotNil(t, s.randF)
+				assert.NotNil(t, s.slowF)
+				s.rand = nil
+				s.randF = nil
+				s.slowF = nil
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, s)
+		})
+	}
+}
+
+// TestReader tests Reader.
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []StreamOption
+		wantReads   []string
+		wantErr     error
+		wantSlept   time.Duration
+	}{
+		{
+			name:        "no participation",
+			giveOptions: []StreamOption{WithShortIO(), WithStreamError(errTestStream)},
+			wantReads:   []string{"abcd", "ef"},
+			wantErr:     io.EOF,
+			wantSlept:   0,
+		},
+		{
+			name:        "slow",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamDelay(time.Second)},
+			wantReads:   []string{"abcd", "ef"},
+			wantErr:     io.EOF,
+			wantSlept:   3 * time.Second,
+		},
+		{
+			name:        "short",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithShortIO()},
+			wantReads:   []string{"ab", "cd", "ef"},
+			wantErr:     io.EOF,
+			wantSlept:   0,
+		},
+		{
+			name:        "error",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamError(errTestStream)},
+			wantReads:   nil,
+			wantErr:     errTestStream,
+			wantSlept:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var slept time.Duration
+			r, err := Reader(strings.NewReader("abcdef"), append(tt.giveOptions,
+				WithSlowFunc(func(d time.Duration) { slept += d }),
+			)...)
+			assert.NoError(t, err)
+
+			var reads []string
+			buf := make([]byte, 4)
+			for {
+				n, err := r.Read(buf)
+				if err != nil {
+					assert.Equal(t, tt.wantErr, err)
+					break
+				}
+				reads = append(reads, string(buf[:n]))
+			}
+
+			assert.Equal(t, tt.wantReads, reads)
+			assert.Equal(t, tt.wantSlept, slept)
+		})
+	}
+}
+
+// TestWriter tests Writer.
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []StreamOption
+		wantN       int
+		wantErr     error
+		wantWritten string
+		wantSlept   time.Duration
+	}{
+		{
+			name:        "no participation",
+			giveOptions: []StreamOption{WithShortIO(), WithStreamError(errTestStream)},
+			wantN:       4,
+			wantErr:     nil,
+			wantWritten: "abcd",
+			wantSlept:   0,
+		},
+		{
+			name:        "slow",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamDelay(time.Second)},
+			wantN:       4,
+			wantErr:     nil,
+			wantWritten: "abcd",
+			wantSlept:   time.Second,
+		},
+		{
+			name:        "short",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithShortIO()},
+			wantN:       2,
+			wantErr:     io.ErrShortWrite,
+			wantWritten: "ab",
+			wantSlept:   0,
+		},
+		{
+			name:        "error",
+			giveOptions: []StreamOption{WithParticipation(1.0), WithStreamError(errTestStream)},
+			wantN:       0,
+			wantErr:     errTestStream,
+			wantWritten: "",
+			wantSlept:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var slept time.Duration
+			var buf bytes.Buffer
+			w, err := Writer(&buf, append(tt.giveOptions,
+				WithSlowFunc(func(d time.Duration) { slept += d }),
+			)...)
+			assert.NoError(t, err)
+
+			n, err := w.Write([]byte("abcd"))
+
+			assert.Equal(t, tt.wantN, n)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantWritten, buf.String())
+			assert.Equal(t, tt.wantSlept, slept)
+		})
+	}
+}
+
+// TestReaderWriterOptionError tests Reader and Writer with invalid options.
+func TestReaderWriterOptionError(t *testing.T) {
+	t.Parallel()
+
+	r, err := Reader(strings.NewReader(""), withError())
+	assert.Nil(t, r)
+	assert.Equal(t, errErrorOption, err)
+
+	w, err := Writer(io.Discard, withError())
+	assert.Nil(t, w)
+	assert.Equal(t, errErrorOption, err)
+}
+
+// TestStreamShortSingleByte tests that WithShortIO never reduces an operation to zero bytes.
+func TestStreamShortSingleByte(t *testing.T) {
+	t.Parallel()
+
+	r, err := Reader(strings.NewReader("a"), WithParticipation(1.0), WithShortIO())
+	assert.NoError(t, err)
+
+	buf := make([]byte, 1)
+	n, err := r.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "a", string(buf))
+}
@@ -0,0 +1,158 @@
+package fault
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// rateSeparator separates an Injector from the rate it runs at in descriptions.
+const rateSeparator = " @ "
+
+// String describes the configuration of the Fault, such as
+// "ErrorInjector(503) @ 5% on /api, blocklist=/health".
+func (f *Fault) String() string {
+	s := injectorString(f.injector) + rateSeparator + f.rateString()
+
+	if f.name != typeName(f.injector) {
+		s = f.name + ": " + s
+	}
+	if len(f.pathAllowlist) > 0 {
+		s += " on " + strings.Join(sortedKeys(f.pathAllowlist), ",")
+	}
+
+	var details []string
+	if !f.enabled.Load() {
+		details = append(details, "disabled")
+	}
+	details = appendDetail(details, "blocklist", sortedKeys(f.pathBlocklist))
+	details = appendDetail(details, "skip", sortedKeys(f.skipPaths))
+	if f.skipPreflight {
+		details = append(details, "skipPreflight")
+	}
+	details = appendDetail(details, "patternAllowlist", sortedKeys(f.patternAllowlist))
+	details = appendDetail(details, "patternBlocklist", sortedKeys(f.patternBlocklist))
+	details = appendDetail(details, "uriAllowlist", sortedKeys(f.uriAllowlist))
+	details = appendDetail(details, "uriBlocklist", sortedKeys(f.uriBlocklist))
+	details = appendDetail(details, "headerAllowlist", headerStrings(f.headerAllowlist))
+	details = appendDetail(details, "headerBlocklist", headerStrings(f.headerBlocklist))
+	if f.brownoutHigh > 0 {
+		details = append(details, fmt.Sprintf("brownout=%d-%d", f.brownoutLow, f.brownoutHigh))
+	}
+	if f.warmupRequests > 0 {
+		details = append(details, fmt.Sprintf("warmup=%d", f.warmupRequests))
+	}
+	if f.warmupDuration > 0 {
+		details = append(details, fmt.Sprintf("warmupDuration=%s", f.warmupDuration))
+	}
+	if f.startupGrace > 0 {
+		details = append(details, fmt.Sprintf("startupGrace=%s", f.startupGrace))
+	}
+
+	if len(details) > 0 {
+		s += ", " + strings.Join(details, ", ")
+	}
+
+	return s
+}
+
+// rateString describes how the Fault chooses which requests to inject.
+func (f *Fault) rateString() string {
+	rate := f.participation()
+
+	switch {
+	case f.bucketing != nil:
+		return f.arm + " arm of " + f.bucketing.name
+	case f.badInstances != nil:
+		return f.instancesString()
+	case f.group != nil:
+		return percentString(f.group.participation) + " in " + f.group.name
+	case f.everyNth > 0:
+		return fmt.Sprintf("every %d", f.everyNth)
+	case f.burstOn > 0:
+		return fmt.Sprintf("burst %d/%d", f.burstOn, f.burstOff)
+	case f.burstOnDuration > 0:
+		return fmt.Sprintf("burst %s/%s", f.burstOnDuration, f.burstOffDuration)
+	case f.targetRate > 0:
+		return strconv.FormatFloat(f.targetRate, 'f', -1, 64) + "/s"
+	case f.participationF != nil:
+		return "dynamic %"
+	case rate.n > 0:
+		return rate.ratioString()
+	default:
+		return percentString(rate.p)
+	}
+}
+
+// percentString formats p, between 0.0 and 1.0, as a percent such as "5%".
+func percentString(p float32) string {
+	return strconv.FormatFloat(float64(p*100), 'f', -1, 32) + "%"
+}
+
+// typeName returns the name of the type of i, dereferencing pointers. An InjectorV2 adapted with
+// AdaptInjectorV2 is named after the InjectorV2.
+func typeName(i any) string {
+	if a, ok := i.(adaptedInjectorV2); ok {
+		i = a.InjectorV2
+	}
+	return reflect.Indirect(reflect.ValueOf(i)).Type().Name()
+}
+
+// injectorString returns the String() of i if it is a fmt.Stringer and otherwise the name of its
+// type. A nil Injector is "nil".
+func injectorString(i Injector) string {
+	if i == nil {
+		return "nil"
+	}
+	if s, ok := i.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return typeName(i)
+}
+
+// injectorsString describes is as a comma separated list.
+func injectorsString(is []Injector) string {
+	ss := make([]string, 0, len(is))
+	for _, i := range is {
+		ss = append(ss, injectorString(i))
+	}
+	return strings.Join(ss, ", ")
+}
+
+// appendDetail appends "key=v1,v2" to details if vals is not empty.
+func appendDetail(details []string, key string, vals []string) []string {
+	if len(vals) == 0 {
+		return details
+	}
+	return append(details, key+"="+strings.Join(vals, ","))
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// headerStrings returns "key:value" for each header in m in sorted order.
+func headerStrings(m map[string]string) []string {
+	var ss []string
+	for _, k := range sortedKeys(m) {
+		ss = append(ss, k+":"+m[k])
+	}
+	return ss
+}
+
+// countStrings returns "key:count" for each key in m in sorted order.
+func countStrings(m map[string]int) []string {
+	var ss []string
+	for _, k := range sortedKeys(m) {
+		ss = append(ss, k+":"+strconv.Itoa(m[k]))
+	}
+	return ss
+}
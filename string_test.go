@@ -0,0 +1,123 @@
+package fault
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFaultString tests Fault.String.
+func TestFaultString(t *testing.T) {
+	t.Parallel()
+
+	ei, err := NewErrorInjector(http.StatusServiceUnavailable)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		wantString  string
+	}{
+		{
+			name:        "disabled",
+			giveOptions: nil,
+			wantString:  "ErrorInjector(503) @ 0%, disabled",
+		},
+		{
+			name: "percent and lists",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipation(0.05),
+				WithPathAllowlist([]string{"/b", "/a"}),
+				WithPathBlocklist([]string{"/health"}),
+				WithPatternAllowlist([]string{"/c/{id}"}),
+				WithPatternBlocklist([]string{"/d/{id}"}),
+				WithURIAllowlist([]string{"/e?b=2&a=1"}),
+				WithURIBlocklist([]string{"/f?x"}),
+				WithHeaderAllowlist(map[string]string{"allow": "yes"}),
+				WithHeaderBlocklist(map[string]string{"block": "yes", "also": "yes"}),
+			},
+			wantString: "ErrorInjector(503) @ 5% on /a,/b, blocklist=/health, patternAllowlist=/c/{id}, " +
+				"patternBlocklist=/d/{id}, uriAllowlist=/e?a=1&b=2, uriBlocklist=/f?x=, " +
+				"headerAllowlist=allow:yes, headerBlocklist=also:yes,block:yes",
+		},
+		{
+			name: "every nth and warmup",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithEveryNth(3),
+				WithBrownout(10, 100),
+				WithWarmup(5),
+				WithWarmupDuration(time.Minute),
+				WithStartupGrace(time.Hour),
+				WithSkipHealthEndpoints(),
+				WithSkipPreflight(),
+			},
+			wantString: "ErrorInjector(503) @ every 3, skip=/health,/healthz,/livez,/metrics,/ping,/readyz, " +
+				"skipPreflight, brownout=10-100, warmup=5, warmupDuration=1m0s, startupGrace=1h0m0s",
+		},
+		{
+			name: "participation function",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipationFunc(func(r *http.Request) float32 { return 0.5 }),
+			},
+			wantString: "ErrorInjector(503) @ dynamic %",
+		},
+		{
+			name: "participation ratio",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithParticipationRatio(1, 100000),
+			},
+			wantString: "ErrorInjector(503) @ 1 in 100000",
+		},
+		{
+			name: "target rate",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithTargetRate(0.5),
+			},
+			wantString: "ErrorInjector(503) @ 0.5/s",
+		},
+		{
+			name: "burst",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithBurst(3, 4),
+			},
+			wantString: "ErrorInjector(503) @ burst 3/4",
+		},
+		{
+			name: "burst duration and name",
+			giveOptions: []Option{
+				WithEnabled(true),
+				WithBurstDuration(time.Second, time.Minute),
+				WithName("custom"),
+			},
+			wantString: "custom: ErrorInjector(503) @ burst 1s/1m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(ei, tt.giveOptions...)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.wantString, f.String())
+		})
+	}
+}
+
+// TestInjectorString tests injectorString.
+func TestInjectorString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "nil", injectorString(nil))
+	assert.Equal(t, "testInjectorNoop", injectorString(newTestInjectorNoop()))
+	assert.Equal(t, "testInjectorNoop, nil", injectorsString([]Injector{newTestInjectorNoop(), nil}))
+}
@@ -0,0 +1,134 @@
+package fault
+
+import (
+	"slices"
+	"time"
+)
+
+// Snapshot is a read-only copy of the configuration of the Faults in a Registry at one point in
+// time.
+type Snapshot struct {
+	// LoadedAt is when Registry.Load last replaced the Faults, or zero if it has not.
+	LoadedAt time.Time `json:"loadedAt"`
+	// Faults describes each registered Fault in the order they were registered.
+	Faults []FaultSnapshot `json:"faults"`
+}
+
+// FaultSnapshot is a read-only copy of the configuration of one Fault in a Snapshot.
+type FaultSnapshot struct {
+	// Name is the name of the Fault, prefixed with its scope if it is in one, such as "payments/a".
+	Name string `json:"name"`
+	// Enabled is true if the Fault and every scope it is in are enabled.
+	Enabled bool `json:"enabled"`
+	// Description is the String of the Fault.
+	Description string `json:"description"`
+}
+
+// subscriber is a function passed to Registry.Subscribe. It is a pointer so that it can be found
+// again to unsubscribe.
+type subscriber struct {
+	fn func(Snapshot)
+}
+
+// Snapshot returns the current configuration of the Faults in the Registry.
+func (r *Registry) Snapshot() Snapshot {
+	entries := r.entries()
+
+	r.mtx.RLock()
+	s := Snapshot{
+		LoadedAt: r.loadedAt,
+		Faults:   make([]FaultSnapshot, 0, len(entries)),
+	}
+	r.mtx.RUnlock()
+
+	for _, e := range entries {
+		s.Faults = append(s.Faults, FaultSnapshot{
+			Name:        e.name,
+			Enabled:     e.enabled,
+			Description: e.fault.String(),
+		})
+	}
+
+	return s
+}
+
+// Subscribe calls fn with a new Snapshot each time the Faults in the Registry change at runtime, so
+// that other subsystems such as dashboards and config exporters can follow them. The Faults change
+// when they are registered or loaded, and when a registered Fault is enabled, disabled, or has its
+// participation set, including by Toggle and Drain. fn is called once for every Fault that
+// changes, one call at a time, after the change is made. fn must not change the Faults in the
+// Registry itself. Call unsubscribe to stop calling fn.
+func (r *Registry) Subscribe(fn func(Snapshot)) (unsubscribe func()) {
+	sub := &subscriber{fn: fn}
+
+	r.subMtx.Lock()
+	r.subscribers = append(r.subscribers, sub)
+	r.subMtx.Unlock()
+
+	return func() {
+		r.subMtx.Lock()
+		defer r.subMtx.Unlock()
+
+		r.subscribers = slices.DeleteFunc(r.subscribers, func(s *subscriber) bool { return s == sub })
+	}
+}
+
+// notify calls every subscriber of r and of every Registry that r is scoped under with a new
+// Snapshot. It must be called without holding r.mtx.
+func (r *Registry) notify() {
+	for reg := r; reg != nil; reg = reg.parent {
+		reg.notifySubscribers()
+	}
+}
+
+// notifySubscribers calls every subscriber of r with a new Snapshot.
+func (r *Registry) notifySubscribers() {
+	r.notifyMtx.Lock()
+	defer r.notifyMtx.Unlock()
+
+	r.subMtx.Lock()
+	subscribers := slices.Clone(r.subscribers)
+	r.subMtx.Unlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	s := r.Snapshot()
+	for _, sub := range subscribers {
+		sub.fn(s)
+	}
+}
+
+// watch adds r to the Registries that f notifies when it changes and makes r the Registry whose
+// scope f is in, or removes r if add is false.
+func (f *Fault) watch(r *Registry, add bool) {
+	f.registriesMtx.Lock()
+	defer f.registriesMtx.Unlock()
+
+	f.registries = slices.DeleteFunc(f.registries, func(reg *Registry) bool { return reg == r })
+	if add {
+		f.registries = append(f.registries, r)
+		f.registry.Store(r)
+	} else {
+		f.registry.CompareAndSwap(r, nil)
+	}
+}
+
+// changed notifies every Registry that f is registered in that f changed.
+func (f *Fault) changed() {
+	f.registriesMtx.Lock()
+	registries := slices.Clone(f.registries)
+	f.registriesMtx.Unlock()
+
+	for _, r := range registries {
+		r.notify()
+	}
+}
+
+// setEnabled updates the enabled state of f, notifying its Registries if it changed.
+func (f *Fault) setEnabled(e bool) {
+	if f.enabled.Swap(e) != e {
+		f.changed()
+	}
+}
@@ -0,0 +1,86 @@
+package fault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistrySnapshot tests Registry.Snapshot.
+func TestRegistrySnapshot(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100, 0)
+	reg, err := NewRegistry(WithNowFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+	assert.Equal(t, Snapshot{Faults: []FaultSnapshot{}}, reg.Snapshot())
+
+	assert.NoError(t, reg.Load(Config{Name: "a", Enabled: true, Injector: &InjectorConfig{Type: InjectorTypeReject}}))
+	assert.Equal(t, Snapshot{
+		LoadedAt: now,
+		Faults: []FaultSnapshot{
+			{Name: "a", Enabled: true, Description: reg.Fault("a").String()},
+		},
+	}, reg.Snapshot())
+}
+
+// TestRegistrySubscribe tests that Registry.Subscribe calls its function each time the Faults in
+// the Registry change.
+func TestRegistrySubscribe(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry()
+	assert.NoError(t, err)
+
+	var got []Snapshot
+	unsubscribe := reg.Subscribe(func(s Snapshot) {
+		got = append(got, s)
+	})
+
+	// enabled returns the enabled state of every Fault in the last Snapshot
+	enabled := func() []bool {
+		var e []bool
+		for _, f := range got[len(got)-1].Faults {
+			e = append(e, f.Enabled)
+		}
+		return e
+	}
+
+	a := testRegistryFault(t, "a")
+	b := testRegistryFault(t, "b")
+	assert.NoError(t, reg.Register(a, b))
+	assert.Len(t, got, 1)
+	assert.Equal(t, []bool{true, true}, enabled())
+
+	// failed registrations and changes that do nothing are not reported
+	assert.Error(t, reg.Register(nil))
+	a.SetEnabled(true)
+	assert.Error(t, a.SetParticipation(2.0))
+	assert.Len(t, got, 1)
+
+	a.SetEnabled(false)
+	assert.Len(t, got, 2)
+	assert.Equal(t, []bool{false, true}, enabled())
+
+	assert.NoError(t, b.SetParticipation(0.5))
+	assert.Len(t, got, 3)
+	assert.Equal(t, b.String(), got[2].Faults[1].Description)
+
+	// Toggle reports once for every Fault that changes
+	reg.Toggle()
+	assert.Len(t, got, 4)
+	assert.Equal(t, []bool{false, false}, enabled())
+
+	assert.NoError(t, reg.Load(Config{Name: "c", Injector: &InjectorConfig{Type: InjectorTypeReject}}))
+	assert.Len(t, got, 5)
+	assert.Equal(t, "c", got[4].Faults[0].Name)
+
+	// Faults replaced by Load are no longer reported
+	a.SetEnabled(true)
+	assert.Len(t, got, 5)
+
+	unsubscribe()
+	reg.Fault("c").SetEnabled(true)
+	assert.Len(t, got, 5)
+}
@@ -0,0 +1,185 @@
+package fault
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// toxic is a Toxiproxy toxic.
+type toxic struct {
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`
+	Stream     string           `json:"stream"`
+	Toxicity   *float32         `json:"toxicity"`
+	Attributes map[string]int64 `json:"attributes"`
+}
+
+const (
+	toxicTypeLatency   = "latency"
+	toxicTypeTimeout   = "timeout"
+	toxicTypeBandwidth = "bandwidth"
+	toxicTypeSlicer    = "slicer"
+
+	// toxicBandwidthUnit is the number of bytes per second in the KB/s rate of a bandwidth toxic.
+	toxicBandwidthUnit = 1000
+
+	// toxicStreamDownstream is the stream from the server to the client, which Toxiproxy uses by
+	// default.
+	toxicStreamDownstream = "downstream"
+)
+
+// ParseToxics returns a Config for each Toxiproxy toxic in a JSON list, such as the response of
+// GET /proxies/{proxy}/toxics. Each Config is enabled, named after its toxic, and its participation
+// is the toxicity of the toxic.
+//
+// Latency toxics become a SlowInjector, timeout toxics become an IdleInjector, bandwidth toxics
+// become a BandwidthInjector, and slicer toxics become a SlicerInjector. Toxiproxy applies toxics to
+// a TCP stream while Faults run per request, so the stream of a toxic is ignored. Latency and
+// timeout delays happen once before the request is handled, and bandwidth and slicer toxics shape
+// the response body. Latency jitter, timeouts and rates of zero, and other types of toxic, such as
+// limit_data, return an error that wraps ErrUnsupported.
+func ParseToxics(data []byte) ([]Config, error) {
+	var toxics []toxic
+	err := json.Unmarshal(data, &toxics)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgs := make([]Config, 0, len(toxics))
+	for _, t := range toxics {
+		i, err := t.injector()
+		if err != nil {
+			return nil, fmt.Errorf("toxic %s: %w", t.Name, err)
+		}
+
+		participation := float32(1.0)
+		if t.Toxicity != nil {
+			participation = *t.Toxicity
+		}
+
+		cfgs = append(cfgs, Config{
+			Name:          t.Name,
+			Enabled:       true,
+			Participation: participation,
+			Injector:      &i,
+		})
+	}
+
+	return cfgs, nil
+}
+
+// injector returns the InjectorConfig for the toxic.
+func (t toxic) injector() (InjectorConfig, error) {
+	switch t.Type {
+	case toxicTypeLatency:
+		if t.Attributes["jitter"] != 0 {
+			return InjectorConfig{}, fmt.Errorf("jitter: %w", ErrUnsupported)
+		}
+		d := time.Duration(t.Attributes["latency"]) * time.Millisecond
+		return InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(d)}, nil
+	case toxicTypeTimeout:
+		d := time.Duration(t.Attributes["timeout"]) * time.Millisecond
+		if d == 0 {
+			return InjectorConfig{}, fmt.Errorf("timeout 0: %w", ErrUnsupported)
+		}
+		return InjectorConfig{Type: InjectorTypeIdle, Duration: Duration(d)}, nil
+	case toxicTypeBandwidth:
+		rate := t.Attributes["rate"]
+		if rate == 0 {
+			return InjectorConfig{}, fmt.Errorf("rate 0: %w", ErrUnsupported)
+		}
+		return InjectorConfig{Type: InjectorTypeBandwidth, Rate: rate * toxicBandwidthUnit}, nil
+	case toxicTypeSlicer:
+		d := time.Duration(t.Attributes["delay"]) * time.Microsecond
+		return InjectorConfig{
+			Type:           InjectorTypeSlicer,
+			SliceSize:      int(t.Attributes["average_size"]),
+			SliceVariation: int(t.Attributes["size_variation"]),
+			Duration:       Duration(d),
+		}, nil
+	default:
+		return InjectorConfig{}, fmt.Errorf("type %s: %w", t.Type, ErrUnsupported)
+	}
+}
+
+// MarshalToxics returns a JSON list with a Toxiproxy toxic for each of cfgs, for teams that still
+// run Toxiproxy next to in-process Faults. SlowInjectors become latency toxics, IdleInjectors
+// become timeout toxics, BandwidthInjectors become bandwidth toxics, and SlicerInjectors become
+// slicer toxics on the downstream. Latency and timeout durations are truncated to milliseconds,
+// slicer delays to microseconds, and bandwidth rates to KB/s, where a rate below 1 KB/s returns an
+// error that wraps ErrUnsupported. The toxicity of each toxic is the participation of the Config,
+// or 0 if it is not enabled, and its name is the name of the Config or, if that is empty, the
+// Toxiproxy default such as "latency_downstream".
+//
+// Toxiproxy cannot select requests, so a Config with any allowlist or blocklist returns an error
+// that wraps ErrUnsupported instead of a toxic that affects every request, as does any other type
+// of Injector.
+func MarshalToxics(cfgs ...Config) ([]byte, error) {
+	toxics := make([]toxic, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		t, err := newToxic(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("config %s: %w", cfg.Name, err)
+		}
+		toxics = append(toxics, t)
+	}
+
+	return json.Marshal(toxics)
+}
+
+// newToxic returns the toxic for cfg.
+func newToxic(cfg Config) (toxic, error) {
+	switch {
+	case cfg.Injector == nil:
+		return toxic{}, fmt.Errorf("injector: %w", ErrNilInjector)
+	case len(cfg.PathBlocklist) > 0, len(cfg.PathAllowlist) > 0,
+		len(cfg.PatternBlocklist) > 0, len(cfg.PatternAllowlist) > 0,
+		len(cfg.URIBlocklist) > 0, len(cfg.URIAllowlist) > 0,
+		len(cfg.HeaderBlocklist) > 0, len(cfg.HeaderAllowlist) > 0:
+		return toxic{}, fmt.Errorf("allowlists and blocklists: %w", ErrUnsupported)
+	}
+
+	var toxicity float32
+	if cfg.Enabled {
+		toxicity = cfg.Participation
+	}
+
+	t := toxic{
+		Name:     cfg.Name,
+		Stream:   toxicStreamDownstream,
+		Toxicity: &toxicity,
+	}
+
+	ms := time.Duration(cfg.Injector.Duration).Milliseconds()
+	switch cfg.Injector.Type {
+	case InjectorTypeSlow:
+		t.Type = toxicTypeLatency
+		t.Attributes = map[string]int64{"latency": ms, "jitter": 0}
+	case InjectorTypeIdle:
+		t.Type = toxicTypeTimeout
+		t.Attributes = map[string]int64{"timeout": ms}
+	case InjectorTypeBandwidth:
+		rate := cfg.Injector.Rate / toxicBandwidthUnit
+		if rate == 0 {
+			return toxic{}, fmt.Errorf("rate %d: %w", cfg.Injector.Rate, ErrUnsupported)
+		}
+		t.Type = toxicTypeBandwidth
+		t.Attributes = map[string]int64{"rate": rate}
+	case InjectorTypeSlicer:
+		t.Type = toxicTypeSlicer
+		t.Attributes = map[string]int64{
+			"average_size":   int64(cfg.Injector.SliceSize),
+			"size_variation": int64(cfg.Injector.SliceVariation),
+			"delay":          time.Duration(cfg.Injector.Duration).Microseconds(),
+		}
+	default:
+		return toxic{}, fmt.Errorf("injector %s: %w", cfg.Injector.Type, ErrUnsupported)
+	}
+
+	if t.Name == "" {
+		t.Name = t.Type + "_" + t.Stream
+	}
+
+	return t, nil
+}
@@ -0,0 +1,226 @@
+package fault
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseToxics tests ParseToxics.
+func TestParseToxics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		give     string
+		wantCfgs []Config
+		wantErr  error
+	}{
+		{
+			name: "latency and timeout",
+			give: `[{"name":"slow","type":"latency","stream":"upstream","toxicity":0.5,
+				"attributes":{"latency":1000,"jitter":0}},
+				{"name":"hang","type":"timeout","attributes":{"timeout":30000}}]`,
+			wantCfgs: []Config{
+				{
+					Name:          "slow",
+					Enabled:       true,
+					Participation: 0.5,
+					Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(time.Second)},
+				},
+				{
+					Name:          "hang",
+					Enabled:       true,
+					Participation: 1.0,
+					Injector:      &InjectorConfig{Type: InjectorTypeIdle, Duration: Duration(30 * time.Second)},
+				},
+			},
+		},
+		{
+			name:     "empty",
+			give:     `[]`,
+			wantCfgs: []Config{},
+		},
+		{
+			name:    "jitter",
+			give:    `[{"type":"latency","attributes":{"latency":1000,"jitter":100}}]`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "zero timeout",
+			give:    `[{"type":"timeout","attributes":{"timeout":0}}]`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name: "bandwidth and slicer",
+			give: `[{"name":"narrow","type":"bandwidth","toxicity":0.1,"attributes":{"rate":100}},
+				{"name":"chop","type":"slicer","attributes":{"average_size":64,"size_variation":32,"delay":10}}]`,
+			wantCfgs: []Config{
+				{
+					Name:          "narrow",
+					Enabled:       true,
+					Participation: 0.1,
+					Injector:      &InjectorConfig{Type: InjectorTypeBandwidth, Rate: 100000},
+				},
+				{
+					Name:          "chop",
+					Enabled:       true,
+					Participation: 1.0,
+					Injector: &InjectorConfig{
+						Type:           InjectorTypeSlicer,
+						SliceSize:      64,
+						SliceVariation: 32,
+						Duration:       Duration(10 * time.Microsecond),
+					},
+				},
+			},
+		},
+		{
+			name:    "zero rate",
+			give:    `[{"type":"bandwidth","attributes":{"rate":0}}]`,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "limit data",
+			give:    `[{"type":"limit_data","attributes":{"bytes":100}}]`,
+			wantErr: ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfgs, err := ParseToxics([]byte(tt.give))
+			if tt.wantErr != nil {
+				assert.Nil(t, cfgs)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCfgs, cfgs)
+			for _, cfg := range cfgs {
+				assert.Empty(t, Validate(cfg))
+			}
+		})
+	}
+}
+
+// TestParseToxicsInvalidJSON tests that ParseToxics returns JSON errors.
+func TestParseToxicsInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	cfgs, err := ParseToxics([]byte(`{}`))
+
+	var typeErr *json.UnmarshalTypeError
+	assert.Nil(t, cfgs)
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+// TestMarshalToxics tests MarshalToxics.
+func TestMarshalToxics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		giveCfgs []Config
+		wantJSON string
+		wantErr  error
+	}{
+		{
+			name: "latency and timeout",
+			giveCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 0.25,
+					Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(1500 * time.Microsecond)},
+				},
+				{
+					Name:          "hang",
+					Participation: 1.0,
+					Injector:      &InjectorConfig{Type: InjectorTypeIdle, Duration: Duration(time.Minute)},
+				},
+			},
+			wantJSON: `[{"name":"latency_downstream","type":"latency","stream":"downstream","toxicity":0.25,
+				"attributes":{"jitter":0,"latency":1}},
+				{"name":"hang","type":"timeout","stream":"downstream","toxicity":0,
+				"attributes":{"timeout":60000}}]`,
+		},
+		{
+			name: "bandwidth and slicer",
+			giveCfgs: []Config{
+				{
+					Enabled:       true,
+					Participation: 0.5,
+					Injector:      &InjectorConfig{Type: InjectorTypeBandwidth, Rate: 64500},
+				},
+				{
+					Name:          "chop",
+					Enabled:       true,
+					Participation: 1.0,
+					Injector: &InjectorConfig{
+						Type:           InjectorTypeSlicer,
+						SliceSize:      64,
+						SliceVariation: 32,
+						Duration:       Duration(time.Millisecond),
+					},
+				},
+			},
+			wantJSON: `[{"name":"bandwidth_downstream","type":"bandwidth","stream":"downstream","toxicity":0.5,
+				"attributes":{"rate":64}},
+				{"name":"chop","type":"slicer","stream":"downstream","toxicity":1,
+				"attributes":{"average_size":64,"size_variation":32,"delay":1000}}]`,
+		},
+		{
+			name: "bandwidth below 1 KB/s",
+			giveCfgs: []Config{{
+				Injector: &InjectorConfig{Type: InjectorTypeBandwidth, Rate: 999},
+			}},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:     "none",
+			wantJSON: `[]`,
+		},
+		{
+			name:     "nil injector",
+			giveCfgs: []Config{{}},
+			wantErr:  ErrNilInjector,
+		},
+		{
+			name: "allowlist",
+			giveCfgs: []Config{{
+				PathAllowlist: []string{"/api"},
+				Injector:      &InjectorConfig{Type: InjectorTypeSlow, Duration: Duration(time.Second)},
+			}},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name: "error injector",
+			giveCfgs: []Config{{
+				Injector: &InjectorConfig{Type: InjectorTypeError, StatusCode: http.StatusInternalServerError},
+			}},
+			wantErr: ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := MarshalToxics(tt.giveCfgs...)
+			if tt.wantErr != nil {
+				assert.Nil(t, data)
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.wantJSON, string(data))
+		})
+	}
+}
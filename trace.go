@@ -0,0 +1,149 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Reason is the check that decided whether a Fault injected into a request.
+type Reason int
+
+const (
+	// ReasonInjected when every check passed and the Fault injected.
+	ReasonInjected Reason = iota + 1
+	// ReasonWarmup when the Fault was still warming up.
+	ReasonWarmup
+	// ReasonDisabled when the Fault was disabled.
+	ReasonDisabled
+	// ReasonEnabledFunc when the function set by WithEnabledFunc returned false.
+	ReasonEnabledFunc
+	// ReasonSkipPath when the path was skipped by WithSkipHealthEndpoints.
+	ReasonSkipPath
+	// ReasonPreflight when the request was a CORS preflight skipped by WithSkipPreflight.
+	ReasonPreflight
+	// ReasonPathBlocklist when the path was in the path blocklist.
+	ReasonPathBlocklist
+	// ReasonPathAllowlist when the path was not in the path allowlist.
+	ReasonPathAllowlist
+	// ReasonHeaderBlocklist when a header matched the header blocklist.
+	ReasonHeaderBlocklist
+	// ReasonHeaderAllowlist when a header did not match the header allowlist.
+	ReasonHeaderAllowlist
+	// ReasonPatternBlocklist when the route pattern was in the pattern blocklist.
+	ReasonPatternBlocklist
+	// ReasonPatternAllowlist when the route pattern was not in the pattern allowlist.
+	ReasonPatternAllowlist
+	// ReasonParticipation when the request was not selected for participation.
+	ReasonParticipation
+	// ReasonGuard when a Guard did not allow the injection.
+	ReasonGuard
+	// ReasonCoordinator when the Coordinator did not allow the injection.
+	ReasonCoordinator
+	// ReasonURIBlocklist when the request URI was in the URI blocklist.
+	ReasonURIBlocklist
+	// ReasonURIAllowlist when the request URI was not in the URI allowlist.
+	ReasonURIAllowlist
+)
+
+// reasonNone is returned by checks that allow a request to proceed.
+const reasonNone Reason = 0
+
+// String returns the name of the Reason, such as "path blocklist".
+func (r Reason) String() string {
+	names := [...]string{
+		ReasonInjected:         "injected",
+		ReasonWarmup:           "warmup",
+		ReasonDisabled:         "disabled",
+		ReasonEnabledFunc:      "enabled func",
+		ReasonSkipPath:         "skip path",
+		ReasonPreflight:        "preflight",
+		ReasonPathBlocklist:    "path blocklist",
+		ReasonPathAllowlist:    "path allowlist miss",
+		ReasonHeaderBlocklist:  "header blocklist",
+		ReasonHeaderAllowlist:  "header allowlist miss",
+		ReasonPatternBlocklist: "pattern blocklist",
+		ReasonPatternAllowlist: "pattern allowlist miss",
+		ReasonParticipation:    "participation",
+		ReasonGuard:            "guard",
+		ReasonCoordinator:      "coordinator",
+		ReasonURIBlocklist:     "uri blocklist",
+		ReasonURIAllowlist:     "uri allowlist miss",
+	}
+
+	if r < ReasonInjected || int(r) >= len(names) {
+		return "Reason(" + strconv.Itoa(int(r)) + ")"
+	}
+
+	return names[r]
+}
+
+// Trace records how a Fault decided whether to inject into a request.
+type Trace struct {
+	// Fault is the name of the Fault.
+	Fault string
+	// Reason is the check that stopped the request, or ReasonInjected.
+	Reason Reason
+	// Rolled is true if the Fault drew a random number for participation.
+	Rolled bool
+	// Roll is the random number drawn for participation. The Fault injects if Roll is less than
+	// Participation.
+	Roll float32
+	// Participation is the participation percent that Roll was compared to.
+	Participation float32
+}
+
+// Injected returns true if the Fault injected into the request.
+func (t Trace) Injected() bool {
+	return t.Reason == ReasonInjected
+}
+
+// String describes the Trace, such as "ErrorInjector: participation (roll 0.73 >= 0.50)".
+func (t Trace) String() string {
+	s := t.Fault + ": " + t.Reason.String()
+
+	if t.Rolled {
+		cmp := ">="
+		if t.Roll < t.Participation {
+			cmp = "<"
+		}
+		s += fmt.Sprintf(" (roll %.2f %s %.2f)", t.Roll, cmp, t.Participation)
+	}
+
+	return s
+}
+
+type debugTraceOption bool
+
+func (o debugTraceOption) applyFault(f *Fault) error {
+	f.debugTrace = bool(o)
+	return nil
+}
+
+// WithDebugTrace sets if the Fault records a Trace of its decision for every request it handles,
+// including requests it skips because it is disabled or warming up. Traces are appended to the
+// []Trace in the request context under ContextKeyTrace and sent with an Event to the EventReporter,
+// which then also receives Events for requests the Fault did not inject. Default false. Use
+// WithDebugTrace to answer why a Fault did or did not fire.
+func WithDebugTrace(d bool) Option {
+	return debugTraceOption(d)
+}
+
+// traceRequest records t in the context of r and sends it to the EventReporter if the Fault did not
+// inject. Injections are reported with t when the injector runs.
+func (f *Fault) traceRequest(r *http.Request, t Trace) *http.Request {
+	existing, _ := r.Context().Value(ContextKeyTrace).([]Trace)
+
+	traces := make([]Trace, 0, len(existing)+1)
+	traces = append(traces, existing...)
+	traces = append(traces, t)
+
+	r = r.WithContext(context.WithValue(r.Context(), ContextKeyTrace, traces))
+
+	if !t.Injected() {
+		f.reportEvent(r, t)
+	}
+
+	return r
+}
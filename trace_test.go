@@ -0,0 +1,284 @@
+package fault
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithDebugTrace tests that WithDebugTrace records the check that decided each request.
+func TestWithDebugTrace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		giveMethod  string
+		givePath    string
+		wantTrace   Trace
+	}{
+		{
+			name:        "injected",
+			giveOptions: []Option{WithRandFloat32Func(func() float32 { return 0.25 })},
+			wantTrace:   Trace{Reason: ReasonInjected, Rolled: true, Roll: 0.25, Participation: 0.5},
+		},
+		{
+			name:        "warmup",
+			giveOptions: []Option{WithWarmup(1)},
+			wantTrace:   Trace{Reason: ReasonWarmup},
+		},
+		{
+			name:        "disabled",
+			giveOptions: []Option{WithEnabled(false)},
+			wantTrace:   Trace{Reason: ReasonDisabled},
+		},
+		{
+			name:        "enabled func",
+			giveOptions: []Option{WithEnabledFunc(func(r *http.Request) bool { return false })},
+			wantTrace:   Trace{Reason: ReasonEnabledFunc},
+		},
+		{
+			name:        "skip path",
+			giveOptions: []Option{WithSkipHealthEndpoints()},
+			givePath:    "/healthz",
+			wantTrace:   Trace{Reason: ReasonSkipPath},
+		},
+		{
+			name:        "preflight",
+			giveOptions: []Option{WithSkipPreflight()},
+			giveMethod:  http.MethodOptions,
+			wantTrace:   Trace{Reason: ReasonPreflight},
+		},
+		{
+			name:        "path blocklist",
+			giveOptions: []Option{WithPathBlocklist([]string{"/"})},
+			wantTrace:   Trace{Reason: ReasonPathBlocklist},
+		},
+		{
+			name:        "path allowlist miss",
+			giveOptions: []Option{WithPathAllowlist([]string{"/other"})},
+			wantTrace:   Trace{Reason: ReasonPathAllowlist},
+		},
+		{
+			name:        "header blocklist",
+			giveOptions: []Option{WithHeaderBlocklist(map[string]string{testHeaderKey: testHeaderVal})},
+			wantTrace:   Trace{Reason: ReasonHeaderBlocklist},
+		},
+		{
+			name:        "header allowlist miss",
+			giveOptions: []Option{WithHeaderAllowlist(map[string]string{testHeaderKey: "other"})},
+			wantTrace:   Trace{Reason: ReasonHeaderAllowlist},
+		},
+		{
+			name:        "pattern blocklist",
+			giveOptions: []Option{WithPatternBlocklist([]string{""})},
+			wantTrace:   Trace{Reason: ReasonPatternBlocklist},
+		},
+		{
+			name:        "pattern allowlist miss",
+			giveOptions: []Option{WithPatternAllowlist([]string{"/other"})},
+			wantTrace:   Trace{Reason: ReasonPatternAllowlist},
+		},
+		{
+			name:        "uri blocklist",
+			giveOptions: []Option{WithURIBlocklist([]string{"/"})},
+			wantTrace:   Trace{Reason: ReasonURIBlocklist},
+		},
+		{
+			name:        "uri allowlist miss",
+			giveOptions: []Option{WithURIAllowlist([]string{"/?other"})},
+			wantTrace:   Trace{Reason: ReasonURIAllowlist},
+		},
+		{
+			name:        "participation",
+			giveOptions: []Option{WithRandFloat32Func(func() float32 { return 0.75 })},
+			wantTrace:   Trace{Reason: ReasonParticipation, Rolled: true, Roll: 0.75, Participation: 0.5},
+		},
+		{
+			name:        "deterministic participation",
+			giveOptions: []Option{WithEveryNth(2)},
+			wantTrace:   Trace{Reason: ReasonParticipation},
+		},
+		{
+			name: "guard",
+			giveOptions: []Option{
+				WithRandFloat32Func(func() float32 { return 0.0 }),
+				WithGuard(&testGuard{allow: false}),
+			},
+			wantTrace: Trace{Reason: ReasonGuard, Rolled: true, Roll: 0.0, Participation: 0.5},
+		},
+		{
+			name: "coordinator",
+			giveOptions: []Option{
+				WithRandFloat32Func(func() float32 { return 0.0 }),
+				WithCoordinator(&testCoordinator{allow: false}),
+			},
+			wantTrace: Trace{Reason: ReasonCoordinator, Rolled: true, Roll: 0.0, Participation: 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			er := &testEventReporter{}
+			opts := append([]Option{
+				WithEnabled(true),
+				WithParticipation(0.5),
+				WithDebugTrace(true),
+				WithEventReporter(er),
+			}, tt.giveOptions...)
+
+			f, err := NewFault(newTestInjectorNoop(), opts...)
+			assert.NoError(t, err)
+
+			method := http.MethodGet
+			if tt.giveMethod != "" {
+				method = tt.giveMethod
+			}
+			path := "/"
+			if tt.givePath != "" {
+				path = tt.givePath
+			}
+
+			var got any
+			h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Context().Value(ContextKeyTrace)
+			}))
+
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set(testHeaderKey, testHeaderVal)
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			tt.wantTrace.Fault = "testInjectorNoop"
+			assert.Equal(t, []Trace{tt.wantTrace}, got)
+
+			assert.Len(t, er.events, 1)
+			assert.Equal(t, &tt.wantTrace, er.events[0].Trace)
+		})
+	}
+}
+
+// TestWithDebugTraceDisabled tests that a Fault without WithDebugTrace records no Trace.
+func TestWithDebugTraceDisabled(t *testing.T) {
+	t.Parallel()
+
+	er := &testEventReporter{}
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithParticipation(1.0),
+		WithDebugTrace(false),
+		WithEventReporter(er),
+	)
+	assert.NoError(t, err)
+
+	var got any
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context().Value(ContextKeyTrace)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Nil(t, got)
+	assert.Len(t, er.events, 1)
+	assert.Nil(t, er.events[0].Trace)
+}
+
+// TestWithDebugTraceChain tests that Faults append their Traces in the order they handle a request.
+func TestWithDebugTraceChain(t *testing.T) {
+	t.Parallel()
+
+	one, err := NewFault(newTestInjectorNoop(), WithName("one"), WithEnabled(false), WithDebugTrace(true))
+	assert.NoError(t, err)
+	two, err := NewFault(newTestInjectorNoop(), WithName("two"), WithEnabled(true), WithDebugTrace(true),
+		WithParticipation(1.0), WithRandFloat32Func(func() float32 { return 0.5 }))
+	assert.NoError(t, err)
+
+	var got any
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context().Value(ContextKeyTrace)
+	}), one, two)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []Trace{
+		{Fault: "one", Reason: ReasonDisabled},
+		{Fault: "two", Reason: ReasonInjected, Rolled: true, Roll: 0.5, Participation: 1.0},
+	}, got)
+}
+
+// TestWithDebugTraceAudit tests that an AuditReporter ignores Events for requests that were not
+// injected.
+func TestWithDebugTraceAudit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ar, err := NewAuditReporter(&buf)
+	assert.NoError(t, err)
+
+	f, err := NewFault(newTestInjectorNoop(),
+		WithEnabled(true),
+		WithEveryNth(2),
+		WithDebugTrace(true),
+		WithEventReporter(ar),
+		WithNowFunc(func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }),
+	)
+	assert.NoError(t, err)
+
+	testRequest(t, f)
+	testRequest(t, f)
+
+	assert.Equal(t, `{"time":"2024-01-02T03:04:05Z","fault":"testInjectorNoop","injector":"testInjectorNoop",`+
+		`"method":"GET","path":"/"}`+"\n", buf.String())
+}
+
+// TestReasonString tests Reason.String.
+func TestReasonString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "injected", ReasonInjected.String())
+	assert.Equal(t, "header allowlist miss", ReasonHeaderAllowlist.String())
+	assert.Equal(t, "coordinator", ReasonCoordinator.String())
+	assert.Equal(t, "uri allowlist miss", ReasonURIAllowlist.String())
+	assert.Equal(t, "Reason(0)", Reason(0).String())
+	assert.Equal(t, "Reason(100)", Reason(100).String())
+}
+
+// TestTraceString tests Trace.String.
+func TestTraceString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		giveTrace Trace
+		wantStr   string
+	}{
+		{
+			name:      "no roll",
+			giveTrace: Trace{Fault: "f", Reason: ReasonPathBlocklist},
+			wantStr:   "f: path blocklist",
+		},
+		{
+			name:      "roll injected",
+			giveTrace: Trace{Fault: "f", Reason: ReasonInjected, Rolled: true, Roll: 0.1, Participation: 0.5},
+			wantStr:   "f: injected (roll 0.10 < 0.50)",
+		},
+		{
+			name:      "roll skipped",
+			giveTrace: Trace{Fault: "f", Reason: ReasonParticipation, Rolled: true, Roll: 0.73, Participation: 0.5},
+			wantStr:   "f: participation (roll 0.73 >= 0.50)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantStr, tt.giveTrace.String())
+			assert.Equal(t, tt.giveTrace.Reason == ReasonInjected, tt.giveTrace.Injected())
+		})
+	}
+}
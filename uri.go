@@ -0,0 +1,228 @@
+package fault
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrInvalidListEntry when an entry of a path or URI list can never match a request.
+	ErrInvalidListEntry = errors.New("list entry cannot be compiled")
+)
+
+type uriBlocklistOption []string
+
+func (o uriBlocklistOption) applyFault(f *Fault) error {
+	blocklist := make(map[string]bool, len(o))
+	for _, uri := range o {
+		blocklist[uri] = true
+	}
+	f.uriBlocklist = blocklist
+	return nil
+}
+
+// WithURIBlocklist is a list of request URIs, a path and query such as "/search?q=test", that the
+// Injector will not run against. Use it when a routing scheme distinguishes behavior by query
+// string. Clients do not send fragments, so fragments in the list are ignored. By default query
+// parameters are compared regardless of their order or escaping, see WithURIExactMatch.
+func WithURIBlocklist(blocklist []string) Option {
+	return uriBlocklistOption(blocklist)
+}
+
+type uriAllowlistOption []string
+
+func (o uriAllowlistOption) applyFault(f *Fault) error {
+	allowlist := make(map[string]bool, len(o))
+	for _, uri := range o {
+		allowlist[uri] = true
+	}
+	f.uriAllowlist = allowlist
+	return nil
+}
+
+// WithURIAllowlist is, if set, a list of the only request URIs, a path and query such as
+// "/search?q=test", that the Injector will run against. See WithURIBlocklist.
+func WithURIAllowlist(allowlist []string) Option {
+	return uriAllowlistOption(allowlist)
+}
+
+type uriExactMatchOption bool
+
+func (o uriExactMatchOption) applyFault(f *Fault) error {
+	f.uriExact = bool(o)
+	return nil
+}
+
+// WithURIExactMatch sets if the URI allowlist and blocklist must match the request URI exactly as
+// the client sent it. Default false, which sorts query parameters by key and escapes them
+// consistently before comparing, so that "/search?b=2&a=%41" matches "/search?a=A&b=2".
+func WithURIExactMatch(e bool) Option {
+	return uriExactMatchOption(e)
+}
+
+type escapedPathMatchOption bool
+
+func (o escapedPathMatchOption) applyFault(f *Fault) error {
+	f.escapedPaths = bool(o)
+	return nil
+}
+
+// WithEscapedPathMatch sets if the path and URI allowlists and blocklists match the path as the
+// client escaped it, see url.URL.EscapedPath. Default false, which unescapes percent-encoding in
+// both the lists and the request first, so that "/foo%2Fbar" and "/foo/bar" match each other and an
+// escaped request path cannot bypass a blocklist.
+func WithEscapedPathMatch(e bool) Option {
+	return escapedPathMatchOption(e)
+}
+
+// compileLists compiles the lists of the Fault once in NewFault, so that checking a request does
+// no parsing of the lists. It normalizes the paths in the path allowlist and blocklist unless the
+// Fault has WithEscapedPathMatch, and the URIs in the URI allowlist and blocklist unless the Fault
+// has WithURIExactMatch. It also builds the header rules from the header allowlist and blocklist,
+// and the bloom filters of the blocklists if the Fault has WithBlocklistBloomFilter. It returns an
+// OptionError for every path or URI that is not validly escaped.
+func (f *Fault) compileLists() error {
+	var errs []error
+	compile := func(option string, m map[string]bool, compileKey func(string) (string, error)) map[string]bool {
+		compiled, keyErrs := compileKeys(option, m, compileKey)
+		errs = append(errs, keyErrs...)
+		return compiled
+	}
+
+	f.pathBlocklist = compile("WithPathBlocklist", f.pathBlocklist, f.compilePath)
+	f.pathAllowlist = compile("WithPathAllowlist", f.pathAllowlist, f.compilePath)
+
+	if !f.uriExact {
+		f.uriBlocklist = compile("WithURIBlocklist", f.uriBlocklist, f.compileURI)
+		f.uriAllowlist = compile("WithURIAllowlist", f.uriAllowlist, f.compileURI)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return err
+	}
+
+	f.headerBlockRules = f.headerRules(f.headerBlocklist)
+	f.headerAllowRules = f.headerRules(f.headerAllowlist)
+
+	if f.blocklistBloom {
+		f.pathBloom = newBloomFilter(f.pathBlocklist)
+		f.patternBloom = newBloomFilter(f.patternBlocklist)
+		f.uriBloom = newBloomFilter(f.uriBlocklist)
+	}
+
+	return nil
+}
+
+// compileKeys returns a copy of m with compile applied to every key, or nil if m is nil. It returns
+// an OptionError for option, in key order, for every key that compile returns an error for.
+func compileKeys(option string, m map[string]bool, compile func(string) (string, error)) (map[string]bool, []error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	var errs []error
+	compiled := make(map[string]bool, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		c, err := compile(key)
+		if err != nil {
+			errs = append(errs, &OptionError{Option: option, Value: key, Err: fmt.Errorf("%w: %w", ErrInvalidListEntry, err)})
+			continue
+		}
+		compiled[c] = true
+	}
+	return compiled, errs
+}
+
+// compilePath returns path as the path allowlist and blocklist compare it, unescaped unless the
+// Fault has WithEscapedPathMatch, or an error if path is not validly escaped.
+func (f *Fault) compilePath(path string) (string, error) {
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return "", err
+	}
+	if f.escapedPaths {
+		return path, nil
+	}
+	return unescaped, nil
+}
+
+// compileURI returns uri normalized by normalizeURI, or an error if its path or query is not validly
+// escaped.
+func (f *Fault) compileURI(uri string) (string, error) {
+	uri, _, _ = strings.Cut(uri, "#")
+	path, query, _ := strings.Cut(uri, "?")
+
+	_, err := url.PathUnescape(path)
+	if err != nil {
+		return "", err
+	}
+	_, err = url.ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+
+	return f.normalizeURI(uri), nil
+}
+
+// requestPath returns the path of r that the path allowlist and blocklist are compared to.
+func (f *Fault) requestPath(r *http.Request) string {
+	if f.escapedPaths {
+		return r.URL.EscapedPath()
+	}
+	return r.URL.Path
+}
+
+// normalizeURI returns uri without a fragment and with its query parameters sorted by key and
+// consistently escaped. Its path is unescaped unless the Fault has WithEscapedPathMatch, and must be
+// validly escaped, as compileURI checks for list entries and url.URL.RequestURI always is. A
+// query that cannot be parsed is kept as is.
+func (f *Fault) normalizeURI(uri string) string {
+	uri, _, _ = strings.Cut(uri, "#")
+
+	path, query, ok := strings.Cut(uri, "?")
+	if !f.escapedPaths {
+		path, _ = url.PathUnescape(path)
+	}
+	if !ok {
+		return path
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return path + "?" + query
+	}
+	if len(values) == 0 {
+		return path
+	}
+
+	return path + "?" + values.Encode()
+}
+
+// checkURILists checks the request URI against the provided URI allowlist and blocklist, returning
+// the Reason the request may not proceed or reasonNone if it may.
+func (f *Fault) checkURILists(r *http.Request) Reason {
+	if len(f.uriBlocklist) == 0 && len(f.uriAllowlist) == 0 {
+		return reasonNone
+	}
+
+	uri := r.URL.RequestURI()
+	if !f.uriExact {
+		uri = f.normalizeURI(uri)
+	}
+
+	if f.uriBloom.mayContain(uri) && f.uriBlocklist[uri] {
+		return ReasonURIBlocklist
+	}
+
+	if len(f.uriAllowlist) > 0 && !f.uriAllowlist[uri] {
+		return ReasonURIAllowlist
+	}
+
+	return reasonNone
+}
@@ -0,0 +1,290 @@
+package fault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeURI tests Fault.normalizeURI.
+func TestNormalizeURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveURI     string
+		giveEscaped bool
+		want        string
+	}{
+		{
+			name:    "path",
+			giveURI: "/search",
+			want:    "/search",
+		},
+		{
+			name:    "empty query",
+			giveURI: "/search?",
+			want:    "/search",
+		},
+		{
+			name:    "sorted",
+			giveURI: "/search?b=2&a=%41",
+			want:    "/search?a=A&b=2",
+		},
+		{
+			name:    "repeated keys keep their order",
+			giveURI: "/search?a=2&a=1",
+			want:    "/search?a=2&a=1",
+		},
+		{
+			name:    "fragment",
+			giveURI: "/search?q=test#results",
+			want:    "/search?q=test",
+		},
+		{
+			name:    "invalid query",
+			giveURI: "/search?q=%zz",
+			want:    "/search?q=%zz",
+		},
+		{
+			name:    "escaped path",
+			giveURI: "/foo%2Fbar?q=a",
+			want:    "/foo/bar?q=a",
+		},
+		{
+			name:        "escaped path match",
+			giveURI:     "/foo%2Fbar?q=a",
+			giveEscaped: true,
+			want:        "/foo%2Fbar?q=a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &Fault{escapedPaths: tt.giveEscaped}
+			assert.Equal(t, tt.want, f.normalizeURI(tt.giveURI))
+		})
+	}
+}
+
+// TestURILists tests WithURIBlocklist, WithURIAllowlist, and WithURIExactMatch.
+func TestURILists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveOptions  []Option
+		giveURI      string
+		wantInjected bool
+	}{
+		{
+			name:         "allowlist",
+			giveOptions:  []Option{WithURIAllowlist([]string{"/search?a=1&b=2"})},
+			giveURI:      "/search?b=2&a=1",
+			wantInjected: true,
+		},
+		{
+			name:         "allowlist miss",
+			giveOptions:  []Option{WithURIAllowlist([]string{"/search?a=1&b=2"})},
+			giveURI:      "/search?a=1",
+			wantInjected: false,
+		},
+		{
+			name:         "blocklist",
+			giveOptions:  []Option{WithURIBlocklist([]string{"/search?debug=true#top"})},
+			giveURI:      "/search?debug=true",
+			wantInjected: false,
+		},
+		{
+			name:         "blocklist miss",
+			giveOptions:  []Option{WithURIBlocklist([]string{"/search?debug=true"})},
+			giveURI:      "/search",
+			wantInjected: true,
+		},
+		{
+			name: "exact",
+			giveOptions: []Option{
+				WithURIAllowlist([]string{"/search?b=2&a=1"}),
+				WithURIExactMatch(true),
+			},
+			giveURI:      "/search?b=2&a=1",
+			wantInjected: true,
+		},
+		{
+			name: "exact miss",
+			giveOptions: []Option{
+				WithURIAllowlist([]string{"/search?a=1&b=2"}),
+				WithURIExactMatch(true),
+			},
+			giveURI:      "/search?b=2&a=1",
+			wantInjected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithParticipation(1.0)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			f.Handler(http.NotFoundHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.giveURI, nil))
+
+			assert.Equal(t, tt.wantInjected, rr.Code == http.StatusInternalServerError)
+		})
+	}
+}
+
+// TestEscapedPathMatch tests that paths are unescaped before they are compared to the path and URI
+// lists unless WithEscapedPathMatch is set.
+func TestEscapedPathMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		giveOptions  []Option
+		giveURI      string
+		wantInjected bool
+	}{
+		{
+			name:         "escaped blocklist",
+			giveOptions:  []Option{WithPathBlocklist([]string{"/foo%2Fbar"})},
+			giveURI:      "/foo%2Fbar",
+			wantInjected: false,
+		},
+		{
+			name:         "unescaped blocklist",
+			giveOptions:  []Option{WithPathBlocklist([]string{"/foo/bar"})},
+			giveURI:      "/foo%2Fbar",
+			wantInjected: false,
+		},
+		{
+			name:         "escaped percent",
+			giveOptions:  []Option{WithPathAllowlist([]string{"/100%25"})},
+			giveURI:      "/100%25",
+			wantInjected: true,
+		},
+		{
+			name:         "uri allowlist",
+			giveOptions:  []Option{WithURIAllowlist([]string{"/foo/%62ar?q=a"})},
+			giveURI:      "/foo%2Fbar?q=a",
+			wantInjected: true,
+		},
+		{
+			name: "escaped path match",
+			giveOptions: []Option{
+				WithPathBlocklist([]string{"/foo%2Fbar"}),
+				WithEscapedPathMatch(true),
+			},
+			giveURI:      "/foo/bar",
+			wantInjected: true,
+		},
+		{
+			name: "escaped path match blocks",
+			giveOptions: []Option{
+				WithPathBlocklist([]string{"/foo%2Fbar"}),
+				WithURIBlocklist([]string{"/foo%2Fbar?q=a"}),
+				WithEscapedPathMatch(true),
+			},
+			giveURI:      "/foo%2Fbar",
+			wantInjected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]Option{WithEnabled(true), WithParticipation(1.0)}, tt.giveOptions...)
+			f, err := NewFault(newTestInjector500s(), opts...)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			f.Handler(http.NotFoundHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.giveURI, nil))
+
+			assert.Equal(t, tt.wantInjected, rr.Code == http.StatusInternalServerError)
+		})
+	}
+}
+
+// TestCompileListsErrors tests that NewFault returns an OptionError for every path and URI list
+// entry that is not validly escaped.
+func TestCompileListsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		giveOptions []Option
+		wantOptions []string
+	}{
+		{
+			name:        "path blocklist",
+			giveOptions: []Option{WithPathBlocklist([]string{"/ok", "/100%"})},
+			wantOptions: []string{"WithPathBlocklist"},
+		},
+		{
+			name: "escaped path allowlist",
+			giveOptions: []Option{
+				WithPathAllowlist([]string{"/%zz"}),
+				WithEscapedPathMatch(true),
+			},
+			wantOptions: []string{"WithPathAllowlist"},
+		},
+		{
+			name:        "uri blocklist path",
+			giveOptions: []Option{WithURIBlocklist([]string{"/%zz?q=a"})},
+			wantOptions: []string{"WithURIBlocklist"},
+		},
+		{
+			name:        "uri allowlist query",
+			giveOptions: []Option{WithURIAllowlist([]string{"/search?q=%zz"})},
+			wantOptions: []string{"WithURIAllowlist"},
+		},
+		{
+			name: "every list",
+			giveOptions: []Option{
+				WithPathBlocklist([]string{"/a%", "/b%"}),
+				WithURIAllowlist([]string{"/search?a=1;b=2"}),
+			},
+			wantOptions: []string{"WithPathBlocklist", "WithPathBlocklist", "WithURIAllowlist"},
+		},
+		{
+			name: "uri exact match",
+			giveOptions: []Option{
+				WithURIAllowlist([]string{"/search?q=%zz"}),
+				WithURIExactMatch(true),
+			},
+			wantOptions: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFault(newTestInjector500s(), tt.giveOptions...)
+
+			if tt.wantOptions == nil {
+				assert.NoError(t, err)
+				assert.NotNil(t, f)
+				return
+			}
+			assert.Nil(t, f)
+			assert.ErrorIs(t, err, ErrInvalidListEntry)
+
+			var options []string
+			for _, e := range splitErrors(err) {
+				var optErr *OptionError
+				assert.ErrorAs(t, e, &optErr)
+				options = append(options, optErr.Option)
+			}
+			assert.Equal(t, tt.wantOptions, options)
+		})
+	}
+}